@@ -115,6 +115,8 @@ resource "aws_ecs_task_definition" "task_definitions" {
       memory = var.service_configs[each.value].memory
       cpu    = var.service_configs[each.value].cpu
       essential = true
+      # SIGKILL after this, the services drain within SHUTDOWN_TIMEOUT (20s)
+      stopTimeout = 30
       portMappings = [
         {
           containerPort = var.service_configs[each.value].container_port
@@ -139,6 +141,8 @@ resource "aws_ecs_service" "ecs_services" {
   launch_type     = "FARGATE"
   desired_count   = 1
 
+  health_check_grace_period_seconds = 60
+
   load_balancer {
     target_group_arn = aws_lb_target_group.target_groups[each.key].arn
     container_name   = aws_ecs_task_definition.task_definitions[each.key].family
@@ -159,6 +163,17 @@ resource "aws_lb_target_group" "target_groups" {
   port        = var.service_configs[each.value].container_port
   protocol    = "HTTP"
   target_type = "ip"
+
+  # /readyz answers 503 while Postgres or Redis is unreachable, downstream
+  # services only degrade it. /healthz is the liveness probe
+  health_check {
+    path                = "/readyz"
+    matcher             = "200"
+    interval            = 15
+    timeout             = 10
+    healthy_threshold   = 3
+    unhealthy_threshold = 2
+  }
 }
 
 resource "aws_lb_listener_rule" "path_based_routing" {
@@ -0,0 +1,39 @@
+package auth
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrMissingToken     = errors.New("missing bearer token")
+	ErrMalformedHeader  = errors.New("malformed authorization header")
+	ErrInvalidToken     = errors.New("invalid token")
+	ErrRevokedToken     = errors.New("token revoked")
+	ErrRevocationFailed = errors.New("token revocation status unavailable")
+)
+
+// ParseBearer extracts the token of an `Authorization: Bearer <token>` header.
+// The scheme is matched case-insensitively (RFC 6750), anything else
+// (other schemes, extra spaces, several tokens) is rejected.
+func ParseBearer(header string) (string, error) {
+	if header == "" {
+		return "", ErrMissingToken
+	}
+
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", ErrMalformedHeader
+	}
+
+	if token == "" || strings.ContainsAny(token, " \t") {
+		return "", ErrMalformedHeader
+	}
+
+	// A JWS compact serialization always has three segments
+	if strings.Count(token, ".") != 2 {
+		return "", ErrMalformedHeader
+	}
+
+	return token, nil
+}
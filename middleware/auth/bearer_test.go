@@ -0,0 +1,39 @@
+package auth
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseBearer(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		token  string
+		err    error
+	}{
+		{name: "valid", header: "Bearer aaa.bbb.ccc", token: "aaa.bbb.ccc"},
+		{name: "lowercase scheme", header: "bearer aaa.bbb.ccc", token: "aaa.bbb.ccc"},
+		{name: "empty", header: "", err: ErrMissingToken},
+		{name: "no scheme", header: "aaa.bbb.ccc", err: ErrMalformedHeader},
+		{name: "basic scheme", header: "Basic dXNlcjpwYXNz", err: ErrMalformedHeader},
+		{name: "scheme only", header: "Bearer", err: ErrMalformedHeader},
+		{name: "scheme with space only", header: "Bearer ", err: ErrMalformedHeader},
+		{name: "double space", header: "Bearer  aaa.bbb.ccc", err: ErrMalformedHeader},
+		{name: "two tokens", header: "Bearer aaa.bbb.ccc ddd.eee.fff", err: ErrMalformedHeader},
+		{name: "scheme inside token", header: "xBearer aaa.bbb.ccc", err: ErrMalformedHeader},
+		{name: "not a jws", header: "Bearer abc", err: ErrMalformedHeader},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := ParseBearer(tt.header)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected error %v, got %v", tt.err, err)
+			}
+			if token != tt.token {
+				t.Fatalf("expected token %q, got %q", tt.token, token)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Claims of an access token issued by the user service
+type Claims struct {
+	UserId string   `json:"userId"`
+	Roles  []string `json:"roles,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// Validate is called by the jwt parser after the registered claims were checked
+func (c Claims) Validate() error {
+	if c.UserId == "" {
+		return errors.New("missing userId claim")
+	}
+	if c.ID == "" {
+		return errors.New("missing jti claim")
+	}
+
+	return nil
+}
+
+// Principal is the authenticated caller, stored in fiber locals by the middleware
+type Principal struct {
+	UserId    string
+	TokenId   string
+	Roles     []string
+	ExpiresAt time.Time
+}
+
+func (c Claims) Principal() Principal {
+	principal := Principal{
+		UserId:  c.UserId,
+		TokenId: c.ID,
+		Roles:   c.Roles,
+	}
+	if c.ExpiresAt != nil {
+		principal.ExpiresAt = c.ExpiresAt.Time
+	}
+
+	return principal
+}
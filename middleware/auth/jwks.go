@@ -0,0 +1,204 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Minimum gap between two fetch attempts, failed ones included,
+// so tokens with random kids can not be used to flood the user service
+const jwksMinRefreshInterval = 30 * time.Second
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+type publicKey struct {
+	alg string
+	key crypto.PublicKey
+}
+
+// JwksClient caches the user service's public keys. Keys are refreshed after the ttl
+// or when a token arrives with a kid that is not cached yet (key rotation).
+type JwksClient struct {
+	url        string
+	ttl        time.Duration
+	httpClient *http.Client
+
+	mu   sync.RWMutex
+	keys map[string]publicKey
+	// Last successful fetch, decides whether the keys are fresh
+	fetchedAt time.Time
+	// Last fetch attempt, taken before the request so a failing user service is not asked again right away
+	attemptedAt time.Time
+	// Closed when the running fetch is done, nil while none runs
+	inflight chan struct{}
+}
+
+func NewJwksClient(url string, ttl time.Duration, httpClient *http.Client) *JwksClient {
+	return &JwksClient{
+		url:        url,
+		ttl:        ttl,
+		httpClient: httpClient,
+		keys:       make(map[string]publicKey),
+	}
+}
+
+// Resolve implements KeyResolver
+func (jc *JwksClient) Resolve(ctx context.Context, kid, alg string) (crypto.PublicKey, error) {
+	key, err := jc.key(ctx, kid)
+	if err != nil {
+		return nil, err
+	}
+	if key.alg != alg {
+		return nil, errors.New("invalid token signing method")
+	}
+
+	return key.key, nil
+}
+
+func (jc *JwksClient) key(ctx context.Context, kid string) (publicKey, error) {
+	jc.mu.RLock()
+	key, ok := jc.keys[kid]
+	fresh := time.Since(jc.fetchedAt) < jc.ttl
+	canRefresh := jc.inflight != nil || time.Since(jc.attemptedAt) >= jwksMinRefreshInterval
+	jc.mu.RUnlock()
+
+	if ok && (fresh || !canRefresh) {
+		return key, nil
+	}
+	if !canRefresh {
+		return publicKey{}, errors.New("unknown signing key")
+	}
+
+	err := jc.refresh(ctx)
+	if err != nil {
+		// A stale key is still better than rejecting every request while the user service is down
+		if ok {
+			return key, nil
+		}
+		return publicKey{}, err
+	}
+
+	jc.mu.RLock()
+	defer jc.mu.RUnlock()
+	key, ok = jc.keys[kid]
+	if !ok {
+		return publicKey{}, errors.New("unknown signing key")
+	}
+
+	return key, nil
+}
+
+// Fetches the keys without holding the lock, concurrent callers wait for the running fetch
+func (jc *JwksClient) refresh(ctx context.Context) error {
+	jc.mu.Lock()
+	if inflight := jc.inflight; inflight != nil {
+		jc.mu.Unlock()
+		select {
+		case <-inflight:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	// Another request attempted a fetch while this one waited for the lock
+	if time.Since(jc.attemptedAt) < jwksMinRefreshInterval {
+		jc.mu.Unlock()
+		return nil
+	}
+	jc.attemptedAt = time.Now()
+	inflight := make(chan struct{})
+	jc.inflight = inflight
+	jc.mu.Unlock()
+
+	keys, err := jc.fetch(ctx)
+
+	jc.mu.Lock()
+	defer jc.mu.Unlock()
+	if err == nil {
+		jc.keys = keys
+		jc.fetchedAt = time.Now()
+	}
+	jc.inflight = nil
+	close(inflight)
+
+	return err
+}
+
+func (jc *JwksClient) fetch(ctx context.Context) (map[string]publicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, jc.url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := jc.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("jwks responded with status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]publicKey, len(body.Keys))
+	for _, k := range body.Keys {
+		key, err := k.publicKey()
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = key
+	}
+
+	return keys, nil
+}
+
+func (k jwk) publicKey() (publicKey, error) {
+	switch {
+	case k.Kty == "OKP" && k.Crv == "Ed25519" && k.Alg == "EdDSA":
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return publicKey{}, errors.New("invalid Ed25519 key")
+		}
+		return publicKey{alg: k.Alg, key: ed25519.PublicKey(x)}, nil
+	case k.Kty == "RSA" && k.Alg == "RS256":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return publicKey{}, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return publicKey{}, err
+		}
+		return publicKey{alg: k.Alg, key: &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}}, nil
+	default:
+		return publicKey{}, fmt.Errorf("unsupported key %s/%s", k.Kty, k.Alg)
+	}
+}
@@ -0,0 +1,116 @@
+package auth
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newJwksServer(t *testing.T, kid string, key ed25519.PublicKey) (*httptest.Server, *atomic.Int32, *atomic.Bool) {
+	t.Helper()
+	var hits atomic.Int32
+	var down atomic.Bool
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if down.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{{
+				"kty": "OKP",
+				"crv": "Ed25519",
+				"alg": "EdDSA",
+				"use": "sig",
+				"kid": kid,
+				"x":   base64.RawURLEncoding.EncodeToString(key),
+			}},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &hits, &down
+}
+
+func TestJwksClientResolve(t *testing.T) {
+	key := newKey(t)
+	public := key.Public().(ed25519.PublicKey)
+	server, hits, _ := newJwksServer(t, "k1", public)
+
+	client := NewJwksClient(server.URL, time.Minute, server.Client())
+
+	resolved, err := client.Resolve(context.Background(), "k1", "EdDSA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !public.Equal(resolved) {
+		t.Fatal("resolved key does not match")
+	}
+
+	if _, err := client.Resolve(context.Background(), "k1", "RS256"); err == nil {
+		t.Fatal("expected an error for a mismatching alg")
+	}
+
+	// Unknown kids right after a fetch must not hit the server again
+	if _, err := client.Resolve(context.Background(), "k2", "EdDSA"); err == nil {
+		t.Fatal("expected an error for an unknown kid")
+	}
+	if hits.Load() != 1 {
+		t.Fatalf("expected a single fetch, got %d", hits.Load())
+	}
+}
+
+func TestJwksClientServesStaleKeys(t *testing.T) {
+	key := newKey(t)
+	public := key.Public().(ed25519.PublicKey)
+	server, _, down := newJwksServer(t, "k1", public)
+
+	client := NewJwksClient(server.URL, time.Minute, server.Client())
+	if _, err := client.Resolve(context.Background(), "k1", "EdDSA"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Expire the cache and take the user service down
+	client.fetchedAt = time.Now().Add(-time.Hour)
+	client.attemptedAt = client.fetchedAt
+	down.Store(true)
+
+	if _, err := client.Resolve(context.Background(), "k1", "EdDSA"); err != nil {
+		t.Fatalf("expected the stale key to be used, got %v", err)
+	}
+}
+
+func TestJwksClientLimitsFailedFetches(t *testing.T) {
+	key := newKey(t)
+	server, hits, down := newJwksServer(t, "k1", key.Public().(ed25519.PublicKey))
+	down.Store(true)
+
+	client := NewJwksClient(server.URL, time.Minute, server.Client())
+	for i := 0; i < 3; i++ {
+		if _, err := client.Resolve(context.Background(), "k1", "EdDSA"); err == nil {
+			t.Fatal("expected an error while the user service is down")
+		}
+	}
+
+	// The failed attempt counts towards the refresh interval as well
+	if hits.Load() != 1 {
+		t.Fatalf("expected a single fetch, got %d", hits.Load())
+	}
+}
+
+func TestMiddlewareWithJwks(t *testing.T) {
+	key := newKey(t)
+	server, _, _ := newJwksServer(t, "k1", key.Public().(ed25519.PublicKey))
+
+	app := newApp(Config{Keys: NewJwksClient(server.URL, time.Minute, server.Client())})
+	if status, body := send(t, app, "Bearer "+sign(t, key, "k1", validClaims())); status != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", status, body)
+	}
+}
@@ -0,0 +1,104 @@
+package auth
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// Fiber locals key of the Principal
+	PrincipalLocalsKey = "auth.principal"
+	// Kept for handlers that read the user id directly
+	UserIdLocalsKey = "userId"
+)
+
+// Body of every 401 returned by the middleware
+type ErrorResponse struct {
+	Message string `json:"message"`
+	Error   string `json:"error"`
+}
+
+type Config struct {
+	Keys KeyResolver
+	// Optional, tokens are not checked against a revocation list when nil
+	Revocations RevocationChecker
+}
+
+// New returns a fiber handler that authenticates the request's bearer token
+// and stores the caller as a Principal in the locals.
+func New(config Config) fiber.Handler {
+	verifier := NewVerifier(config.Keys)
+
+	return func(c *fiber.Ctx) error {
+		token, err := ParseBearer(c.Get(fiber.HeaderAuthorization))
+		if err != nil {
+			return Unauthorized(c, err)
+		}
+
+		claims, err := verifier.Verify(c.UserContext(), token)
+		if err != nil {
+			return Unauthorized(c, ErrInvalidToken)
+		}
+
+		if config.Revocations != nil {
+			// Fails closed, a token can not be trusted when the list is unreachable
+			revoked, err := config.Revocations.IsRevoked(c.UserContext(), claims.ID)
+			if err != nil {
+				return Unauthorized(c, ErrRevocationFailed)
+			}
+			if revoked {
+				return Unauthorized(c, ErrRevokedToken)
+			}
+		}
+
+		principal := claims.Principal()
+		c.Locals(PrincipalLocalsKey, principal)
+		c.Locals(UserIdLocalsKey, claims.UserId)
+		// for code that only gets c.UserContext(), like the request logger
+		c.SetUserContext(WithPrincipal(c.UserContext(), principal))
+
+		return c.Next()
+	}
+}
+
+// Unauthorized writes the shared 401 body. Unknown errors are reported
+// as an invalid token so verification details never leak to the client.
+func Unauthorized(c *fiber.Ctx, err error) error {
+	reason := ErrInvalidToken
+	for _, known := range []error{ErrMissingToken, ErrMalformedHeader, ErrRevokedToken, ErrRevocationFailed} {
+		if errors.Is(err, known) {
+			reason = known
+			break
+		}
+	}
+
+	c.Set(fiber.HeaderWWWAuthenticate, `Bearer error="invalid_token"`)
+	return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{
+		Message: "UNAUTHORIZED",
+		Error:   reason.Error(),
+	})
+}
+
+// PrincipalFrom returns the caller authenticated by the middleware
+func PrincipalFrom(c *fiber.Ctx) (Principal, bool) {
+	principal, ok := c.Locals(PrincipalLocalsKey).(Principal)
+	return principal, ok
+}
+
+type principalKey struct{}
+
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext reads the caller from c.UserContext(), or from the locals
+// when ctx is a fiber request context
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	if principal, ok := ctx.Value(principalKey{}).(Principal); ok {
+		return principal, true
+	}
+	principal, ok := ctx.Value(PrincipalLocalsKey).(Principal)
+	return principal, ok
+}
@@ -0,0 +1,231 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type staticKeys map[string]ed25519.PublicKey
+
+func (sk staticKeys) Resolve(ctx context.Context, kid, alg string) (crypto.PublicKey, error) {
+	key, ok := sk[kid]
+	if !ok || alg != jwt.SigningMethodEdDSA.Alg() {
+		return nil, errors.New("unknown key")
+	}
+	return key, nil
+}
+
+type staticRevocations struct {
+	revoked map[string]bool
+	err     error
+}
+
+func (sr staticRevocations) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	return sr.revoked[jti], sr.err
+}
+
+func newKey(t *testing.T) ed25519.PrivateKey {
+	t.Helper()
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return private
+}
+
+func validClaims() Claims {
+	now := time.Now()
+	return Claims{
+		UserId: "user-1",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        "jti-1",
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+		},
+	}
+}
+
+func sign(t *testing.T, key ed25519.PrivateKey, kid string, claims Claims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func newApp(config Config) *fiber.App {
+	app := fiber.New()
+	app.Get("/", New(config), func(c *fiber.Ctx) error {
+		principal, ok := PrincipalFrom(c)
+		if !ok {
+			return c.SendStatus(fiber.StatusInternalServerError)
+		}
+		return c.JSON(principal)
+	})
+	return app
+}
+
+func send(t *testing.T, app *fiber.App, header string) (int, []byte) {
+	t.Helper()
+	req := httptest.NewRequest("GET", "/", nil)
+	if header != "" {
+		req.Header.Set("Authorization", header)
+	}
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var body json.RawMessage
+	json.NewDecoder(resp.Body).Decode(&body)
+	return resp.StatusCode, body
+}
+
+func TestMiddlewareStoresPrincipal(t *testing.T) {
+	key := newKey(t)
+	app := newApp(Config{Keys: staticKeys{"k1": key.Public().(ed25519.PublicKey)}})
+
+	status, body := send(t, app, "Bearer "+sign(t, key, "k1", validClaims()))
+	if status != fiber.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", status, body)
+	}
+
+	var principal Principal
+	if err := json.Unmarshal(body, &principal); err != nil {
+		t.Fatal(err)
+	}
+	if principal.UserId != "user-1" || principal.TokenId != "jti-1" || principal.ExpiresAt.IsZero() {
+		t.Fatalf("unexpected principal %+v", principal)
+	}
+}
+
+func TestPrincipalFromContext(t *testing.T) {
+	key := newKey(t)
+	app := fiber.New()
+	app.Get("/", New(Config{Keys: staticKeys{"k1": key.Public().(ed25519.PublicKey)}}), func(c *fiber.Ctx) error {
+		for _, ctx := range []context.Context{c.UserContext(), c.Context()} {
+			if principal, ok := PrincipalFromContext(ctx); !ok || principal.UserId != "user-1" {
+				return c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	status, body := send(t, app, "Bearer "+sign(t, key, "k1", validClaims()))
+	if status != fiber.StatusOK {
+		t.Fatalf("expected the principal in both contexts, got %d: %s", status, body)
+	}
+	if _, ok := PrincipalFromContext(context.Background()); ok {
+		t.Fatal("expected no principal outside a request")
+	}
+}
+
+func TestMiddlewareRejects(t *testing.T) {
+	key := newKey(t)
+	otherKey := newKey(t)
+	keys := staticKeys{"k1": key.Public().(ed25519.PublicKey)}
+
+	expired := validClaims()
+	expired.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+
+	noExpiry := validClaims()
+	noExpiry.ExpiresAt = nil
+
+	noUser := validClaims()
+	noUser.UserId = ""
+
+	noJti := validClaims()
+	noJti.ID = ""
+
+	hs256, err := jwt.NewWithClaims(jwt.SigningMethodHS256, validClaims()).SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		header string
+		reason error
+	}{
+		{name: "missing header", header: "", reason: ErrMissingToken},
+		{name: "malformed header", header: "Token abc", reason: ErrMalformedHeader},
+		{name: "expired", header: "Bearer " + sign(t, key, "k1", expired), reason: ErrInvalidToken},
+		{name: "without exp", header: "Bearer " + sign(t, key, "k1", noExpiry), reason: ErrInvalidToken},
+		{name: "without userId", header: "Bearer " + sign(t, key, "k1", noUser), reason: ErrInvalidToken},
+		{name: "without jti", header: "Bearer " + sign(t, key, "k1", noJti), reason: ErrInvalidToken},
+		{name: "without kid", header: "Bearer " + sign(t, key, "", validClaims()), reason: ErrInvalidToken},
+		{name: "unknown kid", header: "Bearer " + sign(t, key, "k2", validClaims()), reason: ErrInvalidToken},
+		{name: "wrong key", header: "Bearer " + sign(t, otherKey, "k1", validClaims()), reason: ErrInvalidToken},
+		{name: "symmetric algorithm", header: "Bearer " + hs256, reason: ErrInvalidToken},
+	}
+
+	app := newApp(Config{Keys: keys})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := send(t, app, tt.header)
+			if status != fiber.StatusUnauthorized {
+				t.Fatalf("expected 401, got %d", status)
+			}
+
+			var resp ErrorResponse
+			if err := json.Unmarshal(body, &resp); err != nil {
+				t.Fatal(err)
+			}
+			if resp.Message != "UNAUTHORIZED" || resp.Error != tt.reason.Error() {
+				t.Fatalf("unexpected body %s", body)
+			}
+		})
+	}
+}
+
+func TestMiddlewareRevocation(t *testing.T) {
+	key := newKey(t)
+	keys := staticKeys{"k1": key.Public().(ed25519.PublicKey)}
+	header := "Bearer " + sign(t, key, "k1", validClaims())
+
+	tests := []struct {
+		name        string
+		revocations staticRevocations
+		reason      error
+	}{
+		{name: "revoked", revocations: staticRevocations{revoked: map[string]bool{"jti-1": true}}, reason: ErrRevokedToken},
+		{name: "list unavailable", revocations: staticRevocations{err: errors.New("connection refused")}, reason: ErrRevocationFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newApp(Config{Keys: keys, Revocations: tt.revocations})
+			status, body := send(t, app, header)
+			if status != fiber.StatusUnauthorized {
+				t.Fatalf("expected 401, got %d", status)
+			}
+
+			var resp ErrorResponse
+			json.Unmarshal(body, &resp)
+			if resp.Error != tt.reason.Error() {
+				t.Fatalf("unexpected body %s", body)
+			}
+		})
+	}
+
+	app := newApp(Config{Keys: keys, Revocations: staticRevocations{revoked: map[string]bool{"other": true}}})
+	if status, _ := send(t, app, header); status != fiber.StatusOK {
+		t.Fatalf("expected 200 for a token that is not revoked, got %d", status)
+	}
+}
@@ -0,0 +1,39 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Key written by the user service on logout, the value is irrelevant
+// and the entry expires together with the token
+const RevokedTokenKeyPrefix = "revoked:jti:"
+
+type RevocationChecker interface {
+	IsRevoked(ctx context.Context, jti string) (bool, error)
+}
+
+type RedisRevocationList struct {
+	client *redis.Client
+}
+
+func NewRedisRevocationList(client *redis.Client) *RedisRevocationList {
+	return &RedisRevocationList{client: client}
+}
+
+func (rl *RedisRevocationList) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	count, err := rl.client.Exists(ctx, RevokedTokenKeyPrefix+jti).Result()
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// RevocationCheckerFunc adapts a function to RevocationChecker
+type RevocationCheckerFunc func(ctx context.Context, jti string) (bool, error)
+
+func (f RevocationCheckerFunc) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	return f(ctx, jti)
+}
@@ -0,0 +1,54 @@
+package auth
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Roles a user can hold, every user is a buyer
+const (
+	RoleBuyer  = "buyer"
+	RoleSeller = "seller"
+	RoleAdmin  = "admin"
+)
+
+var Roles = []string{RoleBuyer, RoleSeller, RoleAdmin}
+
+var ErrMissingRole = errors.New("missing required role")
+
+func IsValidRole(role string) bool {
+	return slices.Contains(Roles, role)
+}
+
+func (p Principal) HasRole(role string) bool {
+	return slices.Contains(p.Roles, role)
+}
+
+// RequireRole returns a fiber handler that only lets callers holding at least
+// one of the roles through. It must run after the handler returned by New.
+func RequireRole(roles ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		principal, ok := PrincipalFrom(c)
+		if !ok {
+			return Unauthorized(c, ErrMissingToken)
+		}
+
+		for _, role := range roles {
+			if principal.HasRole(role) {
+				return c.Next()
+			}
+		}
+
+		return Forbidden(c, ErrMissingRole)
+	}
+}
+
+// Forbidden writes the shared 403 body
+func Forbidden(c *fiber.Ctx, err error) error {
+	return c.Status(fiber.StatusForbidden).JSON(ErrorResponse{
+		Message: "FORBIDDEN",
+		Error:   err.Error(),
+	})
+}
@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestRequireRole(t *testing.T) {
+	key := newKey(t)
+	keys := staticKeys{"k1": key.Public().(ed25519.PublicKey)}
+
+	app := fiber.New()
+	app.Get("/", New(Config{Keys: keys}), RequireRole(RoleSeller, RoleAdmin), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	tests := []struct {
+		name   string
+		roles  []string
+		status int
+	}{
+		{name: "without roles", roles: nil, status: fiber.StatusForbidden},
+		{name: "buyer", roles: []string{RoleBuyer}, status: fiber.StatusForbidden},
+		{name: "seller", roles: []string{RoleBuyer, RoleSeller}, status: fiber.StatusOK},
+		{name: "admin", roles: []string{RoleAdmin}, status: fiber.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := validClaims()
+			claims.Roles = tt.roles
+
+			status, body := send(t, app, "Bearer "+sign(t, key, "k1", claims))
+			if status != tt.status {
+				t.Fatalf("expected %d, got %d: %s", tt.status, status, body)
+			}
+			if status != fiber.StatusForbidden {
+				return
+			}
+
+			var resp ErrorResponse
+			if err := json.Unmarshal(body, &resp); err != nil {
+				t.Fatal(err)
+			}
+			if resp.Message != "FORBIDDEN" || resp.Error != ErrMissingRole.Error() {
+				t.Fatalf("unexpected body %s", body)
+			}
+		})
+	}
+}
+
+func TestRequireRoleWithoutPrincipal(t *testing.T) {
+	app := fiber.New()
+	app.Get("/", RequireRole(RoleAdmin), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	if status, _ := send(t, app, ""); status != fiber.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", status)
+	}
+}
@@ -0,0 +1,51 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"errors"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Algorithms the user service signs with, HS256 is deliberately not accepted
+var SupportedAlgorithms = []string{jwt.SigningMethodEdDSA.Alg(), jwt.SigningMethodRS256.Alg()}
+
+// KeyResolver returns the public key for a kid. Implementations must make sure
+// the key belongs to alg, otherwise a token could pick the wrong verification path.
+type KeyResolver interface {
+	Resolve(ctx context.Context, kid, alg string) (crypto.PublicKey, error)
+}
+
+type Verifier struct {
+	keys   KeyResolver
+	parser *jwt.Parser
+}
+
+func NewVerifier(keys KeyResolver) *Verifier {
+	return &Verifier{
+		keys: keys,
+		parser: jwt.NewParser(
+			jwt.WithValidMethods(SupportedAlgorithms),
+			jwt.WithExpirationRequired(),
+			jwt.WithIssuedAt(),
+		),
+	}
+}
+
+func (v *Verifier) Verify(ctx context.Context, token string) (*Claims, error) {
+	claims := &Claims{}
+	_, err := v.parser.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, ok := t.Header["kid"].(string)
+		if !ok || kid == "" {
+			return nil, errors.New("missing kid header")
+		}
+
+		return v.keys.Resolve(ctx, kid, t.Method.Alg())
+	})
+	if err != nil {
+		return nil, errors.Join(ErrInvalidToken, err)
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,97 @@
+package grpcmw
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// Stops calling a backend after Failures consecutive failed calls. After Cooldown one
+// call is let through, its outcome closes the breaker again or restarts the cooldown
+type Breaker struct {
+	Failures int
+	Cooldown time.Duration
+	// Called when the breaker opens or closes
+	OnStateChange func(open bool)
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+func (b *Breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.Failures <= 0 || b.failures < b.Failures {
+		return true
+	}
+	if b.probing || time.Now().Before(b.openUntil) {
+		return false
+	}
+
+	b.probing = true
+	return true
+}
+
+func (b *Breaker) record(err error) {
+	if b.Failures <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	wasOpen := b.failures >= b.Failures
+	b.probing = false
+	if !isBackendFailure(err) {
+		b.failures = 0
+	} else {
+		b.failures++
+		if b.failures >= b.Failures {
+			b.openUntil = time.Now().Add(b.Cooldown)
+		}
+	}
+	open := b.failures >= b.Failures
+	b.mu.Unlock()
+
+	if open != wasOpen && b.OnStateChange != nil {
+		b.OnStateChange(open)
+	}
+}
+
+// Errors that say something about the backend, not about the request
+func isBackendFailure(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Internal, codes.Unknown:
+		return true
+	}
+	return false
+}
+
+func (b *Breaker) unaryInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if !b.allow() {
+		return status.Error(codes.Unavailable, ErrCircuitOpen.Error())
+	}
+
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	b.record(err)
+	return err
+}
+
+// Only the stream setup is counted, errors while reading belong to the caller
+func (b *Breaker) streamInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	if !b.allow() {
+		return nil, status.Error(codes.Unavailable, ErrCircuitOpen.Error())
+	}
+
+	stream, err := streamer(ctx, desc, cc, method, opts...)
+	b.record(err)
+	return stream, err
+}
@@ -0,0 +1,39 @@
+package grpcmw
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+type ClientConfig struct {
+	// Sent as "authorization: Bearer <token>" when set, leave empty with mTLS
+	Token string
+}
+
+// Forwards the request id of ctx and the service token with every call
+func ClientOptions(cfg ClientConfig) []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			return invoker(cfg.outgoing(ctx), method, req, reply, cc, opts...)
+		}),
+		grpc.WithChainStreamInterceptor(func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			return streamer(cfg.outgoing(ctx), desc, cc, method, opts...)
+		}),
+	}
+}
+
+func (cfg ClientConfig) outgoing(ctx context.Context) context.Context {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	md = md.Copy()
+
+	if requestId := RequestIdFrom(ctx); requestId != "" {
+		md.Set(MetadataRequestId, requestId)
+	}
+	if cfg.Token != "" {
+		md.Set(metadataAuthorization, "Bearer "+cfg.Token)
+	}
+
+	return metadata.NewOutgoingContext(ctx, md)
+}
@@ -0,0 +1,40 @@
+package grpcmw
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/metadata"
+)
+
+func TestClientOutgoing(t *testing.T) {
+	cfg := ClientConfig{Token: "secret"}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want string
+	}{
+		{name: "interceptor context", ctx: WithRequestId(context.Background(), "req-1"), want: "req-1"},
+		// fiber request contexts answer Value with their locals
+		{name: "fiber locals", ctx: context.WithValue(context.Background(), LocalsRequestId, "req-2"), want: "req-2"},
+		{name: "without id", ctx: context.Background(), want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			md, _ := metadata.FromOutgoingContext(cfg.outgoing(tt.ctx))
+
+			var got string
+			if values := md.Get(MetadataRequestId); len(values) > 0 {
+				got = values[0]
+			}
+			if got != tt.want {
+				t.Fatalf("expected request id %q, got %q", tt.want, got)
+			}
+			if auth := md.Get("authorization"); len(auth) != 1 || auth[0] != "Bearer secret" {
+				t.Fatalf("unexpected authorization %v", auth)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package grpcmw
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	_ "google.golang.org/grpc/health"
+	"google.golang.org/grpc/keepalive"
+)
+
+// Timeout and retries of one RPC
+type MethodPolicy struct {
+	// Upper bound of the whole call including retries, the caller's deadline still wins when shorter
+	Timeout time.Duration
+	// Attempts including the first one, only for idempotent RPCs. Retried on UNAVAILABLE, capped at 5
+	MaxAttempts int
+}
+
+type DialConfig struct {
+	Client ClientConfig
+	TLS    TLSConfig
+	// Keyed by full method name, e.g. "/user.UserService/GetUserDetailsWithId"
+	Methods map[string]MethodPolicy
+	// Consecutive failed calls that open the breaker, zero disables it
+	BreakerFailures int
+	BreakerCooldown time.Duration
+	// Called when the breaker opens or closes
+	OnBreakerChange func(open bool)
+	// Ping interval of idle connections, zero disables keepalive
+	Keepalive time.Duration
+	// Appended to the dial options, e.g. tracing.ClientOptions()
+	Options []grpc.DialOption
+}
+
+// Dials target with the client options, the configured transport, per-method policies and a
+// circuit breaker. A plain host:port is resolved through DNS and every address gets a
+// connection, calls go round-robin over the ones passing the grpc.health.v1 check
+func Dial(target string, cfg DialConfig) (*grpc.ClientConn, error) {
+	transport, err := ClientTransport(cfg.TLS)
+	if err != nil {
+		return nil, err
+	}
+
+	serviceConfig, err := cfg.serviceConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	options := append(ClientOptions(cfg.Client),
+		transport,
+		grpc.WithDefaultServiceConfig(serviceConfig),
+	)
+	if cfg.BreakerFailures > 0 {
+		breaker := &Breaker{Failures: cfg.BreakerFailures, Cooldown: cfg.BreakerCooldown, OnStateChange: cfg.OnBreakerChange}
+		options = append(options,
+			grpc.WithChainUnaryInterceptor(breaker.unaryInterceptor),
+			grpc.WithChainStreamInterceptor(breaker.streamInterceptor),
+		)
+	}
+	if cfg.Keepalive > 0 {
+		options = append(options, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:    cfg.Keepalive,
+			Timeout: cfg.Keepalive / 2,
+		}))
+	}
+
+	options = append(options, cfg.Options...)
+
+	if !strings.Contains(target, ":///") {
+		target = "dns:///" + target
+	}
+	return grpc.NewClient(target, options...)
+}
+
+type methodName struct {
+	Service string `json:"service"`
+	Method  string `json:"method"`
+}
+
+type retryPolicy struct {
+	MaxAttempts          int      `json:"maxAttempts"`
+	InitialBackoff       string   `json:"initialBackoff"`
+	MaxBackoff           string   `json:"maxBackoff"`
+	BackoffMultiplier    float64  `json:"backoffMultiplier"`
+	RetryableStatusCodes []string `json:"retryableStatusCodes"`
+}
+
+type methodConfig struct {
+	Name        []methodName `json:"name"`
+	Timeout     string       `json:"timeout,omitempty"`
+	RetryPolicy *retryPolicy `json:"retryPolicy,omitempty"`
+}
+
+// Service config JSON as documented in grpc/doc/service_config.md
+func (cfg DialConfig) serviceConfig() (string, error) {
+	var methods []methodConfig
+	for fullMethod, policy := range cfg.Methods {
+		service, method, ok := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+		if !ok || service == "" || method == "" {
+			return "", fmt.Errorf("invalid method name %q", fullMethod)
+		}
+
+		mc := methodConfig{Name: []methodName{{Service: service, Method: method}}}
+		if policy.Timeout > 0 {
+			mc.Timeout = fmt.Sprintf("%.3fs", policy.Timeout.Seconds())
+		}
+		if policy.MaxAttempts > 1 {
+			mc.RetryPolicy = &retryPolicy{
+				MaxAttempts:          min(policy.MaxAttempts, 5),
+				InitialBackoff:       "0.1s",
+				MaxBackoff:           "1s",
+				BackoffMultiplier:    2,
+				RetryableStatusCodes: []string{"UNAVAILABLE"},
+			}
+		}
+		methods = append(methods, mc)
+	}
+
+	data, err := json.Marshal(map[string]any{
+		"loadBalancingConfig": []map[string]any{{"round_robin": map[string]any{}}},
+		"healthCheckConfig":   map[string]string{"serviceName": ""},
+		"methodConfig":        methods,
+	})
+	return string(data), err
+}
@@ -0,0 +1,162 @@
+package grpcmw
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// Serves every method but the health checks with handler and returns the address
+func serveAny(t *testing.T, handler func(calls int32) error) string {
+	t.Helper()
+	var calls atomic.Int32
+	server := grpc.NewServer(grpc.UnknownServiceHandler(func(srv any, stream grpc.ServerStream) error {
+		if err := stream.RecvMsg(&healthpb.HealthCheckRequest{}); err != nil {
+			return err
+		}
+		if err := handler(calls.Add(1)); err != nil {
+			return err
+		}
+		return stream.SendMsg(&healthpb.HealthCheckResponse{})
+	}))
+	healthpb.RegisterHealthServer(server, health.NewServer())
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	return lis.Addr().String()
+}
+
+func invoke(conn *grpc.ClientConn, method string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return conn.Invoke(ctx, method, &healthpb.HealthCheckRequest{}, &healthpb.HealthCheckResponse{})
+}
+
+func TestDialRetriesIdempotentMethods(t *testing.T) {
+	addr := serveAny(t, func(calls int32) error {
+		if calls < 3 {
+			return status.Error(codes.Unavailable, "warming up")
+		}
+		return nil
+	})
+
+	conn, err := Dial(addr, DialConfig{Methods: map[string]MethodPolicy{
+		"/test.Service/Get": {MaxAttempts: 3},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if err := invoke(conn, "/test.Service/Get"); err != nil {
+		t.Fatalf("expected third attempt to pass, got %v", err)
+	}
+	if err := invoke(conn, "/test.Service/Create"); err != nil {
+		t.Fatalf("expected call to pass, got %v", err)
+	}
+}
+
+func TestDialNoRetryWithoutPolicy(t *testing.T) {
+	addr := serveAny(t, func(calls int32) error {
+		return status.Error(codes.Unavailable, "down")
+	})
+
+	conn, err := Dial(addr, DialConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if code := status.Code(invoke(conn, "/test.Service/Create")); code != codes.Unavailable {
+		t.Fatalf("expected Unavailable, got %s", code)
+	}
+}
+
+func TestDialMethodTimeout(t *testing.T) {
+	addr := serveAny(t, func(calls int32) error {
+		time.Sleep(time.Second)
+		return nil
+	})
+
+	conn, err := Dial(addr, DialConfig{Methods: map[string]MethodPolicy{
+		"/test.Service/Get": {Timeout: 50 * time.Millisecond},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	start := time.Now()
+	if code := status.Code(invoke(conn, "/test.Service/Get")); code != codes.DeadlineExceeded {
+		t.Fatalf("expected DeadlineExceeded, got %s", code)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("expected method timeout to apply, took %s", elapsed)
+	}
+}
+
+func TestDialInvalidMethod(t *testing.T) {
+	_, err := Dial("localhost:1", DialConfig{Methods: map[string]MethodPolicy{"Get": {}}})
+	if err == nil {
+		t.Fatal("expected invalid method name to fail")
+	}
+}
+
+func TestBreaker(t *testing.T) {
+	var changes []bool
+	b := &Breaker{Failures: 2, Cooldown: 20 * time.Millisecond, OnStateChange: func(open bool) { changes = append(changes, open) }}
+	unavailable := status.Error(codes.Unavailable, "down")
+
+	call := func(err error) error {
+		return b.unaryInterceptor(context.Background(), "/test.Service/Get", nil, nil, nil,
+			func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+				return err
+			})
+	}
+
+	call(unavailable)
+	call(status.Error(codes.NotFound, "missing"))
+	call(unavailable)
+	if err := call(nil); err != nil {
+		t.Fatalf("expected request errors to reset the count, got %v", err)
+	}
+
+	call(unavailable)
+	call(unavailable)
+	if err := call(nil); status.Code(err) != codes.Unavailable || status.Convert(err).Message() != ErrCircuitOpen.Error() {
+		t.Fatalf("expected open breaker, got %v", err)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if err := call(unavailable); status.Convert(err).Message() != "down" {
+		t.Fatalf("expected probe after cooldown, got %v", err)
+	}
+	if err := call(nil); status.Convert(err).Message() != ErrCircuitOpen.Error() {
+		t.Fatalf("expected failed probe to reopen, got %v", err)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if err := call(nil); err != nil {
+		t.Fatalf("expected successful probe, got %v", err)
+	}
+	if err := call(unavailable); status.Convert(err).Message() != "down" {
+		t.Fatalf("expected closed breaker, got %v", err)
+	}
+
+	if len(changes) != 2 || !changes[0] || changes[1] {
+		t.Fatalf("expected open then close, got %v", changes)
+	}
+}
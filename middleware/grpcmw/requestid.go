@@ -0,0 +1,44 @@
+package grpcmw
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// Metadata key carrying the correlation id between services
+	MetadataRequestId = "x-request-id"
+	// Fiber locals key of the requestid middleware, fiber contexts expose locals through Value
+	LocalsRequestId = "requestid"
+)
+
+type requestIdKey struct{}
+
+func WithRequestId(ctx context.Context, requestId string) context.Context {
+	return context.WithValue(ctx, requestIdKey{}, requestId)
+}
+
+// Reads the id set by the server interceptor, or by the fiber requestid middleware
+// when ctx is a fiber request context
+func RequestIdFrom(ctx context.Context) string {
+	if requestId, ok := ctx.Value(requestIdKey{}).(string); ok {
+		return requestId
+	}
+	if requestId, ok := ctx.Value(LocalsRequestId).(string); ok {
+		return requestId
+	}
+
+	return ""
+}
+
+func incomingRequestId(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(MetadataRequestId); len(values) > 0 && values[0] != "" {
+			return values[0]
+		}
+	}
+
+	return uuid.NewString()
+}
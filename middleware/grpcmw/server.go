@@ -0,0 +1,272 @@
+package grpcmw
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const metadataAuthorization = "authorization"
+
+var healthMethods = []string{
+	healthpb.Health_Check_FullMethodName,
+	healthpb.Health_Watch_FullMethodName,
+}
+
+var (
+	ErrMissingCredentials = errors.New("missing service credentials")
+	ErrInvalidToken       = errors.New("invalid service token")
+	ErrPeerNotAllowed     = errors.New("peer certificate is not allowed")
+	ErrNoServerAuth       = errors.New("grpc server needs a service token or mTLS outside debug mode")
+)
+
+// One entry per finished call
+type AccessLog struct {
+	Method    string
+	RequestId string
+	Peer      string
+	// Certificate name or "token" for authenticated callers
+	Identity string
+	Code     codes.Code
+	Duration time.Duration
+	Err      error
+}
+
+type ServerConfig struct {
+	// Shared secret callers send as "authorization: Bearer <token>".
+	// Callers are not checked when Token and AllowedPeers are both empty, see RequireAuth
+	Token string
+	// Common names or DNS names of client certificates accepted over mTLS.
+	// Any verified certificate is accepted when empty and the server requires client certs
+	AllowedPeers []string
+	// Full method names that skip authentication. grpc.health.v1 is always public,
+	// client side health checks of grpc-go don't run the interceptors adding the token
+	PublicMethods []string
+	// Applied when the caller sent no deadline, zero keeps calls unbounded
+	DefaultTimeout time.Duration
+
+	Log     func(ctx context.Context, entry AccessLog)
+	OnPanic func(ctx context.Context, method string, recovered any, stack []byte)
+}
+
+// Request id, access log, panic recovery, authentication and the default deadline, in that order
+func ServerOptions(cfg ServerConfig) []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(cfg.unaryInterceptor),
+		grpc.ChainStreamInterceptor(cfg.streamInterceptor),
+	}
+}
+
+// Refuses a server that would let every caller through, called at startup outside debug mode.
+// Either the token is set or tls makes clients present a certificate signed by its CA
+func (cfg ServerConfig) RequireAuth(tls TLSConfig) error {
+	if cfg.Token == "" && !tls.Enabled() {
+		return ErrNoServerAuth
+	}
+	return nil
+}
+
+// Stops accepting connections and waits for the running calls to finish. When ctx is done
+// first the remaining calls are cancelled and ctx.Err() is returned without waiting for
+// handlers that ignore their context
+func GracefulStop(ctx context.Context, server *grpc.Server) error {
+	done := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		// Stop closes the connections right away but still waits for the handlers
+		go server.Stop()
+		return ctx.Err()
+	}
+}
+
+func (cfg ServerConfig) unaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	ctx, finish := cfg.begin(ctx, info.FullMethod)
+	defer func() { finish(ctx, err) }()
+	defer cfg.recover(ctx, info.FullMethod, &err)
+
+	ctx, err = cfg.authenticate(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := cfg.withDefaultTimeout(ctx)
+	defer cancel()
+
+	return handler(ctx, req)
+}
+
+func (cfg ServerConfig) streamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	ctx, finish := cfg.begin(ss.Context(), info.FullMethod)
+	defer func() { finish(ctx, err) }()
+	defer cfg.recover(ctx, info.FullMethod, &err)
+
+	ctx, err = cfg.authenticate(ctx, info.FullMethod)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := cfg.withDefaultTimeout(ctx)
+	defer cancel()
+
+	return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+}
+
+// Attaches the request id and returns the function writing the access log
+func (cfg ServerConfig) begin(ctx context.Context, method string) (context.Context, func(context.Context, error)) {
+	requestId := incomingRequestId(ctx)
+	ctx = WithRequestId(ctx, requestId)
+	grpc.SetHeader(ctx, metadata.Pairs(MetadataRequestId, requestId))
+
+	start := time.Now()
+	return ctx, func(ctx context.Context, err error) {
+		if cfg.Log == nil {
+			return
+		}
+
+		entry := AccessLog{
+			Method:    method,
+			RequestId: requestId,
+			Identity:  identityFrom(ctx),
+			Code:      status.Code(err),
+			Duration:  time.Since(start),
+			Err:       err,
+		}
+		if p, ok := peer.FromContext(ctx); ok {
+			entry.Peer = p.Addr.String()
+		}
+		cfg.Log(ctx, entry)
+	}
+}
+
+func (cfg ServerConfig) recover(ctx context.Context, method string, err *error) {
+	recovered := recover()
+	if recovered == nil {
+		return
+	}
+
+	if cfg.OnPanic != nil {
+		cfg.OnPanic(ctx, method, recovered, debug.Stack())
+	}
+	*err = status.Error(codes.Internal, "internal error")
+}
+
+func (cfg ServerConfig) authenticate(ctx context.Context, method string) (context.Context, error) {
+	if slices.Contains(cfg.PublicMethods, method) || slices.Contains(healthMethods, method) {
+		return ctx, nil
+	}
+
+	if name, ok := verifiedPeerName(ctx, cfg.AllowedPeers); ok {
+		return withIdentity(ctx, name), nil
+	} else if name != "" {
+		return ctx, status.Error(codes.PermissionDenied, ErrPeerNotAllowed.Error())
+	}
+
+	if cfg.Token == "" {
+		if len(cfg.AllowedPeers) > 0 {
+			return ctx, status.Error(codes.Unauthenticated, ErrMissingCredentials.Error())
+		}
+		return ctx, nil
+	}
+
+	token, ok := bearerFromMetadata(ctx)
+	if !ok {
+		return ctx, status.Error(codes.Unauthenticated, ErrMissingCredentials.Error())
+	}
+	if subtle.ConstantTimeCompare([]byte(token), []byte(cfg.Token)) != 1 {
+		return ctx, status.Error(codes.Unauthenticated, ErrInvalidToken.Error())
+	}
+
+	return withIdentity(ctx, "token"), nil
+}
+
+func (cfg ServerConfig) withDefaultTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok || cfg.DefaultTimeout <= 0 {
+		return ctx, func() {}
+	}
+
+	return context.WithTimeout(ctx, cfg.DefaultTimeout)
+}
+
+// Returns the certificate name of a peer with a verified client certificate,
+// ok tells whether that name is allowed
+func verifiedPeerName(ctx context.Context, allowed []string) (name string, ok bool) {
+	p, found := peer.FromContext(ctx)
+	if !found || p.AuthInfo == nil {
+		return "", false
+	}
+	tlsInfo, isTls := p.AuthInfo.(credentials.TLSInfo)
+	if !isTls || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return "", false
+	}
+
+	cert := tlsInfo.State.VerifiedChains[0][0]
+	if len(allowed) == 0 {
+		return cert.Subject.CommonName, true
+	}
+	for _, candidate := range append([]string{cert.Subject.CommonName}, cert.DNSNames...) {
+		if slices.Contains(allowed, candidate) {
+			return candidate, true
+		}
+	}
+
+	return cert.Subject.CommonName, false
+}
+
+func bearerFromMetadata(ctx context.Context) (string, bool) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", false
+	}
+	values := md.Get(metadataAuthorization)
+	if len(values) == 0 {
+		return "", false
+	}
+
+	scheme, token, found := strings.Cut(values[0], " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return "", false
+	}
+
+	return token, true
+}
+
+type identityKey struct{}
+
+func withIdentity(ctx context.Context, identity string) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// Name of the authenticated caller, empty when authentication is off
+func identityFrom(ctx context.Context) string {
+	identity, _ := ctx.Value(identityKey{}).(string)
+	return identity
+}
+
+// Hands the enriched context to stream handlers
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,225 @@
+package grpcmw
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const testMethod = "/test.Service/Call"
+
+func call(cfg ServerConfig, md metadata.MD, handler grpc.UnaryHandler) (any, error) {
+	ctx := metadata.NewIncomingContext(context.Background(), md)
+	return cfg.unaryInterceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: testMethod}, handler)
+}
+
+func ok(ctx context.Context, req any) (any, error) {
+	return "ok", nil
+}
+
+func TestAuthenticate(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  ServerConfig
+		md   metadata.MD
+		code codes.Code
+	}{
+		{name: "disabled", cfg: ServerConfig{}, md: metadata.MD{}, code: codes.OK},
+		{name: "missing token", cfg: ServerConfig{Token: "secret"}, md: metadata.MD{}, code: codes.Unauthenticated},
+		{name: "wrong scheme", cfg: ServerConfig{Token: "secret"}, md: metadata.Pairs("authorization", "Basic secret"), code: codes.Unauthenticated},
+		{name: "wrong token", cfg: ServerConfig{Token: "secret"}, md: metadata.Pairs("authorization", "Bearer other"), code: codes.Unauthenticated},
+		{name: "valid token", cfg: ServerConfig{Token: "secret"}, md: metadata.Pairs("authorization", "Bearer secret"), code: codes.OK},
+		{name: "public method", cfg: ServerConfig{Token: "secret", PublicMethods: []string{testMethod}}, md: metadata.MD{}, code: codes.OK},
+		{name: "mtls only without certificate", cfg: ServerConfig{AllowedPeers: []string{"purchase"}}, md: metadata.MD{}, code: codes.Unauthenticated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := call(tt.cfg, tt.md, ok)
+			if code := status.Code(err); code != tt.code {
+				t.Fatalf("expected %s, got %s: %v", tt.code, code, err)
+			}
+		})
+	}
+}
+
+func TestRequireAuth(t *testing.T) {
+	mtls := TLSConfig{CertFile: "server.pem", KeyFile: "server.key", CAFile: "ca.pem"}
+	tests := []struct {
+		name string
+		cfg  ServerConfig
+		tls  TLSConfig
+		err  error
+	}{
+		{name: "nothing configured", cfg: ServerConfig{}, tls: TLSConfig{}, err: ErrNoServerAuth},
+		{name: "allowed peers without tls", cfg: ServerConfig{AllowedPeers: []string{"purchase"}}, tls: TLSConfig{}, err: ErrNoServerAuth},
+		{name: "token", cfg: ServerConfig{Token: "secret"}, tls: TLSConfig{}, err: nil},
+		{name: "mtls", cfg: ServerConfig{}, tls: mtls, err: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.RequireAuth(tt.tls); !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestHealthMethodsArePublic(t *testing.T) {
+	cfg := ServerConfig{Token: "secret", AllowedPeers: []string{"purchase"}}
+	for _, method := range []string{"/grpc.health.v1.Health/Check", "/grpc.health.v1.Health/Watch"} {
+		_, err := cfg.unaryInterceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: method}, ok)
+		if err != nil {
+			t.Fatalf("expected %s to skip authentication, got %v", method, err)
+		}
+	}
+}
+
+func TestRecoverPanic(t *testing.T) {
+	var recovered any
+	var logged AccessLog
+	cfg := ServerConfig{
+		Log:     func(ctx context.Context, entry AccessLog) { logged = entry },
+		OnPanic: func(ctx context.Context, method string, r any, stack []byte) { recovered = r },
+	}
+
+	_, err := call(cfg, metadata.MD{}, func(ctx context.Context, req any) (any, error) {
+		panic("boom")
+	})
+	if status.Code(err) != codes.Internal {
+		t.Fatalf("expected Internal, got %v", err)
+	}
+	if recovered != "boom" {
+		t.Fatalf("panic handler got %v", recovered)
+	}
+	if logged.Code != codes.Internal || logged.Method != testMethod {
+		t.Fatalf("unexpected access log %+v", logged)
+	}
+}
+
+func TestDefaultTimeout(t *testing.T) {
+	cfg := ServerConfig{DefaultTimeout: time.Minute}
+
+	_, err := call(cfg, metadata.MD{}, func(ctx context.Context, req any) (any, error) {
+		deadline, ok := ctx.Deadline()
+		if !ok || time.Until(deadline) > time.Minute {
+			t.Fatalf("expected the default deadline, got %v %v", deadline, ok)
+		}
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	_, err = cfg.unaryInterceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: testMethod}, func(ctx context.Context, req any) (any, error) {
+		if deadline, _ := ctx.Deadline(); time.Until(deadline) < 59*time.Minute {
+			t.Fatalf("caller deadline was replaced: %v", deadline)
+		}
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRequestId(t *testing.T) {
+	var logged AccessLog
+	cfg := ServerConfig{Log: func(ctx context.Context, entry AccessLog) { logged = entry }}
+
+	_, err := call(cfg, metadata.Pairs(MetadataRequestId, "req-1"), func(ctx context.Context, req any) (any, error) {
+		if id := RequestIdFrom(ctx); id != "req-1" {
+			t.Fatalf("expected req-1, got %q", id)
+		}
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if logged.RequestId != "req-1" {
+		t.Fatalf("unexpected access log %+v", logged)
+	}
+
+	_, err = call(cfg, metadata.MD{}, func(ctx context.Context, req any) (any, error) {
+		if RequestIdFrom(ctx) == "" {
+			t.Fatal("expected a generated request id")
+		}
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Serves calls that block until release is closed, even when cancelled, and starts one of them
+func serveBlocking(t *testing.T, release chan struct{}) (*grpc.Server, chan error) {
+	t.Helper()
+	started := make(chan struct{})
+	server := grpc.NewServer(grpc.UnknownServiceHandler(func(srv any, stream grpc.ServerStream) error {
+		if err := stream.RecvMsg(&healthpb.HealthCheckRequest{}); err != nil {
+			return err
+		}
+		close(started)
+		<-release
+		return stream.SendMsg(&healthpb.HealthCheckResponse{})
+	}))
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go server.Serve(lis)
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	result := make(chan error, 1)
+	go func() {
+		result <- conn.Invoke(context.Background(), testMethod, &healthpb.HealthCheckRequest{}, &healthpb.HealthCheckResponse{})
+	}()
+	<-started
+	return server, result
+}
+
+func TestGracefulStopDrains(t *testing.T) {
+	release := make(chan struct{})
+	server, result := serveBlocking(t, release)
+
+	time.AfterFunc(20*time.Millisecond, func() { close(release) })
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := GracefulStop(ctx, server); err != nil {
+		t.Fatalf("expected drained server, got %v", err)
+	}
+	if err := <-result; err != nil {
+		t.Fatalf("expected running call to finish, got %v", err)
+	}
+}
+
+func TestGracefulStopDeadline(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	server, result := serveBlocking(t, release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := GracefulStop(ctx, server); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline, got %v", err)
+	}
+	if code := status.Code(<-result); code != codes.Unavailable {
+		t.Fatalf("expected cancelled call, got %s", code)
+	}
+}
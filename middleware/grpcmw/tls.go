@@ -0,0 +1,242 @@
+package grpcmw
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const defaultReloadInterval = 30 * time.Second
+
+var (
+	ErrNoCertificate = errors.New("no certificate found in CA file")
+	// A typo in one of the paths must not silently downgrade the service to plaintext
+	ErrPartialTLSConfig = errors.New("gRPC TLS needs the cert, key and CA file, only some of them are set")
+)
+
+// Paths of the PEM files for mutual TLS. Both sides present CertFile and
+// only trust certificates signed by CAFile
+type TLSConfig struct {
+	CertFile string
+	KeyFile  string
+	CAFile   string
+	// Overrides the name checked against the server certificate, defaults to the dialed host
+	ServerName string
+	// How often the files are checked for changes, defaults to 30s
+	ReloadInterval time.Duration
+	// Called when changed files can't be loaded, the previous certificates stay in use
+	OnReloadError func(err error)
+}
+
+func (cfg TLSConfig) Enabled() bool {
+	return cfg.CertFile != "" && cfg.KeyFile != "" && cfg.CAFile != ""
+}
+
+// Fails with ErrPartialTLSConfig when some but not all of the files are set
+func (cfg TLSConfig) Validate() error {
+	if cfg.Enabled() || (cfg.CertFile == "" && cfg.KeyFile == "" && cfg.CAFile == "") {
+		return nil
+	}
+
+	return fmt.Errorf("%w (cert %q, key %q, CA %q)", ErrPartialTLSConfig, cfg.CertFile, cfg.KeyFile, cfg.CAFile)
+}
+
+// Credentials as server option, plaintext when none of the files are set
+func ServerTransport(cfg TLSConfig) ([]grpc.ServerOption, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if !cfg.Enabled() {
+		return nil, nil
+	}
+
+	creds, err := ServerCredentials(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return []grpc.ServerOption{grpc.Creds(creds)}, nil
+}
+
+// Credentials as dial option, plaintext when none of the files are set
+func ClientTransport(cfg TLSConfig) (grpc.DialOption, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if !cfg.Enabled() {
+		return grpc.WithTransportCredentials(insecure.NewCredentials()), nil
+	}
+
+	creds, err := ClientCredentials(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return grpc.WithTransportCredentials(creds), nil
+}
+
+// Handshakes without a client certificate signed by CAFile are refused
+func ServerCredentials(cfg TLSConfig) (credentials.TransportCredentials, error) {
+	kp, err := newKeyPair(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return credentials.NewTLS(&tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// Built per handshake so rotated files apply to new connections
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cert, pool := kp.current()
+			return &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				NextProtos:   []string{"h2"},
+				Certificates: []tls.Certificate{*cert},
+				ClientCAs:    pool,
+				ClientAuth:   tls.RequireAndVerifyClientCert,
+			}, nil
+		},
+	}), nil
+}
+
+func ClientCredentials(cfg TLSConfig) (credentials.TransportCredentials, error) {
+	kp, err := newKeyPair(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return credentials.NewTLS(&tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: cfg.ServerName,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			cert, _ := kp.current()
+			return cert, nil
+		},
+		// The default verification can't follow a reloaded CA, VerifyConnection
+		// does the same checks against the current pool instead
+		InsecureSkipVerify: true,
+		VerifyConnection: func(cs tls.ConnectionState) error {
+			if len(cs.PeerCertificates) == 0 {
+				return errors.New("server presented no certificate")
+			}
+
+			_, pool := kp.current()
+			intermediates := x509.NewCertPool()
+			for _, cert := range cs.PeerCertificates[1:] {
+				intermediates.AddCert(cert)
+			}
+
+			_, err := cs.PeerCertificates[0].Verify(x509.VerifyOptions{
+				DNSName:       cs.ServerName,
+				Roots:         pool,
+				Intermediates: intermediates,
+				KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+			})
+			return err
+		},
+	}), nil
+}
+
+// Certificate and CA pool reloaded from disk when the files change
+type keyPair struct {
+	cfg TLSConfig
+
+	mu        sync.RWMutex
+	cert      *tls.Certificate
+	pool      *x509.CertPool
+	modTimes  [3]time.Time
+	checkedAt time.Time
+}
+
+func newKeyPair(cfg TLSConfig) (*keyPair, error) {
+	if cfg.ReloadInterval <= 0 {
+		cfg.ReloadInterval = defaultReloadInterval
+	}
+
+	kp := &keyPair{cfg: cfg}
+	modTimes, err := kp.stat()
+	if err != nil {
+		return nil, err
+	}
+	if err := kp.load(modTimes); err != nil {
+		return nil, err
+	}
+
+	return kp, nil
+}
+
+func (kp *keyPair) current() (*tls.Certificate, *x509.CertPool) {
+	kp.mu.RLock()
+	due := time.Since(kp.checkedAt) >= kp.cfg.ReloadInterval
+	kp.mu.RUnlock()
+
+	if due {
+		kp.reload()
+	}
+
+	kp.mu.RLock()
+	defer kp.mu.RUnlock()
+	return kp.cert, kp.pool
+}
+
+func (kp *keyPair) reload() {
+	kp.mu.Lock()
+	if time.Since(kp.checkedAt) < kp.cfg.ReloadInterval {
+		kp.mu.Unlock()
+		return
+	}
+	kp.checkedAt = time.Now()
+	previous := kp.modTimes
+	kp.mu.Unlock()
+
+	modTimes, err := kp.stat()
+	if err == nil && modTimes == previous {
+		return
+	}
+	if err == nil {
+		err = kp.load(modTimes)
+	}
+	if err != nil && kp.cfg.OnReloadError != nil {
+		kp.cfg.OnReloadError(err)
+	}
+}
+
+func (kp *keyPair) load(modTimes [3]time.Time) error {
+	cert, err := tls.LoadX509KeyPair(kp.cfg.CertFile, kp.cfg.KeyFile)
+	if err != nil {
+		return fmt.Errorf("load key pair: %w", err)
+	}
+
+	caPem, err := os.ReadFile(kp.cfg.CAFile)
+	if err != nil {
+		return fmt.Errorf("read CA file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPem) {
+		return ErrNoCertificate
+	}
+
+	kp.mu.Lock()
+	defer kp.mu.Unlock()
+	kp.cert, kp.pool, kp.modTimes = &cert, pool, modTimes
+	kp.checkedAt = time.Now()
+	return nil
+}
+
+func (kp *keyPair) stat() ([3]time.Time, error) {
+	var modTimes [3]time.Time
+	for i, path := range []string{kp.cfg.CertFile, kp.cfg.KeyFile, kp.cfg.CAFile} {
+		info, err := os.Stat(path)
+		if err != nil {
+			return modTimes, err
+		}
+		modTimes[i] = info.ModTime()
+	}
+
+	return modTimes, nil
+}
@@ -0,0 +1,271 @@
+package grpcmw
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+
+	return testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// Writes a certificate for name signed by ca and returns the config pointing at it
+func (ca testCA) issue(t *testing.T, dir, name string, serial int64) TLSConfig {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name, "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := TLSConfig{
+		CertFile: filepath.Join(dir, name+".crt"),
+		KeyFile:  filepath.Join(dir, name+".key"),
+		CAFile:   filepath.Join(dir, name+"-ca.crt"),
+	}
+	writeFile(t, cfg.CertFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	writeFile(t, cfg.KeyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}))
+	writeFile(t, cfg.CAFile, ca.pem)
+	return cfg
+}
+
+func writeFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Starts a server answering every method behind mTLS and returns its address
+func serveTLS(t *testing.T, cfg TLSConfig, serverCfg ServerConfig) string {
+	t.Helper()
+	transport, err := ServerTransport(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	options := append(transport, ServerOptions(serverCfg)...)
+	server := grpc.NewServer(append(options, grpc.UnknownServiceHandler(func(srv any, stream grpc.ServerStream) error {
+		if err := stream.RecvMsg(&healthpb.HealthCheckRequest{}); err != nil {
+			return err
+		}
+		return stream.SendMsg(&healthpb.HealthCheckResponse{})
+	}))...)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	_, port, _ := net.SplitHostPort(lis.Addr().String())
+	return net.JoinHostPort("localhost", port)
+}
+
+func credentialsWithoutCert(pool *x509.CertPool) credentials.TransportCredentials {
+	return credentials.NewTLS(&tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12})
+}
+
+func callTLS(t *testing.T, addr string, dial grpc.DialOption) error {
+	t.Helper()
+	conn, err := grpc.NewClient(addr, dial)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return conn.Invoke(ctx, testMethod, &healthpb.HealthCheckRequest{}, &healthpb.HealthCheckResponse{})
+}
+
+func TestMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t)
+	serverTls := ca.issue(t, dir, "user", 2)
+	addr := serveTLS(t, serverTls, ServerConfig{AllowedPeers: []string{"purchase"}})
+
+	clientDial := func(cfg TLSConfig) grpc.DialOption {
+		dial, err := ClientTransport(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return dial
+	}
+
+	t.Run("trusted client", func(t *testing.T) {
+		if err := callTLS(t, addr, clientDial(ca.issue(t, dir, "purchase", 3))); err != nil {
+			t.Fatalf("expected call to pass, got %v", err)
+		}
+	})
+
+	t.Run("client not allowed", func(t *testing.T) {
+		if err := callTLS(t, addr, clientDial(ca.issue(t, dir, "product", 4))); err == nil {
+			t.Fatal("expected peer outside AllowedPeers to be refused")
+		}
+	})
+
+	t.Run("untrusted client", func(t *testing.T) {
+		other := newTestCA(t)
+		cfg := other.issue(t, dir, "purchase-other", 5)
+		// Trusts the server but presents a certificate from another CA
+		writeFile(t, cfg.CAFile, ca.pem)
+		if err := callTLS(t, addr, clientDial(cfg)); err == nil {
+			t.Fatal("expected certificate from another CA to be refused")
+		}
+	})
+
+	t.Run("no client certificate", func(t *testing.T) {
+		pool := x509.NewCertPool()
+		pool.AppendCertsFromPEM(ca.pem)
+		dial := grpc.WithTransportCredentials(credentialsWithoutCert(pool))
+		if err := callTLS(t, addr, dial); err == nil {
+			t.Fatal("expected client without certificate to be refused")
+		}
+	})
+}
+
+func TestKeyPairReload(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t)
+	cfg := ca.issue(t, dir, "user", 2)
+	cfg.ReloadInterval = time.Millisecond
+
+	kp, err := newKeyPair(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ca.issue(t, dir, "user", 3)
+	future := time.Now().Add(time.Minute)
+	for _, path := range []string{cfg.CertFile, cfg.KeyFile} {
+		if err := os.Chtimes(path, future, future); err != nil {
+			t.Fatal(err)
+		}
+	}
+	time.Sleep(2 * time.Millisecond)
+
+	cert, _ := kp.current()
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if leaf.SerialNumber.Int64() != 3 {
+		t.Fatalf("expected reloaded certificate, got serial %d", leaf.SerialNumber.Int64())
+	}
+}
+
+func TestKeyPairReloadKeepsPrevious(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t)
+	cfg := ca.issue(t, dir, "user", 2)
+	cfg.ReloadInterval = time.Millisecond
+	var reloadErr error
+	cfg.OnReloadError = func(err error) { reloadErr = err }
+
+	kp, err := newKeyPair(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous, _ := kp.current()
+
+	writeFile(t, cfg.CAFile, []byte("not a certificate"))
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(cfg.CAFile, future, future); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(2 * time.Millisecond)
+
+	cert, pool := kp.current()
+	if cert != previous || pool == nil {
+		t.Fatal("expected previous certificates to stay in use")
+	}
+	if reloadErr == nil {
+		t.Fatal("expected reload error to be reported")
+	}
+}
+
+func TestPartialTLSConfig(t *testing.T) {
+	partial := []TLSConfig{
+		{CertFile: "cert.pem"},
+		{CertFile: "cert.pem", KeyFile: "key.pem"},
+		{KeyFile: "key.pem", CAFile: "ca.pem"},
+		{CAFile: "ca.pem"},
+	}
+	for _, cfg := range partial {
+		if _, err := ServerTransport(cfg); !errors.Is(err, ErrPartialTLSConfig) {
+			t.Fatalf("ServerTransport(%+v) = %v, want ErrPartialTLSConfig", cfg, err)
+		}
+		if _, err := ClientTransport(cfg); !errors.Is(err, ErrPartialTLSConfig) {
+			t.Fatalf("ClientTransport(%+v) = %v, want ErrPartialTLSConfig", cfg, err)
+		}
+	}
+
+	// Nothing set stays plaintext
+	options, err := ServerTransport(TLSConfig{})
+	if err != nil || options != nil {
+		t.Fatalf("ServerTransport of an empty config = %v, %v, want plaintext", options, err)
+	}
+	if _, err := ClientTransport(TLSConfig{}); err != nil {
+		t.Fatalf("ClientTransport of an empty config = %v, want plaintext", err)
+	}
+}
@@ -0,0 +1,76 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// Full method names of grpc.health.v1, they have to skip service authentication
+var GrpcMethods = []string{
+	healthpb.Health_Check_FullMethodName,
+	healthpb.Health_Watch_FullMethodName,
+}
+
+// Registers grpc.health.v1 on server. The overall status ("") is SERVING while the
+// required checks pass, it is refreshed every interval until Shutdown
+func (c *Checker) RegisterGrpc(server *grpc.Server, interval time.Duration) *health.Server {
+	hs := health.NewServer()
+	healthpb.RegisterHealthServer(server, hs)
+
+	c.mu.Lock()
+	c.servers = append(c.servers, hs)
+	if c.draining {
+		hs.Shutdown()
+	}
+	c.mu.Unlock()
+
+	stop := c.stopped()
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			_, ready := c.Ready(context.Background())
+			serving := healthpb.HealthCheckResponse_SERVING
+			if !ready {
+				serving = healthpb.HealthCheckResponse_NOT_SERVING
+			}
+			// ignored by hs after Shutdown
+			hs.SetServingStatus("", serving)
+
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return hs
+}
+
+// Asks a downstream server through grpc.health.v1. Servers without the health
+// service answered, so they count as reachable
+func GrpcCheck(conn grpc.ClientConnInterface) Check {
+	client := healthpb.NewHealthClient(conn)
+	return func(ctx context.Context) error {
+		response, err := client.Check(ctx, &healthpb.HealthCheckRequest{})
+		if status.Code(err) == codes.Unimplemented {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if response.Status != healthpb.HealthCheckResponse_SERVING {
+			return fmt.Errorf("status %s", response.Status)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,86 @@
+package health
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func serve(t *testing.T, register func(server *grpc.Server)) *grpc.ClientConn {
+	t.Helper()
+	server := grpc.NewServer()
+	register(server)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestRegisterGrpcFollowsReadiness(t *testing.T) {
+	var down atomic.Bool
+	checker := New(0).Add("postgres", func(ctx context.Context) error {
+		if down.Load() {
+			return fail(ctx)
+		}
+		return nil
+	})
+	conn := serve(t, func(server *grpc.Server) { checker.RegisterGrpc(server, 10*time.Millisecond) })
+	check := GrpcCheck(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := check(ctx); err != nil {
+		t.Fatalf("expected SERVING, got %v", err)
+	}
+
+	down.Store(true)
+	deadline := time.Now().Add(time.Second)
+	for check(ctx) == nil {
+		if time.Now().After(deadline) {
+			t.Fatal("expected NOT_SERVING after a failed check")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestGrpcCheckWithoutHealthService(t *testing.T) {
+	conn := serve(t, func(server *grpc.Server) {})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := GrpcCheck(conn)(ctx); err != nil {
+		t.Fatalf("expected reachable server to pass, got %v", err)
+	}
+}
+
+func TestRegisterGrpcShutdown(t *testing.T) {
+	checker := New(0).Add("postgres", pass)
+	conn := serve(t, func(server *grpc.Server) { checker.RegisterGrpc(server, time.Hour) })
+	check := GrpcCheck(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := check(ctx); err != nil {
+		t.Fatalf("expected SERVING, got %v", err)
+	}
+
+	checker.Shutdown()
+	if err := check(ctx); err == nil {
+		t.Fatal("expected NOT_SERVING after Shutdown")
+	}
+}
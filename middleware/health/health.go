@@ -0,0 +1,156 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"google.golang.org/grpc/health"
+)
+
+const (
+	StatusOk           = "ok"
+	StatusDegraded     = "degraded"
+	StatusUnavailable  = "unavailable"
+	StatusShuttingDown = "shutting down" // once Shutdown was called
+)
+
+const defaultTimeout = 2 * time.Second
+
+// Returns nil when the dependency can be used
+type Check func(ctx context.Context) error
+
+type namedCheck struct {
+	name     string
+	check    Check
+	required bool
+}
+
+// Readiness checks of one service, shared by the HTTP routes and grpc.health.v1
+type Checker struct {
+	// Upper bound of one run of all checks, defaults to 2s
+	Timeout time.Duration
+
+	checks []namedCheck
+
+	mu       sync.Mutex
+	draining bool
+	stop     chan struct{}
+	servers  []*health.Server
+}
+
+func New(timeout time.Duration) *Checker {
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	return &Checker{Timeout: timeout}
+}
+
+// A failing required check makes the service unavailable, e.g. its own database
+func (c *Checker) Add(name string, check Check) *Checker {
+	c.checks = append(c.checks, namedCheck{name: name, check: check, required: true})
+	return c
+}
+
+// A failing optional check only degrades the service. Meant for downstream services, taking
+// every caller out of rotation when one of them is down would spread the outage
+func (c *Checker) AddOptional(name string, check Check) *Checker {
+	c.checks = append(c.checks, namedCheck{name: name, check: check})
+	return c
+}
+
+type Result struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// Runs every check concurrently, ready is false when a required check failed
+func (c *Checker) Ready(ctx context.Context) (result Result, ready bool) {
+	if c.Draining() {
+		return Result{Status: StatusShuttingDown}, false
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.Timeout)
+	defer cancel()
+
+	errs := make([]error, len(c.checks))
+	var wg sync.WaitGroup
+	for i, nc := range c.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = nc.check(ctx)
+		}()
+	}
+	wg.Wait()
+
+	result = Result{Status: StatusOk, Checks: make(map[string]string, len(c.checks))}
+	ready = true
+	for i, nc := range c.checks {
+		if errs[i] == nil {
+			result.Checks[nc.name] = StatusOk
+			continue
+		}
+
+		result.Checks[nc.name] = errs[i].Error()
+		if nc.required {
+			ready = false
+			result.Status = StatusUnavailable
+		} else if ready {
+			result.Status = StatusDegraded
+		}
+	}
+
+	return result, ready
+}
+
+// Marks the service as not ready for good, /readyz answers 503 and grpc.health.v1 NOT_SERVING so
+// load balancers and clients stop sending new requests while the servers drain. /healthz keeps
+// answering, the process is still alive
+func (c *Checker) Shutdown() {
+	stop := c.stopped()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.draining {
+		return
+	}
+	c.draining = true
+	close(stop)
+	for _, hs := range c.servers {
+		hs.Shutdown()
+	}
+}
+
+func (c *Checker) Draining() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.draining
+}
+
+// Closed by Shutdown
+func (c *Checker) stopped() chan struct{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stop == nil {
+		c.stop = make(chan struct{})
+	}
+	return c.stop
+}
+
+// GET /healthz answers while the process serves requests, GET /readyz runs the checks
+// and answers 503 when the service should not get traffic
+func (c *Checker) Register(router fiber.Router) {
+	router.Get("/healthz", func(ctx *fiber.Ctx) error {
+		return ctx.JSON(Result{Status: StatusOk})
+	})
+
+	router.Get("/readyz", func(ctx *fiber.Ctx) error {
+		result, ready := c.Ready(ctx.Context())
+		if !ready {
+			ctx.Status(fiber.StatusServiceUnavailable)
+		}
+		return ctx.JSON(result)
+	})
+}
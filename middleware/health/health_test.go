@@ -0,0 +1,99 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func pass(ctx context.Context) error { return nil }
+
+func fail(ctx context.Context) error { return errors.New("down") }
+
+func get(t *testing.T, c *Checker, path string) (int, Result) {
+	t.Helper()
+	app := fiber.New()
+	c.Register(app)
+
+	res, err := app.Test(httptest.NewRequest("GET", path, nil), -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result Result
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		t.Fatal(err)
+	}
+	return res.StatusCode, result
+}
+
+func TestReadyz(t *testing.T) {
+	tests := []struct {
+		name    string
+		checker *Checker
+		code    int
+		status  string
+	}{
+		{name: "no checks", checker: New(0), code: 200, status: StatusOk},
+		{name: "all pass", checker: New(0).Add("postgres", pass).AddOptional("product", pass), code: 200, status: StatusOk},
+		{name: "optional fails", checker: New(0).Add("postgres", pass).AddOptional("product", fail), code: 200, status: StatusDegraded},
+		{name: "required fails", checker: New(0).Add("postgres", fail).AddOptional("product", fail), code: 503, status: StatusUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, result := get(t, tt.checker, "/readyz")
+			if code != tt.code || result.Status != tt.status {
+				t.Fatalf("expected %d %s, got %d %s", tt.code, tt.status, code, result.Status)
+			}
+		})
+	}
+}
+
+func TestReadyzReportsChecks(t *testing.T) {
+	_, result := get(t, New(0).Add("postgres", pass).AddOptional("product", fail), "/readyz")
+	if result.Checks["postgres"] != StatusOk || result.Checks["product"] != "down" {
+		t.Fatalf("unexpected checks %v", result.Checks)
+	}
+}
+
+func TestReadyzTimeout(t *testing.T) {
+	hang := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	start := time.Now()
+	code, _ := get(t, New(50*time.Millisecond).Add("redis", hang), "/readyz")
+	if code != 503 {
+		t.Fatalf("expected 503, got %d", code)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected checks to stop at the timeout, took %s", elapsed)
+	}
+}
+
+func TestHealthzSkipsChecks(t *testing.T) {
+	code, result := get(t, New(0).Add("postgres", fail), "/healthz")
+	if code != 200 || result.Status != StatusOk {
+		t.Fatalf("expected liveness to pass, got %d %s", code, result.Status)
+	}
+}
+
+func TestReadyzAfterShutdown(t *testing.T) {
+	checker := New(0).Add("postgres", pass)
+	checker.Shutdown()
+	checker.Shutdown()
+
+	code, result := get(t, checker, "/readyz")
+	if code != 503 || result.Status != StatusShuttingDown {
+		t.Fatalf("expected 503 %s, got %d %s", StatusShuttingDown, code, result.Status)
+	}
+	if code, _ := get(t, checker, "/healthz"); code != 200 {
+		t.Fatalf("expected liveness to pass while draining, got %d", code)
+	}
+}
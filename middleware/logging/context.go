@@ -0,0 +1,31 @@
+package logging
+
+import (
+	"context"
+
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/auth"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/grpcmw"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/tracing"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// request_id, trace_id and user_id of ctx, each one only when known. ctx may be
+// c.UserContext(), c.Context() or the context of a gRPC call
+func correlationFields(ctx context.Context) []zap.Field {
+	if ctx == nil {
+		return nil
+	}
+
+	fields := make([]zap.Field, 0, 3)
+	if requestId := grpcmw.RequestIdFrom(ctx); requestId != "" {
+		fields = append(fields, zap.String("request_id", requestId))
+	}
+	if spanContext := trace.SpanContextFromContext(tracing.Context(ctx)); spanContext.HasTraceID() {
+		fields = append(fields, zap.String("trace_id", spanContext.TraceID().String()))
+	}
+	if principal, ok := auth.PrincipalFromContext(ctx); ok {
+		fields = append(fields, zap.String("user_id", principal.UserId))
+	}
+	return fields
+}
@@ -0,0 +1,48 @@
+package logging
+
+import (
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/grpcmw"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Carries the id of the requestid middleware into c.UserContext(), so handlers passing
+// c.UserContext() log it as well. Register it right after requestid.New()
+func Fiber() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if requestId, ok := c.Locals(grpcmw.LocalsRequestId).(string); ok && requestId != "" {
+			c.SetUserContext(grpcmw.WithRequestId(c.UserContext(), requestId))
+		}
+		return c.Next()
+	}
+}
+
+type levelBody struct {
+	Level string `json:"level"`
+}
+
+// GET and PUT /log-level, changes the level of this instance until it restarts.
+// Mount it on a group that only lets admins through
+func (l *Logger) RegisterLevel(router fiber.Router) {
+	router.Get("/log-level", l.getLevel)
+	router.Put("/log-level", l.putLevel)
+}
+
+func (l *Logger) getLevel(c *fiber.Ctx) error {
+	return c.JSON(levelBody{Level: l.level.String()})
+}
+
+func (l *Logger) putLevel(c *fiber.Ctx) error {
+	var body levelBody
+	if err := c.BodyParser(&body); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	previous := l.level.String()
+	if err := l.level.UnmarshalText([]byte(body.Level)); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	// logged with the admin's user id, at warn so it shows up with any level
+	l.Warn(c.UserContext(), "log level changed", "from", previous, "to", l.level.String())
+
+	return c.JSON(levelBody{Level: l.level.String()})
+}
@@ -0,0 +1,83 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+)
+
+func TestFiberCarriesRequestId(t *testing.T) {
+	logger, buf := newTestLogger(t, "")
+	app := fiber.New()
+	app.Use(requestid.New())
+	app.Use(Fiber())
+	app.Get("/", func(c *fiber.Ctx) error {
+		logger.Info(c.UserContext(), "user context")
+		logger.Info(c.Context(), "fiber context")
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set(fiber.HeaderXRequestID, "request-1")
+	if _, err := app.Test(req, -1); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, entry := range entries(t, buf) {
+		if entry["request_id"] != "request-1" {
+			t.Fatalf("expected the request id with %s, got %v", entry["msg"], entry)
+		}
+	}
+}
+
+func levelRequest(t *testing.T, app *fiber.App, method string, body string) (int, string) {
+	t.Helper()
+	req := httptest.NewRequest(method, "/admin/log-level", strings.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	res, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res.StatusCode, string(response)
+}
+
+func TestRegisterLevel(t *testing.T) {
+	logger, buf := newTestLogger(t, "info")
+	app := fiber.New()
+	logger.RegisterLevel(app.Group("/admin"))
+
+	if status, body := levelRequest(t, app, "GET", ""); status != fiber.StatusOK || body != `{"level":"info"}` {
+		t.Fatalf("expected the current level, got %d: %s", status, body)
+	}
+
+	logger.Debug(context.Background(), "dropped")
+	if status, body := levelRequest(t, app, "PUT", `{"level":"debug"}`); status != fiber.StatusOK || body != `{"level":"debug"}` {
+		t.Fatalf("expected the level to change, got %d: %s", status, body)
+	}
+	logger.Debug(context.Background(), "kept")
+
+	if status, _ := levelRequest(t, app, "PUT", `{"level":"loud"}`); status != fiber.StatusBadRequest {
+		t.Fatalf("expected an unknown level to be rejected, got %d", status)
+	}
+
+	var messages []string
+	for _, entry := range entries(t, buf) {
+		messages = append(messages, entry["msg"].(string))
+	}
+	if strings.Join(messages, ",") != "log level changed,kept" {
+		t.Fatalf("unexpected entries %v", messages)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte(`"from":"info","to":"debug"`)) {
+		t.Fatalf("expected the change to be logged, got %s", buf.String())
+	}
+}
@@ -0,0 +1,123 @@
+package logging
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+type Config struct {
+	// Added to every entry as "service"
+	Service string
+	// debug, info, warn or error, defaults to info. Can be changed at runtime, see RegisterLevel
+	Level string
+	// Rotated JSON log file, empty writes to stdout only
+	File string
+	// Also writes to stdout when File is set
+	Stdout bool
+}
+
+// Structured JSON logger. Every entry carries the request id, trace id and user id
+// found in ctx, and values under sensitive keys are redacted
+type Logger struct {
+	logger *zap.Logger
+	level  zap.AtomicLevel
+}
+
+func New(cfg Config) (*Logger, error) {
+	var writers []io.Writer
+	if cfg.File != "" {
+		writers = append(writers, &lumberjack.Logger{
+			Filename:   cfg.File,
+			MaxSize:    10, // Max megabytes before log is rotated
+			MaxBackups: 10,
+			MaxAge:     30,
+			Compress:   true,
+		})
+	}
+	if cfg.File == "" || cfg.Stdout {
+		writers = append(writers, os.Stdout)
+	}
+
+	return newLogger(cfg, zapcore.AddSync(io.MultiWriter(writers...)))
+}
+
+func newLogger(cfg Config, output zapcore.WriteSyncer) (*Logger, error) {
+	level := zap.NewAtomicLevel()
+	if cfg.Level != "" {
+		if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
+			return nil, err
+		}
+	}
+
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.TimeKey = "timestamp"
+	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(encoderConfig), output, level)
+	// the caller of Info and friends, not log
+	logger := zap.New(core, zap.AddCaller(), zap.AddCallerSkip(2)).
+		With(zap.String("service", cfg.Service))
+
+	return &Logger{logger: logger, level: level}, nil
+}
+
+// Logs msg with the correlation ids of ctx and the key value pairs, like
+// logger.Error(ctx, "unable to save cart", "error", err, "cartId", id)
+func (l *Logger) Debug(ctx context.Context, msg string, keysAndValues ...any) {
+	l.log(ctx, zapcore.DebugLevel, msg, keysAndValues)
+}
+
+func (l *Logger) Info(ctx context.Context, msg string, keysAndValues ...any) {
+	l.log(ctx, zapcore.InfoLevel, msg, keysAndValues)
+}
+
+func (l *Logger) Warn(ctx context.Context, msg string, keysAndValues ...any) {
+	l.log(ctx, zapcore.WarnLevel, msg, keysAndValues)
+}
+
+func (l *Logger) Error(ctx context.Context, msg string, keysAndValues ...any) {
+	l.log(ctx, zapcore.ErrorLevel, msg, keysAndValues)
+}
+
+// Logs like Error regardless of the level, then exits the process. Only for startup failures
+func (l *Logger) Fatal(ctx context.Context, msg string, keysAndValues ...any) {
+	l.log(ctx, zapcore.FatalLevel, msg, keysAndValues)
+}
+
+// Logger adding the key value pairs to every entry, e.g. the component of a background job
+func (l *Logger) With(keysAndValues ...any) *Logger {
+	return &Logger{logger: l.logger.With(fields(keysAndValues)...), level: l.level}
+}
+
+// Flushes buffered entries, called on shutdown
+func (l *Logger) Sync() error {
+	return l.logger.Sync()
+}
+
+func (l *Logger) log(ctx context.Context, level zapcore.Level, msg string, keysAndValues []any) {
+	// checked first so disabled levels cost neither the context lookups nor the redaction
+	entry := l.logger.Check(level, msg)
+	if entry == nil {
+		return
+	}
+	entry.Write(append(correlationFields(ctx), fields(keysAndValues)...)...)
+}
+
+func fields(keysAndValues []any) []zap.Field {
+	fields := make([]zap.Field, 0, len(keysAndValues)/2)
+	for i := 0; i < len(keysAndValues); i += 2 {
+		key, ok := keysAndValues[i].(string)
+		if !ok || i+1 == len(keysAndValues) {
+			// a missing key must not shift the rest, the values are logged as they are
+			fields = append(fields, zap.Any("unpaired", redactValue(keysAndValues[i:])))
+			break
+		}
+		fields = append(fields, redactField(key, keysAndValues[i+1]))
+	}
+	return fields
+}
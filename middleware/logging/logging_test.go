@@ -0,0 +1,125 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/auth"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/grpcmw"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap/zapcore"
+)
+
+const testTraceId = "0af7651916cd43dd8448eb211c80319c"
+
+// Logger writing into the returned buffer, one JSON entry per line
+func newTestLogger(t *testing.T, level string) (*Logger, *bytes.Buffer) {
+	t.Helper()
+	var buf bytes.Buffer
+	logger, err := newLogger(Config{Service: "test", Level: level}, zapcore.AddSync(&buf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return logger, &buf
+}
+
+func entries(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var entries []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("invalid entry %s: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func lastEntry(t *testing.T, buf *bytes.Buffer) map[string]any {
+	t.Helper()
+	all := entries(t, buf)
+	if len(all) == 0 {
+		t.Fatal("expected an entry")
+	}
+	return all[len(all)-1]
+}
+
+func TestCorrelationIds(t *testing.T) {
+	logger, buf := newTestLogger(t, "")
+
+	traceId, _ := trace.TraceIDFromHex(testTraceId)
+	spanId, _ := trace.SpanIDFromHex("b7ad6b7169203331")
+	ctx := grpcmw.WithRequestId(context.Background(), "request-1")
+	ctx = trace.ContextWithSpanContext(ctx, trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceId, SpanID: spanId}))
+	ctx = auth.WithPrincipal(ctx, auth.Principal{UserId: "user-1"})
+
+	logger.Info(ctx, "cart saved", "cartId", "cart-1")
+
+	entry := lastEntry(t, buf)
+	for key, want := range map[string]string{
+		"msg":        "cart saved",
+		"level":      "info",
+		"service":    "test",
+		"request_id": "request-1",
+		"trace_id":   testTraceId,
+		"user_id":    "user-1",
+		"cartId":     "cart-1",
+	} {
+		if entry[key] != want {
+			t.Fatalf("expected %s %q, got %v", key, want, entry[key])
+		}
+	}
+	if caller, _ := entry["caller"].(string); !strings.HasPrefix(caller, "logging/logging_test.go") {
+		t.Fatalf("expected the caller of Info, got %v", entry["caller"])
+	}
+}
+
+func TestWithoutCorrelationIds(t *testing.T) {
+	logger, buf := newTestLogger(t, "")
+
+	logger.Info(context.Background(), "shutdown complete")
+	logger.Info(nil, "started")
+
+	for _, entry := range entries(t, buf) {
+		for _, key := range []string{"request_id", "trace_id", "user_id"} {
+			if _, ok := entry[key]; ok {
+				t.Fatalf("expected no %s outside a request, got %v", key, entry)
+			}
+		}
+	}
+}
+
+func TestLevel(t *testing.T) {
+	logger, buf := newTestLogger(t, "warn")
+
+	logger.Info(context.Background(), "dropped")
+	logger.Warn(context.Background(), "kept")
+
+	all := entries(t, buf)
+	if len(all) != 1 || all[0]["msg"] != "kept" {
+		t.Fatalf("expected only the warn entry, got %v", all)
+	}
+
+	if _, err := newLogger(Config{Level: "loud"}, zapcore.AddSync(&bytes.Buffer{})); err == nil {
+		t.Fatal("expected an unknown level to be rejected")
+	}
+}
+
+func TestWith(t *testing.T) {
+	logger, buf := newTestLogger(t, "")
+
+	logger.With("component", "priceScheduler", "token", "abc").Error(context.Background(), "run failed", "error", errors.New("timeout"))
+
+	entry := lastEntry(t, buf)
+	if entry["component"] != "priceScheduler" || entry["error"] != "timeout" || entry["token"] != Redacted {
+		t.Fatalf("unexpected entry %v", entry)
+	}
+}
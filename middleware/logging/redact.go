@@ -0,0 +1,96 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// Logged in place of a sensitive value
+const Redacted = "[REDACTED]"
+
+var (
+	// keys containing one of these after lowercasing and dropping "_" and "-",
+	// e.g. "refreshToken", "new_password" or "BankAccountNumber"
+	sensitiveParts = []string{"password", "passwd", "secret", "token", "authorization", "cookie", "apikey", "privatekey", "accountnumber", "cardnumber"}
+	// short keys that would match too much as a part
+	sensitiveKeys = map[string]bool{"otp": true, "pin": true, "cvv": true}
+
+	keyNormalizer = strings.NewReplacer("_", "", "-", "")
+)
+
+func isSensitive(key string) bool {
+	normalized := keyNormalizer.Replace(strings.ToLower(key))
+	if sensitiveKeys[normalized] {
+		return true
+	}
+	for _, part := range sensitiveParts {
+		if strings.Contains(normalized, part) {
+			return true
+		}
+	}
+	return false
+}
+
+func redactField(key string, value any) zap.Field {
+	if isSensitive(key) {
+		return zap.String(key, Redacted)
+	}
+	return zap.Any(key, redactValue(value))
+}
+
+// Scalars are kept as they are. Structs, maps and slices are converted through their
+// JSON form so nested sensitive fields, like the bank account number of a request
+// body, are redacted by their json name
+func redactValue(value any) any {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case error:
+		return v.Error()
+	case json.RawMessage:
+		return redactJSON(v)
+	case []byte:
+		return v
+	}
+
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array, reflect.Pointer, reflect.Interface:
+		raw, err := json.Marshal(value)
+		if err != nil {
+			// never fall back to %v, it would print the sensitive fields
+			return fmt.Sprintf("%T", value)
+		}
+		return redactJSON(raw)
+	}
+	return value
+}
+
+func redactJSON(raw []byte) any {
+	var decoded any
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return Redacted
+	}
+	return redactDecoded(decoded)
+}
+
+func redactDecoded(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, nested := range v {
+			if isSensitive(key) {
+				v[key] = Redacted
+				continue
+			}
+			v[key] = redactDecoded(nested)
+		}
+	case []any:
+		for i, nested := range v {
+			v[i] = redactDecoded(nested)
+		}
+	}
+	return value
+}
@@ -0,0 +1,90 @@
+package logging
+
+import (
+	"context"
+	"testing"
+)
+
+func TestIsSensitive(t *testing.T) {
+	tests := []struct {
+		key       string
+		sensitive bool
+	}{
+		{key: "password", sensitive: true},
+		{key: "new_password", sensitive: true},
+		{key: "refreshToken", sensitive: true},
+		{key: "Authorization", sensitive: true},
+		{key: "bankAccountNumber", sensitive: true},
+		{key: "bank-account-number", sensitive: true},
+		{key: "otp", sensitive: true},
+		{key: "bankAccountName", sensitive: false},
+		{key: "shipping", sensitive: false},
+		{key: "footprint", sensitive: false},
+		{key: "requestId", sensitive: false},
+	}
+	for _, tt := range tests {
+		if sensitive := isSensitive(tt.key); sensitive != tt.sensitive {
+			t.Fatalf("expected isSensitive(%q) to be %v", tt.key, tt.sensitive)
+		}
+	}
+}
+
+type bankAccount struct {
+	BankAccountName   string `json:"bankAccountName"`
+	BankAccountNumber string `json:"bankAccountNumber"`
+}
+
+type cartRequest struct {
+	SenderName string
+	Password   string
+	Sellers    []bankAccount `json:"sellers"`
+}
+
+func TestRedaction(t *testing.T) {
+	logger, buf := newTestLogger(t, "")
+
+	logger.Error(context.Background(), "unable to save cart",
+		"password", "hunter2",
+		"body", cartRequest{
+			SenderName: "buyer",
+			Password:   "hunter2",
+			Sellers:    []bankAccount{{BankAccountName: "BCA", BankAccountNumber: "1234567890"}},
+		},
+		"headers", map[string]string{"Authorization": "Bearer abc", "Accept": "application/json"},
+	)
+
+	entry := lastEntry(t, buf)
+	if entry["password"] != Redacted {
+		t.Fatalf("expected the password to be redacted, got %v", entry["password"])
+	}
+
+	body := entry["body"].(map[string]any)
+	if body["SenderName"] != "buyer" || body["Password"] != Redacted {
+		t.Fatalf("unexpected body %v", body)
+	}
+	seller := body["sellers"].([]any)[0].(map[string]any)
+	if seller["bankAccountName"] != "BCA" || seller["bankAccountNumber"] != Redacted {
+		t.Fatalf("unexpected seller %v", seller)
+	}
+
+	headers := entry["headers"].(map[string]any)
+	if headers["Authorization"] != Redacted || headers["Accept"] != "application/json" {
+		t.Fatalf("unexpected headers %v", headers)
+	}
+}
+
+func TestUnpairedValues(t *testing.T) {
+	logger, buf := newTestLogger(t, "")
+
+	logger.Info(context.Background(), "grpc call", "method", "/user.UserService/GetUser", "dangling")
+	logger.Info(context.Background(), "positional", 42, bankAccount{BankAccountNumber: "1234567890"})
+
+	all := entries(t, buf)
+	if all[0]["method"] != "/user.UserService/GetUser" {
+		t.Fatalf("expected the pair before the dangling value, got %v", all[0])
+	}
+	unpaired := all[1]["unpaired"].([]any)
+	if unpaired[0] != float64(42) || unpaired[1].(map[string]any)["bankAccountNumber"] != Redacted {
+		t.Fatalf("expected the values redacted as they are, got %v", unpaired)
+	}
+}
@@ -0,0 +1,51 @@
+package metrics
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Probes and scrapes, they would drown the real requests
+var skippedPaths = map[string]bool{
+	"/healthz": true,
+	"/readyz":  true,
+	"/metrics": true,
+}
+
+// Rate, errors and duration of every request, labelled with the matched route so
+// path parameters don't create new series
+func (m *Metrics) Fiber() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if skippedPaths[c.Path()] {
+			return c.Next()
+		}
+
+		start := time.Now()
+		m.httpInFlight.Inc()
+		defer m.httpInFlight.Dec()
+
+		err := c.Next()
+
+		status := c.Response().StatusCode()
+		if err != nil {
+			// the error handler writes the response after this middleware returned
+			status = fiber.StatusInternalServerError
+			var fiberErr *fiber.Error
+			if errors.As(err, &fiberErr) {
+				status = fiberErr.Code
+			}
+		}
+
+		// label values are kept by the series, fiber strings point into the reused request buffer
+		method := strings.Clone(c.Method())
+		route := strings.Clone(c.Route().Path)
+		m.httpRequests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+		m.httpDuration.WithLabelValues(method, route).Observe(time.Since(start).Seconds())
+
+		return err
+	}
+}
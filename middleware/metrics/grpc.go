@@ -0,0 +1,62 @@
+package metrics
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// Started, handled and duration of every call on the server
+func (m *Metrics) GrpcServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(m.unaryInterceptor),
+		grpc.ChainStreamInterceptor(m.streamInterceptor),
+	}
+}
+
+func (m *Metrics) unaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	done := m.grpcStart("unary", info.FullMethod)
+	response, err := handler(ctx, req)
+	done(err)
+	return response, err
+}
+
+func (m *Metrics) streamInterceptor(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	done := m.grpcStart(streamType(info), info.FullMethod)
+	err := handler(srv, stream)
+	done(err)
+	return err
+}
+
+func (m *Metrics) grpcStart(rpcType string, fullMethod string) func(err error) {
+	service, method := splitMethod(fullMethod)
+	m.grpcStarted.WithLabelValues(rpcType, service, method).Inc()
+	start := time.Now()
+
+	return func(err error) {
+		m.grpcHandled.WithLabelValues(rpcType, service, method, status.Code(err).String()).Inc()
+		m.grpcDuration.WithLabelValues(rpcType, service, method).Observe(time.Since(start).Seconds())
+	}
+}
+
+func streamType(info *grpc.StreamServerInfo) string {
+	switch {
+	case info.IsClientStream && info.IsServerStream:
+		return "bidi_stream"
+	case info.IsClientStream:
+		return "client_stream"
+	}
+	return "server_stream"
+}
+
+// "/user.UserService/GetUserDetailsWithId" is service "user.UserService", method "GetUserDetailsWithId"
+func splitMethod(fullMethod string) (string, string) {
+	service, method, found := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	if !found {
+		return "unknown", "unknown"
+	}
+	return service, method
+}
@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const testMethod = "/test.Service/Call"
+
+func TestUnaryInterceptor(t *testing.T) {
+	m := New("test")
+	unary := m.unaryInterceptor
+
+	ok := func(ctx context.Context, req any) (any, error) { return "ok", nil }
+	notFound := func(ctx context.Context, req any) (any, error) { return nil, status.Error(codes.NotFound, "missing") }
+	info := &grpc.UnaryServerInfo{FullMethod: testMethod}
+
+	unary(context.Background(), nil, info, ok)
+	unary(context.Background(), nil, info, ok)
+	unary(context.Background(), nil, info, notFound)
+
+	if started := testutil.ToFloat64(m.grpcStarted.WithLabelValues("unary", "test.Service", "Call")); started != 3 {
+		t.Fatalf("expected 3 started calls, got %v", started)
+	}
+	if handled := testutil.ToFloat64(m.grpcHandled.WithLabelValues("unary", "test.Service", "Call", "OK")); handled != 2 {
+		t.Fatalf("expected 2 OK calls, got %v", handled)
+	}
+	if handled := testutil.ToFloat64(m.grpcHandled.WithLabelValues("unary", "test.Service", "Call", "NotFound")); handled != 1 {
+		t.Fatalf("expected 1 NotFound call, got %v", handled)
+	}
+}
+
+func TestStreamInterceptor(t *testing.T) {
+	m := New("test")
+	info := &grpc.StreamServerInfo{FullMethod: "/grpc.health.v1.Health/Watch", IsServerStream: true}
+
+	err := m.streamInterceptor(nil, nil, info, func(srv any, stream grpc.ServerStream) error {
+		return status.Error(codes.Canceled, "gone")
+	})
+	if status.Code(err) != codes.Canceled {
+		t.Fatalf("expected the handler error, got %v", err)
+	}
+	if handled := testutil.ToFloat64(m.grpcHandled.WithLabelValues("server_stream", "grpc.health.v1.Health", "Watch", "Canceled")); handled != 1 {
+		t.Fatalf("expected 1 Canceled stream, got %v", handled)
+	}
+}
+
+func TestSplitMethod(t *testing.T) {
+	tests := []struct {
+		fullMethod string
+		service    string
+		method     string
+	}{
+		{fullMethod: "/user.UserService/GetUserDetailsWithId", service: "user.UserService", method: "GetUserDetailsWithId"},
+		{fullMethod: "/grpc.health.v1.Health/Check", service: "grpc.health.v1.Health", method: "Check"},
+		{fullMethod: "broken", service: "unknown", method: "unknown"},
+	}
+	for _, tt := range tests {
+		service, method := splitMethod(tt.fullMethod)
+		if service != tt.service || method != tt.method {
+			t.Fatalf("expected %s and %s from %s, got %s and %s", tt.service, tt.method, tt.fullMethod, service, method)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package metrics
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Metrics of one service, every series carries the "service" label
+type Metrics struct {
+	registry   *prometheus.Registry
+	registerer prometheus.Registerer
+
+	httpRequests *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+	httpInFlight prometheus.Gauge
+
+	grpcStarted  *prometheus.CounterVec
+	grpcHandled  *prometheus.CounterVec
+	grpcDuration *prometheus.HistogramVec
+
+	cacheLookups *prometheus.CounterVec
+}
+
+func New(service string) *Metrics {
+	registry := prometheus.NewRegistry()
+	registerer := prometheus.WrapRegistererWith(prometheus.Labels{"service": service}, registry)
+	registerer.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	factory := promauto.With(registerer)
+
+	return &Metrics{
+		registry:   registry,
+		registerer: registerer,
+
+		httpRequests: factory.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "HTTP requests by route and status code.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: factory.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "Time until the response was written.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		httpInFlight: factory.NewGauge(prometheus.GaugeOpts{
+			Name: "http_requests_in_flight",
+			Help: "HTTP requests being handled.",
+		}),
+
+		// same names and labels as go-grpc-prometheus, existing dashboards keep working
+		grpcStarted: factory.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_started_total",
+			Help: "RPCs started on the server.",
+		}, []string{"grpc_type", "grpc_service", "grpc_method"}),
+		grpcHandled: factory.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_handled_total",
+			Help: "RPCs completed on the server by status code.",
+		}, []string{"grpc_type", "grpc_service", "grpc_method", "grpc_code"}),
+		grpcDuration: factory.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "grpc_server_handling_seconds",
+			Help:    "Time until the RPC was handled.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"grpc_type", "grpc_service", "grpc_method"}),
+
+		cacheLookups: factory.NewCounterVec(prometheus.CounterOpts{
+			Name: "cache_lookups_total",
+			Help: "Cache lookups by key prefix, result is hit or miss.",
+		}, []string{"prefix", "result"}),
+	}
+}
+
+// For the domain metrics of the service, they get the "service" label as well
+func (m *Metrics) Registerer() prometheus.Registerer {
+	return m.registerer
+}
+
+// Prometheus exposition of every registered metric
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// GET /metrics, register it before any auth middleware
+func (m *Metrics) Register(router fiber.Router) {
+	router.Get("/metrics", adaptor.HTTPHandler(m.Handler()))
+}
+
+// Counts a lookup under the part of key before the first ":", e.g. "product" for "product:42"
+func (m *Metrics) CacheLookup(key string, hit bool) {
+	prefix, _, found := strings.Cut(key, ":")
+	if !found {
+		prefix = "other"
+	}
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	m.cacheLookups.WithLabelValues(prefix, result).Inc()
+}
@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Exposes pool.Stat() on every scrape
+func (m *Metrics) RegisterPgxPool(pool *pgxpool.Pool) error {
+	return m.registerer.Register(newPgxCollector(pool))
+}
+
+type pgxCollector struct {
+	pool *pgxpool.Pool
+
+	acquiredConns     *prometheus.Desc
+	idleConns         *prometheus.Desc
+	constructingConns *prometheus.Desc
+	totalConns        *prometheus.Desc
+	maxConns          *prometheus.Desc
+	acquires          *prometheus.Desc
+	acquireDuration   *prometheus.Desc
+	emptyAcquires     *prometheus.Desc
+	canceledAcquires  *prometheus.Desc
+}
+
+func newPgxCollector(pool *pgxpool.Pool) *pgxCollector {
+	return &pgxCollector{
+		pool:              pool,
+		acquiredConns:     prometheus.NewDesc("pgxpool_acquired_conns", "Connections in use.", nil, nil),
+		idleConns:         prometheus.NewDesc("pgxpool_idle_conns", "Connections waiting to be acquired.", nil, nil),
+		constructingConns: prometheus.NewDesc("pgxpool_constructing_conns", "Connections being opened.", nil, nil),
+		totalConns:        prometheus.NewDesc("pgxpool_total_conns", "Open connections.", nil, nil),
+		maxConns:          prometheus.NewDesc("pgxpool_max_conns", "Upper bound of open connections.", nil, nil),
+		acquires:          prometheus.NewDesc("pgxpool_acquires_total", "Successful acquires.", nil, nil),
+		acquireDuration:   prometheus.NewDesc("pgxpool_acquire_duration_seconds_total", "Time spent in successful acquires.", nil, nil),
+		emptyAcquires:     prometheus.NewDesc("pgxpool_empty_acquires_total", "Acquires that had to wait for a connection.", nil, nil),
+		canceledAcquires:  prometheus.NewDesc("pgxpool_canceled_acquires_total", "Acquires cancelled by their context.", nil, nil),
+	}
+}
+
+func (c *pgxCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.acquiredConns
+	ch <- c.idleConns
+	ch <- c.constructingConns
+	ch <- c.totalConns
+	ch <- c.maxConns
+	ch <- c.acquires
+	ch <- c.acquireDuration
+	ch <- c.emptyAcquires
+	ch <- c.canceledAcquires
+}
+
+func (c *pgxCollector) Collect(ch chan<- prometheus.Metric) {
+	stat := c.pool.Stat()
+	ch <- prometheus.MustNewConstMetric(c.acquiredConns, prometheus.GaugeValue, float64(stat.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(c.idleConns, prometheus.GaugeValue, float64(stat.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(c.constructingConns, prometheus.GaugeValue, float64(stat.ConstructingConns()))
+	ch <- prometheus.MustNewConstMetric(c.totalConns, prometheus.GaugeValue, float64(stat.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(c.maxConns, prometheus.GaugeValue, float64(stat.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(c.acquires, prometheus.CounterValue, float64(stat.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.acquireDuration, prometheus.CounterValue, stat.AcquireDuration().Seconds())
+	ch <- prometheus.MustNewConstMetric(c.emptyAcquires, prometheus.CounterValue, float64(stat.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.canceledAcquires, prometheus.CounterValue, float64(stat.CanceledAcquireCount()))
+}
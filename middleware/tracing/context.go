@@ -0,0 +1,24 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Fiber locals key of the request span. Fiber contexts expose locals through Value, so a
+// c.Context() handed to a repository or gRPC client still leads to the span
+type spanKey struct{}
+
+// Returns ctx carrying the request span when ctx is a fiber request context without one.
+// The gRPC, pgx and redis instrumentation call it, handlers don't have to
+func Context(ctx context.Context) context.Context {
+	if trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx
+	}
+	if span, ok := ctx.Value(spanKey{}).(trace.Span); ok {
+		return trace.ContextWithSpan(ctx, span)
+	}
+
+	return ctx
+}
@@ -0,0 +1,91 @@
+package tracing
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Probes and scrapes, they would drown the real requests
+var skippedPaths = map[string]bool{
+	"/healthz": true,
+	"/readyz":  true,
+	"/metrics": true,
+}
+
+// Starts a server span per request, continuing the trace of the traceparent header. The span
+// is in c.UserContext() and reachable from c.Context() through Context. It is named after
+// the matched route and marked as failed on 5xx
+func Fiber() fiber.Handler {
+	tracer := otel.Tracer(instrumentationName)
+
+	return func(c *fiber.Ctx) error {
+		if skippedPaths[c.Path()] {
+			return c.Next()
+		}
+
+		// fiber strings point into the request buffer, spans are exported after it was reused
+		method := strings.Clone(c.Method())
+		ctx := otel.GetTextMapPropagator().Extract(c.UserContext(), headerCarrier{&c.Request().Header})
+		ctx, span := tracer.Start(ctx, method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(method),
+				semconv.URLPath(strings.Clone(c.Path())),
+			),
+		)
+		defer span.End()
+
+		c.SetUserContext(ctx)
+		c.Locals(spanKey{}, span)
+
+		err := c.Next()
+
+		status := c.Response().StatusCode()
+		if err != nil {
+			// the error handler writes the response after this middleware returned
+			status = fiber.StatusInternalServerError
+			var fiberErr *fiber.Error
+			if errors.As(err, &fiberErr) {
+				status = fiberErr.Code
+			}
+			span.RecordError(err)
+		}
+
+		// unmatched requests get a route made of the raw path
+		route := strings.Clone(c.Route().Path)
+		span.SetName(method + " " + route)
+		span.SetAttributes(semconv.HTTPRoute(route), semconv.HTTPResponseStatusCode(status))
+		if status >= fiber.StatusInternalServerError {
+			span.SetStatus(codes.Error, fasthttp.StatusMessage(status))
+		}
+
+		return err
+	}
+}
+
+type headerCarrier struct {
+	header *fasthttp.RequestHeader
+}
+
+func (hc headerCarrier) Get(key string) string {
+	return string(hc.header.Peek(key))
+}
+
+func (hc headerCarrier) Set(key, value string) {
+	hc.header.Set(key, value)
+}
+
+func (hc headerCarrier) Keys() []string {
+	keys := []string{}
+	hc.header.VisitAll(func(key, _ []byte) {
+		keys = append(keys, string(key))
+	})
+	return keys
+}
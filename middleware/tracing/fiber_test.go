@@ -0,0 +1,136 @@
+package tracing
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	traceId     = "4bf92f3577b34da6a3ce929d0e0e4736"
+	parentId    = "00f067aa0ba902b7"
+)
+
+func serve(t *testing.T, app *fiber.App, path string, header string) {
+	t.Helper()
+	req := httptest.NewRequest("GET", path, nil)
+	if header != "" {
+		req.Header.Set("traceparent", header)
+	}
+	if _, err := app.Test(req, -1); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func attributeOf(span sdktrace.ReadOnlySpan, key attribute.Key) attribute.Value {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestFiberContinuesTrace(t *testing.T) {
+	spans := recordSpans()
+
+	var userContext, bridged trace.SpanContext
+	app := fiber.New()
+	app.Use(Fiber())
+	app.Get("/items/:id", func(c *fiber.Ctx) error {
+		userContext = trace.SpanContextFromContext(c.UserContext())
+		bridged = trace.SpanContextFromContext(Context(c.Context()))
+		return c.SendString("ok")
+	})
+
+	serve(t, app, "/items/42", traceparent)
+
+	ended := spans()
+	if len(ended) != 1 {
+		t.Fatalf("expected one span, got %d", len(ended))
+	}
+	span := ended[0]
+	if span.Name() != "GET /items/:id" {
+		t.Fatalf("expected the route as name, got %q", span.Name())
+	}
+	if span.SpanKind() != trace.SpanKindServer {
+		t.Fatalf("expected a server span, got %s", span.SpanKind())
+	}
+	if span.SpanContext().TraceID().String() != traceId || span.Parent().SpanID().String() != parentId {
+		t.Fatalf("expected the incoming trace to continue, got %s/%s", span.SpanContext().TraceID(), span.Parent().SpanID())
+	}
+	if code := attributeOf(span, "http.response.status_code").AsInt64(); code != 200 {
+		t.Fatalf("expected status 200, got %d", code)
+	}
+	if userContext.SpanID() != span.SpanContext().SpanID() {
+		t.Fatal("expected the span in the user context")
+	}
+	if bridged.SpanID() != span.SpanContext().SpanID() {
+		t.Fatal("expected the span to be reachable from the fiber context")
+	}
+}
+
+func TestFiberServerErrors(t *testing.T) {
+	app := fiber.New()
+	app.Use(Fiber())
+	app.Get("/unavailable", func(c *fiber.Ctx) error {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "later")
+	})
+	app.Get("/broken", func(c *fiber.Ctx) error {
+		return errors.New("broken")
+	})
+	app.Get("/missing", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusNotFound)
+	})
+
+	tests := []struct {
+		path   string
+		code   int64
+		status codes.Code
+	}{
+		{path: "/unavailable", code: 503, status: codes.Error},
+		{path: "/broken", code: 500, status: codes.Error},
+		{path: "/missing", code: 404, status: codes.Unset},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			spans := recordSpans()
+			serve(t, app, tt.path, "")
+
+			ended := spans()
+			if len(ended) != 1 {
+				t.Fatalf("expected one span, got %d", len(ended))
+			}
+			if code := attributeOf(ended[0], "http.response.status_code").AsInt64(); code != tt.code {
+				t.Fatalf("expected status %d, got %d", tt.code, code)
+			}
+			if status := ended[0].Status().Code; status != tt.status {
+				t.Fatalf("expected span status %s, got %s", tt.status, status)
+			}
+		})
+	}
+}
+
+func TestFiberSkipsProbes(t *testing.T) {
+	spans := recordSpans()
+
+	app := fiber.New()
+	app.Use(Fiber())
+	app.Get("/readyz", func(c *fiber.Ctx) error {
+		return c.SendString("ok")
+	})
+
+	serve(t, app, "/readyz", "")
+
+	if ended := spans(); len(ended) != 0 {
+		t.Fatalf("expected no span, got %d", len(ended))
+	}
+}
@@ -0,0 +1,89 @@
+package tracing
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const testMethod = "/test.Service/Call"
+
+// Serves every method with an echo and reports the span context the server saw
+func echoServer(t *testing.T) (*grpc.ClientConn, <-chan trace.SpanContext) {
+	t.Helper()
+	seen := make(chan trace.SpanContext, 1)
+	server := grpc.NewServer(append(ServerOptions(), grpc.UnknownServiceHandler(func(srv any, stream grpc.ServerStream) error {
+		seen <- trace.SpanContextFromContext(stream.Context())
+		var msg emptypb.Empty
+		if err := stream.RecvMsg(&msg); err != nil {
+			return err
+		}
+		return stream.SendMsg(&msg)
+	}))...)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient(listener.Addr().String(), append(ClientOptions(), grpc.WithTransportCredentials(insecure.NewCredentials()))...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return conn, seen
+}
+
+// A handler passing c.Context() to a gRPC client continues the request trace on the server
+func TestGrpcPropagatesFromFiber(t *testing.T) {
+	conn, seen := echoServer(t)
+	spans := recordSpans()
+
+	var request trace.SpanContext
+	app := fiber.New()
+	app.Use(Fiber())
+	app.Get("/purchase", func(c *fiber.Ctx) error {
+		request = trace.SpanContextFromContext(c.UserContext())
+		return conn.Invoke(c.Context(), testMethod, &emptypb.Empty{}, &emptypb.Empty{})
+	})
+
+	serve(t, app, "/purchase", traceparent)
+
+	server := <-seen
+	if server.TraceID().String() != traceId {
+		t.Fatalf("expected the server to continue trace %s, got %s", traceId, server.TraceID())
+	}
+
+	var client trace.SpanContext
+	for _, span := range spans() {
+		if span.SpanKind() == trace.SpanKindClient {
+			client = span.SpanContext()
+			if span.Parent().SpanID() != request.SpanID() {
+				t.Fatal("expected the client span to be a child of the request span")
+			}
+		}
+	}
+	if !client.IsValid() {
+		t.Fatal("expected a client span")
+	}
+}
+
+func TestGrpcWithoutTrace(t *testing.T) {
+	conn, seen := echoServer(t)
+
+	if err := conn.Invoke(context.Background(), testMethod, &emptypb.Empty{}, &emptypb.Empty{}); err != nil {
+		t.Fatal(err)
+	}
+	if server := <-seen; !server.IsValid() {
+		t.Fatal("expected the server to start a trace")
+	}
+}
@@ -0,0 +1,44 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/exaring/otelpgx"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// pgx tracer hook starting a span per query, batch, copy and pool acquire.
+// Set it on pgxConfig.ConnConfig.Tracer before the pool is created
+func PgxTracer() pgx.QueryTracer {
+	return &pgxTracer{Tracer: otelpgx.NewTracer(otelpgx.WithTrimSQLInSpanName())}
+}
+
+// Bridges fiber request contexts, the end hooks get the context returned by the start hooks
+type pgxTracer struct {
+	*otelpgx.Tracer
+}
+
+func (t *pgxTracer) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return t.Tracer.TraceQueryStart(Context(ctx), conn, data)
+}
+
+func (t *pgxTracer) TraceBatchStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceBatchStartData) context.Context {
+	return t.Tracer.TraceBatchStart(Context(ctx), conn, data)
+}
+
+func (t *pgxTracer) TraceCopyFromStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceCopyFromStartData) context.Context {
+	return t.Tracer.TraceCopyFromStart(Context(ctx), conn, data)
+}
+
+func (t *pgxTracer) TracePrepareStart(ctx context.Context, conn *pgx.Conn, data pgx.TracePrepareStartData) context.Context {
+	return t.Tracer.TracePrepareStart(Context(ctx), conn, data)
+}
+
+func (t *pgxTracer) TraceConnectStart(ctx context.Context, data pgx.TraceConnectStartData) context.Context {
+	return t.Tracer.TraceConnectStart(Context(ctx), data)
+}
+
+func (t *pgxTracer) TraceAcquireStart(ctx context.Context, pool *pgxpool.Pool, data pgxpool.TraceAcquireStartData) context.Context {
+	return t.Tracer.TraceAcquireStart(Context(ctx), pool, data)
+}
@@ -0,0 +1,34 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/extra/redisotel/v9"
+	"github.com/redis/go-redis/v9"
+)
+
+// Adds a span per command and pipeline to rdb
+func InstrumentRedis(rdb redis.UniversalClient) error {
+	// hooks run in the order they were added, the bridge has to come first
+	rdb.AddHook(redisContextHook{})
+	return redisotel.InstrumentTracing(rdb)
+}
+
+// Bridges fiber request contexts for the redisotel hook
+type redisContextHook struct{}
+
+func (redisContextHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (redisContextHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		return next(Context(ctx), cmd)
+	}
+}
+
+func (redisContextHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		return next(Context(ctx), cmds)
+	}
+}
@@ -0,0 +1,82 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOtlp   = "otlp"
+)
+
+// Name of the tracer used by the fiber middleware
+const instrumentationName = "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/tracing"
+
+type Config struct {
+	// service.name of every span, e.g. "purchase"
+	ServiceName string
+	// "otlp", "stdout" for local runs, or "none"/"" to only forward the trace context
+	Exporter string
+	// URL of the OTLP gRPC collector, e.g. http://otel-collector:4317. An http:// URL
+	// connects without TLS. OTEL_EXPORTER_OTLP_ENDPOINT is used when empty
+	Endpoint string
+	// Share of new traces that are recorded, 0 < ratio <= 1. Calls with a sampled parent are always recorded
+	SampleRatio float64
+}
+
+// Installs the global tracer provider and the W3C trace context propagator. The returned
+// function flushes the spans still buffered, call it on shutdown after the servers drained
+func Setup(ctx context.Context, cfg Config) (shutdown func(context.Context) error, err error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	switch cfg.Exporter {
+	case "", ExporterNone:
+		// without a provider spans are not recorded, incoming trace ids are still forwarded
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case ExporterOtlp:
+		options := []otlptracegrpc.Option{}
+		if cfg.Endpoint != "" {
+			options = append(options, otlptracegrpc.WithEndpointURL(cfg.Endpoint))
+		}
+		exporter, err = otlptracegrpc.New(ctx, options...)
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", cfg.Exporter)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(cfg.ServiceName),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	ratio := cfg.SampleRatio
+	if ratio <= 0 || ratio > 1 {
+		ratio = 1
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter, sdktrace.WithBatchTimeout(5*time.Second)),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
@@ -0,0 +1,59 @@
+package tracing
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// The global provider can only be replaced once, every test reads the same recorder
+var recorder = tracetest.NewSpanRecorder()
+
+func TestMain(m *testing.M) {
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	os.Exit(m.Run())
+}
+
+// Returns the spans ended after recordSpans was called
+func recordSpans() func() []sdktrace.ReadOnlySpan {
+	start := len(recorder.Ended())
+	return func() []sdktrace.ReadOnlySpan {
+		return recorder.Ended()[start:]
+	}
+}
+
+func TestSetup(t *testing.T) {
+	tests := []struct {
+		name     string
+		exporter string
+		fails    bool
+	}{
+		{name: "disabled", exporter: ""},
+		{name: "none", exporter: ExporterNone},
+		{name: "unknown", exporter: "zipkin", fails: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shutdown, err := Setup(context.Background(), Config{ServiceName: "test", Exporter: tt.exporter})
+			if tt.fails {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := shutdown(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
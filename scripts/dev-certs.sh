@@ -0,0 +1,46 @@
+#!/usr/bin/env bash
+# Generates a local CA and one certificate per service for gRPC mTLS.
+#
+#   scripts/dev-certs.sh [out dir] [extra SAN ...]
+#
+# Every certificate is valid as server and client, its CN is the service name and
+# its SANs cover <service>, <service>-service, localhost and 127.0.0.1 plus the
+# extra SANs (e.g. DNS:host.docker.internal). Running it again keeps the CA and
+# reissues the service certs, which the services pick up without a restart.
+set -euo pipefail
+
+OUT_DIR="${1:-.certs}"
+shift || true
+SERVICES=(user product purchase file)
+DAYS=825
+
+mkdir -p "$OUT_DIR"
+cd "$OUT_DIR"
+
+if [[ ! -f ca.key || ! -f ca.crt ]]; then
+  openssl req -x509 -newkey rsa:4096 -sha256 -nodes -days 3650 \
+    -subj "/CN=tutuplapak-dev-ca" \
+    -keyout ca.key -out ca.crt
+  echo "> created CA in $OUT_DIR/ca.crt"
+fi
+
+for service in "${SERVICES[@]}"; do
+  san="DNS:${service},DNS:${service}-service,DNS:localhost,IP:127.0.0.1"
+  for extra in "$@"; do
+    san="${san},${extra}"
+  done
+
+  openssl req -newkey rsa:2048 -sha256 -nodes \
+    -subj "/CN=${service}" \
+    -keyout "${service}.key.tmp" -out "${service}.csr"
+  openssl x509 -req -sha256 -days "$DAYS" \
+    -in "${service}.csr" -CA ca.crt -CAkey ca.key -CAcreateserial \
+    -extfile <(printf "subjectAltName=%s\nextendedKeyUsage=serverAuth,clientAuth\nkeyUsage=digitalSignature,keyEncipherment\n" "$san") \
+    -out "${service}.crt.tmp"
+
+  # Replaced in one step each so a running service never reads a half written file
+  mv "${service}.key.tmp" "${service}.key"
+  mv "${service}.crt.tmp" "${service}.crt"
+  rm -f "${service}.csr"
+  echo "> issued $OUT_DIR/${service}.crt ($san)"
+done
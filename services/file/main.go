@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/signal"
@@ -8,6 +9,11 @@ import (
 	"sync"
 	"syscall"
 
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/health"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/metrics"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/tracing"
+	"github.com/TimDebug/TutupLapak/File/src/cache"
+	"github.com/TimDebug/TutupLapak/File/src/config"
 	"github.com/TimDebug/TutupLapak/File/src/database/migrations"
 	"github.com/TimDebug/TutupLapak/File/src/database/postgres"
 	"github.com/TimDebug/TutupLapak/File/src/grpc"
@@ -16,55 +22,104 @@ import (
 )
 
 func main() {
-	var wg sync.WaitGroup
-
 	// Initialize logger
 	err := log.Init()
 	if err != nil {
-		log.Logger.Fatal().Err(err).Msg("unable to init basic logger")
+		fmt.Fprintf(os.Stderr, "unable to init basic logger: %v\n", err)
+		os.Exit(1)
+	}
+	log.Logger.Info(context.Background(), "loaded basic configuration")
+	log.Logger.Info(context.Background(), "configured basic logger")
+	log.Logger.Info(context.Background(), "runtime", "numCPU", runtime.NumCPU())
+
+	// tracing before the DB and redis clients, they pick up the tracer provider
+	shutdownTracing, err := tracing.Setup(context.Background(), config.GetConfig().Tracing)
+	if err != nil {
+		log.Logger.Fatal(context.Background(), "unable to setup tracing", "error", err)
 	}
-	log.Logger.Info().Msg("loaded basic configuration")
-	log.Logger.Info().Msg("configured basic logger")
-	log.Logger.Info().Msg(fmt.Sprintf("NumCPU: %d", runtime.NumCPU()))
 
 	// Auto migrate
 	err = migrations.Migrate()
 	if err != nil {
-		log.Logger.Fatal().Err(err).Msg("unable to run migration files")
+		log.Logger.Fatal(context.Background(), "unable to run migration files", "error", err)
 	}
-	log.Logger.Info().Msg("successfully run migration files")
+	log.Logger.Info(context.Background(), "successfully run migration files")
 
 	// initialize DB
 	db, err := postgres.NewPgxConnect()
 	if err != nil {
-		log.Logger.Fatal().Err(err).Msg("unable to make new DB connection")
+		log.Logger.Fatal(context.Background(), "unable to make new DB connection", "error", err)
+	}
+
+	// readiness checks shared by GET /readyz and grpc.health.v1
+	appConfig := config.GetConfig()
+	redis := cache.NewRedisClient()
+	checker := health.New(appConfig.HealthCheckTimeout).
+		Add("postgres", db.Ping).
+		Add("redis", redis.Ping)
+
+	// served by the http server on GET /metrics, the grpc server records into it as well
+	appMetrics := metrics.New("file")
+	if err := appMetrics.RegisterPgxPool(db); err != nil {
+		log.Logger.Fatal(context.Background(), "unable to register pgx pool metrics", "error", err)
 	}
 
 	// Handle graceful shutdown
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
-	go func() {
-		oscall := <-quit
-		log.Logger.Warn().Msgf("system call:%+v", oscall)
-		log.Cleanup()
-		os.Exit(0)
-	}()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
+	httpSrv := httpServer.NewHttpServer(db, checker, appMetrics)
+	grpcSrv, err := grpc.NewGrpcServer(db, checker, appMetrics)
+	if err != nil {
+		log.Logger.Fatal(context.Background(), "unable to create grpc server", "error", err)
+	}
+
+	errs := make(chan error, 2)
 	// Run http server
-	wg.Add(1)
+	go func() { errs <- httpSrv.Listen() }()
+	// run grpc server
+	go func() { errs <- grpcSrv.Listen() }()
+
+	select {
+	case <-ctx.Done():
+		log.Logger.Warn(context.Background(), "shutdown signal received")
+	case err := <-errs:
+		log.Logger.Error(context.Background(), "server stopped", "error", err)
+	}
+
+	// readiness fails first so no new traffic is routed here, then the running requests and
+	// queued uploads drain and only then the connections are closed. Bounded by SHUTDOWN_TIMEOUT
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), appConfig.ShutdownTimeout)
+	defer cancel()
+	checker.Shutdown()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
 	go func() {
 		defer wg.Done()
-		server := httpServer.HttpServer{DB: db}
-		server.Listen()
+		if err := httpSrv.Shutdown(shutdownCtx); err != nil {
+			log.Logger.Error(shutdownCtx, "unable to drain http server and uploads", "error", err)
+		}
 	}()
-
-	// run grpc server
-	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		server := grpc.GrpcServer{DB: db}
-		server.Listen()
+		if err := grpcSrv.Shutdown(shutdownCtx); err != nil {
+			log.Logger.Error(shutdownCtx, "unable to drain grpc server", "error", err)
+		}
 	}()
-
 	wg.Wait()
+
+	if err := redis.Close(); err != nil {
+		log.Logger.Error(shutdownCtx, "unable to close redis", "error", err)
+	}
+	if err := postgres.Close(shutdownCtx, db); err != nil {
+		log.Logger.Error(shutdownCtx, "unable to close DB connections", "error", err)
+	}
+	// flush the spans of the drained requests
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		log.Logger.Error(shutdownCtx, "unable to flush traces", "error", err)
+	}
+
+	log.Logger.Info(shutdownCtx, "shutdown complete")
+	log.Cleanup()
 }
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"sync"
 
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/auth"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/tracing"
 	"github.com/TimDebug/TutupLapak/File/src/config"
 	"github.com/TimDebug/TutupLapak/File/src/logger"
 	"github.com/TimDebug/TutupLapak/File/src/models"
@@ -24,27 +26,44 @@ var (
 
 func NewRedisClient() *RedisClient {
 	redisOnce.Do(func() {
-		redisInstance = &RedisClient{
-			client: redis.NewClient(&redis.Options{
-				Addr: fmt.Sprintf(
-					"%s:%s", appConfig.RedisHost, appConfig.RedisPort,
-				),
-				Password: "",
-				DB:       0,
-			}),
+		client := redis.NewClient(&redis.Options{
+			Addr: fmt.Sprintf(
+				"%s:%s", appConfig.RedisHost, appConfig.RedisPort,
+			),
+			Password: "",
+			DB:       0,
+		})
+		// span per command, child of the request span
+		if err := tracing.InstrumentRedis(client); err != nil {
+			logger.Logger.Fatal(context.Background(), "failed to instrument redis", "error", err)
 		}
+		redisInstance = &RedisClient{client: client}
 	})
 
 	// ping the client
 	ctx := context.Background()
 	_, err := redisInstance.client.Ping(ctx).Result()
 	if err != nil {
-		logger.Logger.Fatal().Err(err).Msg("failed to connect to redis")
+		logger.Logger.Fatal(ctx, "failed to connect to redis", "error", err)
 	}
 
 	return redisInstance
 }
 
+// Tokens the user service revoked, shared through the same redis
+func (c *RedisClient) Revocations() auth.RevocationChecker {
+	return auth.NewRedisRevocationList(c.client)
+}
+
+// used by the readiness check
+func (c *RedisClient) Ping(ctx context.Context) error {
+	return c.client.Ping(ctx).Err()
+}
+
+func (c *RedisClient) Close() error {
+	return c.client.Close()
+}
+
 func (c *RedisClient) Set(ctx context.Context, entity *models.FileEntity) error {
 	// map file entity to map[string]string
 	contents := map[string]string{
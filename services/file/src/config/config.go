@@ -3,9 +3,13 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/logging"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/tracing"
 	"github.com/joho/godotenv"
 )
 
@@ -17,12 +21,34 @@ type Configuration struct {
 	AutoMigrate         bool
 	MigrateFileLocation string
 	IsProduction        bool
+	IsDebug             bool // MODE=DEBUG or empty, only then the gRPC server may run without a service token or mTLS
 	AWSAccessKey        string
 	AWSSecretAccessKey  string
 	AWSRegion           string
 	AWSBucket           string
 	RedisHost           string
 	RedisPort           string
+	// Shared secret callers send, certificate names accepted over mTLS and the deadline of calls without one
+	GRPCServiceToken   string
+	GRPCAllowedPeers   []string
+	GRPCDefaultTimeout time.Duration
+	// mTLS for the gRPC server, plaintext when no path is set and a startup error when only some are
+	GRPCTLSCertFile       string
+	GRPCTLSKeyFile        string
+	GRPCTLSCAFile         string
+	GRPCTLSReloadInterval time.Duration
+	// Upper bound of one readiness run and how often grpc.health.v1 follows it
+	HealthCheckTimeout time.Duration
+	GRPCHealthInterval time.Duration
+	// Upper bound of the whole shutdown after SIGTERM, below the 30s stop timeout of ECS
+	ShutdownTimeout time.Duration
+	// JWKS document of the user service, verifies the tokens of the admin routes
+	JWKSUrl      string
+	JWKSCacheTTL time.Duration
+	// OTEL_EXPORTER: "otlp", "stdout" for local runs, or "none" to only continue the caller's trace
+	Tracing tracing.Config
+	// LOG_LEVEL defaults to debug, or error in production where stdout is off as well
+	Logging logging.Config
 }
 
 var (
@@ -38,19 +64,34 @@ func GetConfig() *Configuration {
 			return
 		}
 		instance = &Configuration{
-			Port:                getPort(),
-			GRPCPort:            getEnv("GRPC_PORT", "5000"),
-			DBConnection:        getDBConnection(),
-			DBConnectionMigrate: getDBConnectionMigrate(),
-			AutoMigrate:         getAutoMigrate(),
-			MigrateFileLocation: getLocationMigrate(),
-			IsProduction:        isProduction(),
-			AWSAccessKey:        getEnv("AWS_ACCESS_KEY_ID", ""),
-			AWSSecretAccessKey:  getEnv("AWS_SECRET_ACCESS_KEY", ""),
-			AWSRegion:           getEnv("AWS_REGION", ""),
-			AWSBucket:           getEnv("AWS_BUCKET", ""),
-			RedisHost:           getEnv("REDIS_HOST", ""),
-			RedisPort:           getEnv("REDIS_PORT", ""),
+			Port:                  getPort(),
+			GRPCPort:              getEnv("GRPC_PORT", "5000"),
+			DBConnection:          getDBConnection(),
+			DBConnectionMigrate:   getDBConnectionMigrate(),
+			AutoMigrate:           getAutoMigrate(),
+			MigrateFileLocation:   getLocationMigrate(),
+			IsProduction:          isProduction(),
+			IsDebug:               isDebug(),
+			AWSAccessKey:          getEnv("AWS_ACCESS_KEY_ID", ""),
+			AWSSecretAccessKey:    getEnv("AWS_SECRET_ACCESS_KEY", ""),
+			AWSRegion:             getEnv("AWS_REGION", ""),
+			AWSBucket:             getEnv("AWS_BUCKET", ""),
+			RedisHost:             getEnv("REDIS_HOST", ""),
+			RedisPort:             getEnv("REDIS_PORT", ""),
+			GRPCServiceToken:      getEnv("GRPC_SERVICE_TOKEN", ""),
+			GRPCAllowedPeers:      getList("GRPC_ALLOWED_PEERS"),
+			GRPCDefaultTimeout:    getDuration("GRPC_DEFAULT_TIMEOUT", 10*time.Second),
+			GRPCTLSCertFile:       getEnv("GRPC_TLS_CERT_FILE", ""),
+			GRPCTLSKeyFile:        getEnv("GRPC_TLS_KEY_FILE", ""),
+			GRPCTLSCAFile:         getEnv("GRPC_TLS_CA_FILE", ""),
+			GRPCTLSReloadInterval: getDuration("GRPC_TLS_RELOAD_INTERVAL", 30*time.Second),
+			HealthCheckTimeout:    getDuration("HEALTH_CHECK_TIMEOUT", 2*time.Second),
+			GRPCHealthInterval:    getDuration("GRPC_HEALTH_INTERVAL", 10*time.Second),
+			ShutdownTimeout:       getDuration("SHUTDOWN_TIMEOUT", 20*time.Second),
+			JWKSUrl:               getEnv("JWKS_URL", "http://localhost:8080/v1/auth/jwks"),
+			JWKSCacheTTL:          getDuration("JWKS_CACHE_TTL", 10*time.Minute),
+			Tracing:               getTracing(),
+			Logging:               getLogging(),
 		}
 	})
 	return instance
@@ -64,6 +105,53 @@ func getEnv(key string, defaultValue string) string {
 	return value
 }
 
+func getDuration(key string, defaultValue time.Duration) time.Duration {
+	value, err := time.ParseDuration(getEnv(key, ""))
+	if err != nil || value <= 0 {
+		return defaultValue
+	}
+	return value
+}
+
+// Comma separated values without the empty ones
+func getList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(getEnv(key, ""), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func getTracing() tracing.Config {
+	ratio, err := strconv.ParseFloat(getEnv("OTEL_TRACES_SAMPLE_RATIO", "1"), 64)
+	if err != nil {
+		ratio = 1
+	}
+
+	return tracing.Config{
+		ServiceName: getEnv("OTEL_SERVICE_NAME", "file"),
+		Exporter:    getEnv("OTEL_EXPORTER", tracing.ExporterNone),
+		Endpoint:    getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", ""),
+		SampleRatio: ratio,
+	}
+}
+
+func getLogging() logging.Config {
+	level, stdout := "debug", "true"
+	if isProduction() {
+		level, stdout = "error", "false"
+	}
+
+	return logging.Config{
+		Service: "file",
+		Level:   getEnv("LOG_LEVEL", level),
+		File:    getEnv("LOG_FILE", "log/app.log"),
+		Stdout:  getEnv("LOG_STDOUT", stdout) == "true",
+	}
+}
+
 func getPort() string {
 	return getEnv("PORT", "3000")
 }
@@ -99,3 +187,8 @@ func getLocationMigrate() string {
 func isProduction() bool {
 	return strings.ToUpper(getEnv("MODE", "DEBUG")) == "PRODUCTION"
 }
+
+func isDebug() bool {
+	mode := strings.ToUpper(getEnv("MODE", "DEBUG"))
+	return mode == "" || mode == "DEBUG"
+}
@@ -1,6 +0,0 @@
-package config
-
-type RequestKeyType string
-
-var RequestKey RequestKeyType = "request_id"
-var ElapsedKey RequestKeyType = "elapsed_time"
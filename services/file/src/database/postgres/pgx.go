@@ -6,6 +6,7 @@ import (
 	"log"
 	"strconv"
 
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/tracing"
 	"github.com/TimDebug/TutupLapak/File/src/config"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -14,8 +15,15 @@ func NewPgxConnect() (*pgxpool.Pool, error) {
 	DbString := config.GetConfig().DBConnection
 	fmt.Printf("Database connection string: %s\n", DbString)
 
+	pgxConfig, err := pgxpool.ParseConfig(DbString)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse connection string: %w", err)
+	}
+	// span per query, child of the request or grpc call span
+	pgxConfig.ConnConfig.Tracer = tracing.PgxTracer()
+
 	// Create connection pool
-	db, err := pgxpool.New(context.Background(), DbString)
+	db, err := pgxpool.NewWithConfig(context.Background(), pgxConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create connection pool: %w", err)
 	}
@@ -35,3 +43,20 @@ func NewPgxConnect() (*pgxpool.Pool, error) {
 
 	return db, nil
 }
+
+// pgxpool.Close waits for every acquired connection to be released, Close gives up
+// on that when ctx is done so a stuck query can't hold the shutdown
+func Close(ctx context.Context, db *pgxpool.Pool) error {
+	done := make(chan struct{})
+	go func() {
+		db.Close()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
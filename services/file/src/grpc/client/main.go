@@ -3,10 +3,10 @@ package main
 import (
 	"context"
 	"fmt"
+	"log"
 	"time"
 
 	"github.com/TimDebug/TutupLapak/File/src/grpc/proto/model/file"
-	"github.com/TimDebug/TutupLapak/File/src/logger"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 )
@@ -17,8 +17,7 @@ func main() {
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
 	)
 	if err != nil {
-		logger.Logger.Fatal().Err(err).Msg("fatal initiating new grpc client")
-		return
+		log.Fatalf("fatal initiating new grpc client: %v", err)
 	}
 	defer conn.Close()
 
@@ -28,7 +27,7 @@ func main() {
 
 	response, err := fileClient.CheckExist(ctx, &file.FileRequest{FileId: "5a54fd34-57c9-42b4-b439-ee16903d68fc"})
 	if err != nil {
-		logger.Logger.Error().Err(err).Msg("check procedure returns error")
+		log.Printf("check procedure returns error: %v", err)
 		return
 	}
 	fmt.Printf("success: %+v\n", response)
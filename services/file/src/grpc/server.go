@@ -5,6 +5,10 @@ import (
 	"fmt"
 	"net"
 
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/grpcmw"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/health"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/metrics"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/tracing"
 	"github.com/TimDebug/TutupLapak/File/src/config"
 	"github.com/TimDebug/TutupLapak/File/src/grpc/proto/model/file"
 	"github.com/TimDebug/TutupLapak/File/src/logger"
@@ -18,9 +22,47 @@ var (
 )
 
 type GrpcServer struct {
-	DB *pgxpool.Pool
+	server *grpc.Server
 }
 
+func NewGrpcServer(db *pgxpool.Pool, checker *health.Checker, m *metrics.Metrics) (*GrpcServer, error) {
+	// mTLS when the certificate paths are configured, clients without a trusted cert are refused
+	tlsConfig := grpcmw.TLSConfig{
+		CertFile:       appConfig.GRPCTLSCertFile,
+		KeyFile:        appConfig.GRPCTLSKeyFile,
+		CAFile:         appConfig.GRPCTLSCAFile,
+		ReloadInterval: appConfig.GRPCTLSReloadInterval,
+		OnReloadError: func(err error) {
+			logger.Logger.Error(context.Background(), "failed to reload grpc tls certificates, keeping the previous ones", "error", err)
+		},
+	}
+	transport, err := grpcmw.ServerTransport(tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	// outside debug mode a server without a token or mTLS would accept anyone
+	cfg := serverConfig()
+	if !appConfig.IsDebug {
+		if err := cfg.RequireAuth(tlsConfig); err != nil {
+			return nil, err
+		}
+	}
+
+	// span per call, continues the trace of the caller, the same metrics as the http server,
+	// then auth, request id, deadline, recovery and access log
+	options := append(append(transport, tracing.ServerOptions()...), m.GrpcServerOptions()...)
+	server := grpc.NewServer(append(options, grpcmw.ServerOptions(cfg)...)...)
+	fileRepo := repo.NewFileRepository(db)
+	fileService := NewFileService(&fileRepo)
+	file.RegisterFileServiceServer(server, fileService)
+	// grpc.health.v1, follows the same readiness checks as GET /readyz
+	checker.RegisterGrpc(server, appConfig.GRPCHealthInterval)
+
+	return &GrpcServer{server: server}, nil
+}
+
+// Blocks until the server stops, returns nil after Shutdown
 func (g *GrpcServer) Listen() error {
 	serverConn := fmt.Sprintf("%s:%s", "0.0.0.0", appConfig.GRPCPort)
 	lis, err := net.Listen("tcp", serverConn)
@@ -28,16 +70,37 @@ func (g *GrpcServer) Listen() error {
 		return err
 	}
 
-	server := grpc.NewServer()
-	fileRepo := repo.NewFileRepository(g.DB)
-	fileService := NewFileService(&fileRepo)
-	file.RegisterFileServiceServer(server, fileService)
+	logger.Logger.Info(context.Background(), "grpc server listens", "address", serverConn)
+	return g.server.Serve(lis)
+}
 
-	go func() {
-		<-context.Background().Done()
-		server.GracefulStop()
-	}()
+// Waits for the running calls until ctx is done, then cancels the rest
+func (g *GrpcServer) Shutdown(ctx context.Context) error {
+	return grpcmw.GracefulStop(ctx, g.server)
+}
 
-	logger.Logger.Info().Msg(fmt.Sprintf("grpc server listens to: %s", serverConn))
-	return server.Serve(lis)
+func serverConfig() grpcmw.ServerConfig {
+	return grpcmw.ServerConfig{
+		Token:          appConfig.GRPCServiceToken,
+		AllowedPeers:   appConfig.GRPCAllowedPeers,
+		DefaultTimeout: appConfig.GRPCDefaultTimeout,
+		Log: func(ctx context.Context, entry grpcmw.AccessLog) {
+			// request_id comes from ctx, the logger attaches it
+			data := []interface{}{
+				"method", entry.Method,
+				"code", entry.Code.String(),
+				"duration", entry.Duration.String(),
+				"peer", entry.Peer,
+				"identity", entry.Identity,
+			}
+			if entry.Err != nil {
+				logger.Logger.Warn(ctx, "grpc call failed", append(data, "error", entry.Err)...)
+				return
+			}
+			logger.Logger.Info(ctx, "grpc call", data...)
+		},
+		OnPanic: func(ctx context.Context, method string, recovered any, stack []byte) {
+			logger.Logger.Error(ctx, "grpc handler panicked", "panic", fmt.Sprint(recovered), "method", method, "stack", string(stack))
+		},
+	}
 }
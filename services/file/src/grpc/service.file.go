@@ -2,7 +2,6 @@ package grpc
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/TimDebug/TutupLapak/File/src/grpc/proto/model/file"
 	"github.com/TimDebug/TutupLapak/File/src/logger"
@@ -29,7 +28,7 @@ func (f *fileService) CheckExist(ctx context.Context, req *file.FileRequest) (*f
 	}
 	entity, err := f.repo.GetRecordsById(ctx, fileId)
 	if err != nil {
-		logger.Logger.Error().Err(err).Msg(fmt.Sprintf("%+v", err))
+		logger.Logger.Error(ctx, "unable to get file record", "error", err, "fileId", fileId)
 		if err == pgx.ErrNoRows {
 			return nil, status.Errorf(codes.NotFound, "records not found")
 		}
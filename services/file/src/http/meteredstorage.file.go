@@ -0,0 +1,46 @@
+package httpServer
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Wraps a StorageClient with upload latency and failure metrics, the uploads run
+// in the background so their errors are not visible anywhere else
+type MeteredStorageClient struct {
+	StorageClient
+	uploadDuration *prometheus.HistogramVec
+	uploadFailures prometheus.Counter
+}
+
+func NewMeteredStorageClient(client StorageClient, registerer prometheus.Registerer) StorageClient {
+	factory := promauto.With(registerer)
+	return &MeteredStorageClient{
+		StorageClient: client,
+		uploadDuration: factory.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "s3_upload_duration_seconds",
+			Help:    "Time spent in PutFile, result is success or failure.",
+			Buckets: prometheus.ExponentialBuckets(0.025, 2, 10),
+		}, []string{"result"}),
+		uploadFailures: factory.NewCounter(prometheus.CounterOpts{
+			Name: "s3_upload_failures_total",
+			Help: "PutFile calls that returned an error.",
+		}),
+	}
+}
+
+func (m *MeteredStorageClient) PutFile(ctx context.Context, key string, mimeType string, fileContent []byte, isPublic bool) (string, error) {
+	start := time.Now()
+	url, err := m.StorageClient.PutFile(ctx, key, mimeType, fileContent, isPublic)
+
+	result := "success"
+	if err != nil {
+		result = "failure"
+		m.uploadFailures.Inc()
+	}
+	m.uploadDuration.WithLabelValues(result).Observe(time.Since(start).Seconds())
+	return url, err
+}
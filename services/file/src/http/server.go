@@ -1,29 +1,47 @@
 package httpServer
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"time"
 
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/auth"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/health"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/logging"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/metrics"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/tracing"
 	"github.com/TimDebug/TutupLapak/File/src/cache"
 	"github.com/TimDebug/TutupLapak/File/src/config"
 	"github.com/TimDebug/TutupLapak/File/src/http/middleware/errorHandler"
-	"github.com/TimDebug/TutupLapak/File/src/http/middleware/identifier"
 	"github.com/TimDebug/TutupLapak/File/src/logger"
 	"github.com/TimDebug/TutupLapak/File/src/repo"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type HttpServer struct {
-	DB *pgxpool.Pool
+	app     *fiber.App
+	service FileService
 }
 
-func (s *HttpServer) Listen() {
+func NewHttpServer(db *pgxpool.Pool, checker *health.Checker, m *metrics.Metrics) *HttpServer {
 	app := fiber.New(fiber.Config{
 		ServerHeader: "TIM-DEBUG",
 		ErrorHandler: errorHandler.ErrorHandler,
 	})
-	app.Use(identifier.RequestID)
+	app.Use(requestid.New())
+	// request id on every log entry of the request, see middleware/logging
+	app.Use(logging.Fiber())
+	// span per request, see middleware/tracing
+	app.Use(tracing.Fiber())
+	// liveness and readiness probes, registered before the access log to keep it quiet
+	checker.Register(app)
+	// RED, S3 upload and pgx pool metrics on GET /metrics, see middleware/metrics
+	m.Register(app)
+	app.Use(m.Fiber())
 	app.Use(cors.New(cors.Config{
 		AllowOrigins:     "*",
 		AllowCredentials: false,
@@ -38,14 +56,33 @@ func (s *HttpServer) Listen() {
 	} else {
 		storageClient = NewMockS3StorageClient()
 	}
-	repo := repo.NewFileRepository(s.DB)
+	storageClient = NewMeteredStorageClient(storageClient, m.Registerer())
+	repo := repo.NewFileRepository(db)
 	redis := cache.NewRedisClient()
 	service := NewFileService(repo, storageClient, redis)
-	defer service.Shutdown()
 	controller := NewFileController(service)
 
 	routes := app.Group("/v1")
 	routes.Post("/file", controller.Upload)
 
-	app.Listen(fmt.Sprintf("%s:%s", "0.0.0.0", appConfig.Port))
+	// GET and PUT /v1/admin/log-level of this instance, tokens come from the user service
+	admin := routes.Group("/admin", auth.New(auth.Config{
+		Keys:        auth.NewJwksClient(appConfig.JWKSUrl, appConfig.JWKSCacheTTL, &http.Client{Timeout: 5 * time.Second}),
+		Revocations: redis.Revocations(),
+	}), auth.RequireRole(auth.RoleAdmin))
+	logger.Logger.RegisterLevel(admin)
+
+	return &HttpServer{app: app, service: service}
+}
+
+func (s *HttpServer) Listen() error {
+	return s.app.Listen(fmt.Sprintf("%s:%s", "0.0.0.0", config.GetConfig().Port))
+}
+
+// Drains the running requests, then the S3 uploads they queued
+func (s *HttpServer) Shutdown(ctx context.Context) error {
+	if err := s.app.ShutdownWithContext(ctx); err != nil {
+		return err
+	}
+	return s.service.Shutdown(ctx)
 }
@@ -2,6 +2,7 @@ package httpServer
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"image"
 	"io"
@@ -51,14 +52,18 @@ func (fs *FileService) UploadFile(
 
 	thumbFileName := fmt.Sprintf("thumbnail-%s", targetFilename)
 
+	// the request context is recycled once the response is sent and cancelled on
+	// shutdown, the queued uploads have to outlive both
+	uploadCtx := context.Background()
+
 	fs.wp.Submit(func() {
-		fs.StorageClient.PutFile(ctx.Context(), targetFilename, mimetype, fileContent, true)
+		fs.StorageClient.PutFile(uploadCtx, targetFilename, mimetype, fileContent, true)
 	})
 
 	fs.wp.Submit(func() {
 		fileBuf, err := fs.compressImage(fileContent)
 		if err == nil {
-			fs.StorageClient.PutFile(ctx.Context(), thumbFileName, mimetype, fileBuf, true)
+			fs.StorageClient.PutFile(uploadCtx, thumbFileName, mimetype, fileBuf, true)
 		}
 	})
 
@@ -101,6 +106,19 @@ func (fs *FileService) decodeImage(data []byte) (image.Image, string, error) {
 	return image.Decode(reader)
 }
 
-func (fs *FileService) Shutdown() {
-	fs.wp.StopWait()
+// Waits for the queued uploads until ctx is done. Call it once the HTTP server drained,
+// the pool takes no tasks afterwards
+func (fs *FileService) Shutdown(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		fs.wp.StopWait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
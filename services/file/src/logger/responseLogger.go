@@ -3,28 +3,23 @@ package logger
 import (
 	"time"
 
-	"github.com/TimDebug/TutupLapak/File/src/config"
 	"github.com/gofiber/fiber/v2"
 )
 
 func ResponseLogger(ctx *fiber.Ctx) error {
+	startTime := time.Now()
 	err := ctx.Next()
-	requestID, _ := ctx.Locals(config.RequestKey).(string)
-	startTime, ok := ctx.Locals(config.ElapsedKey).(time.Time)
-	var elapsed time.Duration = 0
-	if ok {
-		elapsed = time.Since(startTime)
+	// UserContext carries the request id, see logging.Fiber
+	data := []any{
+		"method", ctx.Method(),
+		"path", ctx.Path(),
+		"status", ctx.Response().StatusCode(),
+		"elapsed", time.Since(startTime).String(),
 	}
-	logger := Logger.Info().
-		Str("request_id", requestID).
-		Str("method", ctx.Method()).
-		Str("path", ctx.Path()).
-		Int("status", ctx.Response().StatusCode()).
-		Dur("elapsed", elapsed)
 	if err != nil {
-		logger.Err(err).Msg("request completed with error")
+		Logger.Info(ctx.UserContext(), "request completed with error", append(data, "error", err)...)
 	} else {
-		logger.Msg("request processed")
+		Logger.Info(ctx.UserContext(), "request processed", data...)
 	}
 	return err
 }
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/di"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/repository"
+	"github.com/jackc/pgx/v5/pgxpool"
+	_ "github.com/joho/godotenv/autoload"
+	"github.com/samber/do/v2"
+)
+
+// Membandingkan products.qty dan product_variants.qty dengan total delta di inventory_ledger.
+// Jalankan dari folder services/product: go run ./cmd/reconcile-inventory [-fix]
+func main() {
+	fix := flag.Bool("fix", false, "set qty to the ledger total for drifted products and variants")
+	flag.Parse()
+
+	ctx := context.Background()
+	pool := do.MustInvoke[*pgxpool.Pool](di.Injector)
+	defer pool.Close()
+	inventoryRepo := do.MustInvoke[repository.InventoryRepoInterface](di.Injector)
+
+	drifts, err := inventoryRepo.GetDrift(ctx, pool)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read drift: %v\n", err)
+		os.Exit(1)
+	}
+
+	if len(drifts) == 0 {
+		fmt.Println("No drift found.")
+		return
+	}
+
+	fmt.Printf("%-40s %-40s %10s %10s %10s\n", "PRODUCT", "VARIANT", "QTY", "LEDGER", "DRIFT")
+	for _, drift := range drifts {
+		fmt.Printf("%-40s %-40s %10d %10d %10d\n", drift.ProductId, drift.VariantId, drift.Qty, drift.LedgerQty, drift.Qty-drift.LedgerQty)
+
+		if *fix {
+			if err := inventoryRepo.Reconcile(ctx, pool, drift.ProductId, drift.VariantId); err != nil {
+				fmt.Fprintf(os.Stderr, "failed to reconcile %s %s: %v\n", drift.ProductId, drift.VariantId, err)
+				os.Exit(1)
+			}
+		}
+	}
+
+	if *fix {
+		fmt.Printf("Reconciled %d product(s) and variant(s).\n", len(drifts))
+		return
+	}
+
+	// exit code 2 supaya bisa dipakai di cron/CI untuk mendeteksi drift
+	os.Exit(2)
+}
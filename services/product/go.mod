@@ -9,15 +9,51 @@ require (
 	github.com/golang-migrate/migrate/v4 v4.18.1
 	github.com/jackc/pgx/v5 v5.7.2
 	github.com/joho/godotenv v1.5.1
+	github.com/redis/go-redis/v9 v9.7.0
 	github.com/samber/do/v2 v2.0.0-beta.7
-	go.uber.org/zap v1.27.0
+	golang.org/x/sync v0.10.0
 	google.golang.org/grpc v1.64.1
 	google.golang.org/protobuf v1.36.4
-	gopkg.in/natefinch/lumberjack.v2 v2.2.1
 )
 
 require (
+	github.com/beorn7/perks v1.0.1 // indirect
+	github.com/cenkalti/backoff/v4 v4.3.0 // indirect
+	github.com/exaring/otelpgx v0.7.0 // indirect
+	github.com/go-logr/logr v1.4.2 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/grpc-ecosystem/grpc-gateway/v2 v2.20.0 // indirect
+	github.com/munnerz/goautoneg v0.0.0-20191010083416-a7dc8b61c822 // indirect
+	github.com/prometheus/client_golang v1.20.5 // indirect
+	github.com/prometheus/client_model v0.6.1 // indirect
+	github.com/prometheus/common v0.55.0 // indirect
+	github.com/prometheus/procfs v0.15.1 // indirect
+	github.com/redis/go-redis/extra/rediscmd/v9 v9.7.0 // indirect
+	github.com/redis/go-redis/extra/redisotel/v9 v9.7.0 // indirect
+	go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc v0.52.0 // indirect
+	go.opentelemetry.io/otel v1.29.0 // indirect
+	go.opentelemetry.io/otel/exporters/otlp/otlptrace v1.29.0 // indirect
+	go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc v1.28.0 // indirect
+	go.opentelemetry.io/otel/exporters/stdout/stdouttrace v1.28.0 // indirect
+	go.opentelemetry.io/otel/metric v1.29.0 // indirect
+	go.opentelemetry.io/otel/sdk v1.29.0 // indirect
+	go.opentelemetry.io/otel/trace v1.29.0 // indirect
+	go.opentelemetry.io/proto/otlp v1.3.1 // indirect
+	go.uber.org/zap v1.27.0 // indirect
+	google.golang.org/genproto/googleapis/api v0.0.0-20240701130421-f6361c86f094 // indirect
+	gopkg.in/natefinch/lumberjack.v2 v2.2.1 // indirect
+)
+
+require (
+	github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/auth v0.0.0
+	github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/grpcmw v0.0.0
+	github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/health v0.0.0
+	github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/logging v0.0.0
+	github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/metrics v0.0.0
+	github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/tracing v0.0.0
 	github.com/andybalholm/brotli v1.1.0 // indirect
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
+	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
 	github.com/gabriel-vasile/mimetype v1.4.8 // indirect
 	github.com/go-playground/locales v0.14.1 // indirect
 	github.com/go-playground/universal-translator v0.18.1 // indirect
@@ -49,8 +85,19 @@ require (
 	go.uber.org/multierr v1.11.0 // indirect
 	golang.org/x/crypto v0.32.0 // indirect
 	golang.org/x/net v0.34.0 // indirect
-	golang.org/x/sync v0.10.0 // indirect
 	golang.org/x/sys v0.29.0 // indirect
 	golang.org/x/text v0.21.0 // indirect
-	google.golang.org/genproto/googleapis/rpc v0.0.0-20240513163218-0867130af1f8 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240701130421-f6361c86f094 // indirect
 )
+
+replace github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/auth => ../../middleware/auth
+
+replace github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/grpcmw => ../../middleware/grpcmw
+
+replace github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/health => ../../middleware/health
+
+replace github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/logging => ../../middleware/logging
+
+replace github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/metrics => ../../middleware/metrics
+
+replace github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/tracing => ../../middleware/tracing
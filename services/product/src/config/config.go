@@ -1,6 +1,9 @@
 package config
 
-import "os"
+import (
+	"os"
+	"strings"
+)
 
 func getEnv(key string, defaultValue string) string {
 	value, exists := os.LookupEnv(key)
@@ -17,3 +20,9 @@ func GetPort() string {
 func GetPortGrpc() string {
 	return getEnv("PORTGRPC", "5001")
 }
+
+// MODE=DEBUG or empty, only then the gRPC server may run without a service token or mTLS
+func IsDebug() bool {
+	mode := strings.ToUpper(getEnv("MODE", "DEBUG"))
+	return mode == "" || mode == "DEBUG"
+}
@@ -0,0 +1,5 @@
+package config
+
+func GetFileServiceBaseUrl() string {
+	return getEnv("FILE_SERVICE_BASE_URL", "localhost:5000")
+}
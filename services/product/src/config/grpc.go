@@ -0,0 +1,52 @@
+package config
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/grpcmw"
+)
+
+// Shared secret between the services, sent by clients and required by the server when set
+func GetGrpcServiceToken() string {
+	return getEnv("GRPC_SERVICE_TOKEN", "")
+}
+
+// Client certificate names accepted over mTLS, comma separated
+func GetGrpcAllowedPeers() []string {
+	var peers []string
+	for _, name := range strings.Split(getEnv("GRPC_ALLOWED_PEERS", ""), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			peers = append(peers, name)
+		}
+	}
+	return peers
+}
+
+// Deadline of incoming calls that don't carry their own
+func GetGrpcDefaultTimeout() time.Duration {
+	timeout, err := time.ParseDuration(getEnv("GRPC_DEFAULT_TIMEOUT", "10s"))
+	if err != nil {
+		return 10 * time.Second
+	}
+	return timeout
+}
+
+// Certificate paths for mTLS between the services, plaintext when none is set, a startup error when only some are.
+// The files are reloaded when they change so certificates can rotate without a restart
+func GetGrpcTLS() grpcmw.TLSConfig {
+	reloadInterval, err := time.ParseDuration(getEnv("GRPC_TLS_RELOAD_INTERVAL", "30s"))
+	if err != nil {
+		reloadInterval = 30 * time.Second
+	}
+	return grpcmw.TLSConfig{
+		CertFile: getEnv("GRPC_TLS_CERT_FILE", ""),
+		KeyFile:  getEnv("GRPC_TLS_KEY_FILE", ""),
+		CAFile:   getEnv("GRPC_TLS_CA_FILE", ""),
+		OnReloadError: func(err error) {
+			log.Printf("Failed to reload gRPC TLS certificates, keeping the previous ones: %v", err)
+		},
+		ReloadInterval: reloadInterval,
+	}
+}
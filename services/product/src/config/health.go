@@ -0,0 +1,21 @@
+package config
+
+import "time"
+
+// Upper bound of one readiness run, all dependencies are checked concurrently
+func GetHealthCheckTimeout() time.Duration {
+	timeout, err := time.ParseDuration(getEnv("HEALTH_CHECK_TIMEOUT", "2s"))
+	if err != nil {
+		return 2 * time.Second
+	}
+	return timeout
+}
+
+// How often the grpc.health.v1 status is refreshed from the readiness checks
+func GetGrpcHealthInterval() time.Duration {
+	interval, err := time.ParseDuration(getEnv("GRPC_HEALTH_INTERVAL", "10s"))
+	if err != nil {
+		return 10 * time.Second
+	}
+	return interval
+}
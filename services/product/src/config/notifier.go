@@ -0,0 +1,23 @@
+package config
+
+import (
+	"strings"
+	"time"
+)
+
+// GetLowStockNotifier returns "log" or "webhook"
+func GetLowStockNotifier() string {
+	return strings.ToLower(getEnv("LOW_STOCK_NOTIFIER", "log"))
+}
+
+func GetLowStockWebhookUrl() string {
+	return getEnv("LOW_STOCK_WEBHOOK_URL", "")
+}
+
+func GetLowStockWebhookTimeout() time.Duration {
+	timeout, err := time.ParseDuration(getEnv("LOW_STOCK_WEBHOOK_TIMEOUT", "5s"))
+	if err != nil {
+		return 5 * time.Second
+	}
+	return timeout
+}
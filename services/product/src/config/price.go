@@ -0,0 +1,11 @@
+package config
+
+import "time"
+
+func GetPriceSchedulerInterval() time.Duration {
+	interval, err := time.ParseDuration(getEnv("PRICE_SCHEDULER_INTERVAL", "30s"))
+	if err != nil || interval <= 0 {
+		return 30 * time.Second
+	}
+	return interval
+}
@@ -0,0 +1,24 @@
+package config
+
+import "strconv"
+
+func GetRedisHost() string {
+	return getEnv("REDIS_HOST", "127.0.0.1")
+}
+
+func GetRedisPort() string {
+	return getEnv("REDIS_PORT", "6379")
+}
+
+func GetRedisPassword() string {
+	return getEnv("REDIS_PASSWORD", "")
+}
+
+func GetRedisDbCount() int {
+	count, err := strconv.Atoi(getEnv("REDIS_DB_COUNT", "0"))
+	if err != nil {
+		return 0
+	}
+
+	return count
+}
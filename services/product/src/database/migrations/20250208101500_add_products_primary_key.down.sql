@@ -0,0 +1,2 @@
+-- Menghapus primary key products
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_pkey;
\ No newline at end of file
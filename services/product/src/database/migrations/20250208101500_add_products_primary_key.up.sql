@@ -0,0 +1,2 @@
+-- Menjadikan id sebagai primary key agar bisa direferensikan tabel lain
+ALTER TABLE products ADD CONSTRAINT products_pkey PRIMARY KEY (id);
\ No newline at end of file
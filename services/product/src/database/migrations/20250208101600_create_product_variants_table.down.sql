@@ -0,0 +1,2 @@
+-- Menghapus tabel product_variants
+DROP TABLE IF EXISTS product_variants CASCADE;
\ No newline at end of file
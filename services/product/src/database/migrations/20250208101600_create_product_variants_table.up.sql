@@ -0,0 +1,14 @@
+-- Membuat tabel product_variants (ukuran, warna, dll) dengan stok dan harga masing-masing
+CREATE TABLE IF NOT EXISTS product_variants (
+	id VARCHAR(255) PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+	product_id VARCHAR(255) NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+	sku VARCHAR(50) NOT NULL,
+	price INTEGER NOT NULL,
+	qty INTEGER NOT NULL,
+	attributes JSONB NOT NULL DEFAULT '{}'::jsonb,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+-- SKU varian unik per produk
+CREATE UNIQUE INDEX IF NOT EXISTS product_variants_product_id_sku_idx ON product_variants (product_id, sku);
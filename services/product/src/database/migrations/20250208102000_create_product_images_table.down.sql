@@ -0,0 +1,2 @@
+-- Menghapus tabel product_images
+DROP TABLE IF EXISTS product_images CASCADE;
@@ -0,0 +1,19 @@
+-- Membuat tabel product_images, satu produk bisa memiliki banyak gambar
+CREATE TABLE IF NOT EXISTS product_images (
+	id VARCHAR(255) PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+	product_id VARCHAR(255) NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+	file_id VARCHAR(255) NOT NULL,
+	position INTEGER NOT NULL,
+	is_primary BOOLEAN NOT NULL DEFAULT FALSE,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+-- Urutan gambar unik per produk
+CREATE UNIQUE INDEX IF NOT EXISTS product_images_product_id_position_idx ON product_images (product_id, position);
+
+-- Hanya boleh ada satu gambar utama per produk
+CREATE UNIQUE INDEX IF NOT EXISTS product_images_primary_idx ON product_images (product_id) WHERE is_primary;
+
+-- Pindahkan file_id yang sudah ada sebagai gambar utama
+INSERT INTO product_images (product_id, file_id, position, is_primary)
+SELECT id, file_id, 0, TRUE FROM products WHERE file_id IS NOT NULL AND file_id <> '';
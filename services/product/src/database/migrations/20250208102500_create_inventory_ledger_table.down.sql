@@ -0,0 +1,4 @@
+-- Menghapus tabel inventory_ledger
+DROP TABLE IF EXISTS inventory_ledger CASCADE;
+DROP FUNCTION IF EXISTS inventory_ledger_reject_update();
+DROP TYPE IF EXISTS inventory_entry_types;
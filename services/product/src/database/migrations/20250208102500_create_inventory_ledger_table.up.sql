@@ -0,0 +1,30 @@
+-- Membuat tipe enum jenis perubahan stok
+CREATE TYPE inventory_entry_types AS ENUM ('restock', 'sale', 'reservation', 'correction', 'return');
+
+-- Membuat tabel inventory_ledger, hanya boleh INSERT (append-only)
+CREATE TABLE IF NOT EXISTS inventory_ledger (
+	id VARCHAR(255) PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+	product_id VARCHAR(255) NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+	type inventory_entry_types NOT NULL,
+	delta INTEGER NOT NULL,
+	qty_after INTEGER NOT NULL,
+	reason VARCHAR(255) NOT NULL,
+	actor_id VARCHAR(255) NOT NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS inventory_ledger_product_id_created_at_idx ON inventory_ledger (product_id, created_at);
+
+-- Tolak UPDATE agar riwayat tidak bisa diubah
+CREATE OR REPLACE FUNCTION inventory_ledger_reject_update() RETURNS TRIGGER AS $$
+BEGIN
+	RAISE EXCEPTION 'inventory_ledger is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER inventory_ledger_no_update BEFORE UPDATE ON inventory_ledger
+	FOR EACH ROW EXECUTE FUNCTION inventory_ledger_reject_update();
+
+-- Saldo awal untuk produk yang sudah ada
+INSERT INTO inventory_ledger (product_id, type, delta, qty_after, reason, actor_id)
+SELECT id, 'correction', qty, qty, 'opening balance', user_id FROM products;
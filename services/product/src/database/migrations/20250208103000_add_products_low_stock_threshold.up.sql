@@ -0,0 +1,2 @@
+-- Batas stok minimum per produk, NULL berarti alert tidak aktif
+ALTER TABLE products ADD COLUMN IF NOT EXISTS low_stock_threshold INTEGER CHECK (low_stock_threshold >= 0);
@@ -0,0 +1,5 @@
+-- Menghapus tabel harga
+DROP TRIGGER IF EXISTS products_price_history ON products;
+DROP FUNCTION IF EXISTS products_record_price_history();
+DROP TABLE IF EXISTS product_price_schedules CASCADE;
+DROP TABLE IF EXISTS product_price_history CASCADE;
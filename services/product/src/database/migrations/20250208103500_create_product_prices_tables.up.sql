@@ -0,0 +1,54 @@
+-- Riwayat harga produk, ditulis setiap kali harga berubah
+CREATE TABLE IF NOT EXISTS product_price_history (
+	id VARCHAR(255) PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+	product_id VARCHAR(255) NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+	previous_price INTEGER,
+	price INTEGER NOT NULL,
+	-- create, update, schedule_start, schedule_end
+	source VARCHAR(20) NOT NULL,
+	schedule_id VARCHAR(255),
+	actor_id VARCHAR(255) NOT NULL,
+	changed_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS product_price_history_product_id_changed_at_idx ON product_price_history (product_id, changed_at);
+
+-- Jadwal harga (promo), diaktifkan oleh ticker di product service
+CREATE TABLE IF NOT EXISTS product_price_schedules (
+	id VARCHAR(255) PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+	product_id VARCHAR(255) NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+	price INTEGER NOT NULL CHECK (price >= 0),
+	start_at TIMESTAMPTZ NOT NULL,
+	-- NULL berarti berlaku sampai dibatalkan
+	end_at TIMESTAMPTZ CHECK (end_at IS NULL OR end_at > start_at),
+	-- scheduled, active, completed, cancelled
+	status VARCHAR(20) NOT NULL DEFAULT 'scheduled',
+	created_by VARCHAR(255) NOT NULL,
+	created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS product_price_schedules_status_start_at_idx ON product_price_schedules (status, start_at);
+
+-- Hanya satu jadwal aktif per produk
+CREATE UNIQUE INDEX IF NOT EXISTS product_price_schedules_active_idx ON product_price_schedules (product_id) WHERE status = 'active';
+
+-- Catat perubahan harga dasar langsung dari tabel products
+CREATE OR REPLACE FUNCTION products_record_price_history() RETURNS TRIGGER AS $$
+BEGIN
+	IF TG_OP = 'INSERT' THEN
+		INSERT INTO product_price_history (product_id, previous_price, price, source, actor_id)
+		VALUES (NEW.id, NULL, NEW.price, 'create', NEW.user_id);
+	ELSIF NEW.price IS DISTINCT FROM OLD.price THEN
+		INSERT INTO product_price_history (product_id, previous_price, price, source, actor_id)
+		VALUES (NEW.id, OLD.price, NEW.price, 'update', NEW.user_id);
+	END IF;
+	RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER products_price_history AFTER INSERT OR UPDATE OF price ON products
+	FOR EACH ROW EXECUTE FUNCTION products_record_price_history();
+
+-- Harga awal untuk produk yang sudah ada
+INSERT INTO product_price_history (product_id, previous_price, price, source, actor_id, changed_at)
+SELECT id, NULL, price, 'create', user_id, created_at FROM products;
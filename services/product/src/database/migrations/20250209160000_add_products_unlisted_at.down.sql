@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS products_user_id_idx;
+ALTER TABLE products DROP COLUMN IF EXISTS unlisted_at;
@@ -0,0 +1,3 @@
+-- Diisi saat seller menghapus akunnya, produk yang di-unlist tidak lagi tampil maupun bisa dibeli
+ALTER TABLE products ADD COLUMN IF NOT EXISTS unlisted_at TIMESTAMP;
+CREATE INDEX IF NOT EXISTS products_user_id_idx ON products (user_id);
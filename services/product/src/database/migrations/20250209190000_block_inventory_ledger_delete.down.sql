@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS inventory_ledger_no_delete ON inventory_ledger;
+DROP FUNCTION IF EXISTS inventory_ledger_reject_delete();
@@ -0,0 +1,13 @@
+-- Ledger juga tidak boleh dihapus, kecuali lewat ON DELETE CASCADE saat produknya dihapus.
+-- Cascade berjalan di dalam trigger FK, jadi kedalaman trigger-nya lebih dari 1
+CREATE OR REPLACE FUNCTION inventory_ledger_reject_delete() RETURNS TRIGGER AS $$
+BEGIN
+	IF pg_trigger_depth() > 1 THEN
+		RETURN OLD;
+	END IF;
+	RAISE EXCEPTION 'inventory_ledger is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER inventory_ledger_no_delete BEFORE DELETE ON inventory_ledger
+	FOR EACH ROW EXECUTE FUNCTION inventory_ledger_reject_delete();
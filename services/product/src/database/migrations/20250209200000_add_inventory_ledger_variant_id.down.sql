@@ -0,0 +1,7 @@
+-- Entry varian tidak boleh tersisa sebagai entry produk, ledger append-only jadi trigger dimatikan sebentar
+ALTER TABLE inventory_ledger DISABLE TRIGGER inventory_ledger_no_delete;
+DELETE FROM inventory_ledger WHERE variant_id IS NOT NULL;
+ALTER TABLE inventory_ledger ENABLE TRIGGER inventory_ledger_no_delete;
+
+DROP INDEX IF EXISTS inventory_ledger_variant_id_idx;
+ALTER TABLE inventory_ledger DROP COLUMN IF EXISTS variant_id;
@@ -0,0 +1,11 @@
+-- Stok varian juga dicatat di ledger. Entry tanpa variant_id berlaku untuk products.qty,
+-- entry dengan variant_id untuk product_variants.qty
+ALTER TABLE inventory_ledger ADD COLUMN IF NOT EXISTS variant_id VARCHAR(255) REFERENCES product_variants(id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS inventory_ledger_variant_id_idx ON inventory_ledger (variant_id) WHERE variant_id IS NOT NULL;
+
+-- Saldo awal untuk varian yang sudah ada
+INSERT INTO inventory_ledger (product_id, variant_id, type, delta, qty_after, reason, actor_id)
+SELECT v.product_id, v.id, 'correction', v.qty, v.qty, 'opening balance', p.user_id
+FROM product_variants v
+JOIN products p ON p.id = v.product_id;
@@ -1,15 +1,26 @@
 package di
 
 import (
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/auth"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/health"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/logging"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/metrics"
 	authJwt "github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/auth/jwt"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/database/postgre"
+	redisClient "github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/database/redis"
+	healthCheck "github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/health"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/http/controller"
-	loggerZap "github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/logger/zap"
+	serviceLogger "github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/logger"
+	serviceMetrics "github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/metrics"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/notifier"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/repository"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/service"
+	fileService "github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/service/external/file"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/validation"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/worker"
 	"github.com/go-playground/validator/v10"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
 	"github.com/samber/do/v2"
 )
 
@@ -20,28 +31,74 @@ func init() {
 
 	//? Setup Database Connection
 	do.Provide[*pgxpool.Pool](Injector, postgre.NewPgxConnectInject)
+	//? Redis
+	do.Provide[*redis.Client](Injector, redisClient.NewRedisClientInject)
 
 	//? Setup Validation
 	//? Validator
 	do.Provide[*validator.Validate](Injector, validation.NewValidatorInject)
 
 	//? Logger
-	//? Zap
-	do.Provide[loggerZap.LoggerInterface](Injector, loggerZap.NewInject)
+	//? Structured JSON with request, trace and user ids
+	do.Provide[*logging.Logger](Injector, serviceLogger.NewLoggerInject)
+
+	//? Metrics
+	//? RED and Postgres pool
+	do.Provide[*metrics.Metrics](Injector, serviceMetrics.NewMetricsInject)
 
 	//? Setup Auth
-	//? JWT Service
-	do.Provide[authJwt.JwtServiceInterface](Injector, authJwt.NewJwtServiceInject)
+	//? JWKS Key Resolver
+	do.Provide[auth.KeyResolver](Injector, authJwt.NewKeyResolverInject)
+	//? Token Revocation List
+	do.Provide[auth.RevocationChecker](Injector, authJwt.NewRevocationCheckerInject)
+
+	//? Setup External Services
+	//? File Service
+	do.Provide[fileService.FileServiceInterface](Injector, fileService.NewInject)
+	//? Low Stock Notifier
+	do.Provide[notifier.NotifierInterface](Injector, notifier.NewInject)
 
 	//? Setup Repositories
 	//? Product Repository
 	do.Provide[repository.ProductRepoInterface](Injector, repository.NewInject)
+	//? Product Variant Repository
+	do.Provide[repository.ProductVariantRepoInterface](Injector, repository.NewProductVariantRepoInject)
+	//? Product Image Repository
+	do.Provide[repository.ProductImageRepoInterface](Injector, repository.NewProductImageRepoInject)
+	//? Inventory Repository
+	do.Provide[repository.InventoryRepoInterface](Injector, repository.NewInventoryRepoInject)
+	//? Price Repository
+	do.Provide[repository.PriceRepoInterface](Injector, repository.NewPriceRepoInject)
 
 	//? Setup Services
 	//? Product Service
 	do.Provide[service.ProductServiceInterface](Injector, service.NewInject)
+	//? Product Variant Service
+	do.Provide[service.ProductVariantServiceInterface](Injector, service.NewProductVariantServiceInject)
+	//? Inventory Service
+	do.Provide[service.InventoryServiceInterface](Injector, service.NewInventoryServiceInject)
+	//? Low Stock Service
+	do.Provide[service.LowStockServiceInterface](Injector, service.NewLowStockServiceInject)
+	//? Price Service
+	do.Provide[service.PriceServiceInterface](Injector, service.NewPriceServiceInject)
 
 	//? Setup Controller/Handler
 	//? Product Controller
 	do.Provide[controller.ProductControllerInterface](Injector, controller.NewInject)
+	//? Product Variant Controller
+	do.Provide[controller.ProductVariantControllerInterface](Injector, controller.NewProductVariantControllerInject)
+	//? Inventory Controller
+	do.Provide[controller.InventoryControllerInterface](Injector, controller.NewInventoryControllerInject)
+	//? Low Stock Controller
+	do.Provide[controller.LowStockControllerInterface](Injector, controller.NewLowStockControllerInject)
+	//? Price Controller
+	do.Provide[controller.PriceControllerInterface](Injector, controller.NewPriceControllerInject)
+
+	//? Setup Workers
+	//? Price Scheduler
+	do.Provide[*worker.PriceScheduler](Injector, worker.NewPriceSchedulerInject)
+
+	//? Setup Health
+	//? Readiness Checker
+	do.Provide[*health.Checker](Injector, healthCheck.NewCheckerInject)
 }
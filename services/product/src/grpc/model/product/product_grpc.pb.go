@@ -23,7 +23,10 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	ProductService_GetProductDetailById_FullMethodName = "/product.ProductService/GetProductDetailById"
+	ProductService_GetProductDetailById_FullMethodName  = "/product.ProductService/GetProductDetailById"
+	ProductService_CountProductsByUserId_FullMethodName = "/product.ProductService/CountProductsByUserId"
+	ProductService_ListProductsByUserId_FullMethodName  = "/product.ProductService/ListProductsByUserId"
+	ProductService_RecordSale_FullMethodName            = "/product.ProductService/RecordSale"
 )
 
 // ProductServiceClient is the client API for ProductService service.
@@ -33,6 +36,10 @@ const (
 // Define RPC service
 type ProductServiceClient interface {
 	GetProductDetailById(ctx context.Context, in *ProductRequest, opts ...grpc.CallOption) (*ProductResponse, error)
+	CountProductsByUserId(ctx context.Context, in *ProductCountRequest, opts ...grpc.CallOption) (*ProductCountResponse, error)
+	ListProductsByUserId(ctx context.Context, in *ProductsByUserIdRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ProductResponse], error)
+	// Semua item dicatat atau tidak sama sekali, stok yang kurang ditolak dengan FAILED_PRECONDITION
+	RecordSale(ctx context.Context, in *RecordSaleRequest, opts ...grpc.CallOption) (*RecordSaleResponse, error)
 }
 
 type productServiceClient struct {
@@ -53,6 +60,45 @@ func (c *productServiceClient) GetProductDetailById(ctx context.Context, in *Pro
 	return out, nil
 }
 
+func (c *productServiceClient) CountProductsByUserId(ctx context.Context, in *ProductCountRequest, opts ...grpc.CallOption) (*ProductCountResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ProductCountResponse)
+	err := c.cc.Invoke(ctx, ProductService_CountProductsByUserId_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) ListProductsByUserId(ctx context.Context, in *ProductsByUserIdRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ProductResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ProductService_ServiceDesc.Streams[0], ProductService_ListProductsByUserId_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ProductsByUserIdRequest, ProductResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ProductService_ListProductsByUserIdClient = grpc.ServerStreamingClient[ProductResponse]
+
+func (c *productServiceClient) RecordSale(ctx context.Context, in *RecordSaleRequest, opts ...grpc.CallOption) (*RecordSaleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RecordSaleResponse)
+	err := c.cc.Invoke(ctx, ProductService_RecordSale_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ProductServiceServer is the server API for ProductService service.
 // All implementations must embed UnimplementedProductServiceServer
 // for forward compatibility.
@@ -60,6 +106,10 @@ func (c *productServiceClient) GetProductDetailById(ctx context.Context, in *Pro
 // Define RPC service
 type ProductServiceServer interface {
 	GetProductDetailById(context.Context, *ProductRequest) (*ProductResponse, error)
+	CountProductsByUserId(context.Context, *ProductCountRequest) (*ProductCountResponse, error)
+	ListProductsByUserId(*ProductsByUserIdRequest, grpc.ServerStreamingServer[ProductResponse]) error
+	// Semua item dicatat atau tidak sama sekali, stok yang kurang ditolak dengan FAILED_PRECONDITION
+	RecordSale(context.Context, *RecordSaleRequest) (*RecordSaleResponse, error)
 	mustEmbedUnimplementedProductServiceServer()
 }
 
@@ -73,6 +123,15 @@ type UnimplementedProductServiceServer struct{}
 func (UnimplementedProductServiceServer) GetProductDetailById(context.Context, *ProductRequest) (*ProductResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetProductDetailById not implemented")
 }
+func (UnimplementedProductServiceServer) CountProductsByUserId(context.Context, *ProductCountRequest) (*ProductCountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CountProductsByUserId not implemented")
+}
+func (UnimplementedProductServiceServer) ListProductsByUserId(*ProductsByUserIdRequest, grpc.ServerStreamingServer[ProductResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method ListProductsByUserId not implemented")
+}
+func (UnimplementedProductServiceServer) RecordSale(context.Context, *RecordSaleRequest) (*RecordSaleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RecordSale not implemented")
+}
 func (UnimplementedProductServiceServer) mustEmbedUnimplementedProductServiceServer() {}
 func (UnimplementedProductServiceServer) testEmbeddedByValue()                        {}
 
@@ -112,6 +171,53 @@ func _ProductService_GetProductDetailById_Handler(srv interface{}, ctx context.C
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ProductService_CountProductsByUserId_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProductCountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).CountProductsByUserId(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_CountProductsByUserId_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).CountProductsByUserId(ctx, req.(*ProductCountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_ListProductsByUserId_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ProductsByUserIdRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ProductServiceServer).ListProductsByUserId(m, &grpc.GenericServerStream[ProductsByUserIdRequest, ProductResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ProductService_ListProductsByUserIdServer = grpc.ServerStreamingServer[ProductResponse]
+
+func _ProductService_RecordSale_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RecordSaleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).RecordSale(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_RecordSale_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).RecordSale(ctx, req.(*RecordSaleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ProductService_ServiceDesc is the grpc.ServiceDesc for ProductService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -123,7 +229,21 @@ var ProductService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetProductDetailById",
 			Handler:    _ProductService_GetProductDetailById_Handler,
 		},
+		{
+			MethodName: "CountProductsByUserId",
+			Handler:    _ProductService_CountProductsByUserId_Handler,
+		},
+		{
+			MethodName: "RecordSale",
+			Handler:    _ProductService_RecordSale_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListProductsByUserId",
+			Handler:       _ProductService_ListProductsByUserId_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "src/grpc/proto/product.proto",
 }
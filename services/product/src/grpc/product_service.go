@@ -2,17 +2,146 @@ package grpc
 
 import (
 	"context"
+	"strconv"
 
-	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/model/dtos/response"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/exceptions"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/grpc/model/product"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/model/dtos/request"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/model/entity"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/repository"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/service"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type ProductService struct {
+	product.UnimplementedProductServiceServer
 	DB          *pgxpool.Pool
 	ProductRepo repository.ProductRepoInterface
+	VariantRepo repository.ProductVariantRepoInterface
+	Inventory   service.InventoryServiceInterface
 }
 
-func (ps *ProductService) GetDetailById(ctx context.Context, productId string) (response.Product, error) {
+func (ps *ProductService) GetProductDetailById(ctx context.Context, req *product.ProductRequest) (*product.ProductResponse, error) {
+	p, err := ps.ProductRepo.GetById(ctx, ps.DB, req.ProductId)
+	if err != nil {
+		if _, ok := err.(*exceptions.NotFoundError); ok {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
 
+	variants, err := ps.VariantRepo.GetByProductId(ctx, ps.DB, p.Id)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return toProductResponse(p, variants), nil
+}
+
+// Used by the user service for the public seller profile
+func (ps *ProductService) CountProductsByUserId(ctx context.Context, req *product.ProductCountRequest) (*product.ProductCountResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "UserId is required")
+	}
+
+	count, err := ps.ProductRepo.CountByUserId(ctx, ps.DB, req.UserId)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &product.ProductCountResponse{Count: count}, nil
+}
+
+// Used by the user service for the personal data export
+func (ps *ProductService) ListProductsByUserId(req *product.ProductsByUserIdRequest, stream product.ProductService_ListProductsByUserIdServer) error {
+	if req.UserId == "" {
+		return status.Error(codes.InvalidArgument, "UserId is required")
+	}
+
+	ctx := stream.Context()
+	products, err := ps.ProductRepo.GetAllByUserId(ctx, ps.DB, req.UserId)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	productIds := make([]string, 0, len(products))
+	for _, p := range products {
+		productIds = append(productIds, p.Id)
+	}
+	variants, err := ps.VariantRepo.GetByProductIds(ctx, ps.DB, productIds)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	for _, p := range products {
+		if err := stream.Send(toProductResponse(p, variants[p.Id])); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Used by the purchase service when an order is placed, every item goes through the inventory ledger
+func (ps *ProductService) RecordSale(ctx context.Context, req *product.RecordSaleRequest) (*product.RecordSaleResponse, error) {
+	if req.PurchaseId == "" || len(req.Items) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "PurchaseId and Items are required")
+	}
+
+	// guest checkouts have no buyer, the ledger still needs an actor
+	actorId := req.BuyerId
+	if actorId == "" {
+		actorId = "guest"
+	}
+
+	payload := request.InventorySale{
+		PurchaseId: req.PurchaseId,
+		ActorId:    actorId,
+	}
+	for _, item := range req.Items {
+		payload.Items = append(payload.Items, request.InventorySaleItem{
+			ProductId: item.ProductId,
+			VariantId: item.VariantId,
+			Qty:       int(item.Qty),
+		})
+	}
+
+	_, err := ps.Inventory.RecordSale(ctx, payload)
+	if err != nil {
+		switch err.(type) {
+		case *exceptions.NotFoundError:
+			return nil, status.Error(codes.NotFound, err.Error())
+		case *exceptions.BadRequestError:
+			// insufficient stock, or an item without productId or qty
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &product.RecordSaleResponse{}, nil
+}
+
+func toProductResponse(p entity.Product, variants []entity.ProductVariant) *product.ProductResponse {
+	res := &product.ProductResponse{
+		ProductId: p.Id,
+		Name:      p.Name,
+		Qty:       strconv.Itoa(p.Qty),
+		Price:     strconv.Itoa(p.Price),
+		Sku:       p.Sku,
+		FileId:    p.FileId,
+		UserId:    p.UserId,
+	}
+	for _, variant := range variants {
+		res.Variants = append(res.Variants, &product.ProductVariant{
+			VariantId:  variant.Id,
+			Sku:        variant.Sku,
+			Qty:        strconv.Itoa(variant.Qty),
+			Price:      strconv.Itoa(variant.Price),
+			Attributes: variant.Attributes,
+		})
+	}
+
+	return res
 }
@@ -1,28 +1,112 @@
 package grpc
 
 import (
+	"context"
+	"fmt"
 	"log"
 	"net"
 
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/grpcmw"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/health"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/logging"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/metrics"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/tracing"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/config"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/di"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/grpc/model/product"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/grpc/model/userevents"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/repository"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/service"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/samber/do/v2"
 	"google.golang.org/grpc"
 )
 
-type GrpcServer struct{}
+type GrpcServer struct {
+	server *grpc.Server
+}
+
+func NewGrpcServer() *GrpcServer {
+	// mTLS when the certificate paths are configured, clients without a trusted cert are refused
+	tlsConfig := config.GetGrpcTLS()
+	transport, err := grpcmw.ServerTransport(tlsConfig)
+	if err != nil {
+		log.Fatalf("Failed to load gRPC TLS certificates: %v", err)
+	}
+
+	// outside debug mode a server without a token or mTLS would accept anyone
+	logger := do.MustInvoke[*logging.Logger](di.Injector)
+	cfg := serverConfig(logger)
+	if !config.IsDebug() {
+		if err := cfg.RequireAuth(tlsConfig); err != nil {
+			log.Fatalf("Set GRPC_SERVICE_TOKEN or the GRPC_TLS_* files: %v", err)
+		}
+	}
+
+	// create new grpc server handler with tracing, metrics, auth, request id, deadline, recovery and access log
+	options := append(append(transport, tracing.ServerOptions()...), do.MustInvoke[*metrics.Metrics](di.Injector).GrpcServerOptions()...)
+	server := grpc.NewServer(append(options, grpcmw.ServerOptions(cfg)...)...)
+
+	// register product service
+	product.RegisterProductServiceServer(server, &ProductService{
+		DB:          do.MustInvoke[*pgxpool.Pool](di.Injector),
+		ProductRepo: do.MustInvoke[repository.ProductRepoInterface](di.Injector),
+		VariantRepo: do.MustInvoke[repository.ProductVariantRepoInterface](di.Injector),
+		Inventory:   do.MustInvoke[service.InventoryServiceInterface](di.Injector),
+	})
+
+	// register user event handlers
+	userevents.RegisterUserEventServiceServer(server, &UserEventService{
+		DB:          do.MustInvoke[*pgxpool.Pool](di.Injector),
+		ProductRepo: do.MustInvoke[repository.ProductRepoInterface](di.Injector),
+		Logger:      logger,
+	})
 
-func (gs *GrpcServer) Listen() {
+	// grpc.health.v1, follows the same readiness checks as GET /readyz
+	do.MustInvoke[*health.Checker](di.Injector).RegisterGrpc(server, config.GetGrpcHealthInterval())
+
+	return &GrpcServer{server: server}
+}
+
+// Blocks until the server stops, returns nil after Shutdown
+func (gs *GrpcServer) Listen() error {
 	// create tcp server
 	lis, err := net.Listen("tcp", ":"+config.GetPortGrpc())
-
 	if err != nil {
-		log.Fatal("Failed to Listen on Port: %v", err)
+		return err
 	}
 
-	// create new grpc server handler
-	server := grpc.NewServer()
-
 	// run server
-	if err := server.Serve(lis); err != nil {
-		log.Fatal(err.Error())
+	return gs.server.Serve(lis)
+}
+
+// Waits for the running calls until ctx is done, then cancels the rest
+func (gs *GrpcServer) Shutdown(ctx context.Context) error {
+	return grpcmw.GracefulStop(ctx, gs.server)
+}
+
+func serverConfig(logger *logging.Logger) grpcmw.ServerConfig {
+	return grpcmw.ServerConfig{
+		Token:          config.GetGrpcServiceToken(),
+		AllowedPeers:   config.GetGrpcAllowedPeers(),
+		DefaultTimeout: config.GetGrpcDefaultTimeout(),
+		Log: func(ctx context.Context, entry grpcmw.AccessLog) {
+			// request_id comes from ctx, the logger attaches it
+			data := []interface{}{
+				"method", entry.Method,
+				"code", entry.Code.String(),
+				"duration", entry.Duration.String(),
+				"peer", entry.Peer,
+				"identity", entry.Identity,
+			}
+			if entry.Err != nil {
+				logger.Warn(ctx, "grpc call failed", append(data, "error", entry.Err)...)
+				return
+			}
+			logger.Info(ctx, "grpc call", data...)
+		},
+		OnPanic: func(ctx context.Context, method string, recovered any, stack []byte) {
+			logger.Error(ctx, "grpc handler panicked", "panic", fmt.Sprint(recovered), "method", method, "stack", string(stack))
+		},
 	}
 }
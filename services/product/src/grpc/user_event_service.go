@@ -0,0 +1,37 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/logging"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/grpc/model/userevents"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/repository"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Receives events broadcast by the user service
+type UserEventService struct {
+	userevents.UnimplementedUserEventServiceServer
+	DB          *pgxpool.Pool
+	ProductRepo repository.ProductRepoInterface
+	Logger      *logging.Logger
+}
+
+// Unlists every product of the deleted seller. Redelivery is harmless,
+// products that are already unlisted keep their unlisted_at
+func (us *UserEventService) UserDeleted(ctx context.Context, req *userevents.UserDeletedEvent) (*userevents.UserEventAck, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "UserId is required")
+	}
+
+	unlisted, err := us.ProductRepo.UnlistByUserId(ctx, us.DB, req.UserId)
+	if err != nil {
+		us.Logger.Error(ctx, "unable to unlist products of deleted user", "error", err, "userId", req.UserId)
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	us.Logger.Info(ctx, "user deleted, products unlisted", "userId", req.UserId, "unlisted", unlisted, "deletedAt", req.DeletedAt)
+
+	return &userevents.UserEventAck{}, nil
+}
@@ -0,0 +1,25 @@
+package healthCheck
+
+import (
+	"context"
+
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/health"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/config"
+	fileService "github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/service/external/file"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+	"github.com/samber/do/v2"
+)
+
+// Postgres and Redis (token revocation) are required, the file service only
+// degrades this service since it is needed for product images alone
+func NewCheckerInject(i do.Injector) (*health.Checker, error) {
+	_db := do.MustInvoke[*pgxpool.Pool](i)
+	_redis := do.MustInvoke[*redis.Client](i)
+	_fileService := do.MustInvoke[fileService.FileServiceInterface](i)
+
+	return health.New(config.GetHealthCheckTimeout()).
+		Add("postgres", _db.Ping).
+		Add("redis", func(ctx context.Context) error { return _redis.Ping(ctx).Err() }).
+		AddOptional("file", _fileService.Ready), nil
+}
@@ -7,4 +7,27 @@ type ProductControllerInterface interface {
 	DeleteById(c *fiber.Ctx) error
 	UpdateById(c *fiber.Ctx) error
 	GetAll(c *fiber.Ctx) error
+	GetById(c *fiber.Ctx) error
+}
+
+type ProductVariantControllerInterface interface {
+	Create(c *fiber.Ctx) error
+	GetByProductId(c *fiber.Ctx) error
+	UpdateById(c *fiber.Ctx) error
+	DeleteById(c *fiber.Ctx) error
+}
+
+type InventoryControllerInterface interface {
+	Adjust(c *fiber.Ctx) error
+	GetHistory(c *fiber.Ctx) error
+}
+
+type LowStockControllerInterface interface {
+	SetThreshold(c *fiber.Ctx) error
+}
+
+type PriceControllerInterface interface {
+	CreateSchedule(c *fiber.Ctx) error
+	CancelSchedule(c *fiber.Ctx) error
+	GetTimeline(c *fiber.Ctx) error
 }
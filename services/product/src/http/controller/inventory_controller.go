@@ -0,0 +1,60 @@
+package controller
+
+import (
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/exceptions"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/model/dtos/request"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/service"
+	"github.com/gofiber/fiber/v2"
+	"github.com/samber/do/v2"
+)
+
+type InventoryController struct {
+	InventoryService service.InventoryServiceInterface
+}
+
+func NewInventoryController(inventoryService service.InventoryServiceInterface) InventoryControllerInterface {
+	return &InventoryController{
+		InventoryService: inventoryService,
+	}
+}
+
+func NewInventoryControllerInject(i do.Injector) (InventoryControllerInterface, error) {
+	_inventoryService := do.MustInvoke[service.InventoryServiceInterface](i)
+	return NewInventoryController(_inventoryService), nil
+}
+
+func (ic *InventoryController) Adjust(c *fiber.Ctx) error {
+	payload := request.InventoryAdjust{}
+
+	if err := c.BodyParser(&payload); err != nil {
+		return exceptions.NewBadRequestError(err.Error())
+	}
+
+	payload.ProductId = c.Params("productId")
+	payload.UserId = c.Locals("userId").(string)
+
+	entry, err := ic.InventoryService.Adjust(c.UserContext(), payload)
+
+	if err != nil {
+		return err
+	}
+
+	return c.Status(201).JSON(entry)
+}
+
+func (ic *InventoryController) GetHistory(c *fiber.Ctx) error {
+	filter := request.InventoryFilter{
+		ProductId: c.Params("productId"),
+		UserId:    c.Locals("userId").(string),
+		Limit:     c.QueryInt("limit", 20),
+		Offset:    c.QueryInt("offset", 0),
+	}
+
+	entries, err := ic.InventoryService.GetHistory(c.UserContext(), filter)
+
+	if err != nil {
+		return err
+	}
+
+	return c.Status(200).JSON(entries)
+}
@@ -0,0 +1,43 @@
+package controller
+
+import (
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/exceptions"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/model/dtos/request"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/service"
+	"github.com/gofiber/fiber/v2"
+	"github.com/samber/do/v2"
+)
+
+type LowStockController struct {
+	LowStockService service.LowStockServiceInterface
+}
+
+func NewLowStockController(lowStockService service.LowStockServiceInterface) LowStockControllerInterface {
+	return &LowStockController{
+		LowStockService: lowStockService,
+	}
+}
+
+func NewLowStockControllerInject(i do.Injector) (LowStockControllerInterface, error) {
+	_lowStockService := do.MustInvoke[service.LowStockServiceInterface](i)
+	return NewLowStockController(_lowStockService), nil
+}
+
+func (lc *LowStockController) SetThreshold(c *fiber.Ctx) error {
+	payload := request.LowStockThreshold{}
+
+	if err := c.BodyParser(&payload); err != nil {
+		return exceptions.NewBadRequestError(err.Error())
+	}
+
+	payload.ProductId = c.Params("productId")
+	payload.UserId = c.Locals("userId").(string)
+
+	threshold, err := lc.LowStockService.SetThreshold(c.UserContext(), payload)
+
+	if err != nil {
+		return err
+	}
+
+	return c.Status(200).JSON(threshold)
+}
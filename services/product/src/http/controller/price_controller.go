@@ -0,0 +1,76 @@
+package controller
+
+import (
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/exceptions"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/model/dtos/request"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/model/dtos/response"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/service"
+	"github.com/gofiber/fiber/v2"
+	"github.com/samber/do/v2"
+)
+
+type PriceController struct {
+	PriceService service.PriceServiceInterface
+}
+
+func NewPriceController(priceService service.PriceServiceInterface) PriceControllerInterface {
+	return &PriceController{
+		PriceService: priceService,
+	}
+}
+
+func NewPriceControllerInject(i do.Injector) (PriceControllerInterface, error) {
+	_priceService := do.MustInvoke[service.PriceServiceInterface](i)
+	return NewPriceController(_priceService), nil
+}
+
+func (pc *PriceController) CreateSchedule(c *fiber.Ctx) error {
+	payload := request.PriceScheduleCreate{}
+
+	if err := c.BodyParser(&payload); err != nil {
+		return exceptions.NewBadRequestError(err.Error())
+	}
+
+	payload.ProductId = c.Params("productId")
+	payload.UserId = c.Locals("userId").(string)
+
+	schedule, err := pc.PriceService.CreateSchedule(c.UserContext(), payload)
+
+	if err != nil {
+		return err
+	}
+
+	return c.Status(201).JSON(schedule)
+}
+
+func (pc *PriceController) CancelSchedule(c *fiber.Ctx) error {
+	productId := c.Params("productId")
+	scheduleId := c.Params("scheduleId")
+	userId := c.Locals("userId").(string)
+
+	err := pc.PriceService.CancelSchedule(c.UserContext(), productId, scheduleId, userId)
+
+	if err != nil {
+		return err
+	}
+
+	return c.Status(200).JSON(response.Web{
+		Message: "OK",
+	})
+}
+
+func (pc *PriceController) GetTimeline(c *fiber.Ctx) error {
+	filter := request.PriceTimelineFilter{
+		ProductId: c.Params("productId"),
+		Limit:     c.QueryInt("limit", 20),
+		Offset:    c.QueryInt("offset", 0),
+	}
+
+	timeline, err := pc.PriceService.GetTimeline(c.UserContext(), filter)
+
+	if err != nil {
+		return err
+	}
+
+	return c.Status(200).JSON(timeline)
+}
@@ -1,8 +1,6 @@
 package controller
 
 import (
-	"context"
-
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/exceptions"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/model/dtos/request"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/model/dtos/response"
@@ -35,7 +33,7 @@ func (p *ProductController) Create(c *fiber.Ctx) error {
 		return exceptions.NewBadRequestError(err.Error())
 	}
 
-	product, err := p.ProductService.Create(context.Background(), payload)
+	product, err := p.ProductService.Create(c.UserContext(), payload)
 
 	if err != nil {
 		return err
@@ -48,7 +46,7 @@ func (p *ProductController) DeleteById(c *fiber.Ctx) error {
 	productId := c.Params("productId")
 	UserId := c.Locals("userId").(string)
 
-	err := p.ProductService.DeletedById(context.Background(), productId, UserId)
+	err := p.ProductService.DeletedById(c.UserContext(), productId, UserId)
 
 	if err != nil {
 		return err
@@ -74,7 +72,7 @@ func (p *ProductController) UpdateById(c *fiber.Ctx) error {
 	payload.Id = productId
 	payload.UserId = userId
 
-	product, err := p.ProductService.UpdateById(context.Background(), payload)
+	product, err := p.ProductService.UpdateById(c.UserContext(), payload)
 
 	if err != nil {
 		return err
@@ -91,10 +89,10 @@ func (p *ProductController) GetAll(c *fiber.Ctx) error {
 		ProductId: c.Query("productId", ""),
 		Sku:       c.Query("Sku", ""),
 		Category:  c.Query("category", ""),
-		SortBy:    c.Query("category", ""),
+		SortBy:    c.Query("sortBy", ""),
 	}
 
-	products, err := p.ProductService.GetAll(context.Background(), productFilter)
+	products, err := p.ProductService.GetAll(c.UserContext(), productFilter)
 
 	if err != nil {
 		return err
@@ -102,3 +100,15 @@ func (p *ProductController) GetAll(c *fiber.Ctx) error {
 
 	return c.Status(200).JSON(products)
 }
+
+func (p *ProductController) GetById(c *fiber.Ctx) error {
+	productId := c.Params("productId")
+
+	product, err := p.ProductService.GetById(c.UserContext(), productId)
+
+	if err != nil {
+		return err
+	}
+
+	return c.Status(200).JSON(product)
+}
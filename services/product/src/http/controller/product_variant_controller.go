@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/exceptions"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/model/dtos/request"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/model/dtos/response"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/service"
+	"github.com/gofiber/fiber/v2"
+	"github.com/samber/do/v2"
+)
+
+type ProductVariantController struct {
+	VariantService service.ProductVariantServiceInterface
+}
+
+func NewProductVariantController(variantService service.ProductVariantServiceInterface) ProductVariantControllerInterface {
+	return &ProductVariantController{
+		VariantService: variantService,
+	}
+}
+
+func NewProductVariantControllerInject(i do.Injector) (ProductVariantControllerInterface, error) {
+	_variantService := do.MustInvoke[service.ProductVariantServiceInterface](i)
+	return NewProductVariantController(_variantService), nil
+}
+
+func (v *ProductVariantController) Create(c *fiber.Ctx) error {
+	payload := request.ProductVariantCreate{}
+
+	if err := c.BodyParser(&payload); err != nil {
+		return exceptions.NewBadRequestError(err.Error())
+	}
+
+	payload.ProductId = c.Params("productId")
+	payload.UserId = c.Locals("userId").(string)
+
+	variant, err := v.VariantService.Create(c.UserContext(), payload)
+
+	if err != nil {
+		return err
+	}
+
+	return c.Status(201).JSON(variant)
+}
+
+func (v *ProductVariantController) GetByProductId(c *fiber.Ctx) error {
+	productId := c.Params("productId")
+
+	variants, err := v.VariantService.GetByProductId(c.UserContext(), productId)
+
+	if err != nil {
+		return err
+	}
+
+	return c.Status(200).JSON(variants)
+}
+
+func (v *ProductVariantController) UpdateById(c *fiber.Ctx) error {
+	payload := request.ProductVariantUpdate{}
+
+	if err := c.BodyParser(&payload); err != nil {
+		return exceptions.NewBadRequestError(err.Error())
+	}
+
+	payload.Id = c.Params("variantId")
+	payload.ProductId = c.Params("productId")
+	payload.UserId = c.Locals("userId").(string)
+
+	variant, err := v.VariantService.UpdateById(c.UserContext(), payload)
+
+	if err != nil {
+		return err
+	}
+
+	return c.Status(200).JSON(variant)
+}
+
+func (v *ProductVariantController) DeleteById(c *fiber.Ctx) error {
+	productId := c.Params("productId")
+	variantId := c.Params("variantId")
+	userId := c.Locals("userId").(string)
+
+	err := v.VariantService.DeleteById(c.UserContext(), productId, variantId, userId)
+
+	if err != nil {
+		return err
+	}
+
+	return c.Status(200).JSON(response.Web{
+		Message: "OK",
+	})
+}
@@ -1,40 +1,34 @@
 package middleware
 
 import (
-	"strings"
+	"sync"
 
-	authJwt "github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/auth/jwt"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/auth"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/di"
-	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/model/dtos/response"
 	"github.com/gofiber/fiber/v2"
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/samber/do/v2"
 )
 
+var (
+	authHandlerOnce sync.Once
+	authHandler     fiber.Handler
+)
+
+// AuthMiddleware delegates to the shared auth middleware, the handler is
+// built on first use because routes are registered before the injector is ready
 func AuthMiddleware(c *fiber.Ctx) error {
-	tokenStr := c.Get("Authorization")
-	tokenStr = strings.Replace(tokenStr, "Bearer ", "", 1)
-	if tokenStr == "" {
-		return c.Status(fiber.StatusUnauthorized).JSON(response.Web{
-			Message: "UNAUTHORIZED",
+	authHandlerOnce.Do(func() {
+		authHandler = auth.New(auth.Config{
+			Keys:        do.MustInvoke[auth.KeyResolver](di.Injector),
+			Revocations: do.MustInvoke[auth.RevocationChecker](di.Injector),
 		})
-	}
+	})
 
-	jwtService := do.MustInvoke[authJwt.JwtServiceInterface](di.Injector)
-
-	token, err := jwtService.ValidateToken(tokenStr)
-	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(response.Web{
-			Message: err.Error(),
-		})
-	}
+	return authHandler(c)
+}
 
-	if !token.Valid {
-		return c.Status(fiber.StatusUnauthorized).JSON(response.Web{
-			Message: "UNAUTHORIZED",
-		})
-	}
+// SellerOnly rejects callers without the seller role with 403, it must run after AuthMiddleware
+var SellerOnly = auth.RequireRole(auth.RoleSeller)
 
-	c.Locals("userId", token.Claims.(jwt.MapClaims)["userId"])
-	return c.Next()
-}
+// AdminOnly rejects callers without the admin role with 403, it must run after AuthMiddleware
+var AdminOnly = auth.RequireRole(auth.RoleAdmin)
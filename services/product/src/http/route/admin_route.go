@@ -0,0 +1,14 @@
+package route
+
+import (
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/logging"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/http/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetRouteAdmin(router fiber.Router, logger *logging.Logger) {
+	admin := router.Group("/admin", middleware.AuthMiddleware, middleware.AdminOnly)
+
+	// GET and PUT /admin/log-level of this instance
+	logger.RegisterLevel(admin)
+}
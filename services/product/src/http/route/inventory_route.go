@@ -0,0 +1,12 @@
+package route
+
+import (
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/http/controller"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/http/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetRouteInventory(router fiber.Router, ic controller.InventoryControllerInterface) {
+	router.Post("/product/:productId/inventory", middleware.AuthMiddleware, middleware.SellerOnly, ic.Adjust)
+	router.Get("/product/:productId/inventory", middleware.AuthMiddleware, middleware.SellerOnly, ic.GetHistory)
+}
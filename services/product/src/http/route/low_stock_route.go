@@ -0,0 +1,11 @@
+package route
+
+import (
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/http/controller"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/http/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetRouteLowStock(router fiber.Router, lc controller.LowStockControllerInterface) {
+	router.Put("/product/:productId/low-stock-threshold", middleware.AuthMiddleware, middleware.SellerOnly, lc.SetThreshold)
+}
@@ -0,0 +1,13 @@
+package route
+
+import (
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/http/controller"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/http/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetRoutePrice(router fiber.Router, pc controller.PriceControllerInterface) {
+	router.Get("/product/:productId/prices", pc.GetTimeline)
+	router.Post("/product/:productId/price-schedules", middleware.AuthMiddleware, middleware.SellerOnly, pc.CreateSchedule)
+	router.Delete("/product/:productId/price-schedules/:scheduleId", middleware.AuthMiddleware, middleware.SellerOnly, pc.CancelSchedule)
+}
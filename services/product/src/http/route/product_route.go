@@ -7,8 +7,9 @@ import (
 )
 
 func SetRouteProduct(router fiber.Router, pc controller.ProductControllerInterface) {
-	router.Post("/product", middleware.AuthMiddleware, pc.Create)
-	router.Delete("/product/:productId", middleware.AuthMiddleware, pc.DeleteById)
-	router.Put("/product/:productId", middleware.AuthMiddleware, pc.UpdateById)
+	router.Post("/product", middleware.AuthMiddleware, middleware.SellerOnly, pc.Create)
+	router.Delete("/product/:productId", middleware.AuthMiddleware, middleware.SellerOnly, pc.DeleteById)
+	router.Put("/product/:productId", middleware.AuthMiddleware, middleware.SellerOnly, pc.UpdateById)
 	router.Get("/product", pc.GetAll)
+	router.Get("/product/:productId", pc.GetById)
 }
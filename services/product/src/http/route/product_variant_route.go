@@ -0,0 +1,14 @@
+package route
+
+import (
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/http/controller"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/http/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetRouteProductVariant(router fiber.Router, vc controller.ProductVariantControllerInterface) {
+	router.Post("/product/:productId/variants", middleware.AuthMiddleware, middleware.SellerOnly, vc.Create)
+	router.Get("/product/:productId/variants", vc.GetByProductId)
+	router.Put("/product/:productId/variants/:variantId", middleware.AuthMiddleware, middleware.SellerOnly, vc.UpdateById)
+	router.Delete("/product/:productId/variants/:variantId", middleware.AuthMiddleware, middleware.SellerOnly, vc.DeleteById)
+}
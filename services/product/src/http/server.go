@@ -1,8 +1,13 @@
 package httpServer
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/health"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/logging"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/metrics"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/tracing"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/config"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/di"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/http/controller"
@@ -10,18 +15,37 @@ import (
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/http/route"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
 	"github.com/samber/do/v2"
 )
 
-type HttpServer struct{}
+type HttpServer struct {
+	app *fiber.App
+}
 
-func (s *HttpServer) Listen() {
+func NewHttpServer() *HttpServer {
 	fmt.Printf("New Fiber\n")
 	app := fiber.New(fiber.Config{
 		ServerHeader: "TIM-DEBUG",
 		ErrorHandler: middleware.ErrorHandle,
 	})
 
+	// Forwarded to other services as gRPC metadata, see middleware/grpcmw
+	app.Use(requestid.New())
+	// and logged with every entry of the request, see middleware/logging
+	app.Use(logging.Fiber())
+
+	// Span per request, continues the trace of the caller, see middleware/tracing
+	app.Use(tracing.Fiber())
+
+	// Liveness and readiness probes for the load balancer, before any auth
+	do.MustInvoke[*health.Checker](di.Injector).Register(app)
+
+	// RED and pgx pool metrics on GET /metrics, see middleware/metrics
+	m := do.MustInvoke[*metrics.Metrics](di.Injector)
+	m.Register(app)
+	app.Use(m.Fiber())
+
 	app.Use(cors.New(cors.Config{
 		AllowOrigins:     "*",
 		AllowCredentials: false,
@@ -32,10 +56,31 @@ func (s *HttpServer) Listen() {
 	//? Depedency Injection
 	//? ProductController
 	pc := do.MustInvoke[controller.ProductControllerInterface](di.Injector)
+	//? ProductVariantController
+	vc := do.MustInvoke[controller.ProductVariantControllerInterface](di.Injector)
+	//? InventoryController
+	ic := do.MustInvoke[controller.InventoryControllerInterface](di.Injector)
+	//? LowStockController
+	lc := do.MustInvoke[controller.LowStockControllerInterface](di.Injector)
+	//? PriceController
+	prc := do.MustInvoke[controller.PriceControllerInterface](di.Injector)
 
 	routes := route.SetRoutes(app)
 	route.SetRouteProduct(routes, pc)
+	route.SetRouteProductVariant(routes, vc)
+	route.SetRouteInventory(routes, ic)
+	route.SetRouteLowStock(routes, lc)
+	route.SetRoutePrice(routes, prc)
+	route.SetRouteAdmin(routes, do.MustInvoke[*logging.Logger](di.Injector))
 
+	return &HttpServer{app: app}
+}
+
+func (s *HttpServer) Listen() error {
 	fmt.Printf("Start Lister\n")
-	app.Listen(fmt.Sprintf("%s:%s", "0.0.0.0", config.GetPort()))
+	return s.app.Listen(fmt.Sprintf("%s:%s", "0.0.0.0", config.GetPort()))
+}
+
+func (s *HttpServer) Shutdown(ctx context.Context) error {
+	return s.app.ShutdownWithContext(ctx)
 }
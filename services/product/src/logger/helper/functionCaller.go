@@ -1,9 +0,0 @@
-package functionCallerInfo
-
-type FunctionCaller string
-
-const (
-	UserRepositoryCreate   FunctionCaller = "userRepository.Create"
-	UserServiceRegister    FunctionCaller = "userService.Register"
-	UserControllerRegister FunctionCaller = "userController.Register"
-)
@@ -0,0 +1,11 @@
+package serviceLogger
+
+import (
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/logging"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/config"
+	"github.com/samber/do/v2"
+)
+
+func NewLoggerInject(i do.Injector) (*logging.Logger, error) {
+	return logging.New(config.GetLogging())
+}
@@ -0,0 +1,32 @@
+package request
+
+type InventoryAdjust struct {
+	ProductId string
+	UserId    string
+	// diisi untuk mengubah stok salah satu varian produk
+	VariantId *string `validate:"omitempty,min=1"`
+	Type      *string `validate:"required,oneof=restock sale reservation correction return"`
+	Delta     *int    `validate:"required,ne=0"`
+	Reason    *string `validate:"required,min=1,max=255"`
+}
+
+type InventoryFilter struct {
+	ProductId string
+	UserId    string
+	Limit     int
+	Offset    int
+}
+
+// Penjualan dari purchase service, semua item dicatat dalam satu transaksi
+type InventorySale struct {
+	PurchaseId string              `validate:"required"`
+	ActorId    string              `validate:"required"`
+	Items      []InventorySaleItem `validate:"required,min=1,dive"`
+}
+
+type InventorySaleItem struct {
+	ProductId string `validate:"required"`
+	// kosong untuk stok produk
+	VariantId string
+	Qty       int `validate:"required,min=1"`
+}
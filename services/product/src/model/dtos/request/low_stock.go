@@ -0,0 +1,8 @@
+package request
+
+type LowStockThreshold struct {
+	ProductId string
+	UserId    string
+	// Threshold null mematikan alert
+	Threshold *int `validate:"omitempty,min=0"`
+}
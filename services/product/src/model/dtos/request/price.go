@@ -0,0 +1,17 @@
+package request
+
+import "time"
+
+type PriceScheduleCreate struct {
+	ProductId string
+	UserId    string
+	Price     *int       `validate:"required,min=100"`
+	StartAt   *time.Time `validate:"required"`
+	EndAt     *time.Time `validate:"omitempty,gtfield=StartAt"`
+}
+
+type PriceTimelineFilter struct {
+	ProductId string
+	Limit     int
+	Offset    int
+}
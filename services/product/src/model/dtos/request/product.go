@@ -3,22 +3,24 @@ package request
 type ProductCreate struct {
 	Name     *string `validate:"required,min=4,max=32"`
 	UserId   string
-	Category *string `validate:"required,category_product"`
-	Qty      *int    `validate:"required,min=1"`
-	Price    *int    `validate:"required,min=100"`
-	Sku      *string `validate:"required,min=0"`
-	FileId   *string `validate:"required"`
+	Category *string        `validate:"required,category_product"`
+	Qty      *int           `validate:"required,min=1"`
+	Price    *int           `validate:"required,min=100"`
+	Sku      *string        `validate:"required,min=0"`
+	FileId   *string        `validate:"required_without=Images"`
+	Images   []ProductImage `validate:"omitempty,min=1,max=10,dive"`
 }
 
 type ProductUpdate struct {
 	Id       string
 	Name     *string `validate:"required,min=4,max=32"`
 	UserId   string
-	Category *string `validate:"required,category_product"`
-	Qty      *int    `validate:"required,min=1"`
-	Price    *int    `validate:"required,min=100"`
-	Sku      *string `validate:"required,min=0"`
-	FileId   *string `validate:"required"`
+	Category *string        `validate:"required,category_product"`
+	Qty      *int           `validate:"required,min=1"`
+	Price    *int           `validate:"required,min=100"`
+	Sku      *string        `validate:"required,min=0"`
+	FileId   *string        `validate:"required_without=Images"`
+	Images   []ProductImage `validate:"omitempty,min=1,max=10,dive"`
 }
 
 type ProductFilter struct {
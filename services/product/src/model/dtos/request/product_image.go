@@ -0,0 +1,7 @@
+package request
+
+type ProductImage struct {
+	FileId    *string `validate:"required"`
+	Position  *int    `validate:"required,min=0"`
+	IsPrimary bool
+}
@@ -0,0 +1,20 @@
+package request
+
+type ProductVariantCreate struct {
+	ProductId  string
+	UserId     string
+	Sku        *string           `validate:"required,min=1,max=32"`
+	Price      *int              `validate:"required,min=100"`
+	Qty        *int              `validate:"required,min=0"`
+	Attributes map[string]string `validate:"required,min=1,dive,keys,min=1,max=32,endkeys,required,max=64"`
+}
+
+type ProductVariantUpdate struct {
+	Id         string
+	ProductId  string
+	UserId     string
+	Sku        *string           `validate:"required,min=1,max=32"`
+	Price      *int              `validate:"required,min=100"`
+	Qty        *int              `validate:"required,min=0"`
+	Attributes map[string]string `validate:"required,min=1,dive,keys,min=1,max=32,endkeys,required,max=64"`
+}
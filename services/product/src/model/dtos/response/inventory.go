@@ -0,0 +1,15 @@
+package response
+
+import "time"
+
+type InventoryEntry struct {
+	EntryId   string    `json:"entryId"`
+	ProductId string    `json:"productId"`
+	VariantId string    `json:"variantId,omitempty"`
+	Type      string    `json:"type"`
+	Delta     int       `json:"delta"`
+	QtyAfter  int       `json:"qtyAfter"`
+	Reason    string    `json:"reason"`
+	ActorId   string    `json:"actorId"`
+	CreatedAt time.Time `json:"createdAt"`
+}
@@ -0,0 +1,6 @@
+package response
+
+type LowStockThreshold struct {
+	ProductId string `json:"productId"`
+	Threshold *int   `json:"threshold"`
+}
@@ -0,0 +1,30 @@
+package response
+
+import "time"
+
+type PriceHistory struct {
+	PreviousPrice *int      `json:"previousPrice"`
+	Price         int       `json:"price"`
+	Source        string    `json:"source"`
+	ScheduleId    *string   `json:"scheduleId,omitempty"`
+	ActorId       string    `json:"actorId"`
+	ChangedAt     time.Time `json:"changedAt"`
+}
+
+type PriceSchedule struct {
+	ScheduleId string     `json:"scheduleId"`
+	ProductId  string     `json:"productId"`
+	Price      int        `json:"price"`
+	StartAt    time.Time  `json:"startAt"`
+	EndAt      *time.Time `json:"endAt"`
+	Status     string     `json:"status"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+type PriceTimeline struct {
+	ProductId   string          `json:"productId"`
+	BasePrice   int             `json:"basePrice"`
+	ActivePrice int             `json:"activePrice"`
+	History     []PriceHistory  `json:"history"`
+	Schedules   []PriceSchedule `json:"schedules"`
+}
@@ -13,15 +13,18 @@ type Product struct {
 }
 
 type ProductCreate struct {
-	ProductId        string    `json:"productId"`
-	Name             string    `json:"Name"`
-	Category         string    `json:"category"`
-	Qty              int       `json:"qty"`
-	Price            int       `json:"price"`
-	Sku              string    `json:"sku"`
-	FileId           string    `json:"fileId"`
-	FileUri          string    `json:"fileUri"`
-	FileThumbnailUri string    `json:"fileThumbnailUri"`
-	CreatedAt        time.Time `json:"createdAt"`
-	UpdatedAt        time.Time `json:"updatedAt"`
+	ProductId         string           `json:"productId"`
+	Name              string           `json:"Name"`
+	Category          string           `json:"category"`
+	Qty               int              `json:"qty"`
+	Price             int              `json:"price"`
+	Sku               string           `json:"sku"`
+	FileId            string           `json:"fileId"`
+	FileUri           string           `json:"fileUri"`
+	FileThumbnailUri  string           `json:"fileThumbnailUri"`
+	Images            []ProductImage   `json:"images,omitempty"`
+	Variants          []ProductVariant `json:"variants"`
+	LowStockThreshold *int             `json:"lowStockThreshold,omitempty"`
+	CreatedAt         time.Time        `json:"createdAt"`
+	UpdatedAt         time.Time        `json:"updatedAt"`
 }
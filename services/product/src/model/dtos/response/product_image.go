@@ -0,0 +1,10 @@
+package response
+
+type ProductImage struct {
+	ImageId          string `json:"imageId"`
+	FileId           string `json:"fileId"`
+	FileUri          string `json:"fileUri"`
+	FileThumbnailUri string `json:"fileThumbnailUri"`
+	Position         int    `json:"position"`
+	IsPrimary        bool   `json:"isPrimary"`
+}
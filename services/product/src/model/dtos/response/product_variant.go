@@ -0,0 +1,14 @@
+package response
+
+import "time"
+
+type ProductVariant struct {
+	VariantId  string            `json:"variantId"`
+	ProductId  string            `json:"productId"`
+	Sku        string            `json:"sku"`
+	Price      int               `json:"price"`
+	Qty        int               `json:"qty"`
+	Attributes map[string]string `json:"attributes"`
+	CreatedAt  time.Time         `json:"createdAt"`
+	UpdatedAt  time.Time         `json:"updatedAt"`
+}
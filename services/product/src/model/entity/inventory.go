@@ -0,0 +1,31 @@
+package entity
+
+import "time"
+
+const (
+	InventoryRestock     = "restock"
+	InventorySale        = "sale"
+	InventoryReservation = "reservation"
+	InventoryCorrection  = "correction"
+	InventoryReturn      = "return"
+)
+
+type InventoryEntry struct {
+	Id        string
+	ProductId string
+	// kosong untuk stok produk, diisi untuk stok varian
+	VariantId string
+	Type      string
+	Delta     int
+	QtyAfter  int
+	Reason    string
+	ActorId   string
+	CreatedAt time.Time
+}
+
+type InventoryDrift struct {
+	ProductId string
+	VariantId string
+	Qty       int
+	LedgerQty int
+}
@@ -0,0 +1,35 @@
+package entity
+
+import "time"
+
+const (
+	PriceScheduleScheduled = "scheduled"
+	PriceScheduleActive    = "active"
+	PriceScheduleCompleted = "completed"
+	PriceScheduleCancelled = "cancelled"
+
+	PriceSourceScheduleStart = "schedule_start"
+	PriceSourceScheduleEnd   = "schedule_end"
+)
+
+type PriceHistory struct {
+	Id            string
+	ProductId     string
+	PreviousPrice *int
+	Price         int
+	Source        string
+	ScheduleId    *string
+	ActorId       string
+	ChangedAt     time.Time
+}
+
+type PriceSchedule struct {
+	Id        string
+	ProductId string
+	Price     int
+	StartAt   time.Time
+	EndAt     *time.Time
+	Status    string
+	CreatedBy string
+	CreatedAt time.Time
+}
@@ -3,14 +3,18 @@ package entity
 import "time"
 
 type Product struct {
-	Id        string
-	UserId    string
-	Name      string
-	Category  string
-	Qty       int
+	Id       string
+	UserId   string
+	Name     string
+	Category string
+	Qty      int
+	// Price is the active price, BasePrice is the seller-managed price without promotions
 	Price     int
+	BasePrice int
 	Sku       string
 	FileId    string
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	// LowStockThreshold is nil when the seller has no alert configured
+	LowStockThreshold *int
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
 }
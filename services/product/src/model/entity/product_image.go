@@ -0,0 +1,12 @@
+package entity
+
+import "time"
+
+type ProductImage struct {
+	Id        string
+	ProductId string
+	FileId    string
+	Position  int
+	IsPrimary bool
+	CreatedAt time.Time
+}
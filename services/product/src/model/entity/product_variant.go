@@ -0,0 +1,14 @@
+package entity
+
+import "time"
+
+type ProductVariant struct {
+	Id         string
+	ProductId  string
+	Sku        string
+	Price      int
+	Qty        int
+	Attributes map[string]string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
@@ -0,0 +1,27 @@
+package notifier
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/logging"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/config"
+	"github.com/samber/do/v2"
+)
+
+func NewInject(i do.Injector) (NotifierInterface, error) {
+	switch config.GetLowStockNotifier() {
+	case "webhook":
+		// without a url every alert would fail at send time, refuse to start instead
+		webhookUrl := config.GetLowStockWebhookUrl()
+		if _, err := url.ParseRequestURI(webhookUrl); err != nil {
+			return nil, fmt.Errorf("LOW_STOCK_WEBHOOK_URL must be a valid url when LOW_STOCK_NOTIFIER=webhook: %w", err)
+		}
+		return NewWebhookNotifier(webhookUrl, &http.Client{Timeout: config.GetLowStockWebhookTimeout()}), nil
+	case "log":
+		return NewLogNotifier(do.MustInvoke[*logging.Logger](i)), nil
+	default:
+		return nil, fmt.Errorf("LOW_STOCK_NOTIFIER %q is not one of log, webhook", config.GetLowStockNotifier())
+	}
+}
@@ -0,0 +1,31 @@
+package notifier
+
+import (
+	"testing"
+
+	"github.com/samber/do/v2"
+)
+
+func TestNewInjectRequiresWebhookUrl(t *testing.T) {
+	t.Setenv("LOW_STOCK_NOTIFIER", "webhook")
+
+	for _, webhookUrl := range []string{"", "not a url"} {
+		t.Setenv("LOW_STOCK_WEBHOOK_URL", webhookUrl)
+		if _, err := NewInject(do.New()); err == nil {
+			t.Fatalf("LOW_STOCK_WEBHOOK_URL=%q: expected an error", webhookUrl)
+		}
+	}
+
+	t.Setenv("LOW_STOCK_WEBHOOK_URL", "https://hooks.example.com/low-stock")
+	if _, err := NewInject(do.New()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewInjectRejectsUnknownNotifier(t *testing.T) {
+	t.Setenv("LOW_STOCK_NOTIFIER", "email")
+
+	if _, err := NewInject(do.New()); err == nil {
+		t.Fatal("expected an error for an unknown notifier")
+	}
+}
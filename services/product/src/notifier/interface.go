@@ -0,0 +1,25 @@
+package notifier
+
+import (
+	"context"
+	"time"
+)
+
+type LowStockEvent struct {
+	Event      string    `json:"event"`
+	ProductId  string    `json:"productId"`
+	VariantId  string    `json:"variantId,omitempty"`
+	SellerId   string    `json:"sellerId"`
+	Name       string    `json:"name"`
+	Sku        string    `json:"sku"`
+	Qty        int       `json:"qty"`
+	Threshold  int       `json:"threshold"`
+	OccurredAt time.Time `json:"occurredAt"`
+}
+
+const LowStockEventName = "product.low_stock"
+
+// NotifierInterface delivers low-stock alerts to sellers
+type NotifierInterface interface {
+	NotifyLowStock(ctx context.Context, event LowStockEvent) error
+}
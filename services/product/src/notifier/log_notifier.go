@@ -0,0 +1,22 @@
+package notifier
+
+import (
+	"context"
+
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/logging"
+)
+
+type LogNotifier struct {
+	Logger *logging.Logger
+}
+
+func NewLogNotifier(logger *logging.Logger) NotifierInterface {
+	return &LogNotifier{
+		Logger: logger,
+	}
+}
+
+func (ln *LogNotifier) NotifyLowStock(ctx context.Context, event LowStockEvent) error {
+	ln.Logger.Warn(ctx, event.Event, "event", event)
+	return nil
+}
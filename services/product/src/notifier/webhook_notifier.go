@@ -0,0 +1,46 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type WebhookNotifier struct {
+	Url    string
+	Client *http.Client
+}
+
+func NewWebhookNotifier(url string, client *http.Client) NotifierInterface {
+	return &WebhookNotifier{
+		Url:    url,
+		Client: client,
+	}
+}
+
+func (wn *WebhookNotifier) NotifyLowStock(ctx context.Context, event LowStockEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, wn.Url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := wn.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s responded with status %d", wn.Url, res.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newLowStockEvent() LowStockEvent {
+	return LowStockEvent{
+		Event:      LowStockEventName,
+		ProductId:  "p1",
+		SellerId:   "s1",
+		Name:       "Kopi Susu",
+		Sku:        "KS-01",
+		Qty:        2,
+		Threshold:  5,
+		OccurredAt: time.Date(2025, 2, 9, 10, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestWebhookNotifierPostsEvent(t *testing.T) {
+	received := make(chan LowStockEvent, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("method = %s, want POST", r.Method)
+		}
+		if got := r.Header.Get("Content-Type"); got != "application/json" {
+			t.Errorf("Content-Type = %q, want application/json", got)
+		}
+
+		var event LowStockEvent
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Errorf("body is not a LowStockEvent: %v", err)
+		}
+		received <- event
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+
+	event := newLowStockEvent()
+	err := NewWebhookNotifier(server.URL, server.Client()).NotifyLowStock(context.Background(), event)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := <-received
+	if got != event {
+		t.Fatalf("received %+v, want %+v", got, event)
+	}
+}
+
+func TestWebhookNotifierRejectsNon2xx(t *testing.T) {
+	for _, status := range []int{http.StatusMovedPermanently, http.StatusBadRequest, http.StatusInternalServerError} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+		}))
+
+		err := NewWebhookNotifier(server.URL, server.Client()).NotifyLowStock(context.Background(), newLowStockEvent())
+		server.Close()
+		if err == nil {
+			t.Fatalf("status %d: expected an error", status)
+		}
+	}
+}
+
+func TestWebhookNotifierRespectsContext(t *testing.T) {
+	arrived := make(chan struct{})
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(arrived)
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { close(release) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-arrived
+		cancel()
+	}()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- NewWebhookNotifier(server.URL, server.Client()).NotifyLowStock(ctx, newLowStockEvent())
+	}()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("err = %v, want context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("NotifyLowStock did not return after the context was cancelled")
+	}
+}
@@ -6,12 +6,66 @@ import (
 
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/model/dtos/request"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/model/entity"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// DBTX is satisfied by *pgxpool.Pool and pgx.Tx, so writes can join the transaction of the caller.
+// Begin on a pgx.Tx starts a savepoint
+type DBTX interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
 type ProductRepoInterface interface {
-	Create(ctx context.Context, pool *pgxpool.Pool, product entity.Product) (productId string, err error)
+	Create(ctx context.Context, db DBTX, product entity.Product) (productId string, err error)
 	DeleteById(ctx context.Context, pool *pgxpool.Pool, productId string, userId string) error
-	UpdateById(ctx context.Context, pool *pgxpool.Pool, product entity.Product) (time.Time, error)
+	UpdateById(ctx context.Context, db DBTX, product entity.Product) (time.Time, error)
 	GetAll(ctx context.Context, pool *pgxpool.Pool, filter request.ProductFilter) ([]entity.Product, error)
+	GetById(ctx context.Context, pool *pgxpool.Pool, productId string) (entity.Product, error)
+	UpdateLowStockThreshold(ctx context.Context, pool *pgxpool.Pool, productId string, userId string, threshold *int) error
+	CountByUserId(ctx context.Context, pool *pgxpool.Pool, userId string) (int64, error)
+	GetAllByUserId(ctx context.Context, pool *pgxpool.Pool, userId string) ([]entity.Product, error)
+	// Hides every product of the seller, returns the number of products unlisted by this call
+	UnlistByUserId(ctx context.Context, pool *pgxpool.Pool, userId string) (int64, error)
+}
+
+type ProductVariantRepoInterface interface {
+	Create(ctx context.Context, db DBTX, variant entity.ProductVariant) (variantId string, err error)
+	// qty tidak diubah, stok varian berubah lewat InventoryRepoInterface
+	UpdateById(ctx context.Context, db DBTX, variant entity.ProductVariant) (time.Time, error)
+	DeleteById(ctx context.Context, pool *pgxpool.Pool, productId string, variantId string) error
+	GetById(ctx context.Context, pool *pgxpool.Pool, productId string, variantId string) (entity.ProductVariant, error)
+	GetByProductId(ctx context.Context, pool *pgxpool.Pool, productId string) ([]entity.ProductVariant, error)
+	GetByProductIds(ctx context.Context, pool *pgxpool.Pool, productIds []string) (map[string][]entity.ProductVariant, error)
+}
+
+type ProductImageRepoInterface interface {
+	ReplaceByProductId(ctx context.Context, db DBTX, productId string, images []entity.ProductImage) ([]entity.ProductImage, error)
+	GetByProductId(ctx context.Context, pool *pgxpool.Pool, productId string) ([]entity.ProductImage, error)
+	GetPrimaryByProductIds(ctx context.Context, pool *pgxpool.Pool, productIds []string) (map[string]entity.ProductImage, error)
+}
+
+type InventoryRepoInterface interface {
+	// Adjust appends entry and applies its delta to products.qty, or to product_variants.qty when
+	// entry.VariantId is set, in one transaction
+	Adjust(ctx context.Context, db DBTX, entry entity.InventoryEntry) (entity.InventoryEntry, error)
+	// SetQty appends the correction needed to move the qty of the product or variant to qty
+	SetQty(ctx context.Context, db DBTX, entry entity.InventoryEntry, qty int) (entity.InventoryEntry, error)
+	GetByProductId(ctx context.Context, pool *pgxpool.Pool, productId string, limit int, offset int) ([]entity.InventoryEntry, error)
+	GetDrift(ctx context.Context, pool *pgxpool.Pool) ([]entity.InventoryDrift, error)
+	// variantId kosong untuk qty produk
+	Reconcile(ctx context.Context, pool *pgxpool.Pool, productId string, variantId string) error
+}
+
+type PriceRepoInterface interface {
+	CreateSchedule(ctx context.Context, pool *pgxpool.Pool, schedule entity.PriceSchedule) (scheduleId string, err error)
+	CancelSchedule(ctx context.Context, pool *pgxpool.Pool, productId string, scheduleId string, actorId string) error
+	GetSchedules(ctx context.Context, pool *pgxpool.Pool, productId string) ([]entity.PriceSchedule, error)
+	GetHistory(ctx context.Context, pool *pgxpool.Pool, productId string, limit int, offset int) ([]entity.PriceHistory, error)
+	// ApplyDueSchedules ends expired schedules and starts due ones, returning how many changed
+	ApplyDueSchedules(ctx context.Context, pool *pgxpool.Pool) (started int, ended int, err error)
 }
@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/exceptions"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/model/entity"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/samber/do/v2"
+)
+
+type InventoryRepository struct {
+}
+
+func NewInventoryRepo() InventoryRepoInterface {
+	return &InventoryRepository{}
+}
+
+func NewInventoryRepoInject(i do.Injector) (InventoryRepoInterface, error) {
+	return NewInventoryRepo(), nil
+}
+
+func (ir *InventoryRepository) Adjust(ctx context.Context, db DBTX, entry entity.InventoryEntry) (entity.InventoryEntry, error) {
+	return ir.apply(ctx, db, entry, func(current int) int {
+		return entry.Delta
+	})
+}
+
+func (ir *InventoryRepository) SetQty(ctx context.Context, db DBTX, entry entity.InventoryEntry, qty int) (entity.InventoryEntry, error) {
+	return ir.apply(ctx, db, entry, func(current int) int {
+		return qty - current
+	})
+}
+
+// apply mengunci baris produk, menghitung delta, lalu menulis ledger dan qty dalam satu transaksi (savepoint kalau db sudah transaksi)
+func (ir *InventoryRepository) apply(ctx context.Context, db DBTX, entry entity.InventoryEntry, delta func(current int) int) (entity.InventoryEntry, error) {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return entry, err
+	}
+	defer tx.Rollback(ctx)
+
+	// entry varian mengubah qty varian, bukan qty produk
+	var current int
+	if entry.VariantId == "" {
+		err = tx.QueryRow(ctx, `SELECT qty FROM products WHERE id = $1 FOR UPDATE`, entry.ProductId).Scan(&current)
+	} else {
+		err = tx.QueryRow(ctx, `SELECT qty FROM product_variants WHERE id = $1 AND product_id = $2 FOR UPDATE`, entry.VariantId, entry.ProductId).Scan(&current)
+	}
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) && entry.VariantId != "" {
+			return entry, exceptions.NewNotFoundError(entry.VariantId + " is not found")
+		}
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entry, exceptions.NewNotFoundError(entry.ProductId + " is not found")
+		}
+		return entry, err
+	}
+
+	entry.Delta = delta(current)
+	if entry.Delta == 0 {
+		entry.QtyAfter = current
+		return entry, nil
+	}
+
+	entry.QtyAfter = current + entry.Delta
+	if entry.QtyAfter < 0 {
+		return entry, exceptions.NewBadRequestError("insufficient stock")
+	}
+
+	if entry.VariantId == "" {
+		_, err = tx.Exec(ctx, `UPDATE products SET qty = $1 WHERE id = $2`, entry.QtyAfter, entry.ProductId)
+	} else {
+		_, err = tx.Exec(ctx, `UPDATE product_variants SET qty = $1 WHERE id = $2`, entry.QtyAfter, entry.VariantId)
+	}
+	if err != nil {
+		return entry, err
+	}
+
+	query := `INSERT INTO inventory_ledger (product_id, variant_id, type, delta, qty_after, reason, actor_id, created_at) VALUES ($1, NULLIF($2, ''), $3, $4, $5, $6, $7, $8) RETURNING id`
+	err = tx.QueryRow(ctx, query, entry.ProductId, entry.VariantId, entry.Type, entry.Delta, entry.QtyAfter, entry.Reason, entry.ActorId, entry.CreatedAt).Scan(&entry.Id)
+	if err != nil {
+		return entry, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return entry, err
+	}
+
+	return entry, nil
+}
+
+func (ir *InventoryRepository) GetByProductId(ctx context.Context, pool *pgxpool.Pool, productId string, limit int, offset int) ([]entity.InventoryEntry, error) {
+	query := `SELECT id, product_id, COALESCE(variant_id, ''), type, delta, qty_after, reason, actor_id, created_at FROM inventory_ledger WHERE product_id = $1 ORDER BY created_at DESC, id DESC LIMIT $2 OFFSET $3`
+
+	rows, err := pool.Query(ctx, query, productId, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []entity.InventoryEntry
+	for rows.Next() {
+		var entry entity.InventoryEntry
+		if err := rows.Scan(&entry.Id, &entry.ProductId, &entry.VariantId, &entry.Type, &entry.Delta, &entry.QtyAfter, &entry.Reason, &entry.ActorId, &entry.CreatedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (ir *InventoryRepository) GetDrift(ctx context.Context, pool *pgxpool.Pool) ([]entity.InventoryDrift, error) {
+	query := `
+	SELECT p.id, '' AS variant_id, p.qty, COALESCE(SUM(l.delta), 0)::int AS ledger_qty
+	FROM products p
+	LEFT JOIN inventory_ledger l ON l.product_id = p.id AND l.variant_id IS NULL
+	GROUP BY p.id, p.qty
+	HAVING p.qty <> COALESCE(SUM(l.delta), 0)
+	UNION ALL
+	SELECT v.product_id, v.id, v.qty, COALESCE(SUM(l.delta), 0)::int
+	FROM product_variants v
+	LEFT JOIN inventory_ledger l ON l.variant_id = v.id
+	GROUP BY v.product_id, v.id, v.qty
+	HAVING v.qty <> COALESCE(SUM(l.delta), 0)
+	ORDER BY 1, 2`
+
+	rows, err := pool.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var drifts []entity.InventoryDrift
+	for rows.Next() {
+		var drift entity.InventoryDrift
+		if err := rows.Scan(&drift.ProductId, &drift.VariantId, &drift.Qty, &drift.LedgerQty); err != nil {
+			return nil, err
+		}
+		drifts = append(drifts, drift)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return drifts, nil
+}
+
+// Reconcile menyamakan products.qty (atau product_variants.qty kalau variantId diisi) dengan total delta di ledger.
+// Baris yang dihitung dikunci seperti di apply, jadi Adjust yang berjalan bersamaan ditunggu dan ikut terhitung
+func (ir *InventoryRepository) Reconcile(ctx context.Context, pool *pgxpool.Pool, productId string, variantId string) error {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// READ COMMITTED, UPDATE setelah lock melihat entry yang di-commit selama menunggu
+	if variantId == "" {
+		_, err = tx.Exec(ctx, `SELECT 1 FROM products WHERE id = $1 FOR UPDATE`, productId)
+		if err != nil {
+			return err
+		}
+
+		query := `UPDATE products SET qty = (SELECT COALESCE(SUM(delta), 0) FROM inventory_ledger WHERE product_id = $1 AND variant_id IS NULL) WHERE id = $1`
+		_, err = tx.Exec(ctx, query, productId)
+	} else {
+		_, err = tx.Exec(ctx, `SELECT 1 FROM product_variants WHERE id = $1 FOR UPDATE`, variantId)
+		if err != nil {
+			return err
+		}
+
+		query := `UPDATE product_variants SET qty = (SELECT COALESCE(SUM(delta), 0) FROM inventory_ledger WHERE variant_id = $1) WHERE id = $1`
+		_, err = tx.Exec(ctx, query, variantId)
+	}
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/exceptions"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/model/entity"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/samber/do/v2"
+)
+
+type PriceRepository struct {
+}
+
+func NewPriceRepo() PriceRepoInterface {
+	return &PriceRepository{}
+}
+
+func NewPriceRepoInject(i do.Injector) (PriceRepoInterface, error) {
+	return NewPriceRepo(), nil
+}
+
+func (pr *PriceRepository) CreateSchedule(ctx context.Context, pool *pgxpool.Pool, schedule entity.PriceSchedule) (scheduleId string, err error) {
+	// jadwal tidak boleh beririsan dengan jadwal lain yang belum selesai
+	query := `
+	INSERT INTO product_price_schedules (product_id, price, start_at, end_at, created_by, created_at)
+	SELECT $1, $2, $3, $4, $5, $6
+	WHERE NOT EXISTS (
+		SELECT 1 FROM product_price_schedules
+		WHERE product_id = $1
+			AND status IN ('scheduled', 'active')
+			AND start_at < COALESCE($4::timestamptz, 'infinity')
+			AND COALESCE(end_at, 'infinity') > $3
+	)
+	RETURNING id`
+
+	row := pool.QueryRow(ctx, query, schedule.ProductId, schedule.Price, schedule.StartAt, schedule.EndAt, schedule.CreatedBy, schedule.CreatedAt)
+	err = row.Scan(&scheduleId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", exceptions.NewConflictError("price schedule overlaps with an existing schedule")
+		}
+		return "", err
+	}
+
+	return scheduleId, nil
+}
+
+func (pr *PriceRepository) CancelSchedule(ctx context.Context, pool *pgxpool.Pool, productId string, scheduleId string, actorId string) error {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var (
+		status string
+		price  int
+	)
+	query := `UPDATE product_price_schedules s SET status = 'cancelled'
+	FROM (SELECT id, status FROM product_price_schedules WHERE id = $1 AND product_id = $2 AND status IN ('scheduled', 'active') FOR UPDATE) old
+	WHERE s.id = old.id
+	RETURNING old.status, s.price`
+	err = tx.QueryRow(ctx, query, scheduleId, productId).Scan(&status, &price)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return exceptions.NewNotFoundError(scheduleId + " is not found")
+		}
+		return err
+	}
+
+	// membatalkan jadwal yang sedang aktif mengembalikan harga dasar
+	if status == entity.PriceScheduleActive {
+		history := `
+		INSERT INTO product_price_history (product_id, previous_price, price, source, schedule_id, actor_id)
+		SELECT id, $1, price, $2, $3, $4 FROM products WHERE id = $5`
+		if _, err := tx.Exec(ctx, history, price, entity.PriceSourceScheduleEnd, scheduleId, actorId, productId); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (pr *PriceRepository) GetSchedules(ctx context.Context, pool *pgxpool.Pool, productId string) ([]entity.PriceSchedule, error) {
+	query := `SELECT id, product_id, price, start_at, end_at, status, created_by, created_at FROM product_price_schedules WHERE product_id = $1 AND status IN ('scheduled', 'active') ORDER BY start_at ASC`
+
+	rows, err := pool.Query(ctx, query, productId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var schedules []entity.PriceSchedule
+	for rows.Next() {
+		var schedule entity.PriceSchedule
+		if err := rows.Scan(&schedule.Id, &schedule.ProductId, &schedule.Price, &schedule.StartAt, &schedule.EndAt, &schedule.Status, &schedule.CreatedBy, &schedule.CreatedAt); err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, schedule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return schedules, nil
+}
+
+func (pr *PriceRepository) GetHistory(ctx context.Context, pool *pgxpool.Pool, productId string, limit int, offset int) ([]entity.PriceHistory, error) {
+	query := `SELECT id, product_id, previous_price, price, source, schedule_id, actor_id, changed_at FROM product_price_history WHERE product_id = $1 ORDER BY changed_at DESC, id DESC LIMIT $2 OFFSET $3`
+
+	rows, err := pool.Query(ctx, query, productId, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var history []entity.PriceHistory
+	for rows.Next() {
+		var entry entity.PriceHistory
+		if err := rows.Scan(&entry.Id, &entry.ProductId, &entry.PreviousPrice, &entry.Price, &entry.Source, &entry.ScheduleId, &entry.ActorId, &entry.ChangedAt); err != nil {
+			return nil, err
+		}
+		history = append(history, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
+func (pr *PriceRepository) ApplyDueSchedules(ctx context.Context, pool *pgxpool.Pool) (started int, ended int, err error) {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Selesaikan jadwal yang sudah lewat end_at lebih dulu agar jadwal berikutnya bisa aktif
+	endQuery := `
+	WITH due AS (
+		UPDATE product_price_schedules SET status = 'completed'
+		WHERE id IN (
+			SELECT id FROM product_price_schedules
+			WHERE status = 'active' AND end_at <= now()
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, product_id, price, created_by
+	)
+	INSERT INTO product_price_history (product_id, previous_price, price, source, schedule_id, actor_id)
+	SELECT due.product_id, due.price, p.price, $1, due.id, due.created_by
+	FROM due JOIN products p ON p.id = due.product_id`
+	tag, err := tx.Exec(ctx, endQuery, entity.PriceSourceScheduleEnd)
+	if err != nil {
+		return 0, 0, err
+	}
+	ended = int(tag.RowsAffected())
+
+	// Jadwal yang sudah berakhir sebelum sempat aktif langsung diselesaikan
+	_, err = tx.Exec(ctx, `UPDATE product_price_schedules SET status = 'completed' WHERE status = 'scheduled' AND end_at <= now()`)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	startQuery := `
+	WITH due AS (
+		UPDATE product_price_schedules SET status = 'active'
+		WHERE id IN (
+			SELECT id FROM product_price_schedules
+			WHERE status = 'scheduled' AND start_at <= now()
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, product_id, price, created_by
+	)
+	INSERT INTO product_price_history (product_id, previous_price, price, source, schedule_id, actor_id)
+	SELECT due.product_id, p.price, due.price, $1, due.id, due.created_by
+	FROM due JOIN products p ON p.id = due.product_id`
+	tag, err = tx.Exec(ctx, startQuery, entity.PriceSourceScheduleStart)
+	if err != nil {
+		return 0, 0, err
+	}
+	started = int(tag.RowsAffected())
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, 0, err
+	}
+
+	return started, ended, nil
+}
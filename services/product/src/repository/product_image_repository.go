@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/model/entity"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/samber/do/v2"
+)
+
+type ProductImageRepository struct {
+}
+
+func NewProductImageRepo() ProductImageRepoInterface {
+	return &ProductImageRepository{}
+}
+
+func NewProductImageRepoInject(i do.Injector) (ProductImageRepoInterface, error) {
+	return NewProductImageRepo(), nil
+}
+
+// ReplaceByProductId mengganti seluruh galeri produk dalam satu transaksi, atau savepoint kalau db sudah transaksi
+func (ir *ProductImageRepository) ReplaceByProductId(ctx context.Context, db DBTX, productId string, images []entity.ProductImage) ([]entity.ProductImage, error) {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM product_images WHERE product_id = $1`, productId); err != nil {
+		return nil, err
+	}
+
+	query := `INSERT INTO product_images (product_id, file_id, position, is_primary, created_at) VALUES ($1, $2, $3, $4, $5) RETURNING id`
+	inserted := make([]entity.ProductImage, 0, len(images))
+	for _, image := range images {
+		image.ProductId = productId
+		if err := tx.QueryRow(ctx, query, productId, image.FileId, image.Position, image.IsPrimary, image.CreatedAt).Scan(&image.Id); err != nil {
+			return nil, err
+		}
+		inserted = append(inserted, image)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return inserted, nil
+}
+
+func (ir *ProductImageRepository) GetByProductId(ctx context.Context, pool *pgxpool.Pool, productId string) ([]entity.ProductImage, error) {
+	query := `SELECT id, product_id, file_id, position, is_primary, created_at FROM product_images WHERE product_id = $1 ORDER BY position ASC`
+
+	rows, err := pool.Query(ctx, query, productId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var images []entity.ProductImage
+	for rows.Next() {
+		var image entity.ProductImage
+		if err := rows.Scan(&image.Id, &image.ProductId, &image.FileId, &image.Position, &image.IsPrimary, &image.CreatedAt); err != nil {
+			return nil, err
+		}
+		images = append(images, image)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return images, nil
+}
+
+func (ir *ProductImageRepository) GetPrimaryByProductIds(ctx context.Context, pool *pgxpool.Pool, productIds []string) (map[string]entity.ProductImage, error) {
+	query := `SELECT id, product_id, file_id, position, is_primary, created_at FROM product_images WHERE product_id = ANY($1::text[]) AND is_primary`
+
+	rows, err := pool.Query(ctx, query, productIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	images := make(map[string]entity.ProductImage, len(productIds))
+	for rows.Next() {
+		var image entity.ProductImage
+		if err := rows.Scan(&image.Id, &image.ProductId, &image.FileId, &image.Position, &image.IsPrimary, &image.CreatedAt); err != nil {
+			return nil, err
+		}
+		images[image.ProductId] = image
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return images, nil
+}
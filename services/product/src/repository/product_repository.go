@@ -2,13 +2,14 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/exceptions"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/model/dtos/request"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/model/entity"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/samber/do/v2"
 )
@@ -24,11 +25,11 @@ func NewInject(i do.Injector) (ProductRepoInterface, error) {
 	return New(), nil
 }
 
-func (pr *ProductRepository) Create(ctx context.Context, pool *pgxpool.Pool, product entity.Product) (productId string, err error) {
+func (pr *ProductRepository) Create(ctx context.Context, db DBTX, product entity.Product) (productId string, err error) {
 
 	query := `INSERT INTO products (user_id, name, category, qty, price, sku, file_id, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id`
 
-	row := pool.QueryRow(ctx, query, product.UserId, product.Name, product.Category, product.Qty, product.Price, product.Sku, product.FileId, product.CreatedAt, product.UpdatedAt)
+	row := db.QueryRow(ctx, query, product.UserId, product.Name, product.Category, product.Qty, product.Price, product.Sku, product.FileId, product.CreatedAt, product.UpdatedAt)
 	err = row.Scan(&productId)
 
 	if err != nil {
@@ -52,11 +53,12 @@ func (pr *ProductRepository) DeleteById(ctx context.Context, pool *pgxpool.Pool,
 	return nil
 }
 
-func (pr *ProductRepository) UpdateById(ctx context.Context, pool *pgxpool.Pool, product entity.Product) (time.Time, error) {
+func (pr *ProductRepository) UpdateById(ctx context.Context, db DBTX, product entity.Product) (time.Time, error) {
 
-	query := `UPDATE products SET name = $1, category = $2 , qty = $3, price = $4, sku = $5, file_id = $6 , updated_at = $7 WHERE id = $8 AND user_id = $9 RETURNING id, created_at`
+	// qty tidak diubah di sini, perubahan stok harus melalui inventory ledger
+	query := `UPDATE products SET name = $1, category = $2, price = $3, sku = $4, file_id = $5, updated_at = $6 WHERE id = $7 AND user_id = $8 RETURNING id, created_at`
 
-	row := pool.QueryRow(ctx, query, product.Name, product.Category, product.Qty, product.Price, product.Sku, product.FileId, product.UpdatedAt, product.Id, product.UserId)
+	row := db.QueryRow(ctx, query, product.Name, product.Category, product.Price, product.Sku, product.FileId, product.UpdatedAt, product.Id, product.UserId)
 
 	var (
 		productId string
@@ -75,22 +77,26 @@ func (pr *ProductRepository) GetAll(ctx context.Context, pool *pgxpool.Pool, fil
 	argCounter := 1
 
 	// Query dasar
-	query := `SELECT id, name, category, qty, price, sku, file_id, created_at, updated_at FROM products WHERE 1=1`
+	// Harga yang ditampilkan adalah harga aktif (jadwal promo yang sedang berjalan atau harga dasar)
+	query := `SELECT p.id, p.name, p.category, p.qty, COALESCE(s.price, p.price) AS price, p.price, p.sku, p.file_id, p.created_at, p.updated_at
+	FROM products p
+	LEFT JOIN product_price_schedules s ON s.product_id = p.id AND s.status = 'active'
+	WHERE p.unlisted_at IS NULL`
 
 	if filter.ProductId != "" {
-		query += fmt.Sprintf(" AND id = $%d", argCounter)
+		query += fmt.Sprintf(" AND p.id = $%d", argCounter)
 		args = append(args, filter.ProductId)
 		argCounter++
 	}
 
 	if filter.Sku != "" {
-		query += fmt.Sprintf(" AND sku = $%d", argCounter)
+		query += fmt.Sprintf(" AND p.sku = $%d", argCounter)
 		args = append(args, filter.Sku)
 		argCounter++
 	}
 
 	if filter.Category != "" {
-		query += fmt.Sprintf(" AND category = $%d", argCounter)
+		query += fmt.Sprintf(" AND p.category = $%d", argCounter)
 		args = append(args, filter.Category)
 		argCounter++
 	}
@@ -98,16 +104,12 @@ func (pr *ProductRepository) GetAll(ctx context.Context, pool *pgxpool.Pool, fil
 	// Sorting berdasarkan SortBy
 	if filter.SortBy != "" {
 		if filter.SortBy == "newest" {
-			query += fmt.Sprintf(" ORDER BY updated_at DESC, created_at DESC")
+			query += fmt.Sprintf(" ORDER BY p.updated_at DESC, p.created_at DESC")
 		}
 		if filter.SortBy == "cheapest" {
-			query += fmt.Sprintf(" ORDER BY price ASC")
-		}
-		if strings.HasPrefix(filter.SortBy, "sold-") {
-			// Ambil angka setelah sold-
-			parts := strings.Split(filter.SortBy, "-")
-			fmt.Print(parts)
+			query += fmt.Sprintf(" ORDER BY COALESCE(s.price, p.price) ASC")
 		}
+		// sold-N belum didukung, urutannya tetap default
 	}
 
 	// Offset
@@ -124,9 +126,6 @@ func (pr *ProductRepository) GetAll(ctx context.Context, pool *pgxpool.Pool, fil
 		argCounter++
 	}
 
-	// Debugging query
-	fmt.Println(query)
-
 	// Eksekusi query
 	rows, err := pool.Query(ctx, query, args...)
 	if err != nil {
@@ -138,7 +137,7 @@ func (pr *ProductRepository) GetAll(ctx context.Context, pool *pgxpool.Pool, fil
 	// Iterasi hasil query
 	for rows.Next() {
 		var product entity.Product
-		if err := rows.Scan(&product.Id, &product.Name, &product.Category, &product.Qty, &product.Price, &product.Sku, &product.FileId, &product.CreatedAt, &product.UpdatedAt); err != nil {
+		if err := rows.Scan(&product.Id, &product.Name, &product.Category, &product.Qty, &product.Price, &product.BasePrice, &product.Sku, &product.FileId, &product.CreatedAt, &product.UpdatedAt); err != nil {
 			return nil, err
 		}
 		products = append(products, product)
@@ -151,3 +150,90 @@ func (pr *ProductRepository) GetAll(ctx context.Context, pool *pgxpool.Pool, fil
 
 	return products, nil
 }
+
+func (pr *ProductRepository) GetById(ctx context.Context, pool *pgxpool.Pool, productId string) (entity.Product, error) {
+	query := `SELECT p.id, p.user_id, p.name, p.category, p.qty, COALESCE(s.price, p.price), p.price, p.sku, p.file_id, p.low_stock_threshold, p.created_at, p.updated_at
+	FROM products p
+	LEFT JOIN product_price_schedules s ON s.product_id = p.id AND s.status = 'active'
+	WHERE p.id = $1 AND p.unlisted_at IS NULL`
+
+	var product entity.Product
+	row := pool.QueryRow(ctx, query, productId)
+	err := row.Scan(&product.Id, &product.UserId, &product.Name, &product.Category, &product.Qty, &product.Price, &product.BasePrice, &product.Sku, &product.FileId, &product.LowStockThreshold, &product.CreatedAt, &product.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.Product{}, exceptions.NewNotFoundError(productId + " is not found")
+		}
+		return entity.Product{}, err
+	}
+
+	return product, nil
+}
+
+func (pr *ProductRepository) UpdateLowStockThreshold(ctx context.Context, pool *pgxpool.Pool, productId string, userId string, threshold *int) error {
+	query := `UPDATE products SET low_stock_threshold = $1 WHERE id = $2 AND user_id = $3 RETURNING id`
+
+	var updatedId string
+	row := pool.QueryRow(ctx, query, threshold, productId, userId)
+	err := row.Scan(&updatedId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return exceptions.NewNotFoundError(productId + " is not found")
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (pr *ProductRepository) CountByUserId(ctx context.Context, pool *pgxpool.Pool, userId string) (int64, error) {
+	query := `SELECT COUNT(*) FROM products WHERE user_id = $1 AND unlisted_at IS NULL`
+
+	var count int64
+	err := pool.QueryRow(ctx, query, userId).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (pr *ProductRepository) GetAllByUserId(ctx context.Context, pool *pgxpool.Pool, userId string) ([]entity.Product, error) {
+	query := `SELECT p.id, p.user_id, p.name, p.category, p.qty, COALESCE(s.price, p.price), p.price, p.sku, p.file_id, p.low_stock_threshold, p.created_at, p.updated_at
+	FROM products p
+	LEFT JOIN product_price_schedules s ON s.product_id = p.id AND s.status = 'active'
+	WHERE p.user_id = $1 AND p.unlisted_at IS NULL
+	ORDER BY p.created_at`
+
+	rows, err := pool.Query(ctx, query, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var products []entity.Product
+	for rows.Next() {
+		var product entity.Product
+		if err := rows.Scan(&product.Id, &product.UserId, &product.Name, &product.Category, &product.Qty, &product.Price, &product.BasePrice, &product.Sku, &product.FileId, &product.LowStockThreshold, &product.CreatedAt, &product.UpdatedAt); err != nil {
+			return nil, err
+		}
+		products = append(products, product)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return products, nil
+}
+
+func (pr *ProductRepository) UnlistByUserId(ctx context.Context, pool *pgxpool.Pool, userId string) (int64, error) {
+	query := `UPDATE products SET unlisted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND unlisted_at IS NULL`
+
+	tag, err := pool.Exec(ctx, query, userId)
+	if err != nil {
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
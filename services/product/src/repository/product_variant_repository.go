@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/exceptions"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/model/entity"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/samber/do/v2"
+)
+
+type ProductVariantRepository struct {
+}
+
+func NewProductVariantRepo() ProductVariantRepoInterface {
+	return &ProductVariantRepository{}
+}
+
+func NewProductVariantRepoInject(i do.Injector) (ProductVariantRepoInterface, error) {
+	return NewProductVariantRepo(), nil
+}
+
+func (vr *ProductVariantRepository) Create(ctx context.Context, db DBTX, variant entity.ProductVariant) (variantId string, err error) {
+	query := `INSERT INTO product_variants (product_id, sku, price, qty, attributes, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
+
+	row := db.QueryRow(ctx, query, variant.ProductId, variant.Sku, variant.Price, variant.Qty, variant.Attributes, variant.CreatedAt, variant.UpdatedAt)
+	err = row.Scan(&variantId)
+	if err != nil {
+		return "", err
+	}
+
+	return variantId, nil
+}
+
+func (vr *ProductVariantRepository) UpdateById(ctx context.Context, db DBTX, variant entity.ProductVariant) (time.Time, error) {
+	// qty tidak diubah di sini, perubahan stok varian harus melalui inventory ledger
+	query := `UPDATE product_variants SET sku = $1, price = $2, attributes = $3, updated_at = $4 WHERE id = $5 AND product_id = $6 RETURNING created_at`
+
+	var createdAt time.Time
+	row := db.QueryRow(ctx, query, variant.Sku, variant.Price, variant.Attributes, variant.UpdatedAt, variant.Id, variant.ProductId)
+	err := row.Scan(&createdAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return variant.UpdatedAt, exceptions.NewNotFoundError(variant.Id + " is not found")
+		}
+		return variant.UpdatedAt, err
+	}
+
+	return createdAt, nil
+}
+
+func (vr *ProductVariantRepository) DeleteById(ctx context.Context, pool *pgxpool.Pool, productId string, variantId string) error {
+	query := `DELETE FROM product_variants WHERE id = $1 AND product_id = $2 RETURNING id`
+
+	var deletedId string
+	row := pool.QueryRow(ctx, query, variantId, productId)
+	err := row.Scan(&deletedId)
+	if err != nil {
+		return exceptions.NewNotFoundError(variantId + " is not found")
+	}
+
+	return nil
+}
+
+func (vr *ProductVariantRepository) GetById(ctx context.Context, pool *pgxpool.Pool, productId string, variantId string) (entity.ProductVariant, error) {
+	query := `SELECT id, product_id, sku, price, qty, attributes, created_at, updated_at FROM product_variants WHERE id = $1 AND product_id = $2`
+
+	var variant entity.ProductVariant
+	row := pool.QueryRow(ctx, query, variantId, productId)
+	err := row.Scan(&variant.Id, &variant.ProductId, &variant.Sku, &variant.Price, &variant.Qty, &variant.Attributes, &variant.CreatedAt, &variant.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.ProductVariant{}, exceptions.NewNotFoundError(variantId + " is not found")
+		}
+		return entity.ProductVariant{}, err
+	}
+
+	return variant, nil
+}
+
+func (vr *ProductVariantRepository) GetByProductId(ctx context.Context, pool *pgxpool.Pool, productId string) ([]entity.ProductVariant, error) {
+	variants, err := vr.GetByProductIds(ctx, pool, []string{productId})
+	if err != nil {
+		return nil, err
+	}
+
+	return variants[productId], nil
+}
+
+func (vr *ProductVariantRepository) GetByProductIds(ctx context.Context, pool *pgxpool.Pool, productIds []string) (map[string][]entity.ProductVariant, error) {
+	query := `SELECT id, product_id, sku, price, qty, attributes, created_at, updated_at FROM product_variants WHERE product_id = ANY($1::text[]) ORDER BY created_at ASC`
+
+	rows, err := pool.Query(ctx, query, productIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	variants := make(map[string][]entity.ProductVariant, len(productIds))
+	for rows.Next() {
+		var variant entity.ProductVariant
+		if err := rows.Scan(&variant.Id, &variant.ProductId, &variant.Sku, &variant.Price, &variant.Qty, &variant.Attributes, &variant.CreatedAt, &variant.UpdatedAt); err != nil {
+			return nil, err
+		}
+		variants[variant.ProductId] = append(variants[variant.ProductId], variant)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return variants, nil
+}
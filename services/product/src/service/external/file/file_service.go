@@ -0,0 +1,124 @@
+package fileService
+
+import (
+	"context"
+	"sync"
+
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/grpcmw"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/health"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/tracing"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/config"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/exceptions"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/grpc/model/file"
+	"github.com/samber/do/v2"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// getFilesLimit bounds the CheckExist calls GetFiles keeps in flight
+const getFilesLimit = 8
+
+type File struct {
+	FileId           string
+	FileUri          string
+	FileThumbnailUri string
+}
+
+type FileServiceInterface interface {
+	// Call to external file service
+	GetFile(ctx context.Context, fileId string) (File, error)
+	// Resolves several files concurrently. Files that could not be resolved are left out of
+	// the result and their error is reported by file id
+	GetFiles(ctx context.Context, fileIds []string) (map[string]File, map[string]error)
+	// Asks the file service through grpc.health.v1, used by the readiness check
+	Ready(ctx context.Context) error
+}
+
+type FileService struct {
+	GrpcClient  file.FileServiceClient
+	HealthCheck health.Check
+}
+
+func New(grpcClient file.FileServiceClient, healthCheck health.Check) FileServiceInterface {
+	return &FileService{
+		GrpcClient:  grpcClient,
+		HealthCheck: healthCheck,
+	}
+}
+
+func NewInject(i do.Injector) (FileServiceInterface, error) {
+	transport, err := grpcmw.ClientTransport(config.GetGrpcTLS())
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := grpc.NewClient(
+		config.GetFileServiceBaseUrl(),
+		append(
+			grpcmw.ClientOptions(grpcmw.ClientConfig{Token: config.GetGrpcServiceToken()}),
+			append(tracing.ClientOptions(), transport)...,
+		)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return New(file.NewFileServiceClient(conn), health.GrpcCheck(conn)), nil
+}
+
+func (fs *FileService) GetFile(ctx context.Context, fileId string) (File, error) {
+	res, err := fs.GrpcClient.CheckExist(ctx, &file.FileRequest{FileId: fileId})
+	if err != nil {
+		switch status.Code(err) {
+		case codes.NotFound, codes.InvalidArgument:
+			return File{}, exceptions.NewBadRequestError("fileId " + fileId + " is not valid")
+		}
+		return File{}, err
+	}
+
+	return File{
+		FileId:           res.FileId,
+		FileUri:          res.FileUri,
+		FileThumbnailUri: res.ThumbnailUri,
+	}, nil
+}
+
+func (fs *FileService) GetFiles(ctx context.Context, fileIds []string) (map[string]File, map[string]error) {
+	files := make(map[string]File, len(fileIds))
+	errs := make(map[string]error)
+
+	var mu sync.Mutex
+	var g errgroup.Group
+	g.SetLimit(getFilesLimit)
+
+	seen := make(map[string]bool, len(fileIds))
+	for _, fileId := range fileIds {
+		if seen[fileId] {
+			continue
+		}
+		seen[fileId] = true
+
+		g.Go(func() error {
+			file, err := fs.GetFile(ctx, fileId)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs[fileId] = err
+			} else {
+				files[fileId] = file
+			}
+			// error per file tidak membatalkan file lain
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	return files, errs
+}
+
+func (fs *FileService) Ready(ctx context.Context) error {
+	return fs.HealthCheck(ctx)
+}
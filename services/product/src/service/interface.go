@@ -5,6 +5,7 @@ import (
 
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/model/dtos/request"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/model/dtos/response"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/model/entity"
 )
 
 type ProductServiceInterface interface {
@@ -12,4 +13,33 @@ type ProductServiceInterface interface {
 	DeletedById(ctx context.Context, productId string, userId string) error
 	UpdateById(ctx context.Context, payload request.ProductUpdate) (response.ProductCreate, error)
 	GetAll(ctx context.Context, filter request.ProductFilter) ([]response.ProductCreate, error)
+	GetById(ctx context.Context, productId string) (response.ProductCreate, error)
+}
+
+type ProductVariantServiceInterface interface {
+	Create(ctx context.Context, payload request.ProductVariantCreate) (response.ProductVariant, error)
+	UpdateById(ctx context.Context, payload request.ProductVariantUpdate) (response.ProductVariant, error)
+	DeleteById(ctx context.Context, productId string, variantId string, userId string) error
+	GetByProductId(ctx context.Context, productId string) ([]response.ProductVariant, error)
+}
+
+type InventoryServiceInterface interface {
+	Adjust(ctx context.Context, payload request.InventoryAdjust) (response.InventoryEntry, error)
+	GetHistory(ctx context.Context, filter request.InventoryFilter) ([]response.InventoryEntry, error)
+	// RecordSale mengurangi stok semua item sekaligus, stok yang kurang menolak seluruh penjualan
+	RecordSale(ctx context.Context, payload request.InventorySale) ([]response.InventoryEntry, error)
+}
+
+type LowStockServiceInterface interface {
+	SetThreshold(ctx context.Context, payload request.LowStockThreshold) (response.LowStockThreshold, error)
+	// Check emits a low-stock alert when entry moved qty below the product threshold
+	Check(ctx context.Context, entry entity.InventoryEntry)
+	// Shutdown waits for the alerts still being delivered until ctx is done
+	Shutdown(ctx context.Context) error
+}
+
+type PriceServiceInterface interface {
+	CreateSchedule(ctx context.Context, payload request.PriceScheduleCreate) (response.PriceSchedule, error)
+	CancelSchedule(ctx context.Context, productId string, scheduleId string, userId string) error
+	GetTimeline(ctx context.Context, filter request.PriceTimelineFilter) (response.PriceTimeline, error)
 }
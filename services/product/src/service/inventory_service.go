@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/logging"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/exceptions"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/model/dtos/request"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/model/dtos/response"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/model/entity"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/repository"
+	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/samber/do/v2"
+)
+
+type InventoryService struct {
+	DB          *pgxpool.Pool
+	ProductRepo repository.ProductRepoInterface
+	StockRepo   repository.InventoryRepoInterface
+	LowStock    LowStockServiceInterface
+	Logger      *logging.Logger
+	Validation  *validator.Validate
+}
+
+func NewInventoryService(db *pgxpool.Pool, productRepo repository.ProductRepoInterface, stockRepo repository.InventoryRepoInterface, lowStock LowStockServiceInterface, logger *logging.Logger, validation *validator.Validate) InventoryServiceInterface {
+	return &InventoryService{
+		DB:          db,
+		ProductRepo: productRepo,
+		StockRepo:   stockRepo,
+		LowStock:    lowStock,
+		Logger:      logger,
+		Validation:  validation,
+	}
+}
+
+func NewInventoryServiceInject(i do.Injector) (InventoryServiceInterface, error) {
+	_db := do.MustInvoke[*pgxpool.Pool](i)
+	_productRepo := do.MustInvoke[repository.ProductRepoInterface](i)
+	_stockRepo := do.MustInvoke[repository.InventoryRepoInterface](i)
+	_lowStock := do.MustInvoke[LowStockServiceInterface](i)
+	_logger := do.MustInvoke[*logging.Logger](i)
+	_validation := do.MustInvoke[*validator.Validate](i)
+
+	return NewInventoryService(_db, _productRepo, _stockRepo, _lowStock, _logger, _validation), nil
+}
+
+func (is *InventoryService) Adjust(ctx context.Context, payload request.InventoryAdjust) (response.InventoryEntry, error) {
+	err := is.Validation.Struct(payload)
+	if err != nil {
+		return response.InventoryEntry{}, exceptions.NewBadRequestError(err.Error())
+	}
+
+	// arah delta harus sesuai dengan jenis perubahan
+	switch *payload.Type {
+	case entity.InventoryRestock, entity.InventoryReturn:
+		if *payload.Delta < 0 {
+			return response.InventoryEntry{}, exceptions.NewBadRequestError(*payload.Type + " delta must be positive")
+		}
+	case entity.InventorySale, entity.InventoryReservation:
+		if *payload.Delta > 0 {
+			return response.InventoryEntry{}, exceptions.NewBadRequestError(*payload.Type + " delta must be negative")
+		}
+	}
+
+	if err := is.checkOwner(ctx, payload.ProductId, payload.UserId); err != nil {
+		return response.InventoryEntry{}, err
+	}
+
+	var variantId string
+	if payload.VariantId != nil {
+		variantId = *payload.VariantId
+	}
+
+	entry, err := is.StockRepo.Adjust(ctx, is.DB, entity.InventoryEntry{
+		ProductId: payload.ProductId,
+		VariantId: variantId,
+		Type:      *payload.Type,
+		Delta:     *payload.Delta,
+		Reason:    *payload.Reason,
+		ActorId:   payload.UserId,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return response.InventoryEntry{}, err
+	}
+	is.LowStock.Check(ctx, entry)
+
+	return toInventoryEntryResponse(entry), nil
+}
+
+func (is *InventoryService) RecordSale(ctx context.Context, payload request.InventorySale) ([]response.InventoryEntry, error) {
+	err := is.Validation.Struct(payload)
+	if err != nil {
+		return nil, exceptions.NewBadRequestError(err.Error())
+	}
+
+	// baris dikunci dengan urutan yang sama di setiap penjualan, dua penjualan bersamaan tidak saling deadlock
+	items := append([]request.InventorySaleItem(nil), payload.Items...)
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].ProductId != items[j].ProductId {
+			return items[i].ProductId < items[j].ProductId
+		}
+		return items[i].VariantId < items[j].VariantId
+	})
+
+	tx, err := is.DB.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	now := time.Now()
+	entries := make([]entity.InventoryEntry, 0, len(items))
+	for _, item := range items {
+		entry, err := is.StockRepo.Adjust(ctx, tx, entity.InventoryEntry{
+			ProductId: item.ProductId,
+			VariantId: item.VariantId,
+			Type:      entity.InventorySale,
+			Delta:     -item.Qty,
+			Reason:    "purchase " + payload.PurchaseId,
+			ActorId:   payload.ActorId,
+			CreatedAt: now,
+		})
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	responses := make([]response.InventoryEntry, 0, len(entries))
+	for _, entry := range entries {
+		// setelah commit, alert tidak dikirim untuk stok yang di-rollback
+		is.LowStock.Check(ctx, entry)
+		responses = append(responses, toInventoryEntryResponse(entry))
+	}
+
+	return responses, nil
+}
+
+func (is *InventoryService) GetHistory(ctx context.Context, filter request.InventoryFilter) ([]response.InventoryEntry, error) {
+	if err := is.checkOwner(ctx, filter.ProductId, filter.UserId); err != nil {
+		return nil, err
+	}
+
+	entries, err := is.StockRepo.GetByProductId(ctx, is.DB, filter.ProductId, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]response.InventoryEntry, 0, len(entries))
+	for _, entry := range entries {
+		responses = append(responses, toInventoryEntryResponse(entry))
+	}
+
+	return responses, nil
+}
+
+func (is *InventoryService) checkOwner(ctx context.Context, productId string, userId string) error {
+	product, err := is.ProductRepo.GetById(ctx, is.DB, productId)
+	if err != nil {
+		return err
+	}
+	if product.UserId != userId {
+		return exceptions.NewNotFoundError(productId + " is not found")
+	}
+
+	return nil
+}
+
+func toInventoryEntryResponse(entry entity.InventoryEntry) response.InventoryEntry {
+	return response.InventoryEntry{
+		EntryId:   entry.Id,
+		ProductId: entry.ProductId,
+		VariantId: entry.VariantId,
+		Type:      entry.Type,
+		Delta:     entry.Delta,
+		QtyAfter:  entry.QtyAfter,
+		Reason:    entry.Reason,
+		ActorId:   entry.ActorId,
+		CreatedAt: entry.CreatedAt,
+	}
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/logging"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/exceptions"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/model/dtos/request"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/model/dtos/response"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/model/entity"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/notifier"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/repository"
+	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/samber/do/v2"
+)
+
+const lowStockNotifyTimeout = 10 * time.Second
+
+type LowStockService struct {
+	DB          *pgxpool.Pool
+	ProductRepo repository.ProductRepoInterface
+	VariantRepo repository.ProductVariantRepoInterface
+	Notifier    notifier.NotifierInterface
+	Logger      *logging.Logger
+	Validation  *validator.Validate
+
+	pending sync.WaitGroup
+}
+
+func NewLowStockService(db *pgxpool.Pool, productRepo repository.ProductRepoInterface, variantRepo repository.ProductVariantRepoInterface, notifier notifier.NotifierInterface, logger *logging.Logger, validation *validator.Validate) LowStockServiceInterface {
+	return &LowStockService{
+		DB:          db,
+		ProductRepo: productRepo,
+		VariantRepo: variantRepo,
+		Notifier:    notifier,
+		Logger:      logger,
+		Validation:  validation,
+	}
+}
+
+func NewLowStockServiceInject(i do.Injector) (LowStockServiceInterface, error) {
+	_db := do.MustInvoke[*pgxpool.Pool](i)
+	_productRepo := do.MustInvoke[repository.ProductRepoInterface](i)
+	_variantRepo := do.MustInvoke[repository.ProductVariantRepoInterface](i)
+	_notifier := do.MustInvoke[notifier.NotifierInterface](i)
+	_logger := do.MustInvoke[*logging.Logger](i)
+	_validation := do.MustInvoke[*validator.Validate](i)
+
+	return NewLowStockService(_db, _productRepo, _variantRepo, _notifier, _logger, _validation), nil
+}
+
+func (ls *LowStockService) SetThreshold(ctx context.Context, payload request.LowStockThreshold) (response.LowStockThreshold, error) {
+	err := ls.Validation.Struct(payload)
+	if err != nil {
+		return response.LowStockThreshold{}, exceptions.NewBadRequestError(err.Error())
+	}
+
+	err = ls.ProductRepo.UpdateLowStockThreshold(ctx, ls.DB, payload.ProductId, payload.UserId, payload.Threshold)
+	if err != nil {
+		return response.LowStockThreshold{}, err
+	}
+
+	return response.LowStockThreshold{
+		ProductId: payload.ProductId,
+		Threshold: payload.Threshold,
+	}, nil
+}
+
+func (ls *LowStockService) Check(ctx context.Context, entry entity.InventoryEntry) {
+	if entry.Delta >= 0 {
+		return
+	}
+
+	product, err := ls.ProductRepo.GetById(ctx, ls.DB, entry.ProductId)
+	if err != nil {
+		ls.Logger.Error(ctx, "lowStockService.Check failed", "error", err, "productId", entry.ProductId)
+		return
+	}
+	if product.LowStockThreshold == nil {
+		return
+	}
+
+	// hanya kirim alert saat qty melewati batas, bukan setiap kali qty di bawah batas
+	threshold := *product.LowStockThreshold
+	qtyBefore := entry.QtyAfter - entry.Delta
+	if qtyBefore < threshold || entry.QtyAfter >= threshold {
+		return
+	}
+
+	event := notifier.LowStockEvent{
+		Event:      notifier.LowStockEventName,
+		ProductId:  product.Id,
+		SellerId:   product.UserId,
+		Name:       product.Name,
+		Sku:        product.Sku,
+		Qty:        entry.QtyAfter,
+		Threshold:  threshold,
+		OccurredAt: entry.CreatedAt,
+	}
+
+	// batas stok berlaku per varian, alert-nya membawa sku varian
+	if entry.VariantId != "" {
+		variant, err := ls.VariantRepo.GetById(ctx, ls.DB, entry.ProductId, entry.VariantId)
+		if err != nil {
+			ls.Logger.Error(ctx, "lowStockService.Check failed", "error", err, "productId", entry.ProductId, "variantId", entry.VariantId)
+			return
+		}
+		event.VariantId = variant.Id
+		event.Sku = variant.Sku
+	}
+
+	// pengiriman tidak boleh memperlambat request yang mengubah stok
+	ls.pending.Add(1)
+	go func() {
+		defer ls.pending.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), lowStockNotifyTimeout)
+		defer cancel()
+
+		if err := ls.Notifier.NotifyLowStock(ctx, event); err != nil {
+			ls.Logger.Error(ctx, "lowStockService.Check failed", "error", err, "event", event)
+		}
+	}()
+}
+
+func (ls *LowStockService) Shutdown(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		ls.pending.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/logging"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/exceptions"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/model/dtos/request"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/model/dtos/response"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/model/entity"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/repository"
+	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/samber/do/v2"
+)
+
+type PriceService struct {
+	DB          *pgxpool.Pool
+	ProductRepo repository.ProductRepoInterface
+	PriceRepo   repository.PriceRepoInterface
+	Logger      *logging.Logger
+	Validation  *validator.Validate
+}
+
+func NewPriceService(db *pgxpool.Pool, productRepo repository.ProductRepoInterface, priceRepo repository.PriceRepoInterface, logger *logging.Logger, validation *validator.Validate) PriceServiceInterface {
+	return &PriceService{
+		DB:          db,
+		ProductRepo: productRepo,
+		PriceRepo:   priceRepo,
+		Logger:      logger,
+		Validation:  validation,
+	}
+}
+
+func NewPriceServiceInject(i do.Injector) (PriceServiceInterface, error) {
+	_db := do.MustInvoke[*pgxpool.Pool](i)
+	_productRepo := do.MustInvoke[repository.ProductRepoInterface](i)
+	_priceRepo := do.MustInvoke[repository.PriceRepoInterface](i)
+	_logger := do.MustInvoke[*logging.Logger](i)
+	_validation := do.MustInvoke[*validator.Validate](i)
+
+	return NewPriceService(_db, _productRepo, _priceRepo, _logger, _validation), nil
+}
+
+func (ps *PriceService) CreateSchedule(ctx context.Context, payload request.PriceScheduleCreate) (response.PriceSchedule, error) {
+	err := ps.Validation.Struct(payload)
+	if err != nil {
+		return response.PriceSchedule{}, exceptions.NewBadRequestError(err.Error())
+	}
+
+	now := time.Now()
+	if payload.EndAt != nil && !payload.EndAt.After(now) {
+		return response.PriceSchedule{}, exceptions.NewBadRequestError("endAt must be in the future")
+	}
+
+	if err := ps.checkOwner(ctx, payload.ProductId, payload.UserId); err != nil {
+		return response.PriceSchedule{}, err
+	}
+
+	schedule := entity.PriceSchedule{
+		ProductId: payload.ProductId,
+		Price:     *payload.Price,
+		StartAt:   *payload.StartAt,
+		EndAt:     payload.EndAt,
+		Status:    entity.PriceScheduleScheduled,
+		CreatedBy: payload.UserId,
+		CreatedAt: now,
+	}
+
+	id, err := ps.PriceRepo.CreateSchedule(ctx, ps.DB, schedule)
+	if err != nil {
+		return response.PriceSchedule{}, err
+	}
+	schedule.Id = id
+
+	return toPriceScheduleResponse(schedule), nil
+}
+
+func (ps *PriceService) CancelSchedule(ctx context.Context, productId string, scheduleId string, userId string) error {
+	if err := ps.checkOwner(ctx, productId, userId); err != nil {
+		return err
+	}
+
+	return ps.PriceRepo.CancelSchedule(ctx, ps.DB, productId, scheduleId, userId)
+}
+
+func (ps *PriceService) GetTimeline(ctx context.Context, filter request.PriceTimelineFilter) (response.PriceTimeline, error) {
+	product, err := ps.ProductRepo.GetById(ctx, ps.DB, filter.ProductId)
+	if err != nil {
+		return response.PriceTimeline{}, err
+	}
+
+	history, err := ps.PriceRepo.GetHistory(ctx, ps.DB, filter.ProductId, filter.Limit, filter.Offset)
+	if err != nil {
+		return response.PriceTimeline{}, err
+	}
+
+	schedules, err := ps.PriceRepo.GetSchedules(ctx, ps.DB, filter.ProductId)
+	if err != nil {
+		return response.PriceTimeline{}, err
+	}
+
+	timeline := response.PriceTimeline{
+		ProductId:   product.Id,
+		BasePrice:   product.BasePrice,
+		ActivePrice: product.Price,
+		History:     make([]response.PriceHistory, 0, len(history)),
+		Schedules:   make([]response.PriceSchedule, 0, len(schedules)),
+	}
+	for _, entry := range history {
+		timeline.History = append(timeline.History, response.PriceHistory{
+			PreviousPrice: entry.PreviousPrice,
+			Price:         entry.Price,
+			Source:        entry.Source,
+			ScheduleId:    entry.ScheduleId,
+			ActorId:       entry.ActorId,
+			ChangedAt:     entry.ChangedAt,
+		})
+	}
+	for _, schedule := range schedules {
+		timeline.Schedules = append(timeline.Schedules, toPriceScheduleResponse(schedule))
+	}
+
+	return timeline, nil
+}
+
+func (ps *PriceService) checkOwner(ctx context.Context, productId string, userId string) error {
+	product, err := ps.ProductRepo.GetById(ctx, ps.DB, productId)
+	if err != nil {
+		return err
+	}
+	if product.UserId != userId {
+		return exceptions.NewNotFoundError(productId + " is not found")
+	}
+
+	return nil
+}
+
+func toPriceScheduleResponse(schedule entity.PriceSchedule) response.PriceSchedule {
+	return response.PriceSchedule{
+		ScheduleId: schedule.Id,
+		ProductId:  schedule.ProductId,
+		Price:      schedule.Price,
+		StartAt:    schedule.StartAt,
+		EndAt:      schedule.EndAt,
+		Status:     schedule.Status,
+		CreatedAt:  schedule.CreatedAt,
+	}
+}
@@ -2,14 +2,16 @@ package service
 
 import (
 	"context"
+	"sort"
 	"time"
 
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/logging"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/exceptions"
-	loggerZap "github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/logger/zap"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/model/dtos/request"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/model/dtos/response"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/model/entity"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/repository"
+	fileService "github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/service/external/file"
 	"github.com/go-playground/validator/v10"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/samber/do/v2"
@@ -18,14 +20,24 @@ import (
 type ProductService struct {
 	DB          *pgxpool.Pool
 	ProductRepo repository.ProductRepoInterface
-	Logger      loggerZap.LoggerInterface
+	VariantRepo repository.ProductVariantRepoInterface
+	ImageRepo   repository.ProductImageRepoInterface
+	StockRepo   repository.InventoryRepoInterface
+	FileService fileService.FileServiceInterface
+	LowStock    LowStockServiceInterface
+	Logger      *logging.Logger
 	Validation  *validator.Validate
 }
 
-func New(db *pgxpool.Pool, productRepo repository.ProductRepoInterface, logger loggerZap.LoggerInterface, validation *validator.Validate) ProductServiceInterface {
+func New(db *pgxpool.Pool, productRepo repository.ProductRepoInterface, variantRepo repository.ProductVariantRepoInterface, imageRepo repository.ProductImageRepoInterface, stockRepo repository.InventoryRepoInterface, fileService fileService.FileServiceInterface, lowStock LowStockServiceInterface, logger *logging.Logger, validation *validator.Validate) ProductServiceInterface {
 	return &ProductService{
 		DB:          db,
 		ProductRepo: productRepo,
+		VariantRepo: variantRepo,
+		ImageRepo:   imageRepo,
+		StockRepo:   stockRepo,
+		FileService: fileService,
+		LowStock:    lowStock,
 		Logger:      logger,
 		Validation:  validation,
 	}
@@ -34,10 +46,15 @@ func New(db *pgxpool.Pool, productRepo repository.ProductRepoInterface, logger l
 func NewInject(i do.Injector) (ProductServiceInterface, error) {
 	_db := do.MustInvoke[*pgxpool.Pool](i)
 	_productRepo := do.MustInvoke[repository.ProductRepoInterface](i)
-	_logger := do.MustInvoke[loggerZap.LoggerInterface](i)
+	_variantRepo := do.MustInvoke[repository.ProductVariantRepoInterface](i)
+	_imageRepo := do.MustInvoke[repository.ProductImageRepoInterface](i)
+	_stockRepo := do.MustInvoke[repository.InventoryRepoInterface](i)
+	_fileService := do.MustInvoke[fileService.FileServiceInterface](i)
+	_lowStock := do.MustInvoke[LowStockServiceInterface](i)
+	_logger := do.MustInvoke[*logging.Logger](i)
 	_validation := do.MustInvoke[*validator.Validate](i)
 
-	return New(_db, _productRepo, _logger, _validation), nil
+	return New(_db, _productRepo, _variantRepo, _imageRepo, _stockRepo, _fileService, _lowStock, _logger, _validation), nil
 }
 
 func (ps *ProductService) Create(ctx context.Context, payload request.ProductCreate) (response.ProductCreate, error) {
@@ -46,27 +63,69 @@ func (ps *ProductService) Create(ctx context.Context, payload request.ProductCre
 		return response.ProductCreate{}, exceptions.NewBadRequestError(err.Error())
 	}
 
-	// TODO FindFileId
+	images, err := ps.buildImages(payload.FileId, payload.Images)
+	if err != nil {
+		return response.ProductCreate{}, err
+	}
+
+	files, err := ps.checkFiles(ctx, images)
+	if err != nil {
+		return response.ProductCreate{}, err
+	}
 
 	time := time.Now()
 	product := entity.Product{
 		UserId:    payload.UserId,
 		Name:      *payload.Name,
 		Category:  *payload.Category,
-		Qty:       *payload.Qty,
+		Qty:       0,
 		Price:     *payload.Price,
 		Sku:       *payload.Sku,
-		FileId:    *payload.FileId,
+		FileId:    primaryImage(images).FileId,
 		CreatedAt: time,
 		UpdatedAt: time,
 	}
 
-	id, err := ps.ProductRepo.Create(ctx, ps.DB, product)
+	// produk, stok awal dan galeri ditulis dalam satu transaksi, gagal di tengah tidak meninggalkan produk setengah jadi
+	tx, err := ps.DB.Begin(ctx)
+	if err != nil {
+		return response.ProductCreate{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	id, err := ps.ProductRepo.Create(ctx, tx, product)
 
 	if err != nil {
 		return response.ProductCreate{}, err
 	}
 
+	// stok awal dicatat sebagai restock agar qty selalu bisa diturunkan dari ledger
+	entry, err := ps.StockRepo.Adjust(ctx, tx, entity.InventoryEntry{
+		ProductId: id,
+		Type:      entity.InventoryRestock,
+		Delta:     *payload.Qty,
+		Reason:    "initial stock",
+		ActorId:   payload.UserId,
+		CreatedAt: time,
+	})
+	if err != nil {
+		return response.ProductCreate{}, err
+	}
+	product.Qty = entry.QtyAfter
+
+	for i := range images {
+		images[i].CreatedAt = time
+	}
+	images, err = ps.ImageRepo.ReplaceByProductId(ctx, tx, id, images)
+	if err != nil {
+		return response.ProductCreate{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return response.ProductCreate{}, err
+	}
+
+	primary := files[product.FileId]
 	return response.ProductCreate{
 		ProductId:        id,
 		Name:             product.Name,
@@ -75,8 +134,10 @@ func (ps *ProductService) Create(ctx context.Context, payload request.ProductCre
 		Sku:              product.Sku,
 		FileId:           product.FileId,
 		Category:         product.Category,
-		FileUri:          "",
-		FileThumbnailUri: "",
+		FileUri:          primary.FileUri,
+		FileThumbnailUri: primary.FileThumbnailUri,
+		Images:           toProductImageResponses(images, files),
+		Variants:         []response.ProductVariant{},
 		CreatedAt:        product.CreatedAt,
 		UpdatedAt:        product.UpdatedAt,
 	}, nil
@@ -101,7 +162,15 @@ func (ps *ProductService) UpdateById(ctx context.Context, payload request.Produc
 		return response.ProductCreate{}, exceptions.NewBadRequestError(err.Error())
 	}
 
-	// TODO FIND FILEID
+	images, err := ps.buildImages(payload.FileId, payload.Images)
+	if err != nil {
+		return response.ProductCreate{}, err
+	}
+
+	files, err := ps.checkFiles(ctx, images)
+	if err != nil {
+		return response.ProductCreate{}, err
+	}
 
 	time := time.Now()
 
@@ -113,14 +182,54 @@ func (ps *ProductService) UpdateById(ctx context.Context, payload request.Produc
 		Qty:       *payload.Qty,
 		Price:     *payload.Price,
 		Sku:       *payload.Sku,
-		FileId:    *payload.FileId,
+		FileId:    primaryImage(images).FileId,
 		UpdatedAt: time,
 	}
 
-	createdAt, err := ps.ProductRepo.UpdateById(ctx, ps.DB, product)
+	// sama seperti Create, produk, stok dan galeri berubah bersama atau tidak sama sekali
+	tx, err := ps.DB.Begin(ctx)
+	if err != nil {
+		return response.ProductCreate{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	createdAt, err := ps.ProductRepo.UpdateById(ctx, tx, product)
+	if err != nil {
+		return response.ProductCreate{}, err
+	}
+
+	entry, err := ps.StockRepo.SetQty(ctx, tx, entity.InventoryEntry{
+		ProductId: payload.Id,
+		Type:      entity.InventoryCorrection,
+		Reason:    "product update",
+		ActorId:   payload.UserId,
+		CreatedAt: time,
+	}, *payload.Qty)
+	if err != nil {
+		return response.ProductCreate{}, err
+	}
+	product.Qty = entry.QtyAfter
+
+	for i := range images {
+		images[i].CreatedAt = time
+	}
+	images, err = ps.ImageRepo.ReplaceByProductId(ctx, tx, payload.Id, images)
+	if err != nil {
+		return response.ProductCreate{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return response.ProductCreate{}, err
+	}
+	// setelah commit, alert tidak dikirim untuk stok yang di-rollback
+	ps.LowStock.Check(ctx, entry)
+
+	variants, err := ps.VariantRepo.GetByProductId(ctx, ps.DB, payload.Id)
 	if err != nil {
 		return response.ProductCreate{}, err
 	}
+
+	primary := files[product.FileId]
 	return response.ProductCreate{
 		ProductId:        payload.Id,
 		Name:             product.Name,
@@ -129,13 +238,60 @@ func (ps *ProductService) UpdateById(ctx context.Context, payload request.Produc
 		Sku:              product.Sku,
 		FileId:           product.FileId,
 		Category:         product.Category,
-		FileUri:          "",
-		FileThumbnailUri: "",
+		FileUri:          primary.FileUri,
+		FileThumbnailUri: primary.FileThumbnailUri,
+		Images:           toProductImageResponses(images, files),
+		Variants:         ToProductVariantResponses(variants),
 		CreatedAt:        createdAt,
 		UpdatedAt:        product.UpdatedAt,
 	}, nil
 }
 
+func (ps *ProductService) GetById(ctx context.Context, productId string) (response.ProductCreate, error) {
+	product, err := ps.ProductRepo.GetById(ctx, ps.DB, productId)
+	if err != nil {
+		return response.ProductCreate{}, err
+	}
+
+	images, err := ps.ImageRepo.GetByProductId(ctx, ps.DB, productId)
+	if err != nil {
+		return response.ProductCreate{}, err
+	}
+
+	files := make(map[string]fileService.File, len(images))
+	for _, image := range images {
+		file, err := ps.FileService.GetFile(ctx, image.FileId)
+		if err != nil {
+			ps.Logger.Warn(ctx, "unable to fetch product image", "error", err, "fileId", image.FileId)
+			continue
+		}
+		files[image.FileId] = file
+	}
+
+	variants, err := ps.VariantRepo.GetByProductId(ctx, ps.DB, productId)
+	if err != nil {
+		return response.ProductCreate{}, err
+	}
+
+	primary := files[primaryImage(images).FileId]
+	return response.ProductCreate{
+		ProductId:         product.Id,
+		Name:              product.Name,
+		Category:          product.Category,
+		Qty:               product.Qty,
+		Price:             product.Price,
+		Sku:               product.Sku,
+		FileId:            product.FileId,
+		FileUri:           primary.FileUri,
+		FileThumbnailUri:  primary.FileThumbnailUri,
+		Images:            toProductImageResponses(images, files),
+		Variants:          ToProductVariantResponses(variants),
+		LowStockThreshold: product.LowStockThreshold,
+		CreatedAt:         product.CreatedAt,
+		UpdatedAt:         product.UpdatedAt,
+	}, nil
+}
+
 func (ps *ProductService) GetAll(ctx context.Context, filter request.ProductFilter) ([]response.ProductCreate, error) {
 	// err := ps.Validation.Struct(filter)
 
@@ -145,30 +301,155 @@ func (ps *ProductService) GetAll(ctx context.Context, filter request.ProductFilt
 
 	products, err := ps.ProductRepo.GetAll(ctx, ps.DB, filter)
 
-	// TODO GET FILE SERVICE
-
 	if err != nil {
 		return nil, exceptions.NewBadRequestError(err.Error())
 	}
 
+	productIds := make([]string, 0, len(products))
+	for _, product := range products {
+		productIds = append(productIds, product.Id)
+	}
+
+	variants, err := ps.VariantRepo.GetByProductIds(ctx, ps.DB, productIds)
+	if err != nil {
+		return nil, err
+	}
+
+	primaryImages, err := ps.ImageRepo.GetPrimaryByProductIds(ctx, ps.DB, productIds)
+	if err != nil {
+		return nil, err
+	}
+
+	// file service tidak punya RPC batch, jadi thumbnail di-resolve paralel dengan batas
+	fileIds := make([]string, 0, len(primaryImages))
+	for _, image := range primaryImages {
+		fileIds = append(fileIds, image.FileId)
+	}
+	files, fileErrs := ps.FileService.GetFiles(ctx, fileIds)
+	for fileId, err := range fileErrs {
+		ps.Logger.Warn(ctx, "unable to fetch product image", "error", err, "fileId", fileId)
+	}
+
 	var productResponses []response.ProductCreate
 
 	for _, product := range products {
 		productResponse := response.ProductCreate{
-			ProductId:        product.Id,
-			Name:             product.Name,
-			Category:         product.Category,
-			Qty:              product.Qty,
-			Price:            product.Price,
-			Sku:              product.Sku,
-			FileId:           product.FileId,
-			FileThumbnailUri: "",
-			FileUri:          "",
-			CreatedAt:        product.CreatedAt,
-			UpdatedAt:        product.UpdatedAt,
+			ProductId: product.Id,
+			Name:      product.Name,
+			Category:  product.Category,
+			Qty:       product.Qty,
+			Price:     product.Price,
+			Sku:       product.Sku,
+			FileId:    product.FileId,
+			Variants:  ToProductVariantResponses(variants[product.Id]),
+			CreatedAt: product.CreatedAt,
+			UpdatedAt: product.UpdatedAt,
+		}
+
+		// listing hanya menampilkan thumbnail dari gambar utama
+		if image, ok := primaryImages[product.Id]; ok {
+			if file, ok := files[image.FileId]; ok {
+				productResponse.FileId = image.FileId
+				productResponse.FileUri = file.FileUri
+				productResponse.FileThumbnailUri = file.FileThumbnailUri
+			}
 		}
+
 		productResponses = append(productResponses, productResponse)
 	}
 
 	return productResponses, nil
 }
+
+// buildImages normalizes the gallery of a create/update payload. A payload
+// without images falls back to the single fileId as the primary image.
+func (ps *ProductService) buildImages(fileId *string, payload []request.ProductImage) ([]entity.ProductImage, error) {
+	if len(payload) == 0 {
+		if fileId == nil {
+			return nil, exceptions.NewBadRequestError("fileId or images is required")
+		}
+		return []entity.ProductImage{{FileId: *fileId, Position: 0, IsPrimary: true}}, nil
+	}
+
+	images := make([]entity.ProductImage, 0, len(payload))
+	positions := make(map[int]bool, len(payload))
+	primaryCount := 0
+	for _, image := range payload {
+		if positions[*image.Position] {
+			return nil, exceptions.NewBadRequestError("image position must be unique")
+		}
+		positions[*image.Position] = true
+
+		if image.IsPrimary {
+			primaryCount++
+		}
+
+		images = append(images, entity.ProductImage{
+			FileId:    *image.FileId,
+			Position:  *image.Position,
+			IsPrimary: image.IsPrimary,
+		})
+	}
+
+	if primaryCount > 1 {
+		return nil, exceptions.NewBadRequestError("only one image can be primary")
+	}
+
+	sort.Slice(images, func(i, j int) bool {
+		return images[i].Position < images[j].Position
+	})
+
+	if primaryCount == 0 {
+		images[0].IsPrimary = true
+	}
+
+	return images, nil
+}
+
+// checkFiles validates every image against the file service
+func (ps *ProductService) checkFiles(ctx context.Context, images []entity.ProductImage) (map[string]fileService.File, error) {
+	files := make(map[string]fileService.File, len(images))
+	for _, image := range images {
+		if _, ok := files[image.FileId]; ok {
+			continue
+		}
+
+		file, err := ps.FileService.GetFile(ctx, image.FileId)
+		if err != nil {
+			return nil, err
+		}
+		files[image.FileId] = file
+	}
+
+	return files, nil
+}
+
+func primaryImage(images []entity.ProductImage) entity.ProductImage {
+	for _, image := range images {
+		if image.IsPrimary {
+			return image
+		}
+	}
+	if len(images) > 0 {
+		return images[0]
+	}
+
+	return entity.ProductImage{}
+}
+
+func toProductImageResponses(images []entity.ProductImage, files map[string]fileService.File) []response.ProductImage {
+	responses := make([]response.ProductImage, 0, len(images))
+	for _, image := range images {
+		file := files[image.FileId]
+		responses = append(responses, response.ProductImage{
+			ImageId:          image.Id,
+			FileId:           image.FileId,
+			FileUri:          file.FileUri,
+			FileThumbnailUri: file.FileThumbnailUri,
+			Position:         image.Position,
+			IsPrimary:        image.IsPrimary,
+		})
+	}
+
+	return responses
+}
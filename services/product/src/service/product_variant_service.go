@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/logging"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/exceptions"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/model/dtos/request"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/model/dtos/response"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/model/entity"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/repository"
+	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/samber/do/v2"
+)
+
+const pgUniqueViolation = "23505"
+
+type ProductVariantService struct {
+	DB          *pgxpool.Pool
+	ProductRepo repository.ProductRepoInterface
+	VariantRepo repository.ProductVariantRepoInterface
+	StockRepo   repository.InventoryRepoInterface
+	LowStock    LowStockServiceInterface
+	Logger      *logging.Logger
+	Validation  *validator.Validate
+}
+
+func NewProductVariantService(db *pgxpool.Pool, productRepo repository.ProductRepoInterface, variantRepo repository.ProductVariantRepoInterface, stockRepo repository.InventoryRepoInterface, lowStock LowStockServiceInterface, logger *logging.Logger, validation *validator.Validate) ProductVariantServiceInterface {
+	return &ProductVariantService{
+		DB:          db,
+		ProductRepo: productRepo,
+		VariantRepo: variantRepo,
+		StockRepo:   stockRepo,
+		LowStock:    lowStock,
+		Logger:      logger,
+		Validation:  validation,
+	}
+}
+
+func NewProductVariantServiceInject(i do.Injector) (ProductVariantServiceInterface, error) {
+	_db := do.MustInvoke[*pgxpool.Pool](i)
+	_productRepo := do.MustInvoke[repository.ProductRepoInterface](i)
+	_variantRepo := do.MustInvoke[repository.ProductVariantRepoInterface](i)
+	_stockRepo := do.MustInvoke[repository.InventoryRepoInterface](i)
+	_lowStock := do.MustInvoke[LowStockServiceInterface](i)
+	_logger := do.MustInvoke[*logging.Logger](i)
+	_validation := do.MustInvoke[*validator.Validate](i)
+
+	return NewProductVariantService(_db, _productRepo, _variantRepo, _stockRepo, _lowStock, _logger, _validation), nil
+}
+
+func (vs *ProductVariantService) Create(ctx context.Context, payload request.ProductVariantCreate) (response.ProductVariant, error) {
+	err := vs.Validation.Struct(payload)
+	if err != nil {
+		return response.ProductVariant{}, exceptions.NewBadRequestError(err.Error())
+	}
+
+	if err := vs.checkOwner(ctx, payload.ProductId, payload.UserId); err != nil {
+		return response.ProductVariant{}, err
+	}
+
+	time := time.Now()
+	variant := entity.ProductVariant{
+		ProductId:  payload.ProductId,
+		Sku:        *payload.Sku,
+		Price:      *payload.Price,
+		Qty:        0,
+		Attributes: payload.Attributes,
+		CreatedAt:  time,
+		UpdatedAt:  time,
+	}
+
+	// sama seperti produk, varian dan stok awalnya ditulis dalam satu transaksi
+	tx, err := vs.DB.Begin(ctx)
+	if err != nil {
+		return response.ProductVariant{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	id, err := vs.VariantRepo.Create(ctx, tx, variant)
+	if err != nil {
+		return response.ProductVariant{}, mapVariantError(err, variant.Sku)
+	}
+	variant.Id = id
+
+	entry, err := vs.StockRepo.Adjust(ctx, tx, entity.InventoryEntry{
+		ProductId: payload.ProductId,
+		VariantId: id,
+		Type:      entity.InventoryRestock,
+		Delta:     *payload.Qty,
+		Reason:    "initial stock",
+		ActorId:   payload.UserId,
+		CreatedAt: time,
+	})
+	if err != nil {
+		return response.ProductVariant{}, err
+	}
+	variant.Qty = entry.QtyAfter
+
+	if err := tx.Commit(ctx); err != nil {
+		return response.ProductVariant{}, err
+	}
+
+	return ToProductVariantResponse(variant), nil
+}
+
+func (vs *ProductVariantService) UpdateById(ctx context.Context, payload request.ProductVariantUpdate) (response.ProductVariant, error) {
+	err := vs.Validation.Struct(payload)
+	if err != nil {
+		return response.ProductVariant{}, exceptions.NewBadRequestError(err.Error())
+	}
+
+	if err := vs.checkOwner(ctx, payload.ProductId, payload.UserId); err != nil {
+		return response.ProductVariant{}, err
+	}
+
+	variant := entity.ProductVariant{
+		Id:         payload.Id,
+		ProductId:  payload.ProductId,
+		Sku:        *payload.Sku,
+		Price:      *payload.Price,
+		Attributes: payload.Attributes,
+		UpdatedAt:  time.Now(),
+	}
+
+	// perubahan qty dicatat sebagai koreksi di ledger, bersama perubahan varian lainnya
+	tx, err := vs.DB.Begin(ctx)
+	if err != nil {
+		return response.ProductVariant{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	createdAt, err := vs.VariantRepo.UpdateById(ctx, tx, variant)
+	if err != nil {
+		return response.ProductVariant{}, mapVariantError(err, variant.Sku)
+	}
+	variant.CreatedAt = createdAt
+
+	entry, err := vs.StockRepo.SetQty(ctx, tx, entity.InventoryEntry{
+		ProductId: payload.ProductId,
+		VariantId: payload.Id,
+		Type:      entity.InventoryCorrection,
+		Reason:    "variant update",
+		ActorId:   payload.UserId,
+		CreatedAt: variant.UpdatedAt,
+	}, *payload.Qty)
+	if err != nil {
+		return response.ProductVariant{}, err
+	}
+	variant.Qty = entry.QtyAfter
+
+	if err := tx.Commit(ctx); err != nil {
+		return response.ProductVariant{}, err
+	}
+	// setelah commit, alert tidak dikirim untuk stok yang di-rollback
+	vs.LowStock.Check(ctx, entry)
+
+	return ToProductVariantResponse(variant), nil
+}
+
+func (vs *ProductVariantService) DeleteById(ctx context.Context, productId string, variantId string, userId string) error {
+	if err := vs.checkOwner(ctx, productId, userId); err != nil {
+		return err
+	}
+
+	return vs.VariantRepo.DeleteById(ctx, vs.DB, productId, variantId)
+}
+
+func (vs *ProductVariantService) GetByProductId(ctx context.Context, productId string) ([]response.ProductVariant, error) {
+	if _, err := vs.ProductRepo.GetById(ctx, vs.DB, productId); err != nil {
+		return nil, err
+	}
+
+	variants, err := vs.VariantRepo.GetByProductId(ctx, vs.DB, productId)
+	if err != nil {
+		return nil, err
+	}
+
+	return ToProductVariantResponses(variants), nil
+}
+
+// checkOwner hides products owned by other sellers behind a not found error
+func (vs *ProductVariantService) checkOwner(ctx context.Context, productId string, userId string) error {
+	product, err := vs.ProductRepo.GetById(ctx, vs.DB, productId)
+	if err != nil {
+		return err
+	}
+	if product.UserId != userId {
+		return exceptions.NewNotFoundError(productId + " is not found")
+	}
+
+	return nil
+}
+
+func mapVariantError(err error, sku string) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+		return exceptions.NewConflictError("sku " + sku + " already exists")
+	}
+
+	return err
+}
+
+func ToProductVariantResponse(variant entity.ProductVariant) response.ProductVariant {
+	return response.ProductVariant{
+		VariantId:  variant.Id,
+		ProductId:  variant.ProductId,
+		Sku:        variant.Sku,
+		Price:      variant.Price,
+		Qty:        variant.Qty,
+		Attributes: variant.Attributes,
+		CreatedAt:  variant.CreatedAt,
+		UpdatedAt:  variant.UpdatedAt,
+	}
+}
+
+func ToProductVariantResponses(variants []entity.ProductVariant) []response.ProductVariant {
+	responses := make([]response.ProductVariant, 0, len(variants))
+	for _, variant := range variants {
+		responses = append(responses, ToProductVariantResponse(variant))
+	}
+
+	return responses
+}
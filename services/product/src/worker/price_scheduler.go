@@ -0,0 +1,67 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/logging"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/config"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/go-fiber-template/src/repository"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/samber/do/v2"
+)
+
+// PriceScheduler activates and expires scheduled prices on a fixed interval
+type PriceScheduler struct {
+	DB        *pgxpool.Pool
+	PriceRepo repository.PriceRepoInterface
+	Logger    *logging.Logger
+	Interval  time.Duration
+}
+
+func NewPriceScheduler(db *pgxpool.Pool, priceRepo repository.PriceRepoInterface, logger *logging.Logger, interval time.Duration) *PriceScheduler {
+	return &PriceScheduler{
+		DB:        db,
+		PriceRepo: priceRepo,
+		Logger:    logger,
+		Interval:  interval,
+	}
+}
+
+func NewPriceSchedulerInject(i do.Injector) (*PriceScheduler, error) {
+	_db := do.MustInvoke[*pgxpool.Pool](i)
+	_priceRepo := do.MustInvoke[repository.PriceRepoInterface](i)
+	_logger := do.MustInvoke[*logging.Logger](i)
+
+	return NewPriceScheduler(_db, _priceRepo, _logger, config.GetPriceSchedulerInterval()), nil
+}
+
+// Run blocks until ctx is done, a running tick is finished first
+func (s *PriceScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+
+	s.tick(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.tick(ctx)
+		}
+	}
+}
+
+func (s *PriceScheduler) tick(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.Interval)
+	defer cancel()
+
+	started, ended, err := s.PriceRepo.ApplyDueSchedules(ctx, s.DB)
+	if err != nil {
+		s.Logger.Error(ctx, "unable to apply price schedules", "error", err)
+		return
+	}
+	if started > 0 || ended > 0 {
+		s.Logger.Info(ctx, "price schedules applied", "started", started, "ended", ended)
+	}
+}
@@ -11,30 +11,54 @@ require (
 	github.com/golang-migrate/migrate/v4 v4.18.1
 	github.com/jackc/pgx/v5 v5.7.2
 	github.com/joho/godotenv v1.5.1
+	github.com/prometheus/client_golang v1.20.5
+	github.com/redis/go-redis/v9 v9.7.0
 	github.com/samber/do/v2 v2.0.0-beta.7
 	github.com/swaggo/swag v1.16.4
-	go.uber.org/zap v1.27.0
-	gopkg.in/natefinch/lumberjack.v2 v2.2.1
+	google.golang.org/protobuf v1.36.4
 )
 
 require (
-	github.com/ansrivas/fiberprometheus/v2 v2.7.0 // indirect
 	github.com/beorn7/perks v1.0.1 // indirect
+	github.com/cenkalti/backoff/v4 v4.3.0 // indirect
 	github.com/cespare/xxhash/v2 v2.3.0 // indirect
+	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
 	github.com/dustin/go-humanize v1.0.1 // indirect
+	github.com/exaring/otelpgx v0.7.0 // indirect
+	github.com/go-logr/logr v1.4.2 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
 	github.com/golang/protobuf v1.5.4 // indirect
+	github.com/grpc-ecosystem/grpc-gateway/v2 v2.20.0 // indirect
 	github.com/munnerz/goautoneg v0.0.0-20191010083416-a7dc8b61c822 // indirect
 	github.com/pkg/errors v0.9.1 // indirect
-	github.com/prometheus/client_golang v1.20.5 // indirect
 	github.com/prometheus/client_model v0.6.1 // indirect
 	github.com/prometheus/common v0.62.0 // indirect
 	github.com/prometheus/procfs v0.15.1 // indirect
+	github.com/redis/go-redis/extra/rediscmd/v9 v9.7.0 // indirect
+	github.com/redis/go-redis/extra/redisotel/v9 v9.7.0 // indirect
+	go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc v0.52.0 // indirect
+	go.opentelemetry.io/otel v1.32.0 // indirect
+	go.opentelemetry.io/otel/exporters/otlp/otlptrace v1.29.0 // indirect
+	go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc v1.28.0 // indirect
+	go.opentelemetry.io/otel/exporters/stdout/stdouttrace v1.28.0 // indirect
+	go.opentelemetry.io/otel/metric v1.32.0 // indirect
+	go.opentelemetry.io/otel/sdk v1.32.0 // indirect
+	go.opentelemetry.io/otel/trace v1.32.0 // indirect
+	go.opentelemetry.io/proto/otlp v1.3.1 // indirect
+	go.uber.org/zap v1.27.0 // indirect
+	google.golang.org/genproto/googleapis/api v0.0.0-20241202173237-19429a94021a // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20241202173237-19429a94021a // indirect
-	google.golang.org/protobuf v1.36.4 // indirect
+	gopkg.in/natefinch/lumberjack.v2 v2.2.1 // indirect
 )
 
 require (
 	github.com/KyleBanks/depth v1.2.1 // indirect
+	github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/auth v0.0.0
+	github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/grpcmw v0.0.0
+	github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/health v0.0.0
+	github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/logging v0.0.0
+	github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/metrics v0.0.0
+	github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/tracing v0.0.0
 	github.com/andybalholm/brotli v1.1.1 // indirect
 	github.com/bytedance/sonic/loader v0.2.3 // indirect
 	github.com/cloudwego/base64x v0.1.5 // indirect
@@ -46,7 +70,7 @@ require (
 	github.com/go-openapi/swag v0.23.0 // indirect
 	github.com/go-playground/locales v0.14.1 // indirect
 	github.com/go-playground/universal-translator v0.18.1 // indirect
-	github.com/google/uuid v1.6.0 // indirect
+	github.com/google/uuid v1.6.0
 	github.com/hashicorp/errwrap v1.1.0 // indirect
 	github.com/hashicorp/go-multierror v1.1.1 // indirect
 	github.com/jackc/chunkreader/v2 v2.0.1 // indirect
@@ -91,3 +115,15 @@ require (
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 	gorm.io/gorm v1.25.12
 )
+
+replace github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/auth => ../../middleware/auth
+
+replace github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/grpcmw => ../../middleware/grpcmw
+
+replace github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/health => ../../middleware/health
+
+replace github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/logging => ../../middleware/logging
+
+replace github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/metrics => ../../middleware/metrics
+
+replace github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/tracing => ../../middleware/tracing
@@ -1,13 +1,27 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/health"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/logging"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/tracing"
+	"github.com/TimDebug/FitByte/src/config"
 	"github.com/TimDebug/FitByte/src/database/migrations"
+	"github.com/TimDebug/FitByte/src/database/postgre"
 	"github.com/TimDebug/FitByte/src/di"
+	purchaseGrpcServer "github.com/TimDebug/FitByte/src/grpc/server"
 	httpServer "github.com/TimDebug/FitByte/src/http"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/joho/godotenv"
 	_ "github.com/joho/godotenv/autoload"
+	"github.com/redis/go-redis/v9"
+	"github.com/samber/do/v2"
 )
 
 func main() {
@@ -16,6 +30,13 @@ func main() {
 	if err != nil {
 		panic(err)
 	}
+	//? Tracing first, the pgx and redis clients created by DI use it
+	fmt.Printf("Setup Tracing\n")
+	shutdownTracing, err := tracing.Setup(context.Background(), config.GetTracing())
+	if err != nil {
+		panic(err)
+	}
+
 	fmt.Printf("DI Healthcheck\n")
 	di.HealthCheck()
 
@@ -23,8 +44,69 @@ func main() {
 	fmt.Printf("Migrate\n")
 	migrations.Migrate()
 
+	//? SIGTERM saat deploy atau scale-in, SIGINT dari terminal
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	grpcServer := purchaseGrpcServer.NewGrpcServer()
+	server := httpServer.NewHttpServer()
+
+	errs := make(chan error, 2)
+	fmt.Printf("Start gRPC Server\n")
+	go func() { errs <- grpcServer.Listen() }()
 	fmt.Printf("Start Server\n")
-	server := httpServer.HttpServer{}
-	server.Listen()
+	go func() { errs <- server.Listen() }()
+
+	select {
+	case <-ctx.Done():
+		do.MustInvoke[*logging.Logger](di.Injector).Warn(context.Background(), "shutdown signal received")
+	case err := <-errs:
+		do.MustInvoke[*logging.Logger](di.Injector).Error(context.Background(), "server stopped", "error", err)
+	}
+	shutdown(server, grpcServer, shutdownTracing)
+}
+
+// Readiness fails first so no new traffic is routed here, then the running requests drain
+// and only then the connections they use are closed. Bounded by SHUTDOWN_TIMEOUT
+func shutdown(server httpServer.ServerInterface, grpcServer *purchaseGrpcServer.PurchaseGrpcServer, shutdownTracing func(context.Context) error) {
+	ctx, cancel := context.WithTimeout(context.Background(), config.GetShutdownTimeout())
+	defer cancel()
+	logger := do.MustInvoke[*logging.Logger](di.Injector)
+
+	//? 1. Stop accepting requests
+	do.MustInvoke[*health.Checker](di.Injector).Shutdown()
+
+	//? 2. Drain HTTP and gRPC
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		if err := server.Shutdown(ctx); err != nil {
+			logger.Error(ctx, "unable to drain http server", "error", err)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		if err := grpcServer.Shutdown(ctx); err != nil {
+			logger.Error(ctx, "unable to drain grpc server", "error", err)
+		}
+	}()
+	wg.Wait()
+
+	//? 3. Close Redis and the pgx pool
+	if err := do.MustInvoke[*redis.Client](di.Injector).Close(); err != nil {
+		logger.Error(ctx, "unable to close redis", "error", err)
+	}
+	if err := postgre.Close(ctx, do.MustInvoke[*pgxpool.Pool](di.Injector)); err != nil {
+		logger.Error(ctx, "unable to close DB connections", "error", err)
+	}
+
+	//? 4. Flush the spans of the drained requests
+	if err := shutdownTracing(ctx); err != nil {
+		logger.Error(ctx, "unable to flush traces", "error", err)
+	}
 
+	//? Flush the logger last so the steps above are logged
+	logger.Info(ctx, "shutdown complete")
+	logger.Sync()
 }
@@ -0,0 +1,25 @@
+package authJwt
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/auth"
+	"github.com/TimDebug/FitByte/src/config"
+	"github.com/redis/go-redis/v9"
+	"github.com/samber/do/v2"
+)
+
+// Tokens are minted by the user service only, this service verifies them
+// with the public keys published on its JWKS endpoint
+func NewKeyResolverInject(i do.Injector) (auth.KeyResolver, error) {
+	return auth.NewJwksClient(
+		config.GetJwksUrl(),
+		config.GetJwksCacheTtl(),
+		&http.Client{Timeout: 5 * time.Second},
+	), nil
+}
+
+func NewRevocationCheckerInject(i do.Injector) (auth.RevocationChecker, error) {
+	return auth.NewRedisRevocationList(do.MustInvoke[*redis.Client](i)), nil
+}
@@ -33,3 +33,8 @@ func GetUserGRPCHost() string {
 func GetUserGRPCPort() string {
 	return getEnv("GPRC_USER_PORT", "50050")
 }
+
+// Shared secret sent to the user and product gRPC servers, required by the purchase gRPC server when it is set
+func GetGrpcServiceToken() string {
+	return getEnv("GRPC_SERVICE_TOKEN", "")
+}
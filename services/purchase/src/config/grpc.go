@@ -0,0 +1,94 @@
+package config
+
+import (
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/grpcmw"
+)
+
+// Port of the gRPC server, user calls it for the account export
+func GetPortGrpc() string {
+	return getEnv("GRPC_PORT", "5002")
+}
+
+// Client certificate names accepted over mTLS by the gRPC server, comma separated
+func GetGrpcAllowedPeers() []string {
+	var peers []string
+	for _, name := range strings.Split(getEnv("GRPC_ALLOWED_PEERS", ""), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			peers = append(peers, name)
+		}
+	}
+
+	return peers
+}
+
+// Deadline of incoming calls that don't carry their own
+func GetGrpcDefaultTimeout() time.Duration {
+	return getEnvDuration("GRPC_DEFAULT_TIMEOUT", 10*time.Second)
+}
+
+// Certificate paths for mTLS with user, product and the callers of the gRPC server, plaintext when none is set, a startup error when only some are.
+// The files are reloaded when they change so certificates can rotate without a restart
+func GetGrpcTLS() grpcmw.TLSConfig {
+	return grpcmw.TLSConfig{
+		CertFile:       getEnv("GRPC_TLS_CERT_FILE", ""),
+		KeyFile:        getEnv("GRPC_TLS_KEY_FILE", ""),
+		CAFile:         getEnv("GRPC_TLS_CA_FILE", ""),
+		ReloadInterval: getEnvDuration("GRPC_TLS_RELOAD_INTERVAL", 30*time.Second),
+		OnReloadError: func(err error) {
+			log.Printf("Failed to reload gRPC TLS certificates, keeping the previous ones: %v", err)
+		},
+	}
+}
+
+func GetProductGRPCHost() string {
+	return getEnv("GRPC_PRODUCT_HOST", "localhost")
+}
+
+func GetProductGRPCPort() string {
+	return getEnv("GRPC_PRODUCT_PORT", "5001")
+}
+
+// Upper bound of one call to user or product, retries included
+func GetGrpcCallTimeout() time.Duration {
+	return getEnvDuration("GRPC_CALL_TIMEOUT", 3*time.Second)
+}
+
+// Attempts of idempotent calls, 1 turns retries off
+func GetGrpcRetryMaxAttempts() int {
+	attempts, err := strconv.Atoi(getEnv("GRPC_RETRY_MAX_ATTEMPTS", "3"))
+	if err != nil || attempts < 1 {
+		return 3
+	}
+	return attempts
+}
+
+// Consecutive failures that open the circuit breaker of a backend, 0 turns it off
+func GetGrpcBreakerFailures() int {
+	failures, err := strconv.Atoi(getEnv("GRPC_BREAKER_FAILURES", "5"))
+	if err != nil || failures < 0 {
+		return 5
+	}
+	return failures
+}
+
+// How long an open breaker fails calls right away before letting one through
+func GetGrpcBreakerCooldown() time.Duration {
+	return getEnvDuration("GRPC_BREAKER_COOLDOWN", 10*time.Second)
+}
+
+func GetGrpcKeepalive() time.Duration {
+	return getEnvDuration("GRPC_KEEPALIVE", 30*time.Second)
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value, err := time.ParseDuration(getEnv(key, ""))
+	if err != nil || value <= 0 {
+		return defaultValue
+	}
+	return value
+}
@@ -0,0 +1,13 @@
+package config
+
+import "time"
+
+// Upper bound of one readiness run, all dependencies are checked concurrently
+func GetHealthCheckTimeout() time.Duration {
+	return getEnvDuration("HEALTH_CHECK_TIMEOUT", 2*time.Second)
+}
+
+// How often grpc.health.v1 re-runs the readiness checks
+func GetGrpcHealthInterval() time.Duration {
+	return getEnvDuration("GRPC_HEALTH_INTERVAL", 10*time.Second)
+}
@@ -1,5 +1,17 @@
 package config
 
-func GetSecretKey() string {
-	return getEnv("JWT_SECRET_KEY", "default")
+import "time"
+
+// JWKS document of the user service, the only service holding signing keys
+func GetJwksUrl() string {
+	return getEnv("JWKS_URL", "http://localhost:8080/v1/auth/jwks")
+}
+
+func GetJwksCacheTtl() time.Duration {
+	ttl, err := time.ParseDuration(getEnv("JWKS_CACHE_TTL", "10m"))
+	if err != nil || ttl <= 0 {
+		return 10 * time.Minute
+	}
+
+	return ttl
 }
@@ -0,0 +1,15 @@
+package config
+
+import (
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/logging"
+)
+
+// LOG_LEVEL is where the service starts, admins change it at runtime with PUT /v1/admin/log-level
+func GetLogging() logging.Config {
+	return logging.Config{
+		Service: "purchase",
+		Level:   getEnv("LOG_LEVEL", "info"),
+		File:    getEnv("LOG_FILE", "./logs/app.log"),
+		Stdout:  getEnv("LOG_STDOUT", "false") == "true",
+	}
+}
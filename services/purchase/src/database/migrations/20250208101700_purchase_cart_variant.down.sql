@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS purchase_cart_purchase_id_idx;
+ALTER TABLE purchase_cart DROP COLUMN IF EXISTS variant_id;
+ALTER TABLE purchase_cart DROP CONSTRAINT IF EXISTS purchase_cart_pkey;
+ALTER TABLE purchase_cart DROP COLUMN IF EXISTS id;
+ALTER TABLE purchase_cart ALTER COLUMN purchase_id SET DEFAULT gen_random_uuid();
+ALTER TABLE purchase_cart ADD CONSTRAINT purchase_cart_pkey PRIMARY KEY (purchase_id);
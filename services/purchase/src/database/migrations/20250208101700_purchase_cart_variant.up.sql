@@ -0,0 +1,10 @@
+-- Satu purchase bisa berisi banyak item, jadi purchase_id tidak boleh menjadi primary key
+ALTER TABLE purchase_cart DROP CONSTRAINT IF EXISTS purchase_cart_pkey;
+ALTER TABLE purchase_cart ALTER COLUMN purchase_id DROP DEFAULT;
+ALTER TABLE purchase_cart ADD COLUMN id VARCHAR(255) NOT NULL DEFAULT gen_random_uuid();
+ALTER TABLE purchase_cart ADD CONSTRAINT purchase_cart_pkey PRIMARY KEY (id);
+
+-- Varian produk yang dibeli (opsional)
+ALTER TABLE purchase_cart ADD COLUMN variant_id VARCHAR(255);
+
+CREATE INDEX IF NOT EXISTS purchase_cart_purchase_id_idx ON purchase_cart (purchase_id);
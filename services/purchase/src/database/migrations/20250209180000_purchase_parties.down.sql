@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS purchase_cart_seller_id_idx;
+DROP INDEX IF EXISTS purchase_buyer_id_idx;
+ALTER TABLE purchase_cart DROP COLUMN IF EXISTS seller_id;
+ALTER TABLE purchase DROP COLUMN IF EXISTS created_at;
+ALTER TABLE purchase DROP COLUMN IF EXISTS buyer_id;
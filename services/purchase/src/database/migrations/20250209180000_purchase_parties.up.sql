@@ -0,0 +1,8 @@
+-- Pembeli yang login dan penjual tiap item, dipakai user service untuk export data akun.
+-- Purchase tanpa login dan data lama tidak punya buyer_id, item lama tidak punya seller_id
+ALTER TABLE purchase ADD COLUMN IF NOT EXISTS buyer_id VARCHAR(255);
+ALTER TABLE purchase ADD COLUMN IF NOT EXISTS created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP;
+ALTER TABLE purchase_cart ADD COLUMN IF NOT EXISTS seller_id VARCHAR(255);
+
+CREATE INDEX IF NOT EXISTS purchase_buyer_id_idx ON purchase (buyer_id);
+CREATE INDEX IF NOT EXISTS purchase_cart_seller_id_idx ON purchase_cart (seller_id);
@@ -0,0 +1 @@
+ALTER TABLE purchase_cart DROP COLUMN IF EXISTS bank_account_id;
@@ -0,0 +1,3 @@
+-- Rekening default penjual saat order dibuat. Isinya tidak disalin, dibaca ulang dari user service
+-- lewat GetBankAccount supaya nomor rekening hanya tersimpan (terenkripsi) di user service
+ALTER TABLE purchase_cart ADD COLUMN IF NOT EXISTS bank_account_id VARCHAR(255);
@@ -2,9 +2,9 @@ package postgre
 
 import (
 	"context"
-	"fmt"
 	"strconv"
 
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/tracing"
 	"github.com/TimDebug/FitByte/src/config"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -13,7 +13,6 @@ import (
 
 func NewPgxConnectInject(i do.Injector) (*pgxpool.Pool, error) {
 	DbString := config.GetDBConnection()
-	fmt.Printf("Connection string: %s\n", DbString)
 	tempConn, err := pgx.Connect(context.Background(), DbString)
 	if err != nil {
 		panic(err.Error())
@@ -42,6 +41,8 @@ func NewPgxConnectInject(i do.Injector) (*pgxpool.Pool, error) {
 	}
 
 	pgxConfig.MaxConns = int32(maxOpenConnection)
+	// Span per query, child of the request span
+	pgxConfig.ConnConfig.Tracer = tracing.PgxTracer()
 
 	db, err := pgxpool.NewWithConfig(context.Background(), pgxConfig)
 	if err != nil {
@@ -50,3 +51,20 @@ func NewPgxConnectInject(i do.Injector) (*pgxpool.Pool, error) {
 
 	return db, nil
 }
+
+// pgxpool.Close waits for every acquired connection to be released, Close gives up
+// on that when ctx is done so a stuck query can't hold the shutdown
+func Close(ctx context.Context, db *pgxpool.Pool) error {
+	done := make(chan struct{})
+	go func() {
+		db.Close()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,24 @@
+package redisClient
+
+import (
+	"fmt"
+
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/tracing"
+	"github.com/TimDebug/FitByte/src/config"
+	"github.com/redis/go-redis/v9"
+	"github.com/samber/do/v2"
+)
+
+// Shared by the token revocation list and the readiness check
+func NewRedisClientInject(i do.Injector) (*redis.Client, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:%s", config.GetRedisHost(), config.GetRedisPort()),
+		Password: config.GetRedisPassword(),
+		DB:       config.GetRedisDbCount(),
+	})
+	if err := tracing.InstrumentRedis(client); err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
@@ -1,15 +1,23 @@
 package di
 
 import (
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/auth"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/health"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/logging"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/metrics"
 	authJwt "github.com/TimDebug/FitByte/src/auth/jwt"
 	"github.com/TimDebug/FitByte/src/database/postgre"
+	redisClient "github.com/TimDebug/FitByte/src/database/redis"
 	purchaseGrpc "github.com/TimDebug/FitByte/src/grpc"
+	healthCheck "github.com/TimDebug/FitByte/src/health"
 	appController "github.com/TimDebug/FitByte/src/http/controllers/purchase"
-	loggerZap "github.com/TimDebug/FitByte/src/logger/zap"
+	serviceLogger "github.com/TimDebug/FitByte/src/logger"
+	serviceMetrics "github.com/TimDebug/FitByte/src/metrics"
 	purchaseRepository "github.com/TimDebug/FitByte/src/repositories/purchase"
 	purchaseCartRepository "github.com/TimDebug/FitByte/src/repositories/purchaseCart"
 	purchaseService "github.com/TimDebug/FitByte/src/services/purchase"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
 	"github.com/samber/do/v2"
 )
 
@@ -20,18 +28,30 @@ func init() {
 
 	//? Setup Database Connection
 	do.Provide[*pgxpool.Pool](Injector, postgre.NewPgxConnectInject)
+	//? Redis
+	do.Provide[*redis.Client](Injector, redisClient.NewRedisClientInject)
 
 	//? Logger
-	//? Zap
-	do.Provide[loggerZap.LoggerInterface](Injector, loggerZap.NewLogHandlerInject)
+	//? Structured JSON with request, trace and user ids
+	do.Provide[*logging.Logger](Injector, serviceLogger.NewLoggerInject)
+
+	//? Metrics
+	//? RED and Postgres pool
+	do.Provide[*metrics.Metrics](Injector, serviceMetrics.NewMetricsInject)
+	//? Carts and purchase value
+	do.Provide[*serviceMetrics.PurchaseMetrics](Injector, serviceMetrics.NewPurchaseMetricsInject)
 
 	//? GRPCs
 	//? UserService
 	do.Provide[*purchaseGrpc.ProtoUserController](Injector, purchaseGrpc.NewGRPCClientInject)
+	//? ProductService
+	do.Provide[*purchaseGrpc.ProtoProductController](Injector, purchaseGrpc.NewProductGRPCClientInject)
 
 	//? Setup Auth
-	//? JWT Service
-	do.Provide[authJwt.JwtServiceInterface](Injector, authJwt.NewJwtServiceInject)
+	//? JWKS Key Resolver
+	do.Provide[auth.KeyResolver](Injector, authJwt.NewKeyResolverInject)
+	//? Token Revocation List
+	do.Provide[auth.RevocationChecker](Injector, authJwt.NewRevocationCheckerInject)
 
 	// Repositories
 	do.Provide[purchaseRepository.IPurchaseRepository](Injector, purchaseRepository.NewPurhcaseRepositoryInject)
@@ -40,4 +60,6 @@ func init() {
 	do.Provide[*purchaseService.PurchaseService](Injector, purchaseService.NewInject)
 	// Controllers
 	do.Provide[appController.IPurchaseController](Injector, appController.NewPurchaseControllerInject)
+	// Readiness Checker
+	do.Provide[*health.Checker](Injector, healthCheck.NewCheckerInject)
 }
@@ -1,42 +1,122 @@
 package purchaseGrpc
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/grpcmw"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/health"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/logging"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/tracing"
 	"github.com/TimDebug/FitByte/src/config"
-	functionCallerInfo "github.com/TimDebug/FitByte/src/logger/helper"
-	loggerZap "github.com/TimDebug/FitByte/src/logger/zap"
+	"github.com/TimDebug/FitByte/src/services/proto/product"
 	"github.com/TimDebug/FitByte/src/services/proto/user"
 	"github.com/samber/do/v2"
 	"google.golang.org/grpc"
 )
 
 type ProtoUserController struct {
-	logger      loggerZap.LoggerInterface
+	logger      *logging.Logger
+	healthCheck health.Check
 	UserService user.UserServiceClient
 }
 
+type ProtoProductController struct {
+	logger         *logging.Logger
+	healthCheck    health.Check
+	ProductService product.ProductServiceClient
+}
+
 func NewGRPCClientInject(i do.Injector) (*ProtoUserController, error) {
-	_logger := do.MustInvoke[loggerZap.LoggerInterface](i)
+	_logger := do.MustInvoke[*logging.Logger](i)
 
 	_userServiceAddress := fmt.Sprintf("%s:%s", config.GetUserGRPCHost(), config.GetUserGRPCPort()) // known as 50051
 
-	connection, err := grpc.Dial(_userServiceAddress, grpc.WithInsecure())
+	// Semua RPC user yang dipakai hanya membaca data, jadi aman untuk di-retry
+	connection, err := dial(_userServiceAddress, _logger,
+		user.UserService_GetUserDetails_FullMethodName,
+		user.UserService_GetUserDetailsWithId_FullMethodName,
+		user.UserService_GetSellerProfile_FullMethodName,
+		user.UserService_GetBankAccount_FullMethodName,
+	)
 	if err != nil {
-		_logger.Error(err.Error(), functionCallerInfo.PurhcaseControllerPutCart, "Grpc Connection")
-		fmt.Printf("Grpc Connection Error: %s\n", err.Error())
 		return nil, err
 	}
-	// defer {
-	// 	connection.Close()
-	// }
 
 	// Create new userService client
 	_userServiceClient := user.NewUserServiceClient(connection)
 
-	fmt.Printf("GRPC Client>> Listening to %s\n", _userServiceAddress)
+	_logger.Info(context.Background(), "grpc client ready", "address", _userServiceAddress)
 	return &ProtoUserController{
 		logger:      _logger,
+		healthCheck: health.GrpcCheck(connection),
 		UserService: _userServiceClient,
 	}, nil
 }
+
+func NewProductGRPCClientInject(i do.Injector) (*ProtoProductController, error) {
+	_logger := do.MustInvoke[*logging.Logger](i)
+
+	_productServiceAddress := fmt.Sprintf("%s:%s", config.GetProductGRPCHost(), config.GetProductGRPCPort()) // known as 5001
+
+	// RecordSale tidak di-retry, pengulangan akan mengurangi stok dua kali
+	connection, err := dial(_productServiceAddress, _logger,
+		product.ProductService_GetProductDetailById_FullMethodName,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	_logger.Info(context.Background(), "grpc client ready", "address", _productServiceAddress)
+	return &ProtoProductController{
+		logger:         _logger,
+		healthCheck:    health.GrpcCheck(connection),
+		ProductService: product.NewProductServiceClient(connection),
+	}, nil
+}
+
+// Asks the user service through grpc.health.v1, used by the readiness check
+func (c *ProtoUserController) Ready(ctx context.Context) error {
+	return c.healthCheck(ctx)
+}
+
+// Asks the product service through grpc.health.v1, used by the readiness check
+func (c *ProtoProductController) Ready(ctx context.Context) error {
+	return c.healthCheck(ctx)
+}
+
+// Connection with the call timeout on every listed method and retries for them, they must be idempotent.
+// Forwards the request id, the trace context and the service token, uses mTLS when configured,
+// balances over every address the host resolves to and stops calling a backend that keeps failing
+func dial(address string, logger *logging.Logger, idempotentMethods ...string) (*grpc.ClientConn, error) {
+	methods := map[string]grpcmw.MethodPolicy{}
+	for _, method := range idempotentMethods {
+		methods[method] = grpcmw.MethodPolicy{
+			Timeout:     config.GetGrpcCallTimeout(),
+			MaxAttempts: config.GetGrpcRetryMaxAttempts(),
+		}
+	}
+
+	connection, err := grpcmw.Dial(address, grpcmw.DialConfig{
+		Client:          grpcmw.ClientConfig{Token: config.GetGrpcServiceToken()},
+		TLS:             config.GetGrpcTLS(),
+		Methods:         methods,
+		BreakerFailures: config.GetGrpcBreakerFailures(),
+		BreakerCooldown: config.GetGrpcBreakerCooldown(),
+		OnBreakerChange: func(open bool) {
+			if open {
+				logger.Warn(context.Background(), "circuit breaker opened", "address", address)
+			} else {
+				logger.Info(context.Background(), "circuit breaker closed", "address", address)
+			}
+		},
+		Keepalive: config.GetGrpcKeepalive(),
+		Options:   tracing.ClientOptions(),
+	})
+	if err != nil {
+		logger.Error(context.Background(), "unable to dial grpc service", "error", err, "address", address)
+		return nil, err
+	}
+
+	return connection, nil
+}
@@ -0,0 +1,66 @@
+package purchaseGrpcServer
+
+import (
+	"time"
+
+	Entity "github.com/TimDebug/FitByte/src/model/entities/purchase"
+	"github.com/TimDebug/FitByte/src/services/proto/purchase"
+	purchaseService "github.com/TimDebug/FitByte/src/services/purchase"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	RoleBuyer  = "buyer"
+	RoleSeller = "seller"
+)
+
+type PurchaseService struct {
+	purchase.UnimplementedPurchaseServiceServer
+	PurchaseService *purchaseService.PurchaseService
+}
+
+// Dikirim satu per satu sambil query dibaca, jadi purchase yang banyak tidak ditampung di memori
+func (ps *PurchaseService) ListPurchasesByUserId(req *purchase.PurchasesByUserIdRequest, stream purchase.PurchaseService_ListPurchasesByUserIdServer) error {
+	if req.UserId == "" {
+		return status.Error(codes.InvalidArgument, "UserId is required")
+	}
+
+	err := ps.PurchaseService.ListPurchasesByUserId(stream.Context(), req.UserId, func(p Entity.UserPurchase) error {
+		return stream.Send(toPurchaseResponse(p))
+	})
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	return nil
+}
+
+// Data pengirim milik pembeli, jadi tidak dikirim ke penjual
+func toPurchaseResponse(p Entity.UserPurchase) *purchase.PurchaseResponse {
+	response := &purchase.PurchaseResponse{
+		PurchaseId: p.PurchaseID,
+		Role:       RoleSeller,
+		CreatedAt:  p.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	if p.IsBuyer {
+		response.Role = RoleBuyer
+		response.SenderName = p.SenderName
+		response.SenderContactType = p.SenderContactType
+		response.SenderContactDetail = p.SenderContactDetail
+	}
+
+	for _, item := range p.Items {
+		response.Items = append(response.Items, &purchase.PurchasedItem{
+			ProductId: item.ProductID,
+			VariantId: item.VariantID,
+			Qty:       item.Quantity,
+			SellerId:  item.SellerID,
+		})
+	}
+
+	return response
+}
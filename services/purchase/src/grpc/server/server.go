@@ -0,0 +1,98 @@
+package purchaseGrpcServer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/grpcmw"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/health"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/logging"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/metrics"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/tracing"
+	"github.com/TimDebug/FitByte/src/config"
+	"github.com/TimDebug/FitByte/src/di"
+	"github.com/TimDebug/FitByte/src/services/proto/purchase"
+	purchaseService "github.com/TimDebug/FitByte/src/services/purchase"
+	"github.com/samber/do/v2"
+	"google.golang.org/grpc"
+)
+
+type PurchaseGrpcServer struct {
+	server *grpc.Server
+}
+
+func NewGrpcServer() *PurchaseGrpcServer {
+	// mTLS kalau path sertifikat diisi, client tanpa sertifikat yang dipercaya ditolak
+	tlsConfig := config.GetGrpcTLS()
+	transport, err := grpcmw.ServerTransport(tlsConfig)
+	if err != nil {
+		log.Fatalf("Failed to load gRPC TLS certificates: %v", err)
+	}
+
+	// Di luar DEBUG server tanpa token maupun mTLS menerima siapa saja, jadi ditolak
+	logger := do.MustInvoke[*logging.Logger](di.Injector)
+	cfg := serverConfig(logger)
+	if mode := strings.ToUpper(config.GetMode()); mode != config.MODE_DEBUG && mode != "" {
+		if err := cfg.RequireAuth(tlsConfig); err != nil {
+			log.Fatalf("Set GRPC_SERVICE_TOKEN or the GRPC_TLS_* files: %v", err)
+		}
+	}
+
+	// gRPC server dengan tracing, metrik, lalu auth, request id, deadline, recovery dan access log
+	options := append(append(transport, tracing.ServerOptions()...), do.MustInvoke[*metrics.Metrics](di.Injector).GrpcServerOptions()...)
+	server := grpc.NewServer(append(options, grpcmw.ServerOptions(cfg)...)...)
+
+	purchase.RegisterPurchaseServiceServer(server, &PurchaseService{
+		PurchaseService: do.MustInvoke[*purchaseService.PurchaseService](di.Injector),
+	})
+
+	// grpc.health.v1, mengikuti readiness check yang sama dengan GET /readyz
+	do.MustInvoke[*health.Checker](di.Injector).RegisterGrpc(server, config.GetGrpcHealthInterval())
+
+	return &PurchaseGrpcServer{server: server}
+}
+
+// Blocks until the server stops, returns nil after Shutdown
+func (s *PurchaseGrpcServer) Listen() error {
+	lis, err := net.Listen("tcp", ":"+config.GetPortGrpc())
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("> gRPC server listening on :%s\n", config.GetPortGrpc())
+	return s.server.Serve(lis)
+}
+
+// Waits for the running calls until ctx is done, then cancels the rest
+func (s *PurchaseGrpcServer) Shutdown(ctx context.Context) error {
+	return grpcmw.GracefulStop(ctx, s.server)
+}
+
+func serverConfig(logger *logging.Logger) grpcmw.ServerConfig {
+	return grpcmw.ServerConfig{
+		Token:          config.GetGrpcServiceToken(),
+		AllowedPeers:   config.GetGrpcAllowedPeers(),
+		DefaultTimeout: config.GetGrpcDefaultTimeout(),
+		Log: func(ctx context.Context, entry grpcmw.AccessLog) {
+			// request_id comes from ctx, the logger attaches it
+			data := []interface{}{
+				"method", entry.Method,
+				"code", entry.Code.String(),
+				"duration", entry.Duration.String(),
+				"peer", entry.Peer,
+				"identity", entry.Identity,
+			}
+			if entry.Err != nil {
+				logger.Warn(ctx, "grpc call failed", append(data, "error", entry.Err)...)
+				return
+			}
+			logger.Info(ctx, "grpc call", data...)
+		},
+		OnPanic: func(ctx context.Context, method string, recovered any, stack []byte) {
+			logger.Error(ctx, "grpc handler panicked", "panic", fmt.Sprint(recovered), "method", method, "stack", string(stack))
+		},
+	}
+}
@@ -0,0 +1,27 @@
+package healthCheck
+
+import (
+	"context"
+
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/health"
+	"github.com/TimDebug/FitByte/src/config"
+	purchaseGrpc "github.com/TimDebug/FitByte/src/grpc"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+	"github.com/samber/do/v2"
+)
+
+// Postgres and Redis (token revocation) are required, user and product only degrade
+// the service, their breakers already answer 503 on the routes that need them
+func NewCheckerInject(i do.Injector) (*health.Checker, error) {
+	_db := do.MustInvoke[*pgxpool.Pool](i)
+	_redis := do.MustInvoke[*redis.Client](i)
+	_userClient := do.MustInvoke[*purchaseGrpc.ProtoUserController](i)
+	_productClient := do.MustInvoke[*purchaseGrpc.ProtoProductController](i)
+
+	return health.New(config.GetHealthCheckTimeout()).
+		Add("postgres", _db.Ping).
+		Add("redis", func(ctx context.Context) error { return _redis.Ping(ctx).Err() }).
+		AddOptional("user", _userClient.Ready).
+		AddOptional("product", _productClient.Ready), nil
+}
@@ -4,34 +4,42 @@ package appController
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"regexp"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/auth"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/logging"
 	serviceCache "github.com/TimDebug/FitByte/src/cache"
 	purchaseGrpc "github.com/TimDebug/FitByte/src/grpc"
 	helper "github.com/TimDebug/FitByte/src/helper/validator"
-	functionCallerInfo "github.com/TimDebug/FitByte/src/logger/helper"
-	loggerZap "github.com/TimDebug/FitByte/src/logger/zap"
+	serviceMetrics "github.com/TimDebug/FitByte/src/metrics"
 	"github.com/TimDebug/FitByte/src/model/dtos/request"
 	"github.com/TimDebug/FitByte/src/model/dtos/response"
+	"github.com/TimDebug/FitByte/src/services/proto/product"
 	"github.com/TimDebug/FitByte/src/services/proto/user"
 	purchaseService "github.com/TimDebug/FitByte/src/services/purchase"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
 	"github.com/samber/do/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type PurchaseController struct {
-	logger          loggerZap.LoggerInterface
+	logger          *logging.Logger
 	validator       helper.XValidator
 	grpcClient      *purchaseGrpc.ProtoUserController
+	productClient   *purchaseGrpc.ProtoProductController
 	purchaseService *purchaseService.PurchaseService
+	metrics         *serviceMetrics.PurchaseMetrics
 }
 
-func NewPurchaseController(logger loggerZap.LoggerInterface, grpcClient *purchaseGrpc.ProtoUserController, purchaseService *purchaseService.PurchaseService) IPurchaseController {
+func NewPurchaseController(logger *logging.Logger, grpcClient *purchaseGrpc.ProtoUserController, productClient *purchaseGrpc.ProtoProductController, purchaseService *purchaseService.PurchaseService, metrics *serviceMetrics.PurchaseMetrics) IPurchaseController {
 	xValidator := helper.XValidator{Validator: validator.New()}
 	xValidator.Validator.RegisterValidation("sender_email_or_phone", func(fl validator.FieldLevel) bool {
 		contactType := fl.Parent().FieldByName("SenderContactType").String()
@@ -50,14 +58,16 @@ func NewPurchaseController(logger loggerZap.LoggerInterface, grpcClient *purchas
 		}
 	})
 
-	return &PurchaseController{logger: logger, validator: xValidator, grpcClient: grpcClient, purchaseService: purchaseService}
+	return &PurchaseController{logger: logger, validator: xValidator, grpcClient: grpcClient, productClient: productClient, purchaseService: purchaseService, metrics: metrics}
 }
 
 func NewPurchaseControllerInject(i do.Injector) (IPurchaseController, error) {
-	_logger := do.MustInvoke[loggerZap.LoggerInterface](i)
+	_logger := do.MustInvoke[*logging.Logger](i)
 	_grpcClient := do.MustInvoke[*purchaseGrpc.ProtoUserController](i)
+	_productClient := do.MustInvoke[*purchaseGrpc.ProtoProductController](i)
 	_purchaseService := do.MustInvoke[*purchaseService.PurchaseService](i)
-	return NewPurchaseController(_logger, _grpcClient, _purchaseService), nil
+	_metrics := do.MustInvoke[*serviceMetrics.PurchaseMetrics](i)
+	return NewPurchaseController(_logger, _grpcClient, _productClient, _purchaseService, _metrics), nil
 }
 
 // Purchase godoc
@@ -67,15 +77,18 @@ func NewPurchaseControllerInject(i do.Injector) (IPurchaseController, error) {
 // @Accept json
 // @Produce json
 // @Param request body request.CartDto true "Cart Data"
+// @Param Authorization header string false "Bearer token, opsional. Kalau diisi purchase tercatat sebagai milik pembeli"
 // @Success 201 {object} response.PurchaseResponseDTO "success response"
 // @Failure 400 {object} map[string]interface{} "bad request"
+// @Failure 401 {object} map[string]interface{} "token is set but invalid"
 // @Failure 500 {object} map[string]interface{} "internal server error"
+// @Failure 503 {object} map[string]interface{} "user or product service unavailable"
 // @Router /v1/purchase [post]
 func (pc *PurchaseController) Cart(c *fiber.Ctx) error {
 	//// todo; Parse Body
 	requestBody := new(request.CartDto)
 	if err := c.BodyParser(requestBody); err != nil {
-		pc.logger.Error(err.Error(), functionCallerInfo.PurhcaseControllerPutCart)
+		pc.logger.Error(c.UserContext(), "invalid request body", "error", err)
 		return err
 	}
 
@@ -91,7 +104,7 @@ func (pc *PurchaseController) Cart(c *fiber.Ctx) error {
 				err.Tag,
 			))
 		}
-		pc.logger.Error(strings.Join(errMsgs, " || "), functionCallerInfo.PurhcaseControllerPutCart)
+		pc.logger.Warn(c.UserContext(), "invalid cart", "errors", errMsgs)
 		return fiber.NewError(fiber.StatusBadRequest, strings.Join(errMsgs, " || "))
 	}
 
@@ -119,13 +132,23 @@ func (pc *PurchaseController) Cart(c *fiber.Ctx) error {
 	var sellerIdTotalPrices []float64
 
 	//// todo; buat satu kontainer apabila produk dan seller tidak ada di cache
-	var toGetProductsById []string
+	var toGetProductsById []request.PurchasedItem
 	var toGetSellersById []string
 
 	// todo; task (go-routine)
 	// forloop ambil cache by productIDs
 	for _, item := range requestBody.PurchasedItems {
-		if cachedProductValue, found := serviceCache.GetAsMap(fmt.Sprintf(serviceCache.CacheProductById, item.ProductId)); found {
+		// cache produk hanya berisi harga, stok dan SKU produk dasar. Item dengan varian selalu lewat gRPC,
+		// di sana varian di-resolve dan variantId yang bukan milik produk ditolak, lihat toProductItem
+		if item.VariantId != "" {
+			toGetProductsById = append(toGetProductsById, item)
+			continue
+		}
+
+		productKey := fmt.Sprintf(serviceCache.CacheProductById, item.ProductId)
+		cachedProductValue, found := serviceCache.GetAsMap(productKey)
+		pc.metrics.CacheLookup(productKey, found)
+		if found {
 			/*
 				type ProductItemDTO struct {
 					ProductId        string    `json:"productId"`
@@ -144,29 +167,25 @@ func (pc *PurchaseController) Cart(c *fiber.Ctx) error {
 			_qty, err := strconv.Atoi(cachedProductValue["Qty"]) // Atoi = ASCII to Integer
 			if err != nil {
 				// Tangani error jika string tidak bisa dikonversi ke angka
-				fmt.Println("Error:", err)
-				pc.logger.Error(err.Error(), functionCallerInfo.CachePurhcaseControllerPutCart, "Parse Qty")
+				pc.logger.Error(c.UserContext(), "invalid cached product", "error", err, "productId", item.ProductId, "field", "Qty")
 				return err
 			}
 			_price, err := strconv.ParseFloat(cachedProductValue["Price"], 64) // Atoi = ASCII to Integer
 			if err != nil {
 				// Tangani error jika string tidak bisa dikonversi ke floating number
-				fmt.Println("Error:", err)
-				pc.logger.Error(err.Error(), functionCallerInfo.CachePurhcaseControllerPutCart, "Parse Price")
+				pc.logger.Error(c.UserContext(), "invalid cached product", "error", err, "productId", item.ProductId, "field", "Price")
 				return err
 			}
 			_createdAt, err := time.Parse(time.RFC3339, cachedProductValue["CreatedAt"])
 			if err != nil {
 				// Tangani error jika string tidak bisa dikonversi ke Time sesuai dengan ISO
-				fmt.Println("Error:", err)
-				pc.logger.Error(err.Error(), functionCallerInfo.CachePurhcaseControllerPutCart, "Parse CreatedAt")
+				pc.logger.Error(c.UserContext(), "invalid cached product", "error", err, "productId", item.ProductId, "field", "CreatedAt")
 				return err
 			}
 			_modifiedAt, err := time.Parse(time.RFC3339, cachedProductValue["UpdatedAt"])
 			if err != nil {
 				// Tangani error jika string tidak bisa dikonversi ke Time sesuai dengan ISO
-				fmt.Println("Error:", err)
-				pc.logger.Error(err.Error(), functionCallerInfo.CachePurhcaseControllerPutCart, "Parse ModifiedAt")
+				pc.logger.Error(c.UserContext(), "invalid cached product", "error", err, "productId", item.ProductId, "field", "UpdatedAt")
 				return err
 			}
 
@@ -174,6 +193,7 @@ func (pc *PurchaseController) Cart(c *fiber.Ctx) error {
 
 			cachedProducts = append(cachedProducts, response.ProductItemDTO{
 				ProductId:        item.ProductId,
+				VariantId:        item.VariantId,
 				Name:             cachedProductValue["Name"],
 				Category:         cachedProductValue["Category"],
 				Qty:              _qty,
@@ -209,9 +229,13 @@ func (pc *PurchaseController) Cart(c *fiber.Ctx) error {
 						TotalPrice        float64 `json:"totalPrice"`
 					}
 				*/
-				if cachedSellerValue, found := serviceCache.GetAsMap(fmt.Sprintf(serviceCache.CacheSellerById, _sellerId)); found {
+				sellerKey := fmt.Sprintf(serviceCache.CacheSellerById, _sellerId)
+				cachedSellerValue, found := serviceCache.GetAsMap(sellerKey)
+				pc.metrics.CacheLookup(sellerKey, found)
+				if found {
 					cachedSeller = append(cachedSeller, response.SellerBankDetailDTO{
 						SellerId:          cachedSellerValue["SellerId"],
+						BankAccountId:     cachedSellerValue["BankAccountId"],
 						BankAccountName:   cachedSellerValue["BankAccountName"],
 						BankAccountHolder: cachedSellerValue["BankAccountHolder"],
 						BankAccountNumber: cachedSellerValue["BankAccountNumber"],
@@ -239,26 +263,29 @@ func (pc *PurchaseController) Cart(c *fiber.Ctx) error {
 
 		} else {
 			// todo; kompilasi produkId yang akan diambil
-			toGetProductsById = append(toGetProductsById, item.ProductId)
+			toGetProductsById = append(toGetProductsById, item)
 
 		}
 	}
 
 	// todo; get produkId di produk service
 	if len(toGetProductsById) > 0 {
-		// kirim batch
-
-		// dapat response
-		// masukan ke respons.dto
-		// untuk sementara pakai toGetProductsById terlebih dulu sampai produk service siap
+		// product service belum punya RPC batch, jadi diambil satu per satu.
+		// timeout dan retry per RPC diatur oleh client gRPC, context fiber membawa request id
 		for _, item := range toGetProductsById {
-			_sellerId := "d29bdf02-25dc-4742-b425-728374370351"
-			_price := 0.0
-			cart.PurchasedItems = append(cart.PurchasedItems, response.ProductItemDTO{
-				ProductId: item,
-				Price:     _price,
-				SellerId:  _sellerId,
-			})
+			grpcResponse, err := pc.productClient.ProductService.GetProductDetailById(c.Context(), &product.ProductRequest{ProductId: item.ProductId})
+			if err != nil {
+				pc.logger.Error(c.UserContext(), "unable to get product", "error", err, "productId", item.ProductId)
+				return grpcCallError(err, "productId "+item.ProductId+" is not valid")
+			}
+
+			productItem, err := toProductItem(grpcResponse, item.VariantId)
+			if err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, err.Error())
+			}
+			_sellerId := productItem.SellerId
+			_price := productItem.Price
+			cart.PurchasedItems = append(cart.PurchasedItems, productItem)
 
 			// todo; distinct seller_id
 			// yang jelas, pertama kali ini pasti enggak ada catatannya
@@ -282,9 +309,13 @@ func (pc *PurchaseController) Cart(c *fiber.Ctx) error {
 						TotalPrice        float64 `json:"totalPrice"`
 					}
 				*/
-				if cachedSellerValue, found := serviceCache.GetAsMap(fmt.Sprintf(serviceCache.CacheSellerById, _sellerId)); found {
+				sellerKey := fmt.Sprintf(serviceCache.CacheSellerById, _sellerId)
+				cachedSellerValue, found := serviceCache.GetAsMap(sellerKey)
+				pc.metrics.CacheLookup(sellerKey, found)
+				if found {
 					cachedSeller = append(cachedSeller, response.SellerBankDetailDTO{
 						SellerId:          cachedSellerValue["SellerId"],
+						BankAccountId:     cachedSellerValue["BankAccountId"],
 						BankAccountName:   cachedSellerValue["BankAccountName"],
 						BankAccountHolder: cachedSellerValue["BankAccountHolder"],
 						BankAccountNumber: cachedSellerValue["BankAccountNumber"],
@@ -314,21 +345,18 @@ func (pc *PurchaseController) Cart(c *fiber.Ctx) error {
 	// todo; get SellerId berdasarkan produkId
 	if len(toGetSellersById) > 0 {
 		// kirim batch
-		// Create context with timeout
-		ctx, cancel := context.WithTimeout(context.Background(), 25*time.Second)
-		defer cancel()
-
-		// Call gRPC method
+		// Call gRPC method, timeout dan retry diatur oleh client gRPC, context fiber membawa request id
 		// dapat response
-		grpcResponse, err := pc.grpcClient.UserService.GetUserDetailsWithId(ctx, &user.UserRequest{UserIds: toGetSellersById})
+		grpcResponse, err := pc.grpcClient.UserService.GetUserDetailsWithId(c.Context(), &user.UserRequest{UserIds: toGetSellersById})
 		if err != nil {
-			pc.logger.Error(err.Error(), functionCallerInfo.PurhcaseControllerPutCart, "Grpc Call")
-			return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("error during gRPC call: %s\n", err.Error()))
+			pc.logger.Error(c.UserContext(), "unable to get sellers", "error", err, "sellerIds", toGetSellersById)
+			return grpcCallError(err, "sellers of the products are not valid")
 		}
 		// masukan ke respons.dto
 		for _, item := range grpcResponse.Users {
 			cart.PaymentDetails = append(cart.PaymentDetails, response.SellerBankDetailDTO{
 				SellerId:          item.UserId,
+				BankAccountId:     item.BankAccountId,
 				BankAccountName:   item.BankAccountName,
 				BankAccountHolder: item.BankAccountHolder,
 				BankAccountNumber: item.BankAccountNumber,
@@ -338,13 +366,44 @@ func (pc *PurchaseController) Cart(c *fiber.Ctx) error {
 	// todo; compile respond
 
 	// todo; save into repositories
-	insertedCartId, err := pc.purchaseService.SaveCart(c, *requestBody)
+	// penjual tiap produk dan rekening default-nya disimpan bersama item, pembeli hanya kalau request membawa token
+	sellerIds := make(map[string]string)
+	for _, item := range append(cachedProducts, cart.PurchasedItems...) {
+		sellerIds[item.ProductId] = item.SellerId
+	}
+	bankAccountIds := make(map[string]string)
+	for _, seller := range append(cachedSeller, cart.PaymentDetails...) {
+		bankAccountIds[seller.SellerId] = seller.BankAccountId
+	}
+	var buyerId string
+	if principal, ok := auth.PrincipalFrom(c); ok {
+		buyerId = principal.UserId
+	}
+	// penjualan dicatat di inventory ledger product, stok yang kurang membatalkan purchase
+	recordSale := func(ctx context.Context, purchaseId string) error {
+		sale := &product.RecordSaleRequest{PurchaseId: purchaseId, BuyerId: buyerId}
+		for _, item := range requestBody.PurchasedItems {
+			sale.Items = append(sale.Items, &product.SaleItem{
+				ProductId: item.ProductId,
+				VariantId: item.VariantId,
+				Qty:       int32(item.Qty),
+			})
+		}
+		_, err := pc.productClient.ProductService.RecordSale(ctx, sale)
+		return err
+	}
+	insertedCartId, err := pc.purchaseService.SaveCart(c, *requestBody, buyerId, sellerIds, bankAccountIds, recordSale)
 	if err != nil {
-		// tidak perlu logging lagi semenjak sudah ditangani oleh layar repository/service
-		pc.logger.Error(err.Error(), functionCallerInfo.PurhcaseControllerPutCart, requestBody)
+		// detail error sudah di-log oleh service, di sini body-nya, nomor rekening otomatis di-redact
+		pc.logger.Error(c.UserContext(), "unable to save cart", "error", err, "body", requestBody)
+		if _, ok := status.FromError(err); ok {
+			return grpcCallError(err, status.Convert(err).Message())
+		}
 		return fiber.NewError(fiber.StatusBadRequest, err.Error())
 	}
 	cart.PurchaseId = *insertedCartId
+	pc.metrics.CartsCreated.Inc()
+	pc.metrics.PurchaseValue.Observe(cart.TotalPrice)
 
 	// todo; free temporaries
 	mapSellerId = nil
@@ -355,3 +414,128 @@ func (pc *PurchaseController) Cart(c *fiber.Ctx) error {
 	//
 	return c.Status(fiber.StatusCreated).JSON(cart)
 }
+
+// Purchase godoc
+// @Summary Get a purchase
+// @Description Pembeli membaca ulang purchase miliknya. Detail rekening diambil dari user service sesuai rekening default penjual saat order dibuat
+// @Tags Purchase
+// @Produce json
+// @Param purchaseId path string true "Purchase ID"
+// @Param Authorization header string true "Bearer token pembeli"
+// @Success 200 {object} response.PurchaseDetailDTO "success response"
+// @Failure 401 {object} map[string]interface{} "unauthorized"
+// @Failure 404 {object} map[string]interface{} "purchase not found"
+// @Failure 500 {object} map[string]interface{} "internal server error"
+// @Failure 503 {object} map[string]interface{} "user service unavailable"
+// @Router /v1/purchase/{purchaseId} [get]
+func (pc *PurchaseController) GetPurchase(c *fiber.Ctx) error {
+	principal, ok := auth.PrincipalFrom(c)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	purchaseId := c.Params("purchaseId")
+	purchase, err := pc.purchaseService.GetPurchase(c.UserContext(), purchaseId, principal.UserId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fiber.NewError(fiber.StatusNotFound, "purchase not found")
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	detail := response.PurchaseDetailDTO{
+		PurchaseId:     purchase.PurchaseID,
+		CreatedAt:      purchase.CreatedAt,
+		PurchasedItems: []response.PurchasedItemDTO{},
+		PaymentDetails: []response.SellerBankAccountDTO{},
+	}
+	resolved := make(map[string]bool)
+	for _, item := range purchase.Items {
+		detail.PurchasedItems = append(detail.PurchasedItems, response.PurchasedItemDTO{
+			ProductId: item.ProductID,
+			VariantId: item.VariantID,
+			Qty:       item.Quantity,
+			SellerId:  item.SellerID,
+		})
+
+		// item lama tidak punya rekening, satu penjual cukup diambil sekali
+		if item.BankAccountID == "" || resolved[item.SellerID] {
+			continue
+		}
+		resolved[item.SellerID] = true
+
+		// timeout dan retry diatur oleh client gRPC, context fiber membawa request id
+		account, err := pc.grpcClient.UserService.GetBankAccount(c.Context(), &user.BankAccountRequest{
+			UserId:        item.SellerID,
+			BankAccountId: item.BankAccountID,
+		})
+		if status.Code(err) == codes.NotFound {
+			// rekening sudah dihapus penjual, item tetap ditampilkan tanpa detail pembayaran
+			pc.logger.Warn(c.UserContext(), "bank account of the purchase no longer exists", "purchaseId", purchaseId, "sellerId", item.SellerID, "bankAccountId", item.BankAccountID)
+			continue
+		}
+		if err != nil {
+			pc.logger.Error(c.UserContext(), "unable to get bank account", "error", err, "purchaseId", purchaseId, "sellerId", item.SellerID)
+			return grpcCallError(err, "bank account of the seller is not valid")
+		}
+
+		detail.PaymentDetails = append(detail.PaymentDetails, response.SellerBankAccountDTO{
+			SellerId:          item.SellerID,
+			BankAccountId:     account.BankAccountId,
+			BankAccountName:   account.BankAccountName,
+			BankAccountHolder: account.BankAccountHolder,
+			BankAccountNumber: account.BankAccountNumber,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(detail)
+}
+
+// Upstream yang down atau lambat jadi 503, request yang ditolak upstream jadi 400
+func grpcCallError(err error, invalidMessage string) error {
+	switch status.Code(err) {
+	case codes.NotFound, codes.InvalidArgument, codes.FailedPrecondition:
+		return fiber.NewError(fiber.StatusBadRequest, invalidMessage)
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted:
+		return fiber.NewError(fiber.StatusServiceUnavailable, "upstream service is unavailable, please try again")
+	}
+	return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("error during gRPC call: %s", status.Convert(err).Message()))
+}
+
+// Harga, stok dan SKU diambil dari varian kalau variantId diisi
+func toProductItem(p *product.ProductResponse, variantId string) (response.ProductItemDTO, error) {
+	price, qty, sku := p.Price, p.Qty, p.Sku
+	if variantId != "" {
+		found := false
+		for _, variant := range p.Variants {
+			if variant.VariantId == variantId {
+				price, qty, sku = variant.Price, variant.Qty, variant.Sku
+				found = true
+				break
+			}
+		}
+		if !found {
+			return response.ProductItemDTO{}, fmt.Errorf("variantId %s is not a variant of productId %s", variantId, p.ProductId)
+		}
+	}
+
+	_price, err := strconv.ParseFloat(price, 64)
+	if err != nil {
+		return response.ProductItemDTO{}, fmt.Errorf("productId %s has an invalid price", p.ProductId)
+	}
+	_qty, err := strconv.Atoi(qty)
+	if err != nil {
+		return response.ProductItemDTO{}, fmt.Errorf("productId %s has an invalid qty", p.ProductId)
+	}
+
+	return response.ProductItemDTO{
+		ProductId: p.ProductId,
+		VariantId: variantId,
+		Name:      p.Name,
+		Qty:       _qty,
+		Price:     _price,
+		SKU:       sku,
+		FileID:    p.FileId,
+		SellerId:  p.UserId,
+	}, nil
+}
@@ -1,5 +1,9 @@
 package httpServer
 
+import "context"
+
 type ServerInterface interface {
-	Listen()
+	Listen() error
+	// Stops accepting connections and waits for the running requests until ctx is done
+	Shutdown(ctx context.Context) error
 }
@@ -1,67 +1,38 @@
 package middlewares
 
 import (
-	"errors"
-	"fmt"
-	"os"
-	"strings"
+	"sync"
 
-	response "github.com/TimDebug/FitByte/src/model/web"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/auth"
+	"github.com/TimDebug/FitByte/src/di"
 	"github.com/gofiber/fiber/v2"
-	"github.com/golang-jwt/jwt/v5"
+	"github.com/samber/do/v2"
 )
 
-func AuthMiddleware(c *fiber.Ctx) error {
-	authorizationHeader := c.Get("Authorization")
-	if !strings.Contains(authorizationHeader, "Bearer") && !strings.Contains(authorizationHeader, "bearer") {
-		return c.Status(fiber.StatusUnauthorized).JSON(response.Web{
-			Message: "UNAUTHORIZED",
-			Data:    "Token Invalid",
-		})
-	}
-
-	bearerToken := ""
-	if strings.Contains(authorizationHeader, "Bearer") {
-		bearerToken = strings.Replace(authorizationHeader, "Bearer ", "", -1)
-	}
-	if strings.Contains(authorizationHeader, "bearer") {
-		bearerToken = strings.Replace(authorizationHeader, "bearer ", "", -1)
-	}
-
-	token, err := jwt.Parse(bearerToken, CheckTokenJWT)
-	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(response.Web{
-			Message: "UNAUTHORIZED",
-			Data:    "Token Invalid",
-		})
-	}
-
-	if !token.Valid {
-		return c.Status(fiber.StatusUnauthorized).JSON(response.Web{
-			Message: "UNAUTHORIZED",
-			Data:    "Token Invalid",
-		})
-	}
+var (
+	authHandlerOnce sync.Once
+	authHandler     fiber.Handler
+)
 
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok {
-		return c.Status(fiber.StatusUnauthorized).JSON(response.Web{
-			Message: "UNAUTHORIZED",
-			Data:    "Token Invalid",
+// AuthMiddleware delegates to the shared auth middleware, the handler is
+// built on first use because routes are registered before the injector is ready
+func AuthMiddleware(c *fiber.Ctx) error {
+	authHandlerOnce.Do(func() {
+		authHandler = auth.New(auth.Config{
+			Keys:        do.MustInvoke[auth.KeyResolver](di.Injector),
+			Revocations: do.MustInvoke[auth.RevocationChecker](di.Injector),
 		})
-	}
-
-	userId := claims["userId"].(string)
-	fmt.Printf("userId: %s", userId)
-	c.Locals("userId", userId)
+	})
 
-	return c.Next()
+	return authHandler(c)
 }
 
-func CheckTokenJWT(t *jwt.Token) (interface{}, error) {
-	if t.Method.Alg() != jwt.SigningMethodHS256.Alg() {
-		return nil, errors.New("invalid token signing method")
+// OptionalAuthMiddleware only authenticates requests that carry an Authorization header,
+// anonymous requests pass through without a principal. A header with an invalid token is still a 401
+func OptionalAuthMiddleware(c *fiber.Ctx) error {
+	if c.Get(fiber.HeaderAuthorization) == "" {
+		return c.Next()
 	}
 
-	return []byte(os.Getenv("JWT_SECRET_KEY")), nil
+	return AuthMiddleware(c)
 }
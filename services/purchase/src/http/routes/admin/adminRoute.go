@@ -0,0 +1,15 @@
+package adminRoute
+
+import (
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/auth"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/logging"
+	"github.com/TimDebug/FitByte/src/http/middlewares"
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetRouteAdmin(router fiber.Router, logger *logging.Logger) {
+	admin := router.Group("/admin", middlewares.AuthMiddleware, auth.RequireRole(auth.RoleAdmin))
+
+	// GET and PUT /admin/log-level of this instance
+	logger.RegisterLevel(admin)
+}
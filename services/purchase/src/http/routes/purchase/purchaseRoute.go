@@ -2,10 +2,14 @@ package purchaseRoute
 
 import (
 	appController "github.com/TimDebug/FitByte/src/http/controllers/purchase"
+	"github.com/TimDebug/FitByte/src/http/middlewares"
 	"github.com/gofiber/fiber/v2"
 )
 
 func SetRoutePurchase(router fiber.Router, controller appController.IPurchaseController) {
-	router.Post("/purchase", controller.Cart)
+	// tanpa login tetap boleh, token hanya mencatat pembelinya
+	router.Post("/purchase", middlewares.OptionalAuthMiddleware, controller.Cart)
+	// hanya pembeli yang login, purchase tanpa login tidak bisa dibaca ulang
+	router.Get("/purchase/:purchaseId", middlewares.AuthMiddleware, controller.GetPurchase)
 	// router.Post("/purchase/:purchaseId", controller.Payment)
 }
@@ -1,28 +1,36 @@
 package httpServer
 
 import (
+	"context"
 	"fmt"
 	_ "net/http/pprof" // Import pprof untuk otomatis registrasi ke http server
 	"strings"
 	"time"
 
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/health"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/logging"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/metrics"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/tracing"
 	"github.com/TimDebug/FitByte/src/config"
 	"github.com/TimDebug/FitByte/src/di"
 	appController "github.com/TimDebug/FitByte/src/http/controllers/purchase"
 	"github.com/TimDebug/FitByte/src/http/routes"
+	adminRoute "github.com/TimDebug/FitByte/src/http/routes/admin"
 	swaggerRoutes "github.com/TimDebug/FitByte/src/http/routes/apidocumentation"
 	purchaseRoute "github.com/TimDebug/FitByte/src/http/routes/purchase"
 	response "github.com/TimDebug/FitByte/src/model/web"
-	"github.com/ansrivas/fiberprometheus/v2"
 	"github.com/bytedance/sonic"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/compress"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
 	"github.com/samber/do/v2"
 )
 
-type HttpServer struct{}
+type HttpServer struct {
+	app *fiber.App
+}
 
-func (s *HttpServer) Listen() {
+func NewHttpServer() *HttpServer {
 	fmt.Printf("New Fiber\n")
 	app := fiber.New(fiber.Config{
 		ServerHeader: "TIM-DEBUG",
@@ -39,6 +47,17 @@ func (s *HttpServer) Listen() {
 	// Setup Middlewares
 	fmt.Printf("Setup middlewares\n")
 
+	// Forwarded to the user service as gRPC metadata, see middleware/grpcmw
+	app.Use(requestid.New())
+	// and logged with every entry of the request, see middleware/logging
+	app.Use(logging.Fiber())
+
+	// Span per request, continued by the user and product services, see middleware/tracing
+	app.Use(tracing.Fiber())
+
+	// Liveness and readiness probes for the load balancer
+	do.MustInvoke[*health.Checker](di.Injector).Register(app)
+
 	// Or extend your config for customization
 	app.Use(compress.New(compress.Config{
 		Level: compress.LevelBestSpeed, // 1
@@ -47,12 +66,11 @@ func (s *HttpServer) Listen() {
 
 	// Prometheus
 	fmt.Printf("Setup prometheus\n")
-	prometheus := fiberprometheus.New("purhcase-service")
-	prometheus.RegisterAt(app, "/metrics")
-
-	app.Use(prometheus.Middleware)
+	// RED, cache, cart and pgx pool metrics, see middleware/metrics
+	m := do.MustInvoke[*metrics.Metrics](di.Injector)
+	m.Register(app)
+	app.Use(m.Fiber())
 
-	// app.Use(middlewares.RequestLogger)
 	if strings.ToUpper(config.GetMode()) == config.MODE_DEBUG {
 		// app.Use(logger.New(logger.Config{
 		// 	Format:     "${time} ${status} - ${method} ${path} - Internal Latency: ${latency}\n",
@@ -77,7 +95,16 @@ func (s *HttpServer) Listen() {
 	routes := routes.SetRoutes(app)
 	swaggerRoutes.SetRouteSwagger(routes)
 	purchaseRoute.SetRoutePurchase(routes, pc)
+	adminRoute.SetRouteAdmin(routes, do.MustInvoke[*logging.Logger](di.Injector))
 
+	return &HttpServer{app: app}
+}
+
+func (s *HttpServer) Listen() error {
 	fmt.Printf("Start Listener\n")
-	app.Listen(fmt.Sprintf("%s:%s", "0.0.0.0", config.GetPort()))
+	return s.app.Listen(fmt.Sprintf("%s:%s", "0.0.0.0", config.GetPort()))
+}
+
+func (s *HttpServer) Shutdown(ctx context.Context) error {
+	return s.app.ShutdownWithContext(ctx)
 }
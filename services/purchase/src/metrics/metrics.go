@@ -0,0 +1,45 @@
+package serviceMetrics
+
+import (
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/metrics"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/samber/do/v2"
+)
+
+// RED metrics of the http server and the stats of the postgres pool
+func NewMetricsInject(i do.Injector) (*metrics.Metrics, error) {
+	_db := do.MustInvoke[*pgxpool.Pool](i)
+
+	m := metrics.New("purchase")
+	if err := m.RegisterPgxPool(_db); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+type PurchaseMetrics struct {
+	*metrics.Metrics
+	CartsCreated  prometheus.Counter
+	PurchaseValue prometheus.Histogram
+}
+
+func NewPurchaseMetricsInject(i do.Injector) (*PurchaseMetrics, error) {
+	_metrics := do.MustInvoke[*metrics.Metrics](i)
+	factory := promauto.With(_metrics.Registerer())
+
+	return &PurchaseMetrics{
+		Metrics: _metrics,
+		CartsCreated: factory.NewCounter(prometheus.CounterOpts{
+			Name: "purchase_carts_created_total",
+			Help: "Carts saved by buyers.",
+		}),
+		// total price of a cart in rupiah, from 10.000 up to ~2.6 billion
+		PurchaseValue: factory.NewHistogram(prometheus.HistogramOpts{
+			Name:    "purchase_value",
+			Help:    "Total price of the saved carts.",
+			Buckets: prometheus.ExponentialBuckets(10_000, 4, 10),
+		}),
+	}, nil
+}
@@ -5,6 +5,7 @@ package request
   "purchasedItems": [ // array | minItems: 1
     {
       "productId": "", // string | should a valid productId
+      "variantId": "", // string | optional | should a valid variantId of the product
       "qty": 1, // number | min: 2
     },
   ],
@@ -16,8 +17,9 @@ package request
 
 // PurchasedItem defines the structure for an item in the cart
 type PurchasedItem struct {
-	ProductId string `json:"productId" validate:"required,uuid4"` // Ensure productId is a valid UUID
-	Qty       int    `json:"qty" validate:"required,min=2"`       // Minimum quantity is 2
+	ProductId string `json:"productId" validate:"required,uuid4"`            // Ensure productId is a valid UUID
+	VariantId string `json:"variantId,omitempty" validate:"omitempty,uuid4"` // Optional variant (size/color) of the product
+	Qty       int    `json:"qty" validate:"required,min=2"`                  // Minimum quantity is 2
 }
 
 // CartDto represents the purchase request
@@ -13,6 +13,7 @@ type PurchaseResponseDTO struct {
 // PurchasedItemDTO represents the details of each purchased item.
 type ProductItemDTO struct {
 	ProductId        string    `json:"productId"`
+	VariantId        string    `json:"variantId,omitempty"`
 	Name             string    `json:"name"`
 	Category         string    `json:"category"`
 	Qty              int       `json:"qty"`
@@ -28,9 +29,35 @@ type ProductItemDTO struct {
 
 // PaymentDetailDTO represents the details of payment for each seller.
 type SellerBankDetailDTO struct {
-	SellerId          string
+	SellerId string
+	// Default account of the seller when the order was placed
+	BankAccountId     string  `json:"bankAccountId"`
 	BankAccountName   string  `json:"bankAccountName"`
 	BankAccountHolder string  `json:"bankAccountHolder"`
 	BankAccountNumber string  `json:"bankAccountNumber"`
 	TotalPrice        float64 `json:"totalPrice"`
 }
+
+// PurchaseDetailDTO is a purchase read back by its buyer.
+type PurchaseDetailDTO struct {
+	PurchaseId     string                 `json:"purchaseId"`
+	CreatedAt      time.Time              `json:"createdAt"`
+	PurchasedItems []PurchasedItemDTO     `json:"purchasedItems"`
+	PaymentDetails []SellerBankAccountDTO `json:"paymentDetails"`
+}
+
+type PurchasedItemDTO struct {
+	ProductId string `json:"productId"`
+	VariantId string `json:"variantId,omitempty"`
+	Qty       int32  `json:"qty"`
+	SellerId  string `json:"sellerId"`
+}
+
+// Account the seller had as default when the order was placed, resolved from the user service
+type SellerBankAccountDTO struct {
+	SellerId          string `json:"sellerId"`
+	BankAccountId     string `json:"bankAccountId"`
+	BankAccountName   string `json:"bankAccountName"`
+	BankAccountHolder string `json:"bankAccountHolder"`
+	BankAccountNumber string `json:"bankAccountNumber"`
+}
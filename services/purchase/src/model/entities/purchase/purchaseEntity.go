@@ -4,9 +4,18 @@ import "time"
 
 type Purchase struct {
 	PurchaseID          string
+	BuyerID             string // kosong kalau pembeli tidak login
 	SenderName          string
 	SenderContactDetail string
 	SenderContactType   string
 	CreatedAt           time.Time
 	UpdatedAt           time.Time
 }
+
+// Purchase tempat user menjadi pembeli atau penjual. Kalau user hanya penjual,
+// Items hanya berisi item miliknya
+type UserPurchase struct {
+	Purchase
+	IsBuyer bool
+	Items   []PurchaseCart
+}
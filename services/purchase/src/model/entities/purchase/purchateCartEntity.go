@@ -5,7 +5,12 @@ import "time"
 type PurchaseCart struct {
 	PurchaseID string
 	ProductID  string
+	VariantID  string
+	SellerID   string
 	Quantity   int32
 	CreatedAt  time.Time
 	UpdatedAt  time.Time
+
+	// Rekening penjual saat order dibuat, kosong untuk data lama
+	BankAccountID string
 }
@@ -5,10 +5,15 @@ import (
 
 	Entity "github.com/TimDebug/FitByte/src/model/entities/purchase"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type IPurchaseRepository interface {
 	InsertInto(tx pgx.Tx, ctx context.Context, entity Entity.Purchase) (string, error)
+	// Memanggil fn untuk setiap purchase tempat user menjadi pembeli atau penjual, urut dari yang terlama
+	ListByUserId(pool *pgxpool.Pool, ctx context.Context, userId string, fn func(Entity.UserPurchase) error) error
+	// Purchase milik pembeli beserta semua itemnya, pgx.ErrNoRows kalau tidak ada atau milik pembeli lain
+	GetByIdForBuyer(pool *pgxpool.Pool, ctx context.Context, purchaseId, buyerId string) (Entity.UserPurchase, error)
 	// Create(ctx *fiber.Ctx, pool *pgxpool.Pool, activity Entity.Activity) (activityId string, err error)
 	// GetValidCaloriesFactors(ctx *fiber.Ctx, pool *pgxpool.Pool, activityId, userId string) (*Entity.CaloriesFactor, error)
 	// GetActivityByUserId(ctx *fiber.Ctx, pool *pgxpool.Pool, activityId, userId string) (string, error)
@@ -3,24 +3,24 @@ package purchaseRepository
 import (
 	"context"
 
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/logging"
 	"github.com/TimDebug/FitByte/src/helper"
-	functionCallerInfo "github.com/TimDebug/FitByte/src/logger/helper"
-	loggerZap "github.com/TimDebug/FitByte/src/logger/zap"
 	Entity "github.com/TimDebug/FitByte/src/model/entities/purchase"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/samber/do/v2"
 )
 
 type PurchaseRepository struct {
-	logger loggerZap.LoggerInterface
+	logger *logging.Logger
 }
 
-func NewPurchaseRepository(logger loggerZap.LoggerInterface) IPurchaseRepository {
+func NewPurchaseRepository(logger *logging.Logger) IPurchaseRepository {
 	return &PurchaseRepository{logger}
 }
 
 func NewPurhcaseRepositoryInject(i do.Injector) (IPurchaseRepository, error) {
-	_logger := do.MustInvoke[loggerZap.LoggerInterface](i)
+	_logger := do.MustInvoke[*logging.Logger](i)
 	return NewPurchaseRepository(_logger), nil
 }
 
@@ -28,15 +28,117 @@ func (pr *PurchaseRepository) InsertInto(tx pgx.Tx, ctx context.Context, entity
 	var id string
 	query := `
 	INSERT INTO 
-	purchase(sender_name, sender_contact_detail, sender_contact_type) 
-	VALUES($1, $2, $3) 
+	purchase(sender_name, sender_contact_detail, sender_contact_type, buyer_id) 
+	VALUES($1, $2, $3, NULLIF($4, '')) 
 	RETURNING id
 	`
-	err := tx.QueryRow(ctx, query, entity.SenderName, entity.SenderContactDetail, entity.SenderContactType).Scan(&id)
+	err := tx.QueryRow(ctx, query, entity.SenderName, entity.SenderContactDetail, entity.SenderContactType, entity.BuyerID).Scan(&id)
 	if err != nil {
 		statusCode, message := helper.MapPgxError(err)
-		pr.logger.Error(message, functionCallerInfo.PurchaseRepositoryInsertInto, message, statusCode)
+		pr.logger.Error(ctx, message, "error", err, "statusCode", statusCode)
 		return "", err
 	}
 	return id, nil
 }
+
+func (pr *PurchaseRepository) ListByUserId(pool *pgxpool.Pool, ctx context.Context, userId string, fn func(Entity.UserPurchase) error) error {
+	// pembeli mendapat semua item, penjual hanya item miliknya. Baris satu purchase selalu berurutan
+	query := `
+	SELECT
+		p.id, p.created_at, COALESCE(p.buyer_id = $1, FALSE),
+		COALESCE(p.sender_name, ''), COALESCE(p.sender_contact_type, ''), COALESCE(p.sender_contact_detail, ''),
+		COALESCE(c.product_id, ''), COALESCE(c.variant_id, ''), COALESCE(c.quantity, 0), COALESCE(c.seller_id, '')
+	FROM purchase p
+	JOIN purchase_cart c ON c.purchase_id = p.id
+	WHERE p.buyer_id = $1 OR c.seller_id = $1
+	ORDER BY p.created_at, p.id
+	`
+	rows, err := pool.Query(ctx, query, userId)
+	if err != nil {
+		pr.logger.Error(ctx, "failed to list purchases", "error", err, "userId", userId)
+		return err
+	}
+	defer rows.Close()
+
+	var current *Entity.UserPurchase
+	for rows.Next() {
+		var purchase Entity.UserPurchase
+		var item Entity.PurchaseCart
+		err := rows.Scan(
+			&purchase.PurchaseID, &purchase.CreatedAt, &purchase.IsBuyer,
+			&purchase.SenderName, &purchase.SenderContactType, &purchase.SenderContactDetail,
+			&item.ProductID, &item.VariantID, &item.Quantity, &item.SellerID,
+		)
+		if err != nil {
+			pr.logger.Error(ctx, "failed to scan purchase", "error", err, "userId", userId)
+			return err
+		}
+
+		if current != nil && current.PurchaseID != purchase.PurchaseID {
+			if err := fn(*current); err != nil {
+				return err
+			}
+			current = nil
+		}
+		if current == nil {
+			current = &purchase
+		}
+		item.PurchaseID = purchase.PurchaseID
+		current.Items = append(current.Items, item)
+	}
+	if err := rows.Err(); err != nil {
+		pr.logger.Error(ctx, "failed to list purchases", "error", err, "userId", userId)
+		return err
+	}
+
+	if current != nil {
+		return fn(*current)
+	}
+	return nil
+}
+
+func (pr *PurchaseRepository) GetByIdForBuyer(pool *pgxpool.Pool, ctx context.Context, purchaseId, buyerId string) (Entity.UserPurchase, error) {
+	query := `
+	SELECT
+		p.id, p.created_at,
+		COALESCE(p.sender_name, ''), COALESCE(p.sender_contact_type, ''), COALESCE(p.sender_contact_detail, ''),
+		COALESCE(c.product_id, ''), COALESCE(c.variant_id, ''), COALESCE(c.quantity, 0),
+		COALESCE(c.seller_id, ''), COALESCE(c.bank_account_id, '')
+	FROM purchase p
+	JOIN purchase_cart c ON c.purchase_id = p.id
+	WHERE p.id = $1 AND p.buyer_id = $2
+	ORDER BY c.seller_id, c.product_id
+	`
+	rows, err := pool.Query(ctx, query, purchaseId, buyerId)
+	if err != nil {
+		pr.logger.Error(ctx, "failed to get purchase", "error", err, "purchaseId", purchaseId)
+		return Entity.UserPurchase{}, err
+	}
+	defer rows.Close()
+
+	purchase := Entity.UserPurchase{IsBuyer: true}
+	for rows.Next() {
+		var item Entity.PurchaseCart
+		err := rows.Scan(
+			&purchase.PurchaseID, &purchase.CreatedAt,
+			&purchase.SenderName, &purchase.SenderContactType, &purchase.SenderContactDetail,
+			&item.ProductID, &item.VariantID, &item.Quantity,
+			&item.SellerID, &item.BankAccountID,
+		)
+		if err != nil {
+			pr.logger.Error(ctx, "failed to scan purchase", "error", err, "purchaseId", purchaseId)
+			return Entity.UserPurchase{}, err
+		}
+		item.PurchaseID = purchase.PurchaseID
+		purchase.Items = append(purchase.Items, item)
+	}
+	if err := rows.Err(); err != nil {
+		pr.logger.Error(ctx, "failed to get purchase", "error", err, "purchaseId", purchaseId)
+		return Entity.UserPurchase{}, err
+	}
+
+	if len(purchase.Items) == 0 {
+		return Entity.UserPurchase{}, pgx.ErrNoRows
+	}
+	return purchase, nil
+}
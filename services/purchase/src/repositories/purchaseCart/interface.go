@@ -8,7 +8,9 @@ import (
 )
 
 type IPuchaseCartRepository interface {
-	InsertInto(tx pgx.Tx, ctx context.Context, purchaseId string, entities []request.PurchasedItem) error
+	// sellerIds berisi penjual tiap productId, item dengan produk yang tidak ada di map disimpan tanpa penjual.
+	// bankAccountIds berisi rekening default tiap penjual saat order dibuat
+	InsertInto(tx pgx.Tx, ctx context.Context, purchaseId string, entities []request.PurchasedItem, sellerIds, bankAccountIds map[string]string) error
 	// Create(ctx *fiber.Ctx, pool *pgxpool.Pool, activity Entity.Activity) (activityId string, err error)
 	// GetValidCaloriesFactors(ctx *fiber.Ctx, pool *pgxpool.Pool, activityId, userId string) (*Entity.CaloriesFactor, error)
 	// GetActivityByUserId(ctx *fiber.Ctx, pool *pgxpool.Pool, activityId, userId string) (string, error)
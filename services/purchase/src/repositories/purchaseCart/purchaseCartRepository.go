@@ -3,36 +3,36 @@ package purchaseCartRepository
 import (
 	"context"
 
-	functionCallerInfo "github.com/TimDebug/FitByte/src/logger/helper"
-	loggerZap "github.com/TimDebug/FitByte/src/logger/zap"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/logging"
 	"github.com/TimDebug/FitByte/src/model/dtos/request"
 	"github.com/jackc/pgx/v5"
 	"github.com/samber/do/v2"
 )
 
 type PuchaseCartRepository struct {
-	logger loggerZap.LoggerInterface
+	logger *logging.Logger
 }
 
-func NewPurchaseCartRepository(logger loggerZap.LoggerInterface) IPuchaseCartRepository {
+func NewPurchaseCartRepository(logger *logging.Logger) IPuchaseCartRepository {
 	return &PuchaseCartRepository{logger}
 }
 
 func NewPurhcaseCartRepositoryInject(i do.Injector) (IPuchaseCartRepository, error) {
-	_logger := do.MustInvoke[loggerZap.LoggerInterface](i)
+	_logger := do.MustInvoke[*logging.Logger](i)
 	return NewPurchaseCartRepository(_logger), nil
 }
 
-func (pr *PuchaseCartRepository) InsertInto(tx pgx.Tx, ctx context.Context, purchaseId string, entities []request.PurchasedItem) error {
+func (pr *PuchaseCartRepository) InsertInto(tx pgx.Tx, ctx context.Context, purchaseId string, entities []request.PurchasedItem, sellerIds, bankAccountIds map[string]string) error {
 	// Insert purchased items ke tabel terkait
 	query := `
-	INSERT INTO purchase_cart (purchase_id, product_id, quantity) 
-	VALUES ($1, $2, $3)
+	INSERT INTO purchase_cart (purchase_id, product_id, variant_id, quantity, seller_id, bank_account_id) 
+	VALUES ($1, $2, NULLIF($3, ''), $4, NULLIF($5, ''), NULLIF($6, ''))
 	`
 	for _, item := range entities {
-		_, err := tx.Exec(ctx, query, purchaseId, item.ProductId, item.Qty)
+		sellerId := sellerIds[item.ProductId]
+		_, err := tx.Exec(ctx, query, purchaseId, item.ProductId, item.VariantId, item.Qty, sellerId, bankAccountIds[sellerId])
 		if err != nil {
-			pr.logger.Error("failed to insert purchased item", functionCallerInfo.PurchaseRepositoryInsertInto, err.Error())
+			pr.logger.Error(ctx, "failed to insert purchased item", "error", err, "productId", item.ProductId)
 			return err
 		}
 	}
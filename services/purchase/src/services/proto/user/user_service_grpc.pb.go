@@ -23,6 +23,8 @@ const _ = grpc.SupportPackageIsVersion9
 const (
 	UserService_GetUserDetails_FullMethodName       = "/user.UserService/GetUserDetails"
 	UserService_GetUserDetailsWithId_FullMethodName = "/user.UserService/GetUserDetailsWithId"
+	UserService_GetSellerProfile_FullMethodName     = "/user.UserService/GetSellerProfile"
+	UserService_GetBankAccount_FullMethodName       = "/user.UserService/GetBankAccount"
 )
 
 // UserServiceClient is the client API for UserService service.
@@ -33,6 +35,8 @@ const (
 type UserServiceClient interface {
 	GetUserDetails(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*UsersResponse, error)
 	GetUserDetailsWithId(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*UsersWithIdResponse, error)
+	GetSellerProfile(ctx context.Context, in *SellerProfileRequest, opts ...grpc.CallOption) (*SellerProfileResponse, error)
+	GetBankAccount(ctx context.Context, in *BankAccountRequest, opts ...grpc.CallOption) (*BankAccountResponse, error)
 }
 
 type userServiceClient struct {
@@ -63,6 +67,26 @@ func (c *userServiceClient) GetUserDetailsWithId(ctx context.Context, in *UserRe
 	return out, nil
 }
 
+func (c *userServiceClient) GetSellerProfile(ctx context.Context, in *SellerProfileRequest, opts ...grpc.CallOption) (*SellerProfileResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SellerProfileResponse)
+	err := c.cc.Invoke(ctx, UserService_GetSellerProfile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) GetBankAccount(ctx context.Context, in *BankAccountRequest, opts ...grpc.CallOption) (*BankAccountResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BankAccountResponse)
+	err := c.cc.Invoke(ctx, UserService_GetBankAccount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // UserServiceServer is the server API for UserService service.
 // All implementations must embed UnimplementedUserServiceServer
 // for forward compatibility.
@@ -71,6 +95,8 @@ func (c *userServiceClient) GetUserDetailsWithId(ctx context.Context, in *UserRe
 type UserServiceServer interface {
 	GetUserDetails(context.Context, *UserRequest) (*UsersResponse, error)
 	GetUserDetailsWithId(context.Context, *UserRequest) (*UsersWithIdResponse, error)
+	GetSellerProfile(context.Context, *SellerProfileRequest) (*SellerProfileResponse, error)
+	GetBankAccount(context.Context, *BankAccountRequest) (*BankAccountResponse, error)
 	mustEmbedUnimplementedUserServiceServer()
 }
 
@@ -87,6 +113,12 @@ func (UnimplementedUserServiceServer) GetUserDetails(context.Context, *UserReque
 func (UnimplementedUserServiceServer) GetUserDetailsWithId(context.Context, *UserRequest) (*UsersWithIdResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetUserDetailsWithId not implemented")
 }
+func (UnimplementedUserServiceServer) GetSellerProfile(context.Context, *SellerProfileRequest) (*SellerProfileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSellerProfile not implemented")
+}
+func (UnimplementedUserServiceServer) GetBankAccount(context.Context, *BankAccountRequest) (*BankAccountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBankAccount not implemented")
+}
 func (UnimplementedUserServiceServer) mustEmbedUnimplementedUserServiceServer() {}
 func (UnimplementedUserServiceServer) testEmbeddedByValue()                     {}
 
@@ -144,6 +176,42 @@ func _UserService_GetUserDetailsWithId_Handler(srv interface{}, ctx context.Cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _UserService_GetSellerProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SellerProfileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetSellerProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_GetSellerProfile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetSellerProfile(ctx, req.(*SellerProfileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_GetBankAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BankAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetBankAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_GetBankAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetBankAccount(ctx, req.(*BankAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // UserService_ServiceDesc is the grpc.ServiceDesc for UserService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -159,6 +227,14 @@ var UserService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetUserDetailsWithId",
 			Handler:    _UserService_GetUserDetailsWithId_Handler,
 		},
+		{
+			MethodName: "GetSellerProfile",
+			Handler:    _UserService_GetSellerProfile_Handler,
+		},
+		{
+			MethodName: "GetBankAccount",
+			Handler:    _UserService_GetBankAccount_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/user_service.proto",
@@ -2,31 +2,28 @@ package purchaseService
 
 import (
 	"context"
-	"fmt"
 	"time"
 
-	functionCallerInfo "github.com/TimDebug/FitByte/src/logger/helper"
-	loggerZap "github.com/TimDebug/FitByte/src/logger/zap"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/logging"
 	"github.com/TimDebug/FitByte/src/model/dtos/request"
 	Entity "github.com/TimDebug/FitByte/src/model/entities/purchase"
 	purchaseRepository "github.com/TimDebug/FitByte/src/repositories/purchase"
 	purchaseCartRepository "github.com/TimDebug/FitByte/src/repositories/purchaseCart"
 	"github.com/gofiber/fiber/v2"
-	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/samber/do/v2"
 )
 
 type PurchaseService struct {
-	logger                 loggerZap.LoggerInterface
+	logger                 *logging.Logger
 	purchaseCartRepository purchaseCartRepository.IPuchaseCartRepository
 	purchaseRepository     purchaseRepository.IPurchaseRepository
 	db                     *pgxpool.Pool
 }
 
 func NewInject(i do.Injector) (*PurchaseService, error) {
-	_logger := do.MustInvoke[loggerZap.LoggerInterface](i)
+	_logger := do.MustInvoke[*logging.Logger](i)
 	_db := do.MustInvoke[*pgxpool.Pool](i)
 	_pcr := do.MustInvoke[purchaseCartRepository.IPuchaseCartRepository](i)
 	_pr := do.MustInvoke[purchaseRepository.IPurchaseRepository](i)
@@ -39,11 +36,11 @@ func NewInject(i do.Injector) (*PurchaseService, error) {
 }
 
 // formely returned (*response.PurchaseResponseDTO, error)
-// Service yang menggunakan pool
-func (this PurchaseService) SaveCart(c *fiber.Ctx, entity request.CartDto) (*string, error) {
-	requestId := uuid.New()
-
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+// Service yang menggunakan pool. buyerId kosong kalau pembeli tidak login, sellerIds berisi penjual tiap productId,
+// bankAccountIds rekening default tiap penjual. recordSale dipanggil sebelum commit, kalau stok ditolak purchase ikut di-rollback
+func (this PurchaseService) SaveCart(c *fiber.Ctx, entity request.CartDto, buyerId string, sellerIds, bankAccountIds map[string]string, recordSale func(ctx context.Context, purchaseId string) error) (*string, error) {
+	// UserContext membawa span dan request id, query di bawah jadi child span-nya
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
 	defer cancel()
 
 	// Dapatkan koneksi dari pool (dengan menunggu jika pool penuh)
@@ -51,7 +48,7 @@ func (this PurchaseService) SaveCart(c *fiber.Ctx, entity request.CartDto) (*str
 
 	conn, err := this.db.Acquire(ctx)
 	if err != nil {
-		this.logger.Error(err.Error(), functionCallerInfo.PurchaserServiceSaveCart, "Acquire Connection", fmt.Sprintf("RequestID:%s|WaitTime:%v", requestId, time.Since(start)))
+		this.logger.Error(ctx, "unable to acquire connection", "error", err, "waitTime", time.Since(start).String())
 		return nil, err
 	}
 	defer conn.Release()
@@ -59,34 +56,43 @@ func (this PurchaseService) SaveCart(c *fiber.Ctx, entity request.CartDto) (*str
 	// Gunakan koneksi dari pool
 	tx, err := conn.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
 	if err != nil {
-		this.logger.Error(err.Error(), functionCallerInfo.PurchaserServiceSaveCart, "Begin Transaction", fmt.Sprintf("RequestID:%s", requestId))
+		this.logger.Error(ctx, "unable to begin transaction", "error", err)
 		return nil, err
 	}
 	defer tx.Rollback(ctx)
 
 	// Eksekusi query
 	senderDetail := Entity.Purchase{
+		BuyerID:             buyerId,
 		SenderName:          entity.SenderName,
 		SenderContactDetail: entity.SenderContactDetail,
 		SenderContactType:   entity.SenderContactType,
 	}
 	insertedId, err := this.purchaseRepository.InsertInto(tx, ctx, senderDetail)
 	if err != nil {
-		this.logger.Error(err.Error(), functionCallerInfo.PurchaserServiceSaveCart, senderDetail, fmt.Sprintf("RequestID:%s", requestId))
+		this.logger.Error(ctx, "unable to insert purchase", "error", err, "purchase", senderDetail)
 		tx.Rollback(ctx)
 		return nil, err
 	}
 
-	err = this.purchaseCartRepository.InsertInto(tx, ctx, insertedId, entity.PurchasedItems)
+	err = this.purchaseCartRepository.InsertInto(tx, ctx, insertedId, entity.PurchasedItems, sellerIds, bankAccountIds)
 	if err != nil {
-		this.logger.Error(err.Error(), functionCallerInfo.PurchaserServiceSaveCart, entity.PurchasedItems, fmt.Sprintf("RequestID:%s", requestId))
+		this.logger.Error(ctx, "unable to insert purchased items", "error", err, "purchasedItems", entity.PurchasedItems)
 		tx.Rollback(ctx)
 		return nil, err
 	}
 
+	// Stok dikurangi di product service, errornya (status gRPC) diteruskan apa adanya
+	err = recordSale(ctx, insertedId)
+	if err != nil {
+		this.logger.Error(ctx, "unable to record sale", "error", err, "purchaseId", insertedId)
+		return nil, err
+	}
+
 	// Commit transaksi jika sukses
 	if err := tx.Commit(ctx); err != nil {
-		this.logger.Error(err.Error(), functionCallerInfo.PurchaserServiceSaveCart, fmt.Sprintf("RequestID:%s", requestId))
+		// stok sudah terjual di product tapi purchase-nya tidak tersimpan, perlu dikoreksi lewat inventory ledger
+		this.logger.Error(ctx, "unable to commit transaction after the sale was recorded", "error", err, "purchaseId", insertedId, "purchasedItems", entity.PurchasedItems)
 		tx.Rollback(ctx)
 		return nil, err
 	}
@@ -94,3 +100,13 @@ func (this PurchaseService) SaveCart(c *fiber.Ctx, entity request.CartDto) (*str
 	// Return inserted ID
 	return &insertedId, nil
 }
+
+// Dipakai export data akun lewat gRPC, fn dipanggil per purchase sambil hasil query dibaca
+func (this PurchaseService) ListPurchasesByUserId(ctx context.Context, userId string, fn func(Entity.UserPurchase) error) error {
+	return this.purchaseRepository.ListByUserId(this.db, ctx, userId, fn)
+}
+
+// Purchase milik pembeli, pgx.ErrNoRows kalau tidak ada atau milik pembeli lain
+func (this PurchaseService) GetPurchase(ctx context.Context, purchaseId, buyerId string) (Entity.UserPurchase, error) {
+	return this.purchaseRepository.GetByIdForBuyer(this.db, ctx, purchaseId, buyerId)
+}
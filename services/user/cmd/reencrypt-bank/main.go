@@ -0,0 +1,42 @@
+// Re-encrypts every stored bank account with the active field encryption key.
+// Run it after changing FIELD_ENCRYPTION_ACTIVE_KID, and once after deploying field
+// encryption to seal the rows that are still plaintext. Safe to run more than once.
+//
+//	go run ./cmd/reencrypt-bank -batch 500
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/config"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/di"
+	bankAccountService "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/bankAccount"
+	"github.com/joho/godotenv"
+	"github.com/samber/do/v2"
+)
+
+func main() {
+	batchSize := flag.Int("batch", 500, "rows read per query")
+	flag.Parse()
+
+	err := godotenv.Load()
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	err = config.SetupReusableEnv()
+	if err != nil {
+		panic(err)
+	}
+
+	bs := do.MustInvoke[bankAccountService.BankAccountServiceInterface](di.Injector)
+
+	updated, err := bs.ReencryptBankAccounts(context.Background(), *batchSize)
+	if err != nil {
+		panic(fmt.Sprintf("re-encryption stopped after %d rows: %v", updated, err))
+	}
+
+	fmt.Printf("Re-encrypted %d bank accounts\n", updated)
+}
@@ -1,15 +1,28 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/health"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/logging"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/tracing"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/cache"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/config"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/database/migrations"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/database/postgre"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/di"
 	userGrpc "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/grpc"
 	httpServer "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/http"
+	userEventsService "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/external/userEvents"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/joho/godotenv"
 	_ "github.com/joho/godotenv/autoload"
+	"github.com/samber/do/v2"
 )
 
 func main() {
@@ -24,6 +37,13 @@ func main() {
 		panic(err)
 	}
 
+	//? Tracing first, the pgx and redis clients created by DI use it
+	fmt.Printf("Setup Tracing\n")
+	shutdownTracing, err := tracing.Setup(context.Background(), config.GetTracing())
+	if err != nil {
+		panic(err)
+	}
+
 	fmt.Printf("DI Healthcheck\n")
 	di.HealthCheck()
 
@@ -31,11 +51,92 @@ func main() {
 	fmt.Printf("Migrate\n")
 	migrations.Migrate()
 
-	fmt.Printf("Start gRPC Server\n")
-	userGrpc.StartGrpcServer()
+	//? SIGTERM saat deploy atau scale-in, SIGINT dari terminal
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	grpcServer := userGrpc.NewGrpcServer()
+	server := httpServer.NewHttpServer()
 
+	//? Outbox dispatcher for UserDeleted events, runs until shutdown stops it after the requests drained
+	eventsCtx, stopEvents := context.WithCancel(context.Background())
+	eventsDone := make(chan struct{})
+	fmt.Printf("Start User Events Dispatcher\n")
+	go func() {
+		defer close(eventsDone)
+		do.MustInvoke[userEventsService.UserEventsServiceInterface](di.Injector).Run(eventsCtx)
+	}()
+	stopDispatcher := func(ctx context.Context) error {
+		stopEvents()
+		select {
+		case <-eventsDone:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	errs := make(chan error, 2)
+	fmt.Printf("Start gRPC Server\n")
+	go func() { errs <- grpcServer.Listen() }()
 	fmt.Printf("Start Server\n")
-	server := httpServer.HttpServer{}
-	server.Listen()
+	go func() { errs <- server.Listen() }()
+
+	select {
+	case <-ctx.Done():
+		do.MustInvoke[*logging.Logger](di.Injector).Warn(context.Background(), "shutdown signal received")
+	case err := <-errs:
+		do.MustInvoke[*logging.Logger](di.Injector).Error(context.Background(), "server stopped", "error", err)
+	}
+	shutdown(server, grpcServer, stopDispatcher, shutdownTracing)
+}
+
+// Readiness fails first so no new traffic is routed here, then the running requests drain
+// and only then the connections they use are closed. Bounded by SHUTDOWN_TIMEOUT
+func shutdown(server httpServer.ServerInterface, grpcServer *userGrpc.UserGrpcServer, stopDispatcher, shutdownTracing func(context.Context) error) {
+	ctx, cancel := context.WithTimeout(context.Background(), config.GetShutdownTimeout())
+	defer cancel()
+	logger := do.MustInvoke[*logging.Logger](di.Injector)
+
+	//? 1. Stop accepting requests
+	do.MustInvoke[*health.Checker](di.Injector).Shutdown()
+
+	//? 2. Drain HTTP and gRPC
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		if err := server.Shutdown(ctx); err != nil {
+			logger.Error(ctx, "unable to drain http server", "error", err)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		if err := grpcServer.Shutdown(ctx); err != nil {
+			logger.Error(ctx, "unable to drain grpc server", "error", err)
+		}
+	}()
+	wg.Wait()
+
+	//? 3. Stop the outbox dispatcher, the rows it claimed are acked or rescheduled first
+	if err := stopDispatcher(ctx); err != nil {
+		logger.Error(ctx, "unable to stop user events dispatcher", "error", err)
+	}
+
+	//? 4. Close Redis and the pgx pool
+	if err := do.MustInvoke[cache.RedisCacheClient](di.Injector).Close(); err != nil {
+		logger.Error(ctx, "unable to close redis", "error", err)
+	}
+	if err := postgre.Close(ctx, do.MustInvoke[*pgxpool.Pool](di.Injector)); err != nil {
+		logger.Error(ctx, "unable to close DB connections", "error", err)
+	}
+
+	//? 5. Flush the spans of the drained requests
+	if err := shutdownTracing(ctx); err != nil {
+		logger.Error(ctx, "unable to flush traces", "error", err)
+	}
 
+	//? Flush the logger last so the steps above are logged
+	logger.Info(ctx, "shutdown complete")
+	logger.Sync()
 }
@@ -0,0 +1,166 @@
+package authJwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/config"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type signingKey struct {
+	kid     string
+	method  jwt.SigningMethod
+	private crypto.Signer
+}
+
+// Private keys indexed by kid. Only the active key signs,
+// the others are kept to verify tokens issued before a rotation.
+type KeySet struct {
+	activeKid string
+	keys      map[string]signingKey
+}
+
+// Public part of a signing key in RFC 7517 format
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+func LoadKeySet() (*KeySet, error) {
+	paths := config.GetSigningKeys()
+	if len(paths) == 0 {
+		if strings.ToUpper(config.MODE) != config.MODE_DEBUG {
+			return nil, errors.New("JWT_SIGNING_KEYS value requires to be set")
+		}
+
+		// Local runs only, tokens stop verifying after a restart
+		log.Println("JWT_SIGNING_KEYS is empty, using an ephemeral Ed25519 key")
+		_, private, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		key := signingKey{kid: "ephemeral", method: jwt.SigningMethodEdDSA, private: private}
+		return &KeySet{activeKid: key.kid, keys: map[string]signingKey{key.kid: key}}, nil
+	}
+
+	keySet := &KeySet{
+		activeKid: config.GetActiveSigningKid(),
+		keys:      make(map[string]signingKey),
+	}
+	for kid, path := range paths {
+		key, err := loadSigningKey(kid, path)
+		if err != nil {
+			return nil, err
+		}
+		keySet.keys[kid] = key
+	}
+
+	if _, ok := keySet.keys[keySet.activeKid]; !ok {
+		return nil, fmt.Errorf("JWT_ACTIVE_KID %q is not one of JWT_SIGNING_KEYS", keySet.activeKid)
+	}
+
+	return keySet, nil
+}
+
+func loadSigningKey(kid, path string) (signingKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return signingKey{}, fmt.Errorf("read signing key %s: %w", kid, err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return signingKey{}, fmt.Errorf("signing key %s is not PEM encoded", kid)
+	}
+
+	var parsed any
+	parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return signingKey{}, fmt.Errorf("parse signing key %s: %w", kid, err)
+		}
+	}
+
+	switch private := parsed.(type) {
+	case ed25519.PrivateKey:
+		return signingKey{kid: kid, method: jwt.SigningMethodEdDSA, private: private}, nil
+	case *rsa.PrivateKey:
+		if private.N.BitLen() < 2048 {
+			return signingKey{}, fmt.Errorf("signing key %s: RSA keys must be at least 2048 bits", kid)
+		}
+		return signingKey{kid: kid, method: jwt.SigningMethodRS256, private: private}, nil
+	default:
+		return signingKey{}, fmt.Errorf("signing key %s: only Ed25519 and RSA keys are supported", kid)
+	}
+}
+
+func (ks *KeySet) active() signingKey {
+	return ks.keys[ks.activeKid]
+}
+
+// The alg header must match the key, otherwise an RSA key could verify an EdDSA token
+func (ks *KeySet) Resolve(kid, alg string) (crypto.PublicKey, error) {
+	key, ok := ks.keys[kid]
+	if !ok {
+		return nil, errors.New("unknown signing key")
+	}
+	if key.method.Alg() != alg {
+		return nil, errors.New("invalid token signing method")
+	}
+
+	return key.private.Public(), nil
+}
+
+// Public keys of every loaded kid, sorted so the document is stable between calls
+func (ks *KeySet) JWKS() JWKS {
+	kids := make([]string, 0, len(ks.keys))
+	for kid := range ks.keys {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	jwks := JWKS{Keys: make([]JWK, 0, len(kids))}
+	for _, kid := range kids {
+		key := ks.keys[kid]
+		jwk := JWK{Kid: kid, Use: "sig", Alg: key.method.Alg()}
+
+		switch public := key.private.Public().(type) {
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(public)
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		}
+
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+
+	return jwks
+}
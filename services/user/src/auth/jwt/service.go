@@ -1,36 +1,84 @@
 package authJwt
 
 import (
+	"context"
+	"crypto"
+	"time"
+
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/auth"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/config"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/samber/do/v2"
 )
 
+// Tokens are verified by the shared auth middleware, which resolves
+// the public keys of this service through the embedded KeyResolver
 type JwtServiceInterface interface {
-	GenerateToken(userId string) (string, error)
+	auth.KeyResolver
+	// Roles are embedded in the token and enforced by auth.RequireRole in every service
+	GenerateToken(userId string, roles []string) (AccessToken, error)
+	JWKS() JWKS
+}
+
+type AccessToken struct {
+	Token     string
+	Jti       string
+	ExpiresAt time.Time
 }
 
 type JwtService struct {
+	keySet *KeySet
 }
 
-func NewJwtService() JwtServiceInterface {
-	return &JwtService{}
+func NewJwtService(keySet *KeySet) JwtServiceInterface {
+	return &JwtService{keySet: keySet}
 }
 
 func NewJwtServiceInject(i do.Injector) (JwtServiceInterface, error) {
-	return NewJwtService(), nil
+	keySet, err := LoadKeySet()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewJwtService(keySet), nil
 }
 
-func (js *JwtService) GenerateToken(userId string) (string, error) {
-	claim := jwt.MapClaims{}
-	claim["userId"] = userId
+func (js *JwtService) GenerateToken(userId string, roles []string) (AccessToken, error) {
+	now := time.Now()
+	expiresAt := now.Add(config.GetAccessTokenTtl())
+	jti := uuid.NewString()
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claim)
+	claim := auth.Claims{
+		UserId: userId,
+		Roles:  roles,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	key := js.keySet.active()
+	token := jwt.NewWithClaims(key.method, claim)
+	token.Header["kid"] = key.kid
 
-	signedToken, err := token.SignedString([]byte(config.GetSecretKey()))
+	signedToken, err := token.SignedString(key.private)
 	if err != nil {
-		return signedToken, err
+		return AccessToken{}, err
 	}
 
-	return signedToken, nil
+	return AccessToken{
+		Token:     signedToken,
+		Jti:       jti,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+func (js *JwtService) Resolve(ctx context.Context, kid, alg string) (crypto.PublicKey, error) {
+	return js.keySet.Resolve(kid, alg)
+}
+
+func (js *JwtService) JWKS() JWKS {
+	return js.keySet.JWKS()
 }
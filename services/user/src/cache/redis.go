@@ -4,9 +4,14 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/auth"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/metrics"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/tracing"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/config"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/model/dtos/response"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/model/dtos/service"
@@ -18,6 +23,18 @@ import (
 
 const (
 	DefaultCacheTtl = 5 * time.Minute
+
+	loginFailuresKeyPrefix     = "login:fail:"
+	loginLockKeyPrefix         = "login:lock:"
+	loginLockoutLevelKeyPrefix = "login:level:"
+	// How long a lockout keeps counting towards the next, longer one
+	loginLockoutLevelTtl = 24 * time.Hour
+
+	otpKeyPrefix         = "otp:"
+	otpCooldownKeyPrefix = "otp:cooldown:"
+
+	passwordResetKeyPrefix     = "password:reset:"
+	passwordResetUserKeyPrefix = "password:reset:user:"
 )
 
 var (
@@ -40,12 +57,46 @@ type CacheClientInterface interface {
 	MSetUserProfiles(ctx context.Context, users []response.UserWithIdResponse) error
 
 	GetUserProfile(ctx context.Context, userId string) (*response.UserResponse, bool)
+	// Dropped when data embedded in the profile, like the default bank account, changes elsewhere
+	DeleteUserProfile(ctx context.Context, userId string) error
 	MGetUserProfiles(ctx context.Context, keys []string) (cachedUsers []response.UserWithIdResponse, missedUserIds []string, ok bool)
 	GetFile(ctx context.Context, fileId string) (*service.File, bool)
+
+	// Access token revocation list, entries expire together with the token
+	RevokeToken(ctx context.Context, jti string, ttl time.Duration) error
+	IsTokenRevoked(ctx context.Context, jti string) (bool, error)
+
+	// Login brute-force counters, key is a scope prefixed identifier like "email:a@b.c" or "ip:1.2.3.4"
+	GetLoginLockout(ctx context.Context, key string) (time.Duration, error)
+	IncrLoginFailures(ctx context.Context, key string, window time.Duration) (int64, error)
+	IncrLoginLockoutLevel(ctx context.Context, key string) (int64, error)
+	LockLogin(ctx context.Context, key string, duration time.Duration) error
+	ResetLoginFailures(ctx context.Context, keys ...string) error
+
+	// One time codes, key is the purpose and the user id like "email:USER_ID"
+	SetOtp(ctx context.Context, key string, otp service.Otp, ttl time.Duration) error
+	GetOtp(ctx context.Context, key string) (otp service.Otp, ok bool, err error)
+	IncrOtpAttempts(ctx context.Context, key string) (int64, error)
+	DeleteOtp(ctx context.Context, key string) error
+	// Returns the remaining wait when a code was already sent inside the interval
+	AcquireOtpCooldown(ctx context.Context, key string, interval time.Duration) (time.Duration, error)
+
+	// Forgot-password tokens, a user has at most one pending token
+	SetPasswordResetToken(ctx context.Context, userId, tokenHash string, ttl time.Duration) error
+	// Returns the owner and deletes the token so it can't be used twice
+	ConsumePasswordResetToken(ctx context.Context, tokenHash string) (userId string, ok bool, err error)
+	DeletePasswordResetToken(ctx context.Context, userId string) error
+
+	// Used by the readiness check
+	Ping(ctx context.Context) error
+	// Called once on shutdown
+	Close() error
 }
 
 type RedisCacheClient struct {
 	client *redis.Client
+	// hit ratio of the "user:" keys, nil when built without the injector
+	metrics *metrics.Metrics
 }
 
 func NewRedisCacheClient() CacheClientInterface {
@@ -67,17 +118,20 @@ func NewRedisCacheClient() CacheClientInterface {
 }
 
 func NewRedisCacheClientInject(i do.Injector) (RedisCacheClient, error) {
-	redisCacheClientInstance = &RedisCacheClient{
-		client: redis.NewClient(&redis.Options{
-			Addr: fmt.Sprintf(
-				"%s:%s",
-				config.GetRedisHost(),
-				config.GetRedisPort(),
-			),
-			Password: config.GetRedisPassword(), // default no passoword
-			DB:       config.GetRedisDbCount(),  // Default database number
-		}),
+	client := redis.NewClient(&redis.Options{
+		Addr: fmt.Sprintf(
+			"%s:%s",
+			config.GetRedisHost(),
+			config.GetRedisPort(),
+		),
+		Password: config.GetRedisPassword(), // default no passoword
+		DB:       config.GetRedisDbCount(),  // Default database number
+	})
+	// span per command, child of the request span
+	if err := tracing.InstrumentRedis(client); err != nil {
+		return RedisCacheClient{}, err
 	}
+	redisCacheClientInstance = &RedisCacheClient{client: client, metrics: do.MustInvoke[*metrics.Metrics](i)}
 
 	return *redisCacheClientInstance, nil
 }
@@ -90,9 +144,13 @@ func (d RedisCacheClient) SetUserProfile(ctx context.Context, userId string, use
 	userMap := map[string]string{
 		"email":             user.Email,
 		"phone":             user.Phone,
+		"emailVerified":     strconv.FormatBool(user.EmailVerified),
+		"phoneVerified":     strconv.FormatBool(user.PhoneVerified),
+		"displayName":       user.DisplayName,
 		"fileId":            user.FileId,
 		"fileUri":           user.FileUri,
 		"fileThumbnailUri":  user.FileThumbnailUri,
+		"bankAccountId":     user.BankAccountId,
 		"bankAccountName":   user.BankAccountName,
 		"bankAccountHolder": user.BankAccountHolder,
 		"bankAccountNumber": user.BankAccountNumber,
@@ -108,37 +166,41 @@ func (d RedisCacheClient) SetUserProfile(ctx context.Context, userId string, use
 // want to overwrite existing values. MSET is atomic,
 // so all given keys are set at once. It is not possible
 // for clients to see that some of the keys were updated while others are unchanged.
+// MSET can't set a TTL, the EXPIREs are sent in the same pipeline.
 // Src: https://redis.io/docs/latest/commands/mset/
 func (d RedisCacheClient) MSetUserProfiles(ctx context.Context, users []response.UserWithIdResponse) error {
-	usersMap := make(map[string]map[string]string)
+	if len(users) == 0 {
+		return nil
+	}
+
+	cacheMap := make(map[string]string, len(users))
 	for _, user := range users {
-		usersMap[user.UserId] = map[string]string{
+		data, err := sonic.Marshal(map[string]string{
 			"email":             user.Email,
 			"phone":             user.Phone,
 			"fileId":            user.FileId,
 			"fileUri":           user.FileUri,
 			"fileThumbnailUri":  user.FileThumbnailUri,
+			"bankAccountId":     user.BankAccountId,
 			"bankAccountName":   user.BankAccountName,
 			"bankAccountHolder": user.BankAccountHolder,
 			"bankAccountNumber": user.BankAccountNumber,
-		}
-	}
-
-	cacheMap := make(map[string]string)
-	for k, v := range usersMap {
-		data, err := sonic.Marshal(v)
+		})
 		if err != nil {
 			return err
 		}
 
-		cacheMap["user:"+k] = string(data)
+		cacheMap["user:"+user.UserId] = string(data)
 	}
 
-	_, err := d.client.MSet(ctx, cacheMap).Result()
-	if err != nil {
-		return err
-	}
-	return nil
+	_, err := d.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.MSet(ctx, cacheMap)
+		for key := range cacheMap {
+			pipe.Expire(ctx, key, DefaultCacheTtl)
+		}
+		return nil
+	})
+	return err
 }
 
 func (d RedisCacheClient) GetUserProfile(ctx context.Context, userId string) (*response.UserResponse, bool) {
@@ -147,72 +209,86 @@ func (d RedisCacheClient) GetUserProfile(ctx context.Context, userId string) (*r
 	}()
 
 	result, ok := d.getAsMap(ctx, "user:"+userId)
-	if !ok {
+	// Entries written by MSetUserProfiles lack the verification flags and display name
+	_, full := result["emailVerified"]
+	d.cacheLookup("user:"+userId, ok && full)
+	if !ok || !full {
 		return &response.UserResponse{}, false
 	}
 
 	return &response.UserResponse{
 		Email:             result["email"],
 		Phone:             result["phone"],
+		EmailVerified:     result["emailVerified"] == "true",
+		PhoneVerified:     result["phoneVerified"] == "true",
+		DisplayName:       result["displayName"],
 		FileId:            result["fileId"],
 		FileUri:           result["fileUri"],
 		FileThumbnailUri:  result["fileThumbnailUri"],
+		BankAccountId:     result["bankAccountId"],
 		BankAccountName:   result["bankAccountName"],
 		BankAccountHolder: result["bankAccountHolder"],
 		BankAccountNumber: result["bankAccountNumber"],
 	}, true
 }
 
-// Get many user profiles by utilizing the Redis' `MGET` command to query once to Redis server
+func (d RedisCacheClient) DeleteUserProfile(ctx context.Context, userId string) error {
+	_, err := d.client.Del(ctx, "user:"+userId).Result()
+	return err
+}
+
+// Get many user profiles by utilizing the Redis' `MGET` command to query once to Redis server.
+// Every key ends up in either cachedUsers or missedUserIds, ok is false when redis could not be asked
 func (d RedisCacheClient) MGetUserProfiles(ctx context.Context, keys []string) (cachedUsers []response.UserWithIdResponse, missedUserIds []string, ok bool) {
+	cachedUsers = []response.UserWithIdResponse{}
+	missedUserIds = []string{}
+	if len(keys) == 0 {
+		return cachedUsers, missedUserIds, true
+	}
+
 	val, err := d.client.MGet(ctx, keys...).Result()
 	if err != nil {
-		return []response.UserWithIdResponse{}, []string{}, false
-	}
-
-	cachedMap := make(map[string]map[string]string)
-
-	count := 0
-	for k, v := range val {
-		if vStr, ok := v.(string); ok {
-			var r map[string]string
-			err = sonic.Unmarshal([]byte(vStr), &r)
-			if err == nil {
-				count++
-				cachedMap[keys[k]] = r
-			}
-		} else {
-			cachedMap[keys[k]] = map[string]string{}
+		for _, key := range keys {
+			d.cacheLookup(key, false)
+			missedUserIds = append(missedUserIds, strings.TrimPrefix(key, "user:"))
 		}
-	}
-	if count == 0 {
-		return []response.UserWithIdResponse{}, []string{}, false
+		return cachedUsers, missedUserIds, false
 	}
 
-	for key, userCache := range cachedMap {
-		id := key[5:] // because the cache key format "user:SOME_ID"
+	for i, v := range val {
+		id := strings.TrimPrefix(keys[i], "user:") // because the cache key format "user:SOME_ID"
 
-		if len(userCache) == 0 {
+		var userCache map[string]string
+		vStr, isString := v.(string)
+		if !isString || sonic.Unmarshal([]byte(vStr), &userCache) != nil || len(userCache) == 0 {
+			d.cacheLookup(keys[i], false)
 			missedUserIds = append(missedUserIds, id)
-		} else {
-			user := response.UserWithIdResponse{
-				UserId:            id,
-				Email:             userCache["email"],
-				Phone:             userCache["phone"],
-				FileId:            userCache["fileId"],
-				FileUri:           userCache["fileUri"],
-				FileThumbnailUri:  userCache["fileThumbnailUri"],
-				BankAccountName:   userCache["bankAccountName"],
-				BankAccountHolder: userCache["bankAccountHolder"],
-				BankAccountNumber: userCache["bankAccountNumber"],
-			}
-
-			cachedUsers = append(cachedUsers, user)
+			continue
 		}
+		d.cacheLookup(keys[i], true)
+
+		cachedUsers = append(cachedUsers, response.UserWithIdResponse{
+			UserId:            id,
+			Email:             userCache["email"],
+			Phone:             userCache["phone"],
+			FileId:            userCache["fileId"],
+			FileUri:           userCache["fileUri"],
+			FileThumbnailUri:  userCache["fileThumbnailUri"],
+			BankAccountId:     userCache["bankAccountId"],
+			BankAccountName:   userCache["bankAccountName"],
+			BankAccountHolder: userCache["bankAccountHolder"],
+			BankAccountNumber: userCache["bankAccountNumber"],
+		})
 	}
 	return cachedUsers, missedUserIds, true
 }
 
+func (d RedisCacheClient) cacheLookup(key string, hit bool) {
+	if d.metrics != nil {
+		d.metrics.CacheLookup(key, hit)
+	}
+}
+
 func (d RedisCacheClient) GetFile(ctx context.Context, fileId string) (*service.File, bool) {
 	defer func() {
 		recover()
@@ -228,3 +304,201 @@ func (d RedisCacheClient) GetFile(ctx context.Context, fileId string) (*service.
 		FileThumbnailUri: result["fileThumbnailUri"],
 	}, true
 }
+
+func (d RedisCacheClient) RevokeToken(ctx context.Context, jti string, ttl time.Duration) error {
+	if ttl <= 0 {
+		// token already expired, nothing to revoke
+		return nil
+	}
+
+	_, err := d.client.Set(ctx, auth.RevokedTokenKeyPrefix+jti, "1", ttl).Result()
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (d RedisCacheClient) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	count, err := d.client.Exists(ctx, auth.RevokedTokenKeyPrefix+jti).Result()
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// Remaining lock time, zero when the key is not locked
+func (d RedisCacheClient) GetLoginLockout(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := d.client.PTTL(ctx, loginLockKeyPrefix+key).Result()
+	if err != nil {
+		return 0, err
+	}
+	if ttl < 0 {
+		// -2 missing key, -1 no expiry (never written that way)
+		return 0, nil
+	}
+
+	return ttl, nil
+}
+
+// Counts a failed attempt, the counter starts expiring with the first failure of a window
+func (d RedisCacheClient) IncrLoginFailures(ctx context.Context, key string, window time.Duration) (int64, error) {
+	count, err := d.client.Incr(ctx, loginFailuresKeyPrefix+key).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	if count == 1 {
+		_, err = d.client.PExpire(ctx, loginFailuresKeyPrefix+key, window).Result()
+		if err != nil {
+			return count, err
+		}
+	}
+
+	return count, nil
+}
+
+func (d RedisCacheClient) IncrLoginLockoutLevel(ctx context.Context, key string) (int64, error) {
+	pipe := d.client.TxPipeline()
+	level := pipe.Incr(ctx, loginLockoutLevelKeyPrefix+key)
+	pipe.PExpire(ctx, loginLockoutLevelKeyPrefix+key, loginLockoutLevelTtl)
+
+	_, err := pipe.Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return level.Val(), nil
+}
+
+// Locks the key and starts a fresh failure window for when the lock is over
+func (d RedisCacheClient) LockLogin(ctx context.Context, key string, duration time.Duration) error {
+	pipe := d.client.TxPipeline()
+	pipe.Set(ctx, loginLockKeyPrefix+key, "1", duration)
+	pipe.Del(ctx, loginFailuresKeyPrefix+key)
+
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (d RedisCacheClient) ResetLoginFailures(ctx context.Context, keys ...string) error {
+	redisKeys := make([]string, 0, len(keys)*2)
+	for _, key := range keys {
+		redisKeys = append(redisKeys, loginFailuresKeyPrefix+key, loginLockoutLevelKeyPrefix+key)
+	}
+
+	_, err := d.client.Del(ctx, redisKeys...).Result()
+	return err
+}
+
+// Replaces any pending code for the key, attempts start again from zero
+func (d RedisCacheClient) SetOtp(ctx context.Context, key string, otp service.Otp, ttl time.Duration) error {
+	pipe := d.client.TxPipeline()
+	pipe.Del(ctx, otpKeyPrefix+key)
+	pipe.HSet(ctx, otpKeyPrefix+key, "codeHash", otp.CodeHash, "target", otp.Target, "attempts", 0)
+	pipe.PExpire(ctx, otpKeyPrefix+key, ttl)
+
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (d RedisCacheClient) GetOtp(ctx context.Context, key string) (service.Otp, bool, error) {
+	result, err := d.client.HGetAll(ctx, otpKeyPrefix+key).Result()
+	if err != nil {
+		return service.Otp{}, false, err
+	}
+	if len(result) == 0 {
+		return service.Otp{}, false, nil
+	}
+
+	attempts, _ := strconv.ParseInt(result["attempts"], 10, 64)
+	return service.Otp{
+		CodeHash: result["codeHash"],
+		Target:   result["target"],
+		Attempts: attempts,
+	}, true, nil
+}
+
+func (d RedisCacheClient) IncrOtpAttempts(ctx context.Context, key string) (int64, error) {
+	return d.client.HIncrBy(ctx, otpKeyPrefix+key, "attempts", 1).Result()
+}
+
+func (d RedisCacheClient) DeleteOtp(ctx context.Context, key string) error {
+	_, err := d.client.Del(ctx, otpKeyPrefix+key).Result()
+	return err
+}
+
+func (d RedisCacheClient) AcquireOtpCooldown(ctx context.Context, key string, interval time.Duration) (time.Duration, error) {
+	acquired, err := d.client.SetNX(ctx, otpCooldownKeyPrefix+key, "1", interval).Result()
+	if err != nil {
+		return 0, err
+	}
+	if acquired {
+		return 0, nil
+	}
+
+	ttl, err := d.client.PTTL(ctx, otpCooldownKeyPrefix+key).Result()
+	if err != nil {
+		return 0, err
+	}
+	if ttl <= 0 {
+		// expired between the two calls
+		return time.Millisecond, nil
+	}
+
+	return ttl, nil
+}
+
+// Replaces the pending token of the user, the previous one stops working
+func (d RedisCacheClient) SetPasswordResetToken(ctx context.Context, userId, tokenHash string, ttl time.Duration) error {
+	previous, err := d.client.Get(ctx, passwordResetUserKeyPrefix+userId).Result()
+	if err != nil && err != redis.Nil {
+		return err
+	}
+
+	pipe := d.client.TxPipeline()
+	if previous != "" {
+		pipe.Del(ctx, passwordResetKeyPrefix+previous)
+	}
+	pipe.Set(ctx, passwordResetKeyPrefix+tokenHash, userId, ttl)
+	pipe.Set(ctx, passwordResetUserKeyPrefix+userId, tokenHash, ttl)
+
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+func (d RedisCacheClient) ConsumePasswordResetToken(ctx context.Context, tokenHash string) (string, bool, error) {
+	userId, err := d.client.GetDel(ctx, passwordResetKeyPrefix+tokenHash).Result()
+	if err == redis.Nil {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+
+	// The token itself is already gone, a leftover index expires on its own
+	d.client.Del(ctx, passwordResetUserKeyPrefix+userId)
+
+	return userId, true, nil
+}
+
+func (d RedisCacheClient) DeletePasswordResetToken(ctx context.Context, userId string) error {
+	tokenHash, err := d.client.GetDel(ctx, passwordResetUserKeyPrefix+userId).Result()
+	if err == redis.Nil {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = d.client.Del(ctx, passwordResetKeyPrefix+tokenHash).Result()
+	return err
+}
+
+func (d RedisCacheClient) Ping(ctx context.Context) error {
+	return d.client.Ping(ctx).Err()
+}
+
+func (d RedisCacheClient) Close() error {
+	return d.client.Close()
+}
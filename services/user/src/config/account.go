@@ -0,0 +1,39 @@
+package config
+
+import (
+	"strings"
+	"time"
+)
+
+// gRPC addresses notified when an account is deleted, comma separated.
+// Defaults to the product service, which unlists the products of the user
+func GetUserEventSubscribers() []string {
+	var subscribers []string
+	for _, address := range strings.Split(getEnv("USER_EVENT_SUBSCRIBERS", GetProductServiceBaseURL()), ",") {
+		if address = strings.TrimSpace(address); address != "" {
+			subscribers = append(subscribers, address)
+		}
+	}
+
+	return subscribers
+}
+
+// Deadline of a single event delivery, a claimed outbox row is not picked up by another instance before twice this
+func GetUserEventTimeout() time.Duration {
+	return getEnvDuration("USER_EVENT_TIMEOUT", 5*time.Second)
+}
+
+// How often the outbox is polled for events to deliver or retry. A deleted account
+// wakes the dispatcher right away, so this only bounds how late a retry can be
+func GetUserEventPollInterval() time.Duration {
+	return getEnvDuration("USER_EVENT_POLL_INTERVAL", 10*time.Second)
+}
+
+// First retry of a failed delivery waits USER_EVENT_RETRY_BASE, every following one doubles it up to USER_EVENT_RETRY_MAX
+func GetUserEventRetryBase() time.Duration {
+	return getEnvDuration("USER_EVENT_RETRY_BASE", 10*time.Second)
+}
+
+func GetUserEventRetryMax() time.Duration {
+	return getEnvDuration("USER_EVENT_RETRY_MAX", time.Hour)
+}
@@ -81,6 +81,16 @@ func GetDbMaxConnPercentage() float64 {
 	return percentage
 }
 
+// Product gRPC address, used for the listing count of seller profiles and the data export
+func GetProductServiceBaseURL() string {
+	return getEnv("PRODUCT_SERVICE_BASE_URL", "localhost:5001")
+}
+
+// Purchase gRPC address, used for the data export
+func GetPurchaseServiceBaseURL() string {
+	return getEnv("PURCHASE_SERVICE_BASE_URL", "localhost:5002")
+}
+
 func getFileServiceBaseURL() string {
 	return getEnv("FILE_SERVICE_BASE_URL", "")
 }
@@ -0,0 +1,23 @@
+package config
+
+import "strings"
+
+// Key encryption keys for sensitive columns, `kid=base64 of 32 random bytes` separated by comma.
+// Keep a retired key listed until reencrypt-bank has moved every row to the active one.
+func GetFieldEncryptionKeys() map[string]string {
+	keys := make(map[string]string)
+	for _, entry := range strings.Split(getEnv("FIELD_ENCRYPTION_KEYS", ""), ",") {
+		kid, key, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || kid == "" || key == "" {
+			continue
+		}
+		keys[kid] = key
+	}
+
+	return keys
+}
+
+// Kid of the key used to encrypt new values, must be one of FIELD_ENCRYPTION_KEYS
+func GetFieldEncryptionActiveKid() string {
+	return getEnv("FIELD_ENCRYPTION_ACTIVE_KID", "")
+}
@@ -1,5 +1,46 @@
 package config
 
-func GetSecretKey() string {
-	return getEnv("JWT_SECRET_KEY", "default")
+import (
+	"strings"
+	"time"
+)
+
+// Signing keys as a comma separated list of `kid=path/to/private.pem`.
+// Old keys stay in the list after rotation so tokens they signed still verify.
+func GetSigningKeys() map[string]string {
+	keys := make(map[string]string)
+	for _, entry := range strings.Split(getEnv("JWT_SIGNING_KEYS", ""), ",") {
+		kid, path, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || kid == "" || path == "" {
+			continue
+		}
+		keys[kid] = path
+	}
+
+	return keys
+}
+
+// Kid of the key used to sign new tokens, must be one of JWT_SIGNING_KEYS
+func GetActiveSigningKid() string {
+	return getEnv("JWT_ACTIVE_KID", "")
+}
+
+// Lifetime of an access token, default 15 minutes
+func GetAccessTokenTtl() time.Duration {
+	ttl, err := time.ParseDuration(getEnv("JWT_ACCESS_TTL", "15m"))
+	if err != nil || ttl <= 0 {
+		return 15 * time.Minute
+	}
+
+	return ttl
+}
+
+// Lifetime of a refresh token, default 30 days
+func GetRefreshTokenTtl() time.Duration {
+	ttl, err := time.ParseDuration(getEnv("JWT_REFRESH_TTL", "720h"))
+	if err != nil || ttl <= 0 {
+		return 720 * time.Hour
+	}
+
+	return ttl
 }
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"strconv"
+	"time"
+)
+
+// Failed logins allowed for one email/phone inside the attempt window before it is locked
+func GetLoginMaxAttemptsPerIdentifier() int64 {
+	return getEnvInt64("LOGIN_MAX_ATTEMPTS_PER_IDENTIFIER", 5)
+}
+
+// Failed logins allowed from one IP inside the attempt window, higher because of NAT
+func GetLoginMaxAttemptsPerIp() int64 {
+	return getEnvInt64("LOGIN_MAX_ATTEMPTS_PER_IP", 20)
+}
+
+func GetLoginAttemptWindow() time.Duration {
+	return getEnvDuration("LOGIN_ATTEMPT_WINDOW", 15*time.Minute)
+}
+
+// First lockout lasts LOGIN_LOCKOUT_BASE, every following lockout doubles it up to LOGIN_LOCKOUT_MAX
+func GetLoginLockoutBase() time.Duration {
+	return getEnvDuration("LOGIN_LOCKOUT_BASE", time.Minute)
+}
+
+func GetLoginLockoutMax() time.Duration {
+	return getEnvDuration("LOGIN_LOCKOUT_MAX", time.Hour)
+}
+
+func getEnvInt64(key string, defaultValue int64) int64 {
+	value, err := strconv.ParseInt(getEnv(key, ""), 10, 64)
+	if err != nil || value <= 0 {
+		return defaultValue
+	}
+
+	return value
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value, err := time.ParseDuration(getEnv(key, ""))
+	if err != nil || value <= 0 {
+		return defaultValue
+	}
+
+	return value
+}
@@ -0,0 +1,33 @@
+package config
+
+import "time"
+
+const (
+	SENDER_DRIVER_CONSOLE string = "console"
+	SENDER_DRIVER_FILE           = "file"
+)
+
+// How long an issued verification code stays valid
+func GetOtpTtl() time.Duration {
+	return getEnvDuration("OTP_TTL", 10*time.Minute)
+}
+
+// Wrong codes accepted before the pending code is thrown away
+func GetOtpMaxAttempts() int64 {
+	return getEnvInt64("OTP_MAX_ATTEMPTS", 5)
+}
+
+// Minimum time between two codes sent for the same purpose
+func GetOtpResendInterval() time.Duration {
+	return getEnvDuration("OTP_RESEND_INTERVAL", time.Minute)
+}
+
+// Where outgoing codes are delivered, console or file. Both only run in MODE=DEBUG,
+// console is the default there and anywhere else the driver has to be set
+func GetSenderDriver() string {
+	return getEnv("SENDER_DRIVER", "")
+}
+
+func GetSenderFilePath() string {
+	return getEnv("SENDER_FILE_PATH", "./tmp/outbox.log")
+}
@@ -0,0 +1,8 @@
+package config
+
+import "time"
+
+// How long a forgot-password token can be used
+func GetPasswordResetTtl() time.Duration {
+	return getEnvDuration("PASSWORD_RESET_TTL", 30*time.Minute)
+}
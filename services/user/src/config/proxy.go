@@ -0,0 +1,24 @@
+package config
+
+import "strings"
+
+// Addresses or CIDRs of the load balancers in front of the service, comma separated.
+// Only requests from them have PROXY_HEADER read as the client ip, others use the remote address
+func GetTrustedProxies() []string {
+	var proxies []string
+	for _, proxy := range strings.Split(getEnv("TRUSTED_PROXIES", ""), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
+}
+
+// Header the trusted proxies put the client ip in, the first valid ip of it is used.
+// Empty when no proxy is trusted so a client can't choose its own ip
+func GetProxyHeader() string {
+	if len(GetTrustedProxies()) == 0 {
+		return ""
+	}
+	return getEnv("PROXY_HEADER", "X-Forwarded-For")
+}
@@ -0,0 +1,9 @@
+package config
+
+import "time"
+
+// Upper bound of the whole shutdown after SIGTERM, keep it below the stop timeout of the
+// container (30s on ECS) so the pools are closed before the task is killed
+func GetShutdownTimeout() time.Duration {
+	return getEnvDuration("SHUTDOWN_TIMEOUT", 20*time.Second)
+}
@@ -0,0 +1,23 @@
+package config
+
+import (
+	"strconv"
+
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/tracing"
+)
+
+// OTEL_EXPORTER: "otlp" sends to OTEL_EXPORTER_OTLP_ENDPOINT, "stdout" prints the spans for
+// local runs, "none" only forwards the trace context to the product and file services
+func GetTracing() tracing.Config {
+	ratio, err := strconv.ParseFloat(getEnv("OTEL_TRACES_SAMPLE_RATIO", "1"), 64)
+	if err != nil {
+		ratio = 1
+	}
+
+	return tracing.Config{
+		ServiceName: getEnv("OTEL_SERVICE_NAME", "user"),
+		Exporter:    getEnv("OTEL_EXPORTER", tracing.ExporterNone),
+		Endpoint:    getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", ""),
+		SampleRatio: ratio,
+	}
+}
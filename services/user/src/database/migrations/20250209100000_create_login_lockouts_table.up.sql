@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS login_lockouts (
+    id VARCHAR(255) PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+    -- identifier: email/phone being guessed, ip: client sending the attempts
+    scope VARCHAR(16) NOT NULL CHECK (scope IN ('identifier', 'ip')),
+    identifier VARCHAR(255),
+    ip_address VARCHAR(64) NOT NULL,
+    failed_attempts INT NOT NULL,
+    lockout_level INT NOT NULL,
+    locked_until TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS login_lockouts_identifier_idx ON login_lockouts(identifier);
+CREATE INDEX IF NOT EXISTS login_lockouts_ip_address_idx ON login_lockouts(ip_address);
@@ -0,0 +1,3 @@
+ALTER TABLE users
+    DROP COLUMN IF EXISTS email_verified,
+    DROP COLUMN IF EXISTS phone_verified;
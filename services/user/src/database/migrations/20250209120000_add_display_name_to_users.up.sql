@@ -0,0 +1,2 @@
+-- Shown on the public seller profile instead of contact details
+ALTER TABLE users ADD COLUMN IF NOT EXISTS display_name VARCHAR(64);
@@ -0,0 +1,6 @@
+-- NOT VALID: rows that are already encrypted would fail the check
+ALTER TABLE users
+    ALTER COLUMN bankAccountHolder TYPE VARCHAR(255),
+    ALTER COLUMN bankAccountNumber TYPE VARCHAR(255),
+    ADD CONSTRAINT users_bankaccountholder_check CHECK (length(bankAccountHolder) >= 4 AND length(bankAccountHolder) <= 32) NOT VALID,
+    ADD CONSTRAINT users_bankaccountnumber_check CHECK (length(bankAccountNumber) >= 4 AND length(bankAccountNumber) <= 32) NOT VALID;
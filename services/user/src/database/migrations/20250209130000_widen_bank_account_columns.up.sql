@@ -0,0 +1,7 @@
+-- Holder and number are stored encrypted, the ciphertext is longer than the 32 characters
+-- the old checks allowed. Lengths are still validated on the plaintext by the API.
+ALTER TABLE users
+    DROP CONSTRAINT IF EXISTS users_bankaccountholder_check,
+    DROP CONSTRAINT IF EXISTS users_bankaccountnumber_check,
+    ALTER COLUMN bankAccountHolder TYPE TEXT,
+    ALTER COLUMN bankAccountNumber TYPE TEXT;
@@ -0,0 +1,14 @@
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS bankAccountName VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS bankAccountHolder TEXT,
+    ADD COLUMN IF NOT EXISTS bankAccountNumber TEXT;
+
+UPDATE users u
+SET
+    bankAccountName = b.bank_account_name,
+    bankAccountHolder = b.bank_account_holder,
+    bankAccountNumber = b.bank_account_number
+FROM bank_accounts b
+WHERE b.user_id = u.id AND b.is_default;
+
+DROP TABLE IF EXISTS bank_accounts;
@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS bank_accounts (
+    id VARCHAR(255) PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+    user_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    bank_account_name VARCHAR(32) NOT NULL,
+    -- holder and number are encrypted by the service, see FIELD_ENCRYPTION_KEYS
+    bank_account_holder TEXT NOT NULL,
+    bank_account_number TEXT NOT NULL,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS bank_accounts_user_id_idx ON bank_accounts(user_id);
+-- At most one default account per user
+CREATE UNIQUE INDEX IF NOT EXISTS bank_accounts_user_id_default_idx ON bank_accounts(user_id) WHERE is_default;
+
+-- The single account kept on the profile so far becomes the default one
+INSERT INTO bank_accounts (user_id, bank_account_name, bank_account_holder, bank_account_number, is_default)
+SELECT id, bankAccountName, bankAccountHolder, bankAccountNumber, TRUE
+FROM users
+WHERE bankAccountName IS NOT NULL AND bankAccountHolder IS NOT NULL AND bankAccountNumber IS NOT NULL;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS bankAccountName,
+    DROP COLUMN IF EXISTS bankAccountHolder,
+    DROP COLUMN IF EXISTS bankAccountNumber;
@@ -0,0 +1,10 @@
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS roles TEXT[] NOT NULL DEFAULT ARRAY['buyer']::TEXT[];
+
+ALTER TABLE users
+    ADD CONSTRAINT users_roles_check CHECK (roles <@ ARRAY['buyer', 'seller', 'admin']::TEXT[]);
+
+-- Anyone could sell so far, users with a bank account to receive payments keep doing so
+UPDATE users
+SET roles = ARRAY['buyer', 'seller']::TEXT[]
+WHERE EXISTS (SELECT 1 FROM bank_accounts b WHERE b.user_id = users.id);
@@ -0,0 +1,2 @@
+-- Deleted accounts are anonymized in place, the id stays so purchases and products keep a valid reference
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
@@ -0,0 +1,14 @@
+-- One row per subscriber of a UserDeleted event, written in the transaction that anonymizes the user
+-- and deleted once the subscriber acked it. Rows past next_attempt_at are picked up by the dispatcher
+CREATE TABLE IF NOT EXISTS user_event_outbox (
+    id VARCHAR(255) PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+    subscriber VARCHAR(255) NOT NULL,
+    user_id VARCHAR(255) NOT NULL,
+    deleted_at TIMESTAMP NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_error TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS user_event_outbox_next_attempt_at_idx ON user_event_outbox(next_attempt_at);
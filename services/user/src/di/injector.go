@@ -1,14 +1,40 @@
 package di
 
 import (
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/health"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/logging"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/metrics"
 	authJwt "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/auth/jwt"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/cache"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/database/postgre"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/encryption"
 	protoUserController "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/grpc/controllers/user/proto"
+	healthCheck "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/health"
+	accountController "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/http/controllers/account"
+	adminController "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/http/controllers/admin"
+	authController "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/http/controllers/auth"
+	bankAccountController "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/http/controllers/bankAccount"
+	passwordController "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/http/controllers/password"
 	userController "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/http/controllers/user"
-	loggerZap "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/logger/zap"
+	serviceLogger "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/logger"
+	serviceMetrics "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/metrics"
+	bankAccountRepository "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/repositories/bankAccount"
+	loginLockoutRepository "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/repositories/loginLockout"
+	refreshTokenRepository "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/repositories/refreshToken"
 	userRepository "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/repositories/user"
+	userEventOutboxRepository "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/repositories/userEventOutbox"
+	accountService "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/account"
+	authService "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/auth"
+	bankAccountService "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/bankAccount"
 	fileService "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/external/file"
+	productService "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/external/product"
+	purchaseService "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/external/purchase"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/external/sender"
+	userEventsService "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/external/userEvents"
+	loginAttemptService "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/loginAttempt"
+	otpService "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/otp"
+	passwordService "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/password"
+	roleService "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/role"
 	userService "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/user"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/samber/do/v2"
@@ -23,13 +49,20 @@ func init() {
 	do.Provide[*pgxpool.Pool](Injector, postgre.NewPgxConnectInject)
 
 	//? Logger
-	//? Zap
-	do.Provide[loggerZap.LoggerInterface](Injector, loggerZap.NewLogHandlerInject)
+	//? Structured JSON with request, trace and user ids
+	do.Provide[*logging.Logger](Injector, serviceLogger.NewLoggerInject)
+
+	//? Metrics
+	//? RED, cache and Postgres pool
+	do.Provide[*metrics.Metrics](Injector, serviceMetrics.NewMetricsInject)
 
 	//? Setup Auth
 	//? JWT Service
 	do.Provide[authJwt.JwtServiceInterface](Injector, authJwt.NewJwtServiceInject)
 
+	//? Field Encryption
+	do.Provide[encryption.FieldCipherInterface](Injector, encryption.NewFieldCipherInject)
+
 	//? Setup Redis Client
 	//? Redis Client
 	do.Provide[cache.RedisCacheClient](Injector, cache.NewRedisCacheClientInject)
@@ -38,14 +71,65 @@ func init() {
 	//? User Repository
 	do.Provide[userRepository.UserRepositoryInterface](Injector, userRepository.NewUserRepositoryInject)
 
+	//? Refresh Token Repository
+	do.Provide[refreshTokenRepository.RefreshTokenRepositoryInterface](Injector, refreshTokenRepository.NewRefreshTokenRepositoryInject)
+
+	//? Login Lockout Repository
+	do.Provide[loginLockoutRepository.LoginLockoutRepositoryInterface](Injector, loginLockoutRepository.NewLoginLockoutRepositoryInject)
+
+	//? Bank Account Repository
+	do.Provide[bankAccountRepository.BankAccountRepositoryInterface](Injector, bankAccountRepository.NewBankAccountRepositoryInject)
+
+	//? User Event Outbox Repository
+	do.Provide[userEventOutboxRepository.UserEventOutboxRepositoryInterface](Injector, userEventOutboxRepository.NewUserEventOutboxRepositoryInject)
+
 	//? Setup Services
+	//? Sender
+	do.Provide[sender.SenderInterface](Injector, sender.NewSenderInject)
+
+	//? OTP Service
+	do.Provide[otpService.OtpServiceInterface](Injector, otpService.NewOtpServiceInject)
+
+	//? Login Attempt Service
+	do.Provide[loginAttemptService.LoginAttemptServiceInterface](Injector, loginAttemptService.NewLoginAttemptServiceInject)
+
+	//? Auth Service
+	do.Provide[authService.AuthServiceInterface](Injector, authService.NewAuthServiceInject)
+
 	//? User Service
 	do.Provide[userService.UserServiceInterface](Injector, userService.NewUserServiceInject)
 
+	//? Password Service
+	do.Provide[passwordService.PasswordServiceInterface](Injector, passwordService.NewPasswordServiceInject)
+
+	//? Role Service
+	do.Provide[roleService.RoleServiceInterface](Injector, roleService.NewRoleServiceInject)
+
+	//? Bank Account Service
+	do.Provide[bankAccountService.BankAccountServiceInterface](Injector, bankAccountService.NewBankAccountServiceInject)
+
+	//? Account Service
+	do.Provide[accountService.AccountServiceInterface](Injector, accountService.NewAccountServiceInject)
+
 	//? Setup Controller/Handler
 	//? User Controller
 	do.Provide[userController.UserControllerInterface](Injector, userController.NewUserControllerInject)
 
+	//? Auth Controller
+	do.Provide[authController.AuthControllerInterface](Injector, authController.NewAuthControllerInject)
+
+	//? Password Controller
+	do.Provide[passwordController.PasswordControllerInterface](Injector, passwordController.NewPasswordControllerInject)
+
+	//? Bank Account Controller
+	do.Provide[bankAccountController.BankAccountControllerInterface](Injector, bankAccountController.NewBankAccountControllerInject)
+
+	//? Account Controller
+	do.Provide[accountController.AccountControllerInterface](Injector, accountController.NewAccountControllerInject)
+
+	//? Admin Controller
+	do.Provide[adminController.AdminControllerInterface](Injector, adminController.NewAdminControllerInject)
+
 	//? Proto User Controller
 	do.Provide[*protoUserController.ProtoUserController](Injector, protoUserController.NewProtoUserControllerInject)
 
@@ -53,4 +137,16 @@ func init() {
 	//? File Service
 	do.Provide[fileService.FileServiceInterface](Injector, fileService.NewFileServiceInject)
 
+	//? Product Service
+	do.Provide[productService.ProductServiceInterface](Injector, productService.NewProductServiceInject)
+
+	//? Purchase Service
+	do.Provide[purchaseService.PurchaseServiceInterface](Injector, purchaseService.NewPurchaseServiceInject)
+
+	//? User Events Outbox Dispatcher
+	do.Provide[userEventsService.UserEventsServiceInterface](Injector, userEventsService.NewUserEventsServiceInject)
+
+	//? Readiness checks for /readyz and grpc.health.v1
+	do.Provide[*health.Checker](Injector, healthCheck.NewCheckerInject)
+
 }
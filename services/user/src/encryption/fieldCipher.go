@@ -0,0 +1,212 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/config"
+	"github.com/samber/do/v2"
+)
+
+// Sealed values look like enc:v2:<kid>:<wrapped data key>:<ciphertext>, the ciphertext is bound to
+// the owner, row and column it was written for. Anything without the prefix is a legacy plaintext value
+const sealedPrefix = "enc:v2:"
+
+// Sealed columns, see Binding
+const (
+	BankAccountHolderColumn = "bank_account_holder"
+	BankAccountNumberColumn = "bank_account_number"
+)
+
+// Additional data a value is sealed with. A value copied to another user, bank account or column fails to open
+func Binding(userId, bankAccountId, column string) string {
+	return userId + "/" + bankAccountId + "/" + column
+}
+
+// Envelope encryption of single column values. Every value gets its own
+// AES-256-GCM data key, which is stored next to it wrapped by a key from config.
+type FieldCipherInterface interface {
+	// binding has to be the same for Encrypt and Decrypt of a value, see Binding
+	Encrypt(plaintext, binding string) (string, error)
+	Decrypt(value, binding string) (string, error)
+	// True for plaintext values and values sealed with a key other than the active one
+	NeedsRotation(value string) bool
+}
+
+type fieldCipher struct {
+	activeKid string
+	keys      map[string]cipher.AEAD
+}
+
+func NewFieldCipher(keys map[string][]byte, activeKid string) (FieldCipherInterface, error) {
+	fc := &fieldCipher{
+		activeKid: activeKid,
+		keys:      make(map[string]cipher.AEAD),
+	}
+
+	for kid, key := range keys {
+		if strings.Contains(kid, ":") {
+			return nil, fmt.Errorf("field encryption kid %q must not contain ':'", kid)
+		}
+
+		aead, err := newAead(key)
+		if err != nil {
+			return nil, fmt.Errorf("field encryption key %s: %w", kid, err)
+		}
+		fc.keys[kid] = aead
+	}
+
+	if _, ok := fc.keys[activeKid]; !ok {
+		return nil, fmt.Errorf("FIELD_ENCRYPTION_ACTIVE_KID %q is not one of FIELD_ENCRYPTION_KEYS", activeKid)
+	}
+
+	return fc, nil
+}
+
+func NewFieldCipherInject(i do.Injector) (FieldCipherInterface, error) {
+	encoded := config.GetFieldEncryptionKeys()
+	if len(encoded) == 0 {
+		if strings.ToUpper(config.MODE) != config.MODE_DEBUG {
+			return nil, errors.New("FIELD_ENCRYPTION_KEYS value requires to be set")
+		}
+
+		// Local runs only, a fixed key keeps the data readable between restarts
+		log.Println("FIELD_ENCRYPTION_KEYS is empty, using the insecure development key")
+		devKey := sha256.Sum256([]byte("tutuplapak-dev-field-encryption"))
+		return NewFieldCipher(map[string][]byte{"dev": devKey[:]}, "dev")
+	}
+
+	keys := make(map[string][]byte, len(encoded))
+	for kid, value := range encoded {
+		key, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, fmt.Errorf("field encryption key %s is not base64: %w", kid, err)
+		}
+		keys[kid] = key
+	}
+
+	return NewFieldCipher(keys, config.GetFieldEncryptionActiveKid())
+}
+
+func (fc *fieldCipher) Encrypt(plaintext, binding string) (string, error) {
+	// Empty means "not set", there is nothing to hide
+	if plaintext == "" {
+		return "", nil
+	}
+	if binding == "" {
+		return "", errors.New("field encryption needs a binding")
+	}
+
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", err
+	}
+
+	dataAead, err := newAead(dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	sealedValue, err := seal(dataAead, []byte(plaintext), []byte(binding))
+	if err != nil {
+		return "", err
+	}
+
+	wrappedKey, err := seal(fc.keys[fc.activeKid], dataKey, nil)
+	if err != nil {
+		return "", err
+	}
+
+	return sealedPrefix + fc.activeKid + ":" +
+		base64.RawStdEncoding.EncodeToString(wrappedKey) + ":" +
+		base64.RawStdEncoding.EncodeToString(sealedValue), nil
+}
+
+func (fc *fieldCipher) Decrypt(value, binding string) (string, error) {
+	if !strings.HasPrefix(value, sealedPrefix) {
+		return value, nil
+	}
+
+	parts := strings.Split(strings.TrimPrefix(value, sealedPrefix), ":")
+	if len(parts) != 3 {
+		return "", errors.New("malformed encrypted value")
+	}
+
+	keyAead, ok := fc.keys[parts[0]]
+	if !ok {
+		return "", fmt.Errorf("unknown field encryption key %q", parts[0])
+	}
+
+	wrappedKey, err := base64.RawStdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", err
+	}
+	sealedValue, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", err
+	}
+
+	dataKey, err := open(keyAead, wrappedKey, nil)
+	if err != nil {
+		return "", err
+	}
+
+	dataAead, err := newAead(dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	plaintext, err := open(dataAead, sealedValue, []byte(binding))
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
+
+func (fc *fieldCipher) NeedsRotation(value string) bool {
+	if value == "" {
+		return false
+	}
+
+	return !strings.HasPrefix(value, sealedPrefix+fc.activeKid+":")
+}
+
+func newAead(key []byte) (cipher.AEAD, error) {
+	if len(key) != 32 {
+		return nil, errors.New("key must be 32 bytes")
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// nonce || ciphertext
+func seal(aead cipher.AEAD, plaintext, additionalData []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func open(aead cipher.AEAD, sealed, additionalData []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("malformed encrypted value")
+	}
+
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, additionalData)
+}
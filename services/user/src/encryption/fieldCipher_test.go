@@ -0,0 +1,195 @@
+package encryption
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func testKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, 32)
+}
+
+func newTestCipher(t *testing.T, keys map[string][]byte, activeKid string) FieldCipherInterface {
+	t.Helper()
+	fc, err := NewFieldCipher(keys, activeKid)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return fc
+}
+
+func TestFieldCipherRoundTrip(t *testing.T) {
+	fc := newTestCipher(t, map[string][]byte{"k1": testKey(1)}, "k1")
+	binding := Binding("user-1", "account-1", BankAccountNumberColumn)
+
+	sealed, err := fc.Encrypt("1234567890", binding)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(sealed, sealedPrefix+"k1:") || strings.Contains(sealed, "1234567890") {
+		t.Fatalf("sealed = %q, want an enc:v2 value of k1 without the plaintext", sealed)
+	}
+
+	again, err := fc.Encrypt("1234567890", binding)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again == sealed {
+		t.Fatal("sealing the same value twice gave the same ciphertext")
+	}
+
+	plaintext, err := fc.Decrypt(sealed, binding)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plaintext != "1234567890" {
+		t.Fatalf("Decrypt = %q, want 1234567890", plaintext)
+	}
+	if fc.NeedsRotation(sealed) {
+		t.Fatal("a value sealed with the active key needs no rotation")
+	}
+}
+
+func TestFieldCipherEmptyValue(t *testing.T) {
+	fc := newTestCipher(t, map[string][]byte{"k1": testKey(1)}, "k1")
+
+	sealed, err := fc.Encrypt("", Binding("user-1", "account-1", BankAccountHolderColumn))
+	if err != nil || sealed != "" {
+		t.Fatalf("Encrypt(\"\") = %q, %v, want an empty value", sealed, err)
+	}
+	if fc.NeedsRotation("") {
+		t.Fatal("an empty value needs no rotation")
+	}
+}
+
+func TestFieldCipherRequiresBinding(t *testing.T) {
+	fc := newTestCipher(t, map[string][]byte{"k1": testKey(1)}, "k1")
+
+	if _, err := fc.Encrypt("1234567890", ""); err == nil {
+		t.Fatal("expected an error without a binding")
+	}
+}
+
+func TestFieldCipherRejectsOtherBinding(t *testing.T) {
+	fc := newTestCipher(t, map[string][]byte{"k1": testKey(1)}, "k1")
+
+	sealed, err := fc.Encrypt("1234567890", Binding("user-1", "account-1", BankAccountNumberColumn))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, binding := range []string{
+		Binding("user-2", "account-1", BankAccountNumberColumn),
+		Binding("user-1", "account-2", BankAccountNumberColumn),
+		Binding("user-1", "account-1", BankAccountHolderColumn),
+		"",
+	} {
+		if _, err := fc.Decrypt(sealed, binding); err == nil {
+			t.Fatalf("binding %q: expected the value to be rejected", binding)
+		}
+	}
+}
+
+func TestFieldCipherRotation(t *testing.T) {
+	old := newTestCipher(t, map[string][]byte{"k1": testKey(1)}, "k1")
+	binding := Binding("user-1", "account-1", BankAccountHolderColumn)
+	sealed, err := old.Encrypt("Budi", binding)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rotated := newTestCipher(t, map[string][]byte{"k1": testKey(1), "k2": testKey(2)}, "k2")
+	if !rotated.NeedsRotation(sealed) {
+		t.Fatal("a value sealed with a retired key needs rotation")
+	}
+
+	// Retired keys still open their values until reencrypt-bank moved them
+	plaintext, err := rotated.Decrypt(sealed, binding)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plaintext != "Budi" {
+		t.Fatalf("Decrypt = %q, want Budi", plaintext)
+	}
+
+	resealed, err := rotated.Encrypt(plaintext, binding)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rotated.NeedsRotation(resealed) {
+		t.Fatal("a value sealed with the active key needs no rotation")
+	}
+
+	withoutOldKey := newTestCipher(t, map[string][]byte{"k2": testKey(2)}, "k2")
+	if _, err := withoutOldKey.Decrypt(sealed, binding); err == nil {
+		t.Fatal("expected an error for a value of an unknown key")
+	}
+}
+
+func TestFieldCipherLegacyValues(t *testing.T) {
+	fc := newTestCipher(t, map[string][]byte{"k1": testKey(1)}, "k1")
+	binding := Binding("user-1", "account-1", BankAccountHolderColumn)
+
+	// Written before field encryption was enabled
+	plaintext, err := fc.Decrypt("Budi", binding)
+	if err != nil || plaintext != "Budi" {
+		t.Fatalf("Decrypt(plaintext) = %q, %v, want Budi", plaintext, err)
+	}
+	if !fc.NeedsRotation("Budi") {
+		t.Fatal("a plaintext value needs rotation")
+	}
+}
+
+func TestFieldCipherRejectsTamperedValues(t *testing.T) {
+	fc := newTestCipher(t, map[string][]byte{"k1": testKey(1)}, "k1")
+	binding := Binding("user-1", "account-1", BankAccountNumberColumn)
+	sealed, err := fc.Encrypt("1234567890", binding)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(strings.TrimPrefix(sealed, sealedPrefix), ":")
+
+	flipLastByte := func(encoded string) string {
+		raw, err := base64.RawStdEncoding.DecodeString(encoded)
+		if err != nil {
+			t.Fatal(err)
+		}
+		raw[len(raw)-1] ^= 1
+		return base64.RawStdEncoding.EncodeToString(raw)
+	}
+
+	cases := map[string]string{
+		"ciphertext":         sealedPrefix + parts[0] + ":" + parts[1] + ":" + flipLastByte(parts[2]),
+		"wrapped key":        sealedPrefix + parts[0] + ":" + flipLastByte(parts[1]) + ":" + parts[2],
+		"swapped parts":      sealedPrefix + parts[0] + ":" + parts[2] + ":" + parts[1],
+		"missing part":       sealedPrefix + parts[0] + ":" + parts[1],
+		"extra part":         sealed + ":x",
+		"not base64":         sealedPrefix + parts[0] + ":" + parts[1] + ":!!!",
+		"shorter than nonce": sealedPrefix + parts[0] + ":" + parts[1] + ":AAAA",
+		"unknown kid":        sealedPrefix + "k9:" + parts[1] + ":" + parts[2],
+	}
+	for name, value := range cases {
+		if _, err := fc.Decrypt(value, binding); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestNewFieldCipherValidatesKeys(t *testing.T) {
+	cases := map[string]struct {
+		keys      map[string][]byte
+		activeKid string
+	}{
+		"short key":         {map[string][]byte{"k1": []byte("short")}, "k1"},
+		"kid with colon":    {map[string][]byte{"k:1": testKey(1)}, "k:1"},
+		"unknown activeKid": {map[string][]byte{"k1": testKey(1)}, "k2"},
+	}
+	for name, c := range cases {
+		if _, err := NewFieldCipher(c.keys, c.activeKid); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
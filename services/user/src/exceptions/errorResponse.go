@@ -1,8 +1,15 @@
 package exceptions
 
+import (
+	"math"
+	"time"
+)
+
 type ErrorResponse struct {
 	StatusCode int16
 	Message    string
+	// Seconds until the client may retry, sent as the Retry-After header on 429
+	RetryAfter int `json:"-"`
 }
 
 func NewErrorResponse(statusCode int16, message string) ErrorResponse {
@@ -32,3 +39,11 @@ func ErrConflict(message string) error {
 func ErrServer(message string) error {
 	return NewErrorResponse(500, message)
 }
+
+func ErrTooManyRequests(message string, retryAfter time.Duration) error {
+	return ErrorResponse{
+		StatusCode: 429,
+		Message:    message,
+		RetryAfter: int(math.Ceil(retryAfter.Seconds())),
+	}
+}
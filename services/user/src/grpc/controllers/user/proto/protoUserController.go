@@ -3,19 +3,26 @@ package protoUserController
 import (
 	"context"
 	"fmt"
+	"time"
 
-	functionCallerInfo "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/logger/helper"
-	loggerZap "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/logger/zap"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/logging"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/exceptions"
+
+	bankAccountService "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/bankAccount"
 	fileService "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/external/file"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/proto/user"
 	userService "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/user"
+	"github.com/gofiber/fiber/v2"
 	"github.com/samber/do/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type ProtoUserController struct {
-	userService userService.UserServiceInterface
-	fileService fileService.FileServiceInterface
-	logger      loggerZap.LoggerInterface
+	userService        userService.UserServiceInterface
+	bankAccountService bankAccountService.BankAccountServiceInterface
+	fileService        fileService.FileServiceInterface
+	logger             *logging.Logger
 
 	// Embed UnimplementedUserServiceServer to satisfy gRPC interface
 	user.UnimplementedUserServiceServer
@@ -23,15 +30,17 @@ type ProtoUserController struct {
 
 func NewProtoUserControllerInject(i do.Injector) (*ProtoUserController, error) {
 	_userService := do.MustInvoke[userService.UserServiceInterface](i)
+	_bankAccountService := do.MustInvoke[bankAccountService.BankAccountServiceInterface](i)
 	_fileService := do.MustInvoke[fileService.FileServiceInterface](i)
-	_logger := do.MustInvoke[loggerZap.LoggerInterface](i)
+	_logger := do.MustInvoke[*logging.Logger](i)
 	// kalau pakai interface, dibawah ini
 	// return NewUserController(_userService, _fileService, _logger), nil
 
 	return &ProtoUserController{
-		userService: _userService,
-		fileService: _fileService,
-		logger:      _logger,
+		userService:        _userService,
+		bankAccountService: _bankAccountService,
+		fileService:        _fileService,
+		logger:             _logger,
 	}, nil
 }
 
@@ -45,7 +54,7 @@ func (puc ProtoUserController) GetUserDetails(ctx context.Context, request *user
 	response, err := puc.userService.GetUserProfiles(ctx, request.UserIds)
 	if err != nil {
 		fmt.Sprintf("GetUserDetails Error %s", err.Error())
-		puc.logger.Error(err.Error(), functionCallerInfo.UserControllerGetUserProfile)
+		puc.logger.Error(ctx, "userController.GetUserProfile failed", "error", err)
 		return nil, err
 	}
 
@@ -73,7 +82,7 @@ func (puc ProtoUserController) GetUserDetailsWithId(ctx context.Context, request
 	response, err := puc.userService.GetUserProfilesWithId(ctx, request.UserIds)
 	if err != nil {
 		fmt.Sprintf("GetUserDetailsWithId Error %s", err.Error())
-		puc.logger.Error(err.Error(), functionCallerInfo.UserControllerGetUserProfile)
+		puc.logger.Error(ctx, "userController.GetUserProfile failed", "error", err)
 		return nil, err
 	}
 
@@ -83,6 +92,7 @@ func (puc ProtoUserController) GetUserDetailsWithId(ctx context.Context, request
 			UserId:            profile.UserId,
 			Email:             profile.Email,
 			Phone:             profile.Phone,
+			BankAccountId:     profile.BankAccountId,
 			BankAccountName:   profile.BankAccountName,
 			BankAccountHolder: profile.BankAccountHolder,
 			BankAccountNumber: profile.BankAccountNumber,
@@ -92,6 +102,55 @@ func (puc ProtoUserController) GetUserDetailsWithId(ctx context.Context, request
 	return &result, nil
 }
 
+// GetSellerProfile implements user.UserServiceServer.
+func (puc ProtoUserController) GetSellerProfile(ctx context.Context, request *user.SellerProfileRequest) (*user.SellerProfileResponse, error) {
+	if request.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "userId is required")
+	}
+
+	profile, err := puc.userService.GetSellerProfile(ctx, request.UserId)
+	if err != nil {
+		puc.logger.Error(ctx, "userController.GetSellerProfile failed", "error", err, "userId", request.UserId)
+		if errResponse, ok := err.(exceptions.ErrorResponse); ok && errResponse.StatusCode == fiber.StatusNotFound {
+			return nil, status.Error(codes.NotFound, errResponse.Message)
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &user.SellerProfileResponse{
+		UserId:       profile.UserId,
+		DisplayName:  profile.DisplayName,
+		AvatarUri:    profile.AvatarUri,
+		JoinedAt:     profile.JoinedAt.Format(time.RFC3339),
+		ListingCount: profile.ListingCount,
+	}, nil
+}
+
+// GetBankAccount implements user.UserServiceServer.
+func (puc ProtoUserController) GetBankAccount(ctx context.Context, request *user.BankAccountRequest) (*user.BankAccountResponse, error) {
+	if request.UserId == "" || request.BankAccountId == "" {
+		return nil, status.Error(codes.InvalidArgument, "userId and bankAccountId are required")
+	}
+
+	account, err := puc.bankAccountService.GetBankAccount(ctx, request.UserId, request.BankAccountId)
+	if err != nil {
+		puc.logger.Error(ctx, "userController.GetBankAccount failed", "error", err, "bankAccountId", request.BankAccountId)
+		if errResponse, ok := err.(exceptions.ErrorResponse); ok && errResponse.StatusCode == fiber.StatusNotFound {
+			return nil, status.Error(codes.NotFound, errResponse.Message)
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &user.BankAccountResponse{
+		BankAccountId:     account.BankAccountId,
+		UserId:            request.UserId,
+		BankAccountName:   account.BankAccountName,
+		BankAccountHolder: account.BankAccountHolder,
+		BankAccountNumber: account.BankAccountNumber,
+		IsDefault:         account.IsDefault,
+	}, nil
+}
+
 // mustEmbedUnimplementedUserServiceServer implements user.UserServiceServer.
 func (puc ProtoUserController) mustEmbedUnimplementedUserServiceServer() {
 	return
@@ -1,72 +1,126 @@
 package userGrpc
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net"
 	"net/http"
+	"strings"
 
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/grpcmw"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/health"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/logging"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/metrics"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/tracing"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/config"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/di"
 	protoUserController "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/grpc/controllers/user/proto"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/proto/user"
-	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
-	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/samber/do/v2"
 	"google.golang.org/grpc"
 )
 
 type UserGrpcServer struct {
+	server        *grpc.Server
+	metricsServer *http.Server
 }
 
-func StartGrpcServer() {
-	// Start gRPC server
-	// Buat registry untuk Prometheus
-	registry := prometheus.NewRegistry()
-	grpcMetrics := grpc_prometheus.NewServerMetrics()
+func NewGrpcServer() *UserGrpcServer {
+	// Registry yang sama dengan /metrics di HTTP server, lihat middleware/metrics
+	m := do.MustInvoke[*metrics.Metrics](di.Injector)
 
-	// Registrasikan grpcMetrics ke registry
-	registry.MustRegister(grpcMetrics)
-
-	_PORT := config.GetGRPCPort()
 	_GRPC_METRIC_PORT := config.GetMetricGRPCPort()
 
-	// Buat gRPC server dengan interceptor Prometheus
-	grpcServer := grpc.NewServer(
-		grpc.UnaryInterceptor(grpcMetrics.UnaryServerInterceptor()),
-		grpc.StreamInterceptor(grpcMetrics.StreamServerInterceptor()),
-	)
+	// mTLS kalau path sertifikat diisi, client tanpa sertifikat yang dipercaya ditolak
+	tlsConfig := config.GetGrpcTLS()
+	transport, err := grpcmw.ServerTransport(tlsConfig)
+	if err != nil {
+		log.Fatalf("Failed to load gRPC TLS certificates: %v", err)
+	}
+
+	// Di luar DEBUG server tanpa token maupun mTLS menerima siapa saja, jadi ditolak
+	logger := do.MustInvoke[*logging.Logger](di.Injector)
+	cfg := serverConfig(logger)
+	if strings.ToUpper(config.MODE) != config.MODE_DEBUG {
+		if err := cfg.RequireAuth(tlsConfig); err != nil {
+			log.Fatalf("Set GRPC_SERVICE_TOKEN or the GRPC_TLS_* files: %v", err)
+		}
+	}
+
+	// Buat gRPC server dengan tracing, interceptor Prometheus, lalu auth, request id, deadline, recovery dan access log
+	options := append(append(transport, tracing.ServerOptions()...), m.GrpcServerOptions()...)
+	grpcServer := grpc.NewServer(append(options, grpcmw.ServerOptions(cfg)...)...)
 
 	// Registrasikan service gRPC Anda
 	puc := do.MustInvoke[*protoUserController.ProtoUserController](di.Injector)
 	user.RegisterUserServiceServer(grpcServer, puc)
 
-	// Inisialisasi metrik Prometheus untuk gRPC
-	grpcMetrics.InitializeMetrics(grpcServer)
+	// grpc.health.v1, dipakai client untuk melewati backend yang tidak siap
+	healthChecker := do.MustInvoke[*health.Checker](di.Injector)
+	healthChecker.RegisterGrpc(grpcServer, config.GetGrpcHealthInterval())
 
-	// Jalankan HTTP server untuk endpoint metrik
-	httpServer := &http.Server{
-		Handler: promhttp.HandlerFor(registry, promhttp.HandlerOpts{}),
-		Addr:    fmt.Sprintf("0.0.0.0:%s", _GRPC_METRIC_PORT), // Port untuk endpoint Prometheus
+	return &UserGrpcServer{
+		server: grpcServer,
+		// HTTP server untuk endpoint metrik
+		metricsServer: &http.Server{
+			Handler: m.Handler(),
+			Addr:    fmt.Sprintf("0.0.0.0:%s", _GRPC_METRIC_PORT), // Port untuk endpoint Prometheus
+		},
 	}
+}
+
+// Blocks until the server stops, returns nil after Shutdown
+func (s *UserGrpcServer) Listen() error {
 	go func() {
-		fmt.Printf("> Starting Prometheus metrics server at 0.0.0.0:%s\n", _GRPC_METRIC_PORT)
-		if err := httpServer.ListenAndServe(); err != nil {
+		fmt.Printf("> Starting Prometheus metrics server at %s\n", s.metricsServer.Addr)
+		if err := s.metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			log.Fatalf("Failed to start Prometheus metrics server: %v", err)
 		}
 	}()
-	go func() {
-		// Jalankan gRPC server
-		lis, err := net.Listen("tcp", fmt.Sprintf(":%s", _PORT))
-		if err != nil {
-			log.Fatalf("Failed to listen: %v", err)
-		}
 
-		fmt.Printf("> gRPC server listening on :%s\n", _PORT)
+	// Jalankan gRPC server
+	_PORT := config.GetGRPCPort()
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%s", _PORT))
+	if err != nil {
+		return err
+	}
 
-		if err := grpcServer.Serve(lis); err != nil {
-			log.Fatalf("Failed to serve: %v", err)
-		}
-	}()
+	fmt.Printf("> gRPC server listening on :%s\n", _PORT)
+	return s.server.Serve(lis)
+}
+
+// Waits for the running calls until ctx is done, then cancels the rest
+func (s *UserGrpcServer) Shutdown(ctx context.Context) error {
+	return errors.Join(
+		grpcmw.GracefulStop(ctx, s.server),
+		s.metricsServer.Shutdown(ctx),
+	)
+}
+
+func serverConfig(logger *logging.Logger) grpcmw.ServerConfig {
+	return grpcmw.ServerConfig{
+		Token:          config.GetGrpcServiceToken(),
+		AllowedPeers:   config.GetGrpcAllowedPeers(),
+		DefaultTimeout: config.GetGrpcDefaultTimeout(),
+		Log: func(ctx context.Context, entry grpcmw.AccessLog) {
+			// request_id comes from ctx, the logger attaches it
+			data := []interface{}{
+				"method", entry.Method,
+				"code", entry.Code.String(),
+				"duration", entry.Duration.String(),
+				"peer", entry.Peer,
+				"identity", entry.Identity,
+			}
+			if entry.Err != nil {
+				logger.Warn(ctx, "grpc call failed", append(data, "error", entry.Err)...)
+				return
+			}
+			logger.Info(ctx, "grpc call", data...)
+		},
+		OnPanic: func(ctx context.Context, method string, recovered any, stack []byte) {
+			logger.Error(ctx, "grpc handler panicked", "panic", fmt.Sprint(recovered), "method", method, "stack", string(stack))
+		},
+	}
 }
@@ -0,0 +1,29 @@
+package healthCheck
+
+import (
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/health"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/cache"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/config"
+	fileService "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/external/file"
+	productService "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/external/product"
+	purchaseService "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/external/purchase"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/samber/do/v2"
+)
+
+// Postgres and Redis are required, file, product and purchase only degrade the service
+// since most routes work without them
+func NewCheckerInject(i do.Injector) (*health.Checker, error) {
+	_db := do.MustInvoke[*pgxpool.Pool](i)
+	_cache := do.MustInvoke[cache.RedisCacheClient](i)
+	_fileService := do.MustInvoke[fileService.FileServiceInterface](i)
+	_productService := do.MustInvoke[productService.ProductServiceInterface](i)
+	_purchaseService := do.MustInvoke[purchaseService.PurchaseServiceInterface](i)
+
+	return health.New(config.GetHealthCheckTimeout()).
+		Add("postgres", _db.Ping).
+		Add("redis", _cache.Ping).
+		AddOptional("file", _fileService.Ready).
+		AddOptional("product", _productService.Ready).
+		AddOptional("purchase", _purchaseService.Ready), nil
+}
@@ -11,7 +11,10 @@ import (
 // which later can be safely converted by this function to empty string (e.g. "email": "")
 // instead of null (e.g. "email": null) to satisfy the API specs.
 func ConvertUserToResponse(user *repository.User) response.UserResponse {
-	response := response.UserResponse{}
+	response := response.UserResponse{
+		EmailVerified: user.EmailVerified,
+		PhoneVerified: user.PhoneVerified,
+	}
 
 	if user.Email != nil {
 		response.Email = *user.Email
@@ -19,6 +22,9 @@ func ConvertUserToResponse(user *repository.User) response.UserResponse {
 	if user.Phone != nil {
 		response.Phone = *user.Phone
 	}
+	if user.DisplayName != nil {
+		response.DisplayName = *user.DisplayName
+	}
 	if user.FileId != nil {
 		response.FileId = *user.FileId
 	}
@@ -28,6 +34,9 @@ func ConvertUserToResponse(user *repository.User) response.UserResponse {
 	if user.FileThumbnailUri != nil {
 		response.FileThumbnailUri = *user.FileThumbnailUri
 	}
+	if user.BankAccountId != nil {
+		response.BankAccountId = *user.BankAccountId
+	}
 	if user.BankAccountName != nil {
 		response.BankAccountName = *user.BankAccountName
 	}
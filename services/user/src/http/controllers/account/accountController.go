@@ -0,0 +1,99 @@
+package accountController
+
+import (
+	"net/http"
+
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/auth"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/logging"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/exceptions"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/helper"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/model/dtos/request"
+	accountService "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/account"
+	"github.com/gofiber/fiber/v2"
+	"github.com/samber/do/v2"
+)
+
+type AccountControllerInterface interface {
+	DeleteAccount(C *fiber.Ctx) error
+	Export(C *fiber.Ctx) error
+}
+
+type AccountController struct {
+	accountService accountService.AccountServiceInterface
+	logger         *logging.Logger
+}
+
+func NewAccountController(accountService accountService.AccountServiceInterface, logger *logging.Logger) AccountControllerInterface {
+	return &AccountController{accountService: accountService, logger: logger}
+}
+
+func NewAccountControllerInject(i do.Injector) (AccountControllerInterface, error) {
+	_accountService := do.MustInvoke[accountService.AccountServiceInterface](i)
+	_logger := do.MustInvoke[*logging.Logger](i)
+	return NewAccountController(_accountService, _logger), nil
+}
+
+// Account godoc
+// @Summary Delete the account of the logged in user
+// @Description Personal data is erased, sessions are revoked and the products of the user are unlisted
+// @Tags Account
+// @Accept json
+// @Produce json
+// @Param request body request.DeleteAccountRequest true "Payload"
+// @Success 204 "success response"
+// @Failure 400 {object} map[string]interface{} "bad request"
+// @Failure 401 {object} map[string]interface{} "unauthorized"
+// @Failure 404 {object} map[string]interface{} "not found"
+// @Failure 500 {object} map[string]interface{} "internal server error"
+// @Router /v1/user [delete]
+func (ac *AccountController) DeleteAccount(ctx *fiber.Ctx) error {
+	principal, ok := auth.PrincipalFrom(ctx)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(exceptions.ErrUnauthorized("Token Invalid"))
+	}
+
+	requestParse := request.DeleteAccountRequest{}
+
+	if err := ctx.BodyParser(&requestParse); err != nil {
+		ac.logger.Error(ctx.UserContext(), "accountController.DeleteAccount failed", "error", err)
+		return ctx.Status(http.StatusBadRequest).JSON(exceptions.ErrBadRequest(err.Error()))
+	}
+
+	err := ac.accountService.DeleteAccount(ctx.Context(), requestParse, principal)
+	if err != nil {
+		ac.logger.Error(ctx.UserContext(), "accountController.DeleteAccount failed", "error", err)
+		return ctx.Status(int(err.(exceptions.ErrorResponse).StatusCode)).JSON(err)
+	}
+
+	ctx.Set(helper.X_AUTHOR_HEADER_KEY, helper.X_AUTHOR_HEADER_VALUE)
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
+
+// Account godoc
+// @Summary Export the data of the logged in user
+// @Description Streams a JSON archive with the profile, bank accounts, products and purchases. complete is false when the product or purchase list was cut off
+// @Tags Account
+// @Produce json
+// @Success 200 {object} map[string]interface{} "archive"
+// @Failure 401 {object} map[string]interface{} "unauthorized"
+// @Failure 404 {object} map[string]interface{} "not found"
+// @Failure 500 {object} map[string]interface{} "internal server error"
+// @Router /v1/user/export [get]
+func (ac *AccountController) Export(ctx *fiber.Ctx) error {
+	userId, ok := ctx.Locals("userId").(string)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(exceptions.ErrUnauthorized("Token Invalid"))
+	}
+
+	write, err := ac.accountService.Export(ctx.Context(), userId)
+	if err != nil {
+		ac.logger.Error(ctx.UserContext(), "accountController.Export failed", "error", err)
+		return ctx.Status(int(err.(exceptions.ErrorResponse).StatusCode)).JSON(err)
+	}
+
+	ctx.Set(helper.X_AUTHOR_HEADER_KEY, helper.X_AUTHOR_HEADER_VALUE)
+	ctx.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSONCharsetUTF8)
+	ctx.Set(fiber.HeaderContentDisposition, `attachment; filename="tutuplapak-export.json"`)
+	ctx.Status(fiber.StatusOK).Context().SetBodyStreamWriter(write)
+	return nil
+}
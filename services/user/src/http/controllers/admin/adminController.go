@@ -0,0 +1,107 @@
+package adminController
+
+import (
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/logging"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/exceptions"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/helper"
+	roleService "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/role"
+	"github.com/gofiber/fiber/v2"
+	"github.com/samber/do/v2"
+)
+
+type AdminControllerInterface interface {
+	GetRoles(C *fiber.Ctx) error
+	GrantRole(C *fiber.Ctx) error
+	RevokeRole(C *fiber.Ctx) error
+}
+
+type AdminController struct {
+	roleService roleService.RoleServiceInterface
+	logger      *logging.Logger
+}
+
+func NewAdminController(roleService roleService.RoleServiceInterface, logger *logging.Logger) AdminControllerInterface {
+	return &AdminController{roleService: roleService, logger: logger}
+}
+
+func NewAdminControllerInject(i do.Injector) (AdminControllerInterface, error) {
+	_roleService := do.MustInvoke[roleService.RoleServiceInterface](i)
+	_logger := do.MustInvoke[*logging.Logger](i)
+	return NewAdminController(_roleService, _logger), nil
+}
+
+// Admin godoc
+// @Summary Roles of a user
+// @Description Admin only
+// @Tags Admin
+// @Produce json
+// @Param id path string true "User id"
+// @Success 200 {object} response.RolesResponse "success response"
+// @Failure 401 {object} map[string]interface{} "unauthorized"
+// @Failure 403 {object} map[string]interface{} "forbidden"
+// @Failure 404 {object} map[string]interface{} "not found"
+// @Router /v1/admin/users/{id}/roles [get]
+func (ac *AdminController) GetRoles(ctx *fiber.Ctx) error {
+	response, err := ac.roleService.GetRoles(ctx.Context(), ctx.Params("id"))
+	if err != nil {
+		ac.logger.Error(ctx.UserContext(), "adminController.GetRoles failed", "error", err)
+		return ctx.Status(int(err.(exceptions.ErrorResponse).StatusCode)).JSON(err)
+	}
+
+	ctx.Set(helper.X_AUTHOR_HEADER_KEY, helper.X_AUTHOR_HEADER_VALUE)
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+// Admin godoc
+// @Summary Grant a role to a user
+// @Description Admin only. The role is in the user's token after their next login or refresh
+// @Tags Admin
+// @Produce json
+// @Param id path string true "User id"
+// @Param role path string true "buyer, seller or admin"
+// @Success 200 {object} response.RolesResponse "success response"
+// @Failure 400 {object} map[string]interface{} "unknown role"
+// @Failure 401 {object} map[string]interface{} "unauthorized"
+// @Failure 403 {object} map[string]interface{} "forbidden"
+// @Failure 404 {object} map[string]interface{} "not found"
+// @Router /v1/admin/users/{id}/roles/{role} [put]
+func (ac *AdminController) GrantRole(ctx *fiber.Ctx) error {
+	response, err := ac.roleService.GrantRole(ctx.Context(), ctx.Params("id"), ctx.Params("role"))
+	if err != nil {
+		ac.logger.Error(ctx.UserContext(), "adminController.GrantRole failed", "error", err)
+		return ctx.Status(int(err.(exceptions.ErrorResponse).StatusCode)).JSON(err)
+	}
+
+	ctx.Set(helper.X_AUTHOR_HEADER_KEY, helper.X_AUTHOR_HEADER_VALUE)
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+// Admin godoc
+// @Summary Revoke a role from a user
+// @Description Admin only. The buyer role can't be revoked, and admins can't revoke their own admin role
+// @Tags Admin
+// @Produce json
+// @Param id path string true "User id"
+// @Param role path string true "seller or admin"
+// @Success 200 {object} response.RolesResponse "success response"
+// @Failure 400 {object} map[string]interface{} "unknown role"
+// @Failure 401 {object} map[string]interface{} "unauthorized"
+// @Failure 403 {object} map[string]interface{} "forbidden"
+// @Failure 404 {object} map[string]interface{} "not found"
+// @Failure 409 {object} map[string]interface{} "own admin role"
+// @Router /v1/admin/users/{id}/roles/{role} [delete]
+func (ac *AdminController) RevokeRole(ctx *fiber.Ctx) error {
+	adminId, ok := ctx.Locals("userId").(string)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(exceptions.ErrUnauthorized("Token Invalid"))
+	}
+
+	response, err := ac.roleService.RevokeRole(ctx.Context(), adminId, ctx.Params("id"), ctx.Params("role"))
+	if err != nil {
+		ac.logger.Error(ctx.UserContext(), "adminController.RevokeRole failed", "error", err)
+		return ctx.Status(int(err.(exceptions.ErrorResponse).StatusCode)).JSON(err)
+	}
+
+	ctx.Set(helper.X_AUTHOR_HEADER_KEY, helper.X_AUTHOR_HEADER_VALUE)
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
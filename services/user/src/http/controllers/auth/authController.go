@@ -0,0 +1,117 @@
+package authController
+
+import (
+	"net/http"
+
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/auth"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/logging"
+	authJwt "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/auth/jwt"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/exceptions"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/helper"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/model/dtos/request"
+	authService "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/auth"
+	"github.com/gofiber/fiber/v2"
+	"github.com/samber/do/v2"
+)
+
+type AuthControllerInterface interface {
+	Refresh(C *fiber.Ctx) error
+	Logout(C *fiber.Ctx) error
+	Jwks(C *fiber.Ctx) error
+}
+
+type AuthController struct {
+	authService authService.AuthServiceInterface
+	jwtService  authJwt.JwtServiceInterface
+	logger      *logging.Logger
+}
+
+func NewAuthController(authService authService.AuthServiceInterface, jwtService authJwt.JwtServiceInterface, logger *logging.Logger) AuthControllerInterface {
+	return &AuthController{authService: authService, jwtService: jwtService, logger: logger}
+}
+
+func NewAuthControllerInject(i do.Injector) (AuthControllerInterface, error) {
+	_authService := do.MustInvoke[authService.AuthServiceInterface](i)
+	_jwtService := do.MustInvoke[authJwt.JwtServiceInterface](i)
+	_logger := do.MustInvoke[*logging.Logger](i)
+	return NewAuthController(_authService, _jwtService, _logger), nil
+}
+
+// Auth godoc
+// @Summary Exchange a refresh token for a new token pair
+// @Description The presented refresh token is revoked, reusing it revokes the whole session
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body request.RefreshTokenRequest true "Payload"
+// @Success 200 {object} response.TokenResponse "success response"
+// @Failure 400 {object} map[string]interface{} "bad request"
+// @Failure 401 {object} map[string]interface{} "unauthorized"
+// @Failure 500 {object} map[string]interface{} "internal server error"
+// @Router /v1/auth/refresh [post]
+func (ac *AuthController) Refresh(ctx *fiber.Ctx) error {
+	requestParse := request.RefreshTokenRequest{}
+
+	if err := ctx.BodyParser(&requestParse); err != nil {
+		ac.logger.Error(ctx.UserContext(), "authController.Refresh failed", "error", err)
+		return ctx.Status(http.StatusBadRequest).JSON(exceptions.ErrBadRequest(err.Error()))
+	}
+
+	response, err := ac.authService.Refresh(ctx.Context(), requestParse)
+	if err != nil {
+		ac.logger.Error(ctx.UserContext(), "authController.Refresh failed", "error", err)
+		return ctx.Status(int(err.(exceptions.ErrorResponse).StatusCode)).JSON(err)
+	}
+
+	ctx.Set(helper.X_AUTHOR_HEADER_KEY, helper.X_AUTHOR_HEADER_VALUE)
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+// Auth godoc
+// @Summary Revoke the current access token and its refresh token
+// @Description
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body request.LogoutRequest false "Payload"
+// @Success 204 "success response"
+// @Failure 401 {object} map[string]interface{} "unauthorized"
+// @Failure 500 {object} map[string]interface{} "internal server error"
+// @Router /v1/auth/logout [post]
+func (ac *AuthController) Logout(ctx *fiber.Ctx) error {
+	principal, ok := auth.PrincipalFrom(ctx)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(exceptions.ErrUnauthorized("Token Invalid"))
+	}
+
+	requestParse := request.LogoutRequest{}
+
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(&requestParse); err != nil {
+			ac.logger.Error(ctx.UserContext(), "authController.Logout failed", "error", err)
+			return ctx.Status(http.StatusBadRequest).JSON(exceptions.ErrBadRequest(err.Error()))
+		}
+	}
+
+	err := ac.authService.Logout(ctx.Context(), requestParse, principal)
+	if err != nil {
+		ac.logger.Error(ctx.UserContext(), "authController.Logout failed", "error", err)
+		return ctx.Status(int(err.(exceptions.ErrorResponse).StatusCode)).JSON(err)
+	}
+
+	ctx.Set(helper.X_AUTHOR_HEADER_KEY, helper.X_AUTHOR_HEADER_VALUE)
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
+
+// Auth godoc
+// @Summary Public keys used to verify access tokens
+// @Description Product and purchase cache this document and pick the key by the token's `kid` header
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} authJwt.JWKS "success response"
+// @Router /v1/auth/jwks [get]
+func (ac *AuthController) Jwks(ctx *fiber.Ctx) error {
+	ctx.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	ctx.Set(helper.X_AUTHOR_HEADER_KEY, helper.X_AUTHOR_HEADER_VALUE)
+	return ctx.Status(fiber.StatusOK).JSON(ac.jwtService.JWKS())
+}
@@ -0,0 +1,148 @@
+package bankAccountController
+
+import (
+	"net/http"
+
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/logging"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/exceptions"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/helper"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/model/dtos/request"
+	bankAccountService "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/bankAccount"
+	"github.com/gofiber/fiber/v2"
+	"github.com/samber/do/v2"
+)
+
+type BankAccountControllerInterface interface {
+	CreateBankAccount(C *fiber.Ctx) error
+	GetBankAccounts(C *fiber.Ctx) error
+	DeleteBankAccount(C *fiber.Ctx) error
+	SetDefaultBankAccount(C *fiber.Ctx) error
+}
+
+type BankAccountController struct {
+	bankAccountService bankAccountService.BankAccountServiceInterface
+	logger             *logging.Logger
+}
+
+func NewBankAccountController(bankAccountService bankAccountService.BankAccountServiceInterface, logger *logging.Logger) BankAccountControllerInterface {
+	return &BankAccountController{bankAccountService: bankAccountService, logger: logger}
+}
+
+func NewBankAccountControllerInject(i do.Injector) (BankAccountControllerInterface, error) {
+	_bankAccountService := do.MustInvoke[bankAccountService.BankAccountServiceInterface](i)
+	_logger := do.MustInvoke[*logging.Logger](i)
+	return NewBankAccountController(_bankAccountService, _logger), nil
+}
+
+// BankAccount godoc
+// @Summary Add a bank account to the logged in user
+// @Description The first account, or one sent with `isDefault`, becomes the default account. A user has at most 10 accounts
+// @Tags Bank Account
+// @Accept json
+// @Produce json
+// @Param request body request.CreateBankAccountRequest true "Payload"
+// @Success 201 {object} response.BankAccountResponse "success response"
+// @Failure 400 {object} map[string]interface{} "bad request"
+// @Failure 401 {object} map[string]interface{} "unauthorized"
+// @Failure 409 {object} map[string]interface{} "too many accounts"
+// @Failure 500 {object} map[string]interface{} "internal server error"
+// @Router /v1/user/bank-accounts [post]
+func (bc *BankAccountController) CreateBankAccount(ctx *fiber.Ctx) error {
+	userId, ok := ctx.Locals("userId").(string)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(exceptions.ErrUnauthorized("Token Invalid"))
+	}
+
+	requestParse := request.CreateBankAccountRequest{}
+
+	if err := ctx.BodyParser(&requestParse); err != nil {
+		bc.logger.Error(ctx.UserContext(), "bankAccountController.CreateBankAccount failed", "error", err)
+		return ctx.Status(http.StatusBadRequest).JSON(exceptions.ErrBadRequest(err.Error()))
+	}
+
+	response, err := bc.bankAccountService.CreateBankAccount(ctx.Context(), requestParse, userId)
+	if err != nil {
+		bc.logger.Error(ctx.UserContext(), "bankAccountController.CreateBankAccount failed", "error", err)
+		return ctx.Status(int(err.(exceptions.ErrorResponse).StatusCode)).JSON(err)
+	}
+
+	ctx.Set(helper.X_AUTHOR_HEADER_KEY, helper.X_AUTHOR_HEADER_VALUE)
+	return ctx.Status(fiber.StatusCreated).JSON(response)
+}
+
+// BankAccount godoc
+// @Summary List the bank accounts of the logged in user
+// @Description The default account comes first
+// @Tags Bank Account
+// @Produce json
+// @Success 200 {array} response.BankAccountResponse "success response"
+// @Failure 401 {object} map[string]interface{} "unauthorized"
+// @Failure 500 {object} map[string]interface{} "internal server error"
+// @Router /v1/user/bank-accounts [get]
+func (bc *BankAccountController) GetBankAccounts(ctx *fiber.Ctx) error {
+	userId, ok := ctx.Locals("userId").(string)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(exceptions.ErrUnauthorized("Token Invalid"))
+	}
+
+	response, err := bc.bankAccountService.GetBankAccounts(ctx.Context(), userId)
+	if err != nil {
+		bc.logger.Error(ctx.UserContext(), "bankAccountController.GetBankAccounts failed", "error", err)
+		return ctx.Status(int(err.(exceptions.ErrorResponse).StatusCode)).JSON(err)
+	}
+
+	ctx.Set(helper.X_AUTHOR_HEADER_KEY, helper.X_AUTHOR_HEADER_VALUE)
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+// BankAccount godoc
+// @Summary Delete a bank account of the logged in user
+// @Description Deleting the default account makes the most recent remaining account the default
+// @Tags Bank Account
+// @Param id path string true "Bank account id"
+// @Success 204 "success response"
+// @Failure 401 {object} map[string]interface{} "unauthorized"
+// @Failure 404 {object} map[string]interface{} "not found"
+// @Failure 500 {object} map[string]interface{} "internal server error"
+// @Router /v1/user/bank-accounts/{id} [delete]
+func (bc *BankAccountController) DeleteBankAccount(ctx *fiber.Ctx) error {
+	userId, ok := ctx.Locals("userId").(string)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(exceptions.ErrUnauthorized("Token Invalid"))
+	}
+
+	err := bc.bankAccountService.DeleteBankAccount(ctx.Context(), userId, ctx.Params("id"))
+	if err != nil {
+		bc.logger.Error(ctx.UserContext(), "bankAccountController.DeleteBankAccount failed", "error", err)
+		return ctx.Status(int(err.(exceptions.ErrorResponse).StatusCode)).JSON(err)
+	}
+
+	ctx.Set(helper.X_AUTHOR_HEADER_KEY, helper.X_AUTHOR_HEADER_VALUE)
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
+
+// BankAccount godoc
+// @Summary Make a bank account the default one
+// @Description The default account is the one returned with the profile and shown to buyers
+// @Tags Bank Account
+// @Param id path string true "Bank account id"
+// @Success 204 "success response"
+// @Failure 401 {object} map[string]interface{} "unauthorized"
+// @Failure 404 {object} map[string]interface{} "not found"
+// @Failure 500 {object} map[string]interface{} "internal server error"
+// @Router /v1/user/bank-accounts/{id}/default [put]
+func (bc *BankAccountController) SetDefaultBankAccount(ctx *fiber.Ctx) error {
+	userId, ok := ctx.Locals("userId").(string)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(exceptions.ErrUnauthorized("Token Invalid"))
+	}
+
+	err := bc.bankAccountService.SetDefaultBankAccount(ctx.Context(), userId, ctx.Params("id"))
+	if err != nil {
+		bc.logger.Error(ctx.UserContext(), "bankAccountController.SetDefaultBankAccount failed", "error", err)
+		return ctx.Status(int(err.(exceptions.ErrorResponse).StatusCode)).JSON(err)
+	}
+
+	ctx.Set(helper.X_AUTHOR_HEADER_KEY, helper.X_AUTHOR_HEADER_VALUE)
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,133 @@
+package passwordController
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/logging"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/exceptions"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/helper"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/model/dtos/request"
+	passwordService "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/password"
+	"github.com/gofiber/fiber/v2"
+	"github.com/samber/do/v2"
+)
+
+type PasswordControllerInterface interface {
+	ChangePassword(C *fiber.Ctx) error
+	ForgotPassword(C *fiber.Ctx) error
+	ResetPassword(C *fiber.Ctx) error
+}
+
+type PasswordController struct {
+	passwordService passwordService.PasswordServiceInterface
+	logger          *logging.Logger
+}
+
+func NewPasswordController(passwordService passwordService.PasswordServiceInterface, logger *logging.Logger) PasswordControllerInterface {
+	return &PasswordController{passwordService: passwordService, logger: logger}
+}
+
+func NewPasswordControllerInject(i do.Injector) (PasswordControllerInterface, error) {
+	_passwordService := do.MustInvoke[passwordService.PasswordServiceInterface](i)
+	_logger := do.MustInvoke[*logging.Logger](i)
+	return NewPasswordController(_passwordService, _logger), nil
+}
+
+// Password godoc
+// @Summary Change the password of the logged in user
+// @Description Every refresh token of the user is revoked, the response carries a new token pair
+// @Tags Password
+// @Accept json
+// @Produce json
+// @Param request body request.ChangePasswordRequest true "Payload"
+// @Success 200 {object} response.TokenResponse "success response"
+// @Failure 400 {object} map[string]interface{} "bad request"
+// @Failure 401 {object} map[string]interface{} "unauthorized"
+// @Failure 429 {object} map[string]interface{} "too many wrong current passwords, see Retry-After"
+// @Failure 500 {object} map[string]interface{} "internal server error"
+// @Router /v1/user/password [put]
+func (pc *PasswordController) ChangePassword(ctx *fiber.Ctx) error {
+	userId, ok := ctx.Locals("userId").(string)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(exceptions.ErrUnauthorized("Token Invalid"))
+	}
+
+	requestParse := request.ChangePasswordRequest{}
+
+	if err := ctx.BodyParser(&requestParse); err != nil {
+		pc.logger.Error(ctx.UserContext(), "passwordController.ChangePassword failed", "error", err)
+		return ctx.Status(http.StatusBadRequest).JSON(exceptions.ErrBadRequest(err.Error()))
+	}
+
+	response, err := pc.passwordService.ChangePassword(ctx.Context(), requestParse, userId, ctx.IP())
+	if err != nil {
+		pc.logger.Error(ctx.UserContext(), "passwordController.ChangePassword failed", "error", err)
+		errResponse := err.(exceptions.ErrorResponse)
+		if errResponse.RetryAfter > 0 {
+			ctx.Set(fiber.HeaderRetryAfter, strconv.Itoa(errResponse.RetryAfter))
+		}
+		return ctx.Status(int(errResponse.StatusCode)).JSON(err)
+	}
+
+	ctx.Set(helper.X_AUTHOR_HEADER_KEY, helper.X_AUTHOR_HEADER_VALUE)
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+// Password godoc
+// @Summary Send a password reset token
+// @Description Always accepted, whether the account exists or not
+// @Tags Password
+// @Accept json
+// @Produce json
+// @Param request body request.ForgotPasswordRequest true "Payload"
+// @Success 202 "accepted"
+// @Failure 400 {object} map[string]interface{} "bad request"
+// @Failure 500 {object} map[string]interface{} "internal server error"
+// @Router /v1/password/forgot [post]
+func (pc *PasswordController) ForgotPassword(ctx *fiber.Ctx) error {
+	requestParse := request.ForgotPasswordRequest{}
+
+	if err := ctx.BodyParser(&requestParse); err != nil {
+		pc.logger.Error(ctx.UserContext(), "passwordController.ForgotPassword failed", "error", err)
+		return ctx.Status(http.StatusBadRequest).JSON(exceptions.ErrBadRequest(err.Error()))
+	}
+
+	err := pc.passwordService.ForgotPassword(ctx.Context(), requestParse)
+	if err != nil {
+		pc.logger.Error(ctx.UserContext(), "passwordController.ForgotPassword failed", "error", err)
+		return ctx.Status(int(err.(exceptions.ErrorResponse).StatusCode)).JSON(err)
+	}
+
+	ctx.Set(helper.X_AUTHOR_HEADER_KEY, helper.X_AUTHOR_HEADER_VALUE)
+	return ctx.SendStatus(fiber.StatusAccepted)
+}
+
+// Password godoc
+// @Summary Set a new password with a reset token
+// @Description The token works once, every refresh token of the user is revoked
+// @Tags Password
+// @Accept json
+// @Produce json
+// @Param request body request.ResetPasswordRequest true "Payload"
+// @Success 204 "success response"
+// @Failure 400 {object} map[string]interface{} "bad request"
+// @Failure 500 {object} map[string]interface{} "internal server error"
+// @Router /v1/password/reset [post]
+func (pc *PasswordController) ResetPassword(ctx *fiber.Ctx) error {
+	requestParse := request.ResetPasswordRequest{}
+
+	if err := ctx.BodyParser(&requestParse); err != nil {
+		pc.logger.Error(ctx.UserContext(), "passwordController.ResetPassword failed", "error", err)
+		return ctx.Status(http.StatusBadRequest).JSON(exceptions.ErrBadRequest(err.Error()))
+	}
+
+	err := pc.passwordService.ResetPassword(ctx.Context(), requestParse)
+	if err != nil {
+		pc.logger.Error(ctx.UserContext(), "passwordController.ResetPassword failed", "error", err)
+		return ctx.Status(int(err.(exceptions.ErrorResponse).StatusCode)).JSON(err)
+	}
+
+	ctx.Set(helper.X_AUTHOR_HEADER_KEY, helper.X_AUTHOR_HEADER_VALUE)
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
@@ -2,11 +2,11 @@ package userController
 
 import (
 	"net/http"
+	"strconv"
 
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/logging"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/exceptions"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/helper"
-	functionCallerInfo "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/logger/helper"
-	loggerZap "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/logger/zap"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/model/dtos/request"
 	fileService "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/external/file"
 	userService "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/user"
@@ -22,24 +22,27 @@ type UserControllerInterface interface {
 
 	LinkEmail(C *fiber.Ctx) error
 	LinkPhone(C *fiber.Ctx) error
+	VerifyEmail(C *fiber.Ctx) error
+	VerifyPhone(C *fiber.Ctx) error
 	GetUserProfile(C *fiber.Ctx) error
+	GetSellerProfile(C *fiber.Ctx) error
 	UpdateUserProfile(C *fiber.Ctx) error
 }
 
 type UserController struct {
 	userService userService.UserServiceInterface
 	fileService fileService.FileServiceInterface
-	logger      loggerZap.LoggerInterface
+	logger      *logging.Logger
 }
 
-func NewUserController(userService userService.UserServiceInterface, fileService fileService.FileServiceInterface, logger loggerZap.LoggerInterface) UserControllerInterface {
+func NewUserController(userService userService.UserServiceInterface, fileService fileService.FileServiceInterface, logger *logging.Logger) UserControllerInterface {
 	return &UserController{userService: userService, logger: logger}
 }
 
 func NewUserControllerInject(i do.Injector) (UserControllerInterface, error) {
 	_userService := do.MustInvoke[userService.UserServiceInterface](i)
 	_fileService := do.MustInvoke[fileService.FileServiceInterface](i)
-	_logger := do.MustInvoke[loggerZap.LoggerInterface](i)
+	_logger := do.MustInvoke[*logging.Logger](i)
 	return NewUserController(_userService, _fileService, _logger), nil
 }
 
@@ -60,13 +63,13 @@ func (uc *UserController) RegisterByEmail(ctx *fiber.Ctx) error {
 	userRequestParse := request.AuthByEmailRequest{}
 
 	if err := ctx.BodyParser(&userRequestParse); err != nil {
-		uc.logger.Error(err.Error(), functionCallerInfo.UserControllerRegisterByEmail)
+		uc.logger.Error(ctx.UserContext(), "userController.RegisterByEmail failed", "error", err)
 		return ctx.Status(http.StatusBadRequest).JSON(exceptions.ErrBadRequest(err.Error()))
 	}
 
 	response, err := uc.userService.RegisterByEmail(ctx.Context(), userRequestParse)
 	if err != nil {
-		uc.logger.Error(err.Error(), functionCallerInfo.UserControllerRegisterByEmail, userRequestParse)
+		uc.logger.Error(ctx.UserContext(), "userController.RegisterByEmail failed", "error", err, "request", userRequestParse)
 		return ctx.Status(int(err.(exceptions.ErrorResponse).StatusCode)).
 			JSON(err)
 	}
@@ -90,13 +93,13 @@ func (uc *UserController) RegisterByPhone(ctx *fiber.Ctx) error {
 	userRequestParse := request.AuthByPhoneRequest{}
 
 	if err := ctx.BodyParser(&userRequestParse); err != nil {
-		uc.logger.Error(err.Error(), functionCallerInfo.UserControllerRegisterByPhone)
+		uc.logger.Error(ctx.UserContext(), "userController.RegisterByPhone failed", "error", err)
 		return ctx.Status(http.StatusBadRequest).JSON(exceptions.ErrBadRequest(err.Error()))
 	}
 
 	response, err := uc.userService.RegisterByPhone(ctx.Context(), userRequestParse)
 	if err != nil {
-		uc.logger.Error(err.Error(), functionCallerInfo.UserControllerRegisterByPhone, userRequestParse)
+		uc.logger.Error(ctx.UserContext(), "userController.RegisterByPhone failed", "error", err, "request", userRequestParse)
 		return ctx.Status(int(err.(exceptions.ErrorResponse).StatusCode)).
 			JSON(err)
 	}
@@ -115,19 +118,24 @@ func (uc *UserController) RegisterByPhone(ctx *fiber.Ctx) error {
 // @Success 200 {object} response.AuthResponse "success response"
 // @Failure 400 {object} map[string]interface{} "bad request"
 // @Failure 500 {object} map[string]interface{} "internal server error"
+// @Failure 429 {object} map[string]interface{} "too many failed attempts, see Retry-After"
 // @Router /v1/login/email [post]
 func (uc *UserController) LoginByEmail(ctx *fiber.Ctx) error {
 	userRequestParse := request.AuthByEmailRequest{}
 
 	if err := ctx.BodyParser(&userRequestParse); err != nil {
-		uc.logger.Error(err.Error(), functionCallerInfo.UserControllerLoginByEmail)
+		uc.logger.Error(ctx.UserContext(), "userController.LoginByEmail failed", "error", err)
 		return ctx.Status(http.StatusBadRequest).JSON(exceptions.ErrBadRequest(err.Error()))
 	}
 
-	response, err := uc.userService.LoginByEmail(ctx.Context(), userRequestParse)
+	response, err := uc.userService.LoginByEmail(ctx.Context(), userRequestParse, ctx.IP())
 	if err != nil {
-		uc.logger.Error(err.Error(), functionCallerInfo.UserControllerLoginByEmail, userRequestParse)
-		return ctx.Status(int(err.(exceptions.ErrorResponse).StatusCode)).JSON(err)
+		uc.logger.Error(ctx.UserContext(), "userController.LoginByEmail failed", "error", err, "request", userRequestParse)
+		errResponse := err.(exceptions.ErrorResponse)
+		if errResponse.RetryAfter > 0 {
+			ctx.Set(fiber.HeaderRetryAfter, strconv.Itoa(errResponse.RetryAfter))
+		}
+		return ctx.Status(int(errResponse.StatusCode)).JSON(err)
 	}
 
 	ctx.Set(helper.X_AUTHOR_HEADER_KEY, helper.X_AUTHOR_HEADER_VALUE)
@@ -144,19 +152,24 @@ func (uc *UserController) LoginByEmail(ctx *fiber.Ctx) error {
 // @Success 200 {object} response.AuthResponse "success response"
 // @Failure 400 {object} map[string]interface{} "bad request"
 // @Failure 500 {object} map[string]interface{} "internal server error"
+// @Failure 429 {object} map[string]interface{} "too many failed attempts, see Retry-After"
 // @Router /v1/login/phone [post]
 func (uc *UserController) LoginByPhone(c *fiber.Ctx) error {
 	userRequestParse := request.AuthByPhoneRequest{}
 
 	if err := c.BodyParser(&userRequestParse); err != nil {
-		uc.logger.Error(err.Error(), functionCallerInfo.UserControllerRegisterByPhone)
+		uc.logger.Error(c.UserContext(), "userController.LoginByPhone failed", "error", err)
 		return c.Status(http.StatusBadRequest).JSON(exceptions.ErrBadRequest(err.Error()))
 	}
 
-	response, err := uc.userService.LoginByPhone(c.Context(), userRequestParse)
+	response, err := uc.userService.LoginByPhone(c.Context(), userRequestParse, c.IP())
 	if err != nil {
-		uc.logger.Error(err.Error(), functionCallerInfo.UserControllerLoginByPhone, userRequestParse)
-		return c.Status(int(err.(exceptions.ErrorResponse).StatusCode)).JSON(err)
+		uc.logger.Error(c.UserContext(), "userController.LoginByPhone failed", "error", err, "request", userRequestParse)
+		errResponse := err.(exceptions.ErrorResponse)
+		if errResponse.RetryAfter > 0 {
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(errResponse.RetryAfter))
+		}
+		return c.Status(int(errResponse.StatusCode)).JSON(err)
 	}
 
 	c.Set(helper.X_AUTHOR_HEADER_KEY, helper.X_AUTHOR_HEADER_VALUE)
@@ -174,8 +187,10 @@ func (uc *UserController) LoginByPhone(c *fiber.Ctx) error {
 // @Accept json
 // @Produce json
 // @Param request body request.LinkEmailRequest true "Payload"
-// @Success 200 {object} response.UserResponse "success response"
+// @Success 202 {object} response.VerificationResponse "code sent, verify it to link"
 // @Failure 400 {object} map[string]interface{} "bad request"
+// @Failure 409 {object} map[string]interface{} "already used by another user"
+// @Failure 429 {object} map[string]interface{} "code sent recently, see Retry-After"
 // @Failure 500 {object} map[string]interface{} "internal server error"
 // @Router /v1/user/link/email [post]
 func (uc *UserController) LinkEmail(ctx *fiber.Ctx) error {
@@ -187,14 +202,58 @@ func (uc *UserController) LinkEmail(ctx *fiber.Ctx) error {
 	userRequestParse := request.LinkEmailRequest{}
 
 	if err := ctx.BodyParser(&userRequestParse); err != nil {
-		uc.logger.Error(err.Error(), functionCallerInfo.UserControllerLinkEmail)
+		uc.logger.Error(ctx.UserContext(), "userController.LinkEmail failed", "error", err)
 		return ctx.Status(http.StatusBadRequest).JSON(exceptions.ErrBadRequest(err.Error()))
 	}
 
 	response, err := uc.userService.LinkEmail(ctx.Context(), userRequestParse, userId)
 	if err != nil {
-		uc.logger.Error(err.Error(), functionCallerInfo.UserControllerLinkEmail, userRequestParse)
-		return ctx.Status(int(err.(exceptions.ErrorResponse).StatusCode)).JSON(err)
+		uc.logger.Error(ctx.UserContext(), "userController.LinkEmail failed", "error", err, "request", userRequestParse)
+		errResponse := err.(exceptions.ErrorResponse)
+		if errResponse.RetryAfter > 0 {
+			ctx.Set(fiber.HeaderRetryAfter, strconv.Itoa(errResponse.RetryAfter))
+		}
+		return ctx.Status(int(errResponse.StatusCode)).JSON(err)
+	}
+
+	ctx.Set(helper.X_AUTHOR_HEADER_KEY, helper.X_AUTHOR_HEADER_VALUE)
+	return ctx.Status(fiber.StatusAccepted).JSON(response)
+}
+
+// UserProfile godoc
+// @Summary
+// @Description
+// @Tags UserProfile
+// @Accept json
+// @Produce json
+// @Param request body request.VerifyCodeRequest true "Payload"
+// @Success 200 {object} response.UserResponse "success response"
+// @Failure 400 {object} map[string]interface{} "bad request"
+// @Failure 409 {object} map[string]interface{} "already used by another user"
+// @Failure 429 {object} map[string]interface{} "too many wrong codes"
+// @Failure 500 {object} map[string]interface{} "internal server error"
+// @Router /v1/user/verify/email [post]
+func (uc *UserController) VerifyEmail(ctx *fiber.Ctx) error {
+	userId, ok := ctx.Locals("userId").(string)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(exceptions.ErrUnauthorized)
+	}
+
+	userRequestParse := request.VerifyCodeRequest{}
+
+	if err := ctx.BodyParser(&userRequestParse); err != nil {
+		uc.logger.Error(ctx.UserContext(), "userController.VerifyEmail failed", "error", err)
+		return ctx.Status(http.StatusBadRequest).JSON(exceptions.ErrBadRequest(err.Error()))
+	}
+
+	response, err := uc.userService.VerifyEmail(ctx.Context(), userRequestParse, userId)
+	if err != nil {
+		uc.logger.Error(ctx.UserContext(), "userController.VerifyEmail failed", "error", err)
+		errResponse := err.(exceptions.ErrorResponse)
+		if errResponse.RetryAfter > 0 {
+			ctx.Set(fiber.HeaderRetryAfter, strconv.Itoa(errResponse.RetryAfter))
+		}
+		return ctx.Status(int(errResponse.StatusCode)).JSON(err)
 	}
 
 	ctx.Set(helper.X_AUTHOR_HEADER_KEY, helper.X_AUTHOR_HEADER_VALUE)
@@ -208,8 +267,10 @@ func (uc *UserController) LinkEmail(ctx *fiber.Ctx) error {
 // @Accept json
 // @Produce json
 // @Param request body request.LinkPhoneRequest true "Payload"
-// @Success 200 {object} response.UserResponse "success response"
+// @Success 202 {object} response.VerificationResponse "code sent, verify it to link"
 // @Failure 400 {object} map[string]interface{} "bad request"
+// @Failure 409 {object} map[string]interface{} "already used by another user"
+// @Failure 429 {object} map[string]interface{} "code sent recently, see Retry-After"
 // @Failure 500 {object} map[string]interface{} "internal server error"
 // @Router /v1/user/link/phone [post]
 func (uc *UserController) LinkPhone(ctx *fiber.Ctx) error {
@@ -221,14 +282,58 @@ func (uc *UserController) LinkPhone(ctx *fiber.Ctx) error {
 	userRequestParse := request.LinkPhoneRequest{}
 
 	if err := ctx.BodyParser(&userRequestParse); err != nil {
-		uc.logger.Error(err.Error(), functionCallerInfo.UserControllerLinkPhone)
+		uc.logger.Error(ctx.UserContext(), "userController.LinkPhone failed", "error", err)
 		return ctx.Status(http.StatusBadRequest).JSON(exceptions.ErrBadRequest(err.Error()))
 	}
 
 	response, err := uc.userService.LinkPhone(ctx.Context(), userRequestParse, userId)
 	if err != nil {
-		uc.logger.Error(err.Error(), functionCallerInfo.UserControllerLinkPhone, userRequestParse)
-		return ctx.Status(int(err.(exceptions.ErrorResponse).StatusCode)).JSON(err)
+		uc.logger.Error(ctx.UserContext(), "userController.LinkPhone failed", "error", err, "request", userRequestParse)
+		errResponse := err.(exceptions.ErrorResponse)
+		if errResponse.RetryAfter > 0 {
+			ctx.Set(fiber.HeaderRetryAfter, strconv.Itoa(errResponse.RetryAfter))
+		}
+		return ctx.Status(int(errResponse.StatusCode)).JSON(err)
+	}
+
+	ctx.Set(helper.X_AUTHOR_HEADER_KEY, helper.X_AUTHOR_HEADER_VALUE)
+	return ctx.Status(fiber.StatusAccepted).JSON(response)
+}
+
+// UserProfile godoc
+// @Summary
+// @Description
+// @Tags UserProfile
+// @Accept json
+// @Produce json
+// @Param request body request.VerifyCodeRequest true "Payload"
+// @Success 200 {object} response.UserResponse "success response"
+// @Failure 400 {object} map[string]interface{} "bad request"
+// @Failure 409 {object} map[string]interface{} "already used by another user"
+// @Failure 429 {object} map[string]interface{} "too many wrong codes"
+// @Failure 500 {object} map[string]interface{} "internal server error"
+// @Router /v1/user/verify/phone [post]
+func (uc *UserController) VerifyPhone(ctx *fiber.Ctx) error {
+	userId, ok := ctx.Locals("userId").(string)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(exceptions.ErrUnauthorized)
+	}
+
+	userRequestParse := request.VerifyCodeRequest{}
+
+	if err := ctx.BodyParser(&userRequestParse); err != nil {
+		uc.logger.Error(ctx.UserContext(), "userController.VerifyPhone failed", "error", err)
+		return ctx.Status(http.StatusBadRequest).JSON(exceptions.ErrBadRequest(err.Error()))
+	}
+
+	response, err := uc.userService.VerifyPhone(ctx.Context(), userRequestParse, userId)
+	if err != nil {
+		uc.logger.Error(ctx.UserContext(), "userController.VerifyPhone failed", "error", err)
+		errResponse := err.(exceptions.ErrorResponse)
+		if errResponse.RetryAfter > 0 {
+			ctx.Set(fiber.HeaderRetryAfter, strconv.Itoa(errResponse.RetryAfter))
+		}
+		return ctx.Status(int(errResponse.StatusCode)).JSON(err)
 	}
 
 	ctx.Set(helper.X_AUTHOR_HEADER_KEY, helper.X_AUTHOR_HEADER_VALUE)
@@ -253,7 +358,30 @@ func (uc *UserController) GetUserProfile(ctx *fiber.Ctx) error {
 
 	response, err := uc.userService.GetUserProfile(ctx.Context(), userId)
 	if err != nil {
-		uc.logger.Error(err.Error(), functionCallerInfo.UserControllerGetUserProfile)
+		uc.logger.Error(ctx.UserContext(), "userController.GetUserProfile failed", "error", err)
+		return ctx.Status(int(err.(exceptions.ErrorResponse).StatusCode)).JSON(err)
+	}
+
+	ctx.Set(helper.X_AUTHOR_HEADER_KEY, helper.X_AUTHOR_HEADER_VALUE)
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+// UserProfile godoc
+// @Summary Public profile of a seller
+// @Description Contact and bank details are never part of this response
+// @Tags UserProfile
+// @Produce json
+// @Param id path string true "Seller user id"
+// @Success 200 {object} response.SellerProfileResponse "success response"
+// @Failure 404 {object} map[string]interface{} "not found"
+// @Failure 500 {object} map[string]interface{} "internal server error"
+// @Router /v1/seller/{id} [get]
+func (uc *UserController) GetSellerProfile(ctx *fiber.Ctx) error {
+	sellerId := ctx.Params("id")
+
+	response, err := uc.userService.GetSellerProfile(ctx.Context(), sellerId)
+	if err != nil {
+		uc.logger.Error(ctx.UserContext(), "userController.GetSellerProfile failed", "error", err, "sellerId", sellerId)
 		return ctx.Status(int(err.(exceptions.ErrorResponse).StatusCode)).JSON(err)
 	}
 
@@ -281,13 +409,13 @@ func (uc *UserController) UpdateUserProfile(ctx *fiber.Ctx) error {
 	userRequestParse := request.UpdateUserProfileRequest{}
 
 	if err := ctx.BodyParser(&userRequestParse); err != nil {
-		uc.logger.Error(err.Error(), functionCallerInfo.UserControllerUpdateUserProfile)
+		uc.logger.Error(ctx.UserContext(), "userController.UpdateUserProfile failed", "error", err)
 		return ctx.Status(http.StatusBadRequest).JSON(exceptions.ErrBadRequest(err.Error()))
 	}
 
 	response, err := uc.userService.UpdateUserProfile(ctx.Context(), userRequestParse, userId)
 	if err != nil {
-		uc.logger.Error(err.Error(), functionCallerInfo.UserControllerUpdateUserProfile)
+		uc.logger.Error(ctx.UserContext(), "userController.UpdateUserProfile failed", "error", err)
 		return ctx.Status(int(err.(exceptions.ErrorResponse).StatusCode)).JSON(err)
 	}
 
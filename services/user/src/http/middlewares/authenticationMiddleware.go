@@ -1,65 +1,31 @@
 package middlewares
 
 import (
-	"errors"
-	"os"
-	"strings"
+	"sync"
 
-	response "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/model/web"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/auth"
+	authJwt "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/auth/jwt"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/cache"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/di"
 	"github.com/gofiber/fiber/v2"
-	"github.com/golang-jwt/jwt/v5"
+	"github.com/samber/do/v2"
 )
 
-func AuthMiddleware(c *fiber.Ctx) error {
-	authorizationHeader := c.Get("Authorization")
-	if !strings.Contains(authorizationHeader, "Bearer") && !strings.Contains(authorizationHeader, "bearer") {
-		return c.Status(fiber.StatusUnauthorized).JSON(response.Web{
-			Message: "UNAUTHORIZED",
-			Data:    "Token Invalid",
-		})
-	}
-
-	bearerToken := ""
-	if strings.Contains(authorizationHeader, "Bearer") {
-		bearerToken = strings.Replace(authorizationHeader, "Bearer ", "", -1)
-	}
-	if strings.Contains(authorizationHeader, "bearer") {
-		bearerToken = strings.Replace(authorizationHeader, "bearer ", "", -1)
-	}
-
-	token, err := jwt.Parse(bearerToken, CheckTokenJWT)
-	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(response.Web{
-			Message: "UNAUTHORIZED",
-			Data:    "Token Invalid",
-		})
-	}
-
-	if !token.Valid {
-		return c.Status(fiber.StatusUnauthorized).JSON(response.Web{
-			Message: "UNAUTHORIZED",
-			Data:    "Token Invalid",
-		})
-	}
+var (
+	authHandlerOnce sync.Once
+	authHandler     fiber.Handler
+)
 
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok {
-		return c.Status(fiber.StatusUnauthorized).JSON(response.Web{
-			Message: "UNAUTHORIZED",
-			Data:    "Token Invalid",
+// AuthMiddleware verifies tokens with this service's own keys, the handler is
+// built on first use because routes are registered before the injector is ready
+func AuthMiddleware(c *fiber.Ctx) error {
+	authHandlerOnce.Do(func() {
+		cacheClient := do.MustInvoke[cache.RedisCacheClient](di.Injector)
+		authHandler = auth.New(auth.Config{
+			Keys:        do.MustInvoke[authJwt.JwtServiceInterface](di.Injector),
+			Revocations: auth.RevocationCheckerFunc(cacheClient.IsTokenRevoked),
 		})
-	}
-
-	userId := claims["userId"].(string)
-	c.Locals("userId", userId)
-
-	return c.Next()
-}
-
-func CheckTokenJWT(t *jwt.Token) (interface{}, error) {
-	if t.Method.Alg() != jwt.SigningMethodHS256.Alg() {
-		return nil, errors.New("invalid token signing method")
-	}
+	})
 
-	return []byte(os.Getenv("JWT_SECRET_KEY")), nil
+	return authHandler(c)
 }
@@ -11,6 +11,10 @@ import (
 
 func CacheMiddleware() func(*fiber.Ctx) error {
 	return cache.New(cache.Config{
+		// Keyed by path only, so responses for a logged in user must never be shared
+		Next: func(c *fiber.Ctx) bool {
+			return c.Get(fiber.HeaderAuthorization) != ""
+		},
 		ExpirationGenerator: func(c *fiber.Ctx, cfg *cache.Config) time.Duration {
 			newCacheTime, _ := strconv.Atoi(c.GetRespHeader("Cache-Time", "600"))
 			return time.Second * time.Duration(newCacheTime)
@@ -0,0 +1,12 @@
+package accountroutes
+
+import (
+	accountController "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/http/controllers/account"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/http/middlewares"
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetRouteAccount(router fiber.Router, ac accountController.AccountControllerInterface) {
+	router.Delete("/user", middlewares.AuthMiddleware, ac.DeleteAccount)
+	router.Get("/user/export", middlewares.AuthMiddleware, ac.Export)
+}
@@ -0,0 +1,20 @@
+package adminroutes
+
+import (
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/auth"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/logging"
+	adminController "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/http/controllers/admin"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/http/middlewares"
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetRouteAdmin(router fiber.Router, ac adminController.AdminControllerInterface, logger *logging.Logger) {
+	admin := router.Group("/admin", middlewares.AuthMiddleware, auth.RequireRole(auth.RoleAdmin))
+
+	admin.Get("/users/:id/roles", ac.GetRoles)
+	admin.Put("/users/:id/roles/:role", ac.GrantRole)
+	admin.Delete("/users/:id/roles/:role", ac.RevokeRole)
+
+	// GET and PUT /admin/log-level of this instance
+	logger.RegisterLevel(admin)
+}
@@ -0,0 +1,13 @@
+package authroutes
+
+import (
+	authController "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/http/controllers/auth"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/http/middlewares"
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetRouteAuth(router fiber.Router, ac authController.AuthControllerInterface) {
+	router.Post("/auth/refresh", ac.Refresh)
+	router.Post("/auth/logout", middlewares.AuthMiddleware, ac.Logout)
+	router.Get("/auth/jwks", ac.Jwks)
+}
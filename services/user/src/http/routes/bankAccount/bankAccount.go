@@ -0,0 +1,14 @@
+package bankaccountroutes
+
+import (
+	bankAccountController "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/http/controllers/bankAccount"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/http/middlewares"
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetRouteBankAccounts(router fiber.Router, bc bankAccountController.BankAccountControllerInterface) {
+	router.Post("/user/bank-accounts", middlewares.AuthMiddleware, bc.CreateBankAccount)
+	router.Get("/user/bank-accounts", middlewares.AuthMiddleware, bc.GetBankAccounts)
+	router.Delete("/user/bank-accounts/:id", middlewares.AuthMiddleware, bc.DeleteBankAccount)
+	router.Put("/user/bank-accounts/:id/default", middlewares.AuthMiddleware, bc.SetDefaultBankAccount)
+}
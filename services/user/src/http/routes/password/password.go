@@ -0,0 +1,13 @@
+package passwordroutes
+
+import (
+	passwordController "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/http/controllers/password"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/http/middlewares"
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetRoutePassword(router fiber.Router, pc passwordController.PasswordControllerInterface) {
+	router.Put("/user/password", middlewares.AuthMiddleware, pc.ChangePassword)
+	router.Post("/password/forgot", pc.ForgotPassword)
+	router.Post("/password/reset", pc.ResetPassword)
+}
@@ -14,6 +14,9 @@ func SetRouteUsers(router fiber.Router, uc userController.UserControllerInterfac
 
 	router.Post("/user/link/email", middlewares.AuthMiddleware, uc.LinkEmail)
 	router.Post("/user/link/phone", middlewares.AuthMiddleware, uc.LinkPhone)
+	router.Post("/user/verify/email", middlewares.AuthMiddleware, uc.VerifyEmail)
+	router.Post("/user/verify/phone", middlewares.AuthMiddleware, uc.VerifyPhone)
 	router.Get("/user", middlewares.AuthMiddleware, uc.GetUserProfile)
+	router.Get("/seller/:id", uc.GetSellerProfile)
 	router.Put("/user", middlewares.AuthMiddleware, uc.UpdateUserProfile)
 }
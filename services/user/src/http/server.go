@@ -1,41 +1,60 @@
 package httpServer
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
 
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/health"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/logging"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/metrics"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/tracing"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/config"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/di"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/helper"
+	accountController "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/http/controllers/account"
+	adminController "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/http/controllers/admin"
 	swaggerRoutes "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/http/controllers/apiDocumentation"
+	authController "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/http/controllers/auth"
+	bankAccountController "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/http/controllers/bankAccount"
+	passwordController "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/http/controllers/password"
 	userController "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/http/controllers/user"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/http/middlewares"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/http/routes"
+	accountroutes "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/http/routes/account"
+	adminroutes "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/http/routes/admin"
+	authroutes "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/http/routes/auth"
+	bankaccountroutes "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/http/routes/bankAccount"
+	passwordroutes "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/http/routes/password"
 	userroutes "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/http/routes/user"
-	loggerZap "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/logger/zap"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/model/dtos/response"
 	"github.com/bytedance/sonic"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/fiber/v2/middleware/monitor"
 	"github.com/gofiber/fiber/v2/middleware/recover"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
 	"github.com/samber/do/v2"
 )
 
 type ServerInterface interface {
-	Listen()
+	Listen() error
+	// Stops accepting connections and waits for the running requests until ctx is done
+	Shutdown(ctx context.Context) error
 }
 
-type HttpServer struct{}
+type HttpServer struct {
+	app *fiber.App
+}
 
-func (s *HttpServer) Listen() {
+func NewHttpServer() *HttpServer {
 	fmt.Printf("New Fiber\n")
-	_logger := do.MustInvoke[loggerZap.LoggerInterface](di.Injector)
+	_logger := do.MustInvoke[*logging.Logger](di.Injector)
 	app := fiber.New(fiber.Config{
 		ServerHeader: helper.X_AUTHOR_HEADER_VALUE,
 		ErrorHandler: func(c *fiber.Ctx, err error) error {
-			_logger.Error(err.Error(), "Global HandlerError")
+			_logger.Error(c.UserContext(), "unhandled error", "error", err, "path", c.Path())
 			return c.Status(fiber.StatusBadRequest).JSON(response.GlobalErrorHandlerResp{
 				Success: false,
 				Message: err.Error(),
@@ -43,9 +62,27 @@ func (s *HttpServer) Listen() {
 		},
 		JSONEncoder: sonic.Marshal,
 		JSONDecoder: sonic.Unmarshal,
+		// Client ip of the login attempt limits, taken from the proxy header only for trusted proxies
+		ProxyHeader:             config.GetProxyHeader(),
+		EnableTrustedProxyCheck: true,
+		TrustedProxies:          config.GetTrustedProxies(),
+		EnableIPValidation:      true,
 	})
 
 	app.Use(recover.New())
+	// Forwarded to other services as gRPC metadata, see middleware/grpcmw
+	app.Use(requestid.New())
+	// and logged with every entry of the request, see middleware/logging
+	app.Use(logging.Fiber())
+	// Span per request, cache hits included, see middleware/tracing
+	app.Use(tracing.Fiber())
+	//? Health routes go before the cache so readiness is never served stale
+	healthChecker := do.MustInvoke[*health.Checker](di.Injector)
+	healthChecker.Register(app)
+	//? Metrics too, and the middleware counts cached responses as well, see middleware/metrics
+	m := do.MustInvoke[*metrics.Metrics](di.Injector)
+	m.Register(app)
+	app.Use(m.Fiber())
 	app.Use(middlewares.CacheMiddleware())
 
 	if strings.ToUpper(config.MODE) == config.MODE_DEBUG {
@@ -67,11 +104,34 @@ func (s *HttpServer) Listen() {
 	//? Dependency Injection
 	//? UserController
 	uc := do.MustInvoke[userController.UserControllerInterface](di.Injector)
+	//? AuthController
+	ac := do.MustInvoke[authController.AuthControllerInterface](di.Injector)
+	//? PasswordController
+	pc := do.MustInvoke[passwordController.PasswordControllerInterface](di.Injector)
+	//? BankAccountController
+	bc := do.MustInvoke[bankAccountController.BankAccountControllerInterface](di.Injector)
+	//? AccountController
+	acc := do.MustInvoke[accountController.AccountControllerInterface](di.Injector)
+	//? AdminController
+	adc := do.MustInvoke[adminController.AdminControllerInterface](di.Injector)
 
 	routes := routes.SetRoutes(app)
 	swaggerRoutes.SetRouteSwagger(routes)
 	userroutes.SetRouteUsers(routes, uc)
+	authroutes.SetRouteAuth(routes, ac)
+	passwordroutes.SetRoutePassword(routes, pc)
+	bankaccountroutes.SetRouteBankAccounts(routes, bc)
+	accountroutes.SetRouteAccount(routes, acc)
+	adminroutes.SetRouteAdmin(routes, adc, _logger)
+
+	return &HttpServer{app: app}
+}
 
+func (s *HttpServer) Listen() error {
 	fmt.Printf("Start Listener\n")
-	app.Listen(fmt.Sprintf("%s:%s", "0.0.0.0", config.GetPort()))
+	return s.app.Listen(fmt.Sprintf("%s:%s", "0.0.0.0", config.GetPort()))
+}
+
+func (s *HttpServer) Shutdown(ctx context.Context) error {
+	return s.app.ShutdownWithContext(ctx)
 }
@@ -0,0 +1,18 @@
+package serviceMetrics
+
+import (
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/metrics"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/samber/do/v2"
+)
+
+// RED metrics of the http and gRPC servers, "user:" cache hits and the stats of the postgres pool
+func NewMetricsInject(i do.Injector) (*metrics.Metrics, error) {
+	_db := do.MustInvoke[*pgxpool.Pool](i)
+
+	m := metrics.New("user")
+	if err := m.RegisterPgxPool(_db); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
@@ -0,0 +1,17 @@
+package repository
+
+import "time"
+
+const (
+	LoginLockoutScopeIdentifier = "identifier"
+	LoginLockoutScopeIp         = "ip"
+)
+
+type LoginLockout struct {
+	Scope          string
+	Identifier     string
+	IpAddress      string
+	FailedAttempts int64
+	LockoutLevel   int64
+	LockedUntil    time.Time
+}
@@ -0,0 +1,13 @@
+package repository
+
+import "time"
+
+type RefreshToken struct {
+	Id         string
+	UserId     string
+	TokenHash  string
+	FamilyId   string
+	ExpiresAt  time.Time
+	RevokedAt  *time.Time
+	ReplacedBy *string
+}
@@ -1,5 +1,7 @@
 package repository
 
+import "time"
+
 type AuthByEmail struct {
 	UserId       string
 	HashPassword string
@@ -15,19 +17,43 @@ type AuthByPhone struct {
 type User struct {
 	Email             *string
 	Phone             *string
+	EmailVerified     bool
+	PhoneVerified     bool
+	DisplayName       *string
 	FileId            *string
 	FileUri           *string
 	FileThumbnailUri  *string
+	BankAccountId     *string
 	BankAccountName   *string
 	BankAccountHolder *string
 	BankAccountNumber *string
 }
 
 type UpdateUser struct {
+	DisplayName       *string
 	FileId            *string
 	FileUri           *string
 	FileThumbnailUri  *string
+	BankAccountId     *string
 	BankAccountName   *string
 	BankAccountHolder *string
 	BankAccountNumber *string
 }
+
+type SellerProfile struct {
+	UserId           string
+	DisplayName      *string
+	FileThumbnailUri *string
+	CreatedAt        time.Time
+}
+
+// Holder and number are sealed by the field cipher
+type BankAccount struct {
+	Id                string
+	UserId            string
+	BankAccountName   string
+	BankAccountHolder string
+	BankAccountNumber string
+	IsDefault         bool
+	CreatedAt         time.Time
+}
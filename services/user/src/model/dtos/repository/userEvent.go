@@ -0,0 +1,13 @@
+package repository
+
+import "time"
+
+// Pending UserDeleted event of one subscriber
+type UserDeletedOutbox struct {
+	Id         string
+	Subscriber string
+	UserId     string
+	DeletedAt  time.Time
+	// Includes the delivery the row was just claimed for
+	Attempts int
+}
@@ -18,9 +18,50 @@ type LinkPhoneRequest struct {
 	Phone string `json:"phone" validate:"required,e164"`
 }
 
+type VerifyCodeRequest struct {
+	Code string `json:"code" validate:"required,numeric,len=6"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"currentPassword" validate:"required"`
+	NewPassword     string `json:"newPassword" validate:"required,min=8,max=32,nefield=CurrentPassword"`
+}
+
+// Either the email or the phone of the account
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required_without=Phone,omitempty,email"`
+	Phone string `json:"phone" validate:"required_without=Email,omitempty,e164"`
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token" validate:"required"`
+	NewPassword string `json:"newPassword" validate:"required,min=8,max=32"`
+}
+
 type UpdateUserProfileRequest struct {
 	FileId            string `json:"fileId"`
+	DisplayName       string `json:"displayName" validate:"omitempty,min=2,max=64"`
 	BankAccountName   string `json:"bankAccountName" validate:"required,min=4,max=32"`
 	BankAccountHolder string `json:"bankAccountHolder" validate:"required,min=4,max=32"`
 	BankAccountNumber string `json:"bankAccountNumber" validate:"required,min=4,max=32"`
 }
+
+type CreateBankAccountRequest struct {
+	BankAccountName   string `json:"bankAccountName" validate:"required,min=4,max=32"`
+	BankAccountHolder string `json:"bankAccountHolder" validate:"required,min=4,max=32"`
+	BankAccountNumber string `json:"bankAccountNumber" validate:"required,min=4,max=32"`
+	// The first account of a user is always the default one
+	IsDefault bool `json:"isDefault"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refreshToken" validate:"required"`
+}
+
+type LogoutRequest struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
+type DeleteAccountRequest struct {
+	Password string `json:"password" validate:"required"`
+}
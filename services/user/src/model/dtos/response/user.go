@@ -1,31 +1,123 @@
 package response
 
-import "database/sql"
+import (
+	"database/sql"
+	"time"
+)
 
 type AuthResponse struct {
-	Email string `json:"email"`
-	Phone string `json:"phone"`
-	Token string `json:"token"`
+	Email        string `json:"email"`
+	Phone        string `json:"phone"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refreshToken"`
+}
+
+// Returned when a code was sent, the change is applied once the code is verified
+type VerificationResponse struct {
+	Target    string `json:"target"`
+	ExpiresIn int    `json:"expiresIn"`
+}
+
+type TokenResponse struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refreshToken"`
 }
 
 type UserResponse struct {
-	Email             string `json:"email"`
-	Phone             string `json:"phone"`
-	FileId            string `json:"fileId"`
-	FileUri           string `json:"fileUri"`
-	FileThumbnailUri  string `json:"fileThumbnailUri"`
+	Email            string `json:"email"`
+	Phone            string `json:"phone"`
+	EmailVerified    bool   `json:"emailVerified"`
+	PhoneVerified    bool   `json:"phoneVerified"`
+	DisplayName      string `json:"displayName"`
+	FileId           string `json:"fileId"`
+	FileUri          string `json:"fileUri"`
+	FileThumbnailUri string `json:"fileThumbnailUri"`
+	// Bank fields below belong to the default account
+	BankAccountId     string `json:"bankAccountId"`
 	BankAccountName   string `json:"bankAccountName"`
 	BankAccountHolder string `json:"bankAccountHolder"`
 	BankAccountNumber string `json:"bankAccountNumber"`
 }
 
+type RolesResponse struct {
+	UserId string   `json:"userId"`
+	Roles  []string `json:"roles"`
+}
+
+// Public part of a user, contact and bank details are deliberately left out
+type SellerProfileResponse struct {
+	UserId      string    `json:"userId"`
+	DisplayName string    `json:"displayName"`
+	AvatarUri   string    `json:"avatarUri"`
+	JoinedAt    time.Time `json:"joinedAt"`
+	// nil when the product service could not be reached
+	ListingCount *int64 `json:"listingCount"`
+}
+
+type BankAccountResponse struct {
+	BankAccountId     string    `json:"bankAccountId"`
+	BankAccountName   string    `json:"bankAccountName"`
+	BankAccountHolder string    `json:"bankAccountHolder"`
+	BankAccountNumber string    `json:"bankAccountNumber"`
+	IsDefault         bool      `json:"isDefault"`
+	CreatedAt         time.Time `json:"createdAt"`
+}
+
+// Everything the user service holds about a user, products are streamed after it
+type AccountExport struct {
+	ExportedAt   time.Time             `json:"exportedAt"`
+	UserId       string                `json:"userId"`
+	Roles        []string              `json:"roles"`
+	Profile      UserResponse          `json:"profile"`
+	BankAccounts []BankAccountResponse `json:"bankAccounts"`
+}
+
+type ExportedProduct struct {
+	ProductId string                   `json:"productId"`
+	Name      string                   `json:"name"`
+	Sku       string                   `json:"sku"`
+	Qty       string                   `json:"qty"`
+	Price     string                   `json:"price"`
+	FileId    string                   `json:"fileId"`
+	Variants  []ExportedProductVariant `json:"variants"`
+}
+
+type ExportedProductVariant struct {
+	VariantId  string            `json:"variantId"`
+	Sku        string            `json:"sku"`
+	Qty        string            `json:"qty"`
+	Price      string            `json:"price"`
+	Attributes map[string]string `json:"attributes"`
+}
+
+// Role is buyer or seller, the sender fields are only set for the buyer
+// and a seller only gets their own items
+type ExportedPurchase struct {
+	PurchaseId          string                  `json:"purchaseId"`
+	Role                string                  `json:"role"`
+	SenderName          string                  `json:"senderName,omitempty"`
+	SenderContactType   string                  `json:"senderContactType,omitempty"`
+	SenderContactDetail string                  `json:"senderContactDetail,omitempty"`
+	Items               []ExportedPurchasedItem `json:"items"`
+	CreatedAt           string                  `json:"createdAt"`
+}
+
+type ExportedPurchasedItem struct {
+	ProductId string `json:"productId"`
+	VariantId string `json:"variantId,omitempty"`
+	Qty       int32  `json:"qty"`
+	SellerId  string `json:"sellerId"`
+}
+
 type UserWithIdResponse struct {
-	UserId            string `json:"userId"`
-	Email             string `json:"email"`
-	Phone             string `json:"phone"`
-	FileId            string `json:"fileId"`
-	FileUri           string `json:"fileUri"`
-	FileThumbnailUri  string `json:"fileThumbnailUri"`
+	UserId           string `json:"userId"`
+	Email            string `json:"email"`
+	Phone            string `json:"phone"`
+	FileId           string `json:"fileId"`
+	FileUri          string `json:"fileUri"`
+	FileThumbnailUri string `json:"fileThumbnailUri"`
+	// Bank fields below belong to the default account
+	BankAccountId     string `json:"bankAccountId"`
 	BankAccountName   string `json:"bankAccountName"`
 	BankAccountHolder string `json:"bankAccountHolder"`
 	BankAccountNumber string `json:"bankAccountNumber"`
@@ -49,6 +141,7 @@ type UserWithIdResponseSqlNulString struct {
 	FileId            sql.NullString `json:"fileId"`
 	FileUri           sql.NullString `json:"fileUri"`
 	FileThumbnailUri  sql.NullString `json:"fileThumbnailUri"`
+	BankAccountId     sql.NullString `json:"bankAccountId"`
 	BankAccountName   sql.NullString `json:"bankAccountName"`
 	BankAccountHolder sql.NullString `json:"bankAccountHolder"`
 	BankAccountNumber sql.NullString `json:"bankAccountNumber"`
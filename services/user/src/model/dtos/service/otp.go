@@ -0,0 +1,8 @@
+package service
+
+// Pending verification code, only the hash of the code is kept
+type Otp struct {
+	CodeHash string
+	Target   string
+	Attempts int64
+}
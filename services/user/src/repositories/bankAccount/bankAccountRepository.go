@@ -0,0 +1,290 @@
+package bankAccountRepository
+
+import (
+	"context"
+
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/model/dtos/repository"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/samber/do/v2"
+)
+
+type BankAccountRepositoryInterface interface {
+	// The account becomes the default one when asked to or when it is the first of the user
+	Create(ctx context.Context, pool *pgxpool.Pool, account repository.BankAccount) (repository.BankAccount, error)
+	CountByUserId(ctx context.Context, pool *pgxpool.Pool, userId string) (int, error)
+	ListByUserId(ctx context.Context, pool *pgxpool.Pool, userId string) ([]repository.BankAccount, error)
+	// pgx.ErrNoRows when the account does not exist or belongs to another user
+	GetById(ctx context.Context, pool *pgxpool.Pool, userId, bankAccountId string) (repository.BankAccount, error)
+	// Deleting the default account promotes the most recent remaining one
+	Delete(ctx context.Context, pool *pgxpool.Pool, userId, bankAccountId string) error
+	SetDefault(ctx context.Context, pool *pgxpool.Pool, userId, bankAccountId string) error
+
+	// Keyset pagination over all accounts, ordered by id
+	ListForRotation(ctx context.Context, pool *pgxpool.Pool, afterId string, limit int) ([]repository.BankAccount, error)
+	// Writes the new values only if the row still holds the ones in current
+	ReplaceSealedFields(ctx context.Context, pool *pgxpool.Pool, current repository.BankAccount, holder, number string) (bool, error)
+}
+
+type BankAccountRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewBankAccountRepository(db *pgxpool.Pool) BankAccountRepositoryInterface {
+	return &BankAccountRepository{
+		db: db,
+	}
+}
+
+func NewBankAccountRepositoryInject(i do.Injector) (BankAccountRepositoryInterface, error) {
+	return NewBankAccountRepository(
+		do.MustInvoke[*pgxpool.Pool](i),
+	), nil
+}
+
+func (br *BankAccountRepository) Create(ctx context.Context, pool *pgxpool.Pool, account repository.BankAccount) (repository.BankAccount, error) {
+	tx, err := pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return repository.BankAccount{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	if account.IsDefault {
+		_, err = tx.Exec(ctx, `UPDATE bank_accounts SET is_default = FALSE WHERE user_id = $1 AND is_default;`, account.UserId)
+		if err != nil {
+			return repository.BankAccount{}, err
+		}
+	}
+
+	query := `
+		INSERT INTO bank_accounts(id, user_id, bank_account_name, bank_account_holder, bank_account_number, is_default)
+		VALUES($1, $2, $3, $4, $5, $6 OR NOT EXISTS (SELECT 1 FROM bank_accounts WHERE user_id = $2))
+		RETURNING is_default, created_at;`
+
+	err = tx.QueryRow(
+		ctx,
+		query,
+		account.Id,
+		account.UserId,
+		account.BankAccountName,
+		account.BankAccountHolder,
+		account.BankAccountNumber,
+		account.IsDefault,
+	).Scan(&account.IsDefault, &account.CreatedAt)
+	if err != nil {
+		return repository.BankAccount{}, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return repository.BankAccount{}, err
+	}
+
+	return account, nil
+}
+
+func (br *BankAccountRepository) CountByUserId(ctx context.Context, pool *pgxpool.Pool, userId string) (int, error) {
+	query := `SELECT COUNT(*) FROM bank_accounts WHERE user_id = $1;`
+
+	var count int
+	err := pool.QueryRow(ctx, query, userId).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (br *BankAccountRepository) ListByUserId(ctx context.Context, pool *pgxpool.Pool, userId string) ([]repository.BankAccount, error) {
+	query := `
+		SELECT
+			id,
+			user_id,
+			bank_account_name,
+			bank_account_holder,
+			bank_account_number,
+			is_default,
+			created_at
+		FROM bank_accounts
+		WHERE user_id = $1
+		ORDER BY is_default DESC, created_at DESC;`
+
+	rows, err := pool.Query(ctx, query, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []repository.BankAccount{}
+	for rows.Next() {
+		account, err := scanBankAccount(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, account)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (br *BankAccountRepository) GetById(ctx context.Context, pool *pgxpool.Pool, userId, bankAccountId string) (repository.BankAccount, error) {
+	query := `
+		SELECT
+			id,
+			user_id,
+			bank_account_name,
+			bank_account_holder,
+			bank_account_number,
+			is_default,
+			created_at
+		FROM bank_accounts
+		WHERE id = $1 AND user_id = $2;`
+
+	return scanBankAccount(pool.QueryRow(ctx, query, bankAccountId, userId))
+}
+
+func (br *BankAccountRepository) Delete(ctx context.Context, pool *pgxpool.Pool, userId, bankAccountId string) error {
+	tx, err := pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var wasDefault bool
+	err = tx.QueryRow(
+		ctx,
+		`DELETE FROM bank_accounts WHERE id = $1 AND user_id = $2 RETURNING is_default;`,
+		bankAccountId,
+		userId,
+	).Scan(&wasDefault)
+	if err != nil {
+		return err
+	}
+
+	if wasDefault {
+		promoteQuery := `
+			UPDATE bank_accounts
+			SET is_default = TRUE
+			WHERE id = (
+				SELECT id FROM bank_accounts
+				WHERE user_id = $1
+				ORDER BY created_at DESC
+				LIMIT 1
+			);`
+
+		_, err = tx.Exec(ctx, promoteQuery, userId)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (br *BankAccountRepository) SetDefault(ctx context.Context, pool *pgxpool.Pool, userId, bankAccountId string) error {
+	tx, err := pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(
+		ctx,
+		`UPDATE bank_accounts SET is_default = FALSE WHERE user_id = $1 AND is_default AND id <> $2;`,
+		userId,
+		bankAccountId,
+	)
+	if err != nil {
+		return err
+	}
+
+	tag, err := tx.Exec(
+		ctx,
+		`UPDATE bank_accounts SET is_default = TRUE WHERE id = $1 AND user_id = $2;`,
+		bankAccountId,
+		userId,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (br *BankAccountRepository) ListForRotation(ctx context.Context, pool *pgxpool.Pool, afterId string, limit int) ([]repository.BankAccount, error) {
+	query := `
+		SELECT
+			id,
+			user_id,
+			bank_account_holder,
+			bank_account_number
+		FROM bank_accounts
+		WHERE id > $1
+		ORDER BY id
+		LIMIT $2;`
+
+	rows, err := pool.Query(ctx, query, afterId, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []repository.BankAccount
+	for rows.Next() {
+		var account repository.BankAccount
+		err := rows.Scan(&account.Id, &account.UserId, &account.BankAccountHolder, &account.BankAccountNumber)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, account)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (br *BankAccountRepository) ReplaceSealedFields(ctx context.Context, pool *pgxpool.Pool, current repository.BankAccount, holder, number string) (bool, error) {
+	query := `
+		UPDATE bank_accounts
+		SET
+			bank_account_holder = $1,
+			bank_account_number = $2
+		WHERE id = $3
+			AND bank_account_holder = $4
+			AND bank_account_number = $5;`
+
+	tag, err := pool.Exec(ctx, query, holder, number, current.Id, current.BankAccountHolder, current.BankAccountNumber)
+	if err != nil {
+		return false, err
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+func scanBankAccount(row pgx.Row) (repository.BankAccount, error) {
+	var account repository.BankAccount
+	err := row.Scan(
+		&account.Id,
+		&account.UserId,
+		&account.BankAccountName,
+		&account.BankAccountHolder,
+		&account.BankAccountNumber,
+		&account.IsDefault,
+		&account.CreatedAt,
+	)
+	if err != nil {
+		return repository.BankAccount{}, err
+	}
+
+	return account, nil
+}
@@ -0,0 +1,50 @@
+package loginLockoutRepository
+
+import (
+	"context"
+
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/model/dtos/repository"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/samber/do/v2"
+)
+
+// Append-only audit trail of login lockouts, the lock itself lives in Redis
+type LoginLockoutRepositoryInterface interface {
+	Create(ctx context.Context, pool *pgxpool.Pool, lockout repository.LoginLockout) error
+}
+
+type LoginLockoutRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewLoginLockoutRepository(db *pgxpool.Pool) LoginLockoutRepositoryInterface {
+	return &LoginLockoutRepository{
+		db: db,
+	}
+}
+
+func NewLoginLockoutRepositoryInject(i do.Injector) (LoginLockoutRepositoryInterface, error) {
+	return NewLoginLockoutRepository(
+		do.MustInvoke[*pgxpool.Pool](i),
+	), nil
+}
+
+func (lr *LoginLockoutRepository) Create(ctx context.Context, pool *pgxpool.Pool, lockout repository.LoginLockout) error {
+	query := `
+		INSERT INTO login_lockouts(scope, identifier, ip_address, failed_attempts, lockout_level, locked_until)
+		VALUES($1, NULLIF($2, ''), $3, $4, $5, $6);`
+
+	_, err := pool.Exec(ctx, query,
+		lockout.Scope,
+		lockout.Identifier,
+		lockout.IpAddress,
+		lockout.FailedAttempts,
+		lockout.LockoutLevel,
+		lockout.LockedUntil,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
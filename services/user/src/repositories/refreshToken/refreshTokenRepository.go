@@ -0,0 +1,143 @@
+package refreshTokenRepository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/model/dtos/repository"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/samber/do/v2"
+)
+
+// Returned by Rotate when the token was revoked or rotated by a concurrent request
+var ErrTokenAlreadyRevoked = errors.New("refresh token already revoked")
+
+type RefreshTokenRepositoryInterface interface {
+	Create(ctx context.Context, pool *pgxpool.Pool, token repository.RefreshToken) error
+	GetByHash(ctx context.Context, pool *pgxpool.Pool, tokenHash string) (repository.RefreshToken, error)
+	Rotate(ctx context.Context, pool *pgxpool.Pool, oldTokenId string, next repository.RefreshToken) error
+	RevokeFamily(ctx context.Context, pool *pgxpool.Pool, familyId string) error
+	RevokeAllByUserId(ctx context.Context, pool *pgxpool.Pool, userId string) error
+}
+
+type RefreshTokenRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewRefreshTokenRepository(db *pgxpool.Pool) RefreshTokenRepositoryInterface {
+	return &RefreshTokenRepository{
+		db: db,
+	}
+}
+
+func NewRefreshTokenRepositoryInject(i do.Injector) (RefreshTokenRepositoryInterface, error) {
+	return NewRefreshTokenRepository(
+		do.MustInvoke[*pgxpool.Pool](i),
+	), nil
+}
+
+func (rr *RefreshTokenRepository) Create(ctx context.Context, pool *pgxpool.Pool, token repository.RefreshToken) error {
+	query := `
+		INSERT INTO refresh_tokens(id, user_id, token_hash, family_id, expires_at)
+		VALUES($1, $2, $3, $4, $5);`
+
+	_, err := pool.Exec(ctx, query, token.Id, token.UserId, token.TokenHash, token.FamilyId, token.ExpiresAt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (rr *RefreshTokenRepository) GetByHash(ctx context.Context, pool *pgxpool.Pool, tokenHash string) (repository.RefreshToken, error) {
+	query := `
+		SELECT
+			id,
+			user_id,
+			token_hash,
+			family_id,
+			expires_at,
+			revoked_at,
+			replaced_by
+		FROM refresh_tokens
+		WHERE token_hash = $1;`
+
+	var token repository.RefreshToken
+	err := pool.QueryRow(ctx, query, tokenHash).Scan(
+		&token.Id,
+		&token.UserId,
+		&token.TokenHash,
+		&token.FamilyId,
+		&token.ExpiresAt,
+		&token.RevokedAt,
+		&token.ReplacedBy,
+	)
+	if err != nil {
+		return repository.RefreshToken{}, err
+	}
+
+	return token, nil
+}
+
+// Revokes the old token and stores its successor in one transaction,
+// so a refresh token can only ever be exchanged once
+func (rr *RefreshTokenRepository) Rotate(ctx context.Context, pool *pgxpool.Pool, oldTokenId string, next repository.RefreshToken) error {
+	tx, err := pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	revokeQuery := `
+		UPDATE refresh_tokens
+		SET revoked_at = CURRENT_TIMESTAMP, replaced_by = $2
+		WHERE id = $1 AND revoked_at IS NULL;`
+
+	tag, err := tx.Exec(ctx, revokeQuery, oldTokenId, next.Id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrTokenAlreadyRevoked
+	}
+
+	insertQuery := `
+		INSERT INTO refresh_tokens(id, user_id, token_hash, family_id, expires_at)
+		VALUES($1, $2, $3, $4, $5);`
+
+	_, err = tx.Exec(ctx, insertQuery, next.Id, next.UserId, next.TokenHash, next.FamilyId, next.ExpiresAt)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (rr *RefreshTokenRepository) RevokeFamily(ctx context.Context, pool *pgxpool.Pool, familyId string) error {
+	query := `
+		UPDATE refresh_tokens
+		SET revoked_at = CURRENT_TIMESTAMP
+		WHERE family_id = $1 AND revoked_at IS NULL;`
+
+	_, err := pool.Exec(ctx, query, familyId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (rr *RefreshTokenRepository) RevokeAllByUserId(ctx context.Context, pool *pgxpool.Pool, userId string) error {
+	query := `
+		UPDATE refresh_tokens
+		SET revoked_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1 AND revoked_at IS NULL;`
+
+	_, err := pool.Exec(ctx, query, userId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
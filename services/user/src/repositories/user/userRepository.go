@@ -2,9 +2,11 @@ package userRepository
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/model/dtos/repository"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/model/dtos/response"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/samber/do/v2"
 )
@@ -17,9 +19,24 @@ type UserRepositoryInterface interface {
 	UpdateEmail(ctx context.Context, pool *pgxpool.Pool, email, userId string) (user *repository.User, err error)
 	UpdatePhone(ctx context.Context, pool *pgxpool.Pool, phone, userId string) (user *repository.User, err error)
 	GetUserProfile(ctx context.Context, pool *pgxpool.Pool, userId string) (user *repository.User, err error)
+	GetSellerProfile(ctx context.Context, pool *pgxpool.Pool, userId string) (seller *repository.SellerProfile, err error)
+	GetRoles(ctx context.Context, pool *pgxpool.Pool, userId string) (roles []string, err error)
+	// Both return the roles after the change, granting a role the user holds is a no-op
+	GrantRole(ctx context.Context, pool *pgxpool.Pool, userId, role string) (roles []string, err error)
+	RevokeRole(ctx context.Context, pool *pgxpool.Pool, userId, role string) (roles []string, err error)
+	GetPasswordHash(ctx context.Context, pool *pgxpool.Pool, userId string) (passwordHash string, err error)
+	UpdatePassword(ctx context.Context, pool *pgxpool.Pool, userId, passwordHash string) error
+	// Clears the personal data of the user and deletes their bank accounts, the id is kept. A UserDeleted
+	// event is queued in the outbox for every subscriber within the same transaction.
+	// Returns pgx.ErrNoRows when the user does not exist or is already deleted
+	AnonymizeUser(ctx context.Context, pool *pgxpool.Pool, userId string, subscribers []string) error
+	// pgx.ErrNoRows when the user has no default bank account
+	GetDefaultBankAccountId(ctx context.Context, pool *pgxpool.Pool, userId string) (bankAccountId string, err error)
+	// Bank fields of the input replace the default bank account with the input id, an account with that id
+	// is created when the user has none
 	UpdateUserProfile(ctx context.Context, pool *pgxpool.Pool, input repository.UpdateUser, userId string) (*repository.User, error)
 
-	GetUserProfiles(ctx context.Context, pool *pgxpool.Pool, userId []string) (user []response.UserResponse, err error)
+	GetUserProfiles(ctx context.Context, pool *pgxpool.Pool, userId []string) (user []response.UserWithIdResponse, err error)
 	GetUserProfilesWithId(ctx context.Context, pool *pgxpool.Pool, userIds []string) (user []response.UserWithIdResponse, err error)
 }
 
@@ -99,26 +116,46 @@ func (ur *UserRepository) GetAuthByPhone(ctx context.Context, pool *pgxpool.Pool
 	return auth, nil
 }
 
+// Only called with a verified address, so the flag is set together with the email
 func (ur *UserRepository) UpdateEmail(ctx context.Context, pool *pgxpool.Pool, email, userId string) (*repository.User, error) {
 	query := `
-		UPDATE users 
-		SET email = $1 
-		WHERE id = $2 
-		RETURNING
-			phone,
-			fileId,
-			fileUri,
-			fileThumbnailUri,
-			bankAccountName,
-			bankAccountHolder,
-			bankAccountNumber;`
+		WITH updated AS (
+			UPDATE users 
+			SET email = $1, email_verified = TRUE
+			WHERE id = $2 
+			RETURNING
+				id,
+				phone,
+				phone_verified,
+				display_name,
+				fileId,
+				fileUri,
+				fileThumbnailUri
+		)
+		SELECT
+			updated.phone,
+			updated.phone_verified,
+			updated.display_name,
+			updated.fileId,
+			updated.fileUri,
+			updated.fileThumbnailUri,
+			b.id,
+			b.bank_account_name,
+			b.bank_account_holder,
+			b.bank_account_number
+		FROM updated
+		LEFT JOIN bank_accounts b ON b.user_id = updated.id AND b.is_default;`
 
 	var user repository.User
+	user.EmailVerified = true
 	err := pool.QueryRow(ctx, query, email, userId).Scan(
 		&user.Phone,
+		&user.PhoneVerified,
+		&user.DisplayName,
 		&user.FileId,
 		&user.FileUri,
 		&user.FileThumbnailUri,
+		&user.BankAccountId,
 		&user.BankAccountName,
 		&user.BankAccountHolder,
 		&user.BankAccountNumber,
@@ -130,26 +167,46 @@ func (ur *UserRepository) UpdateEmail(ctx context.Context, pool *pgxpool.Pool, e
 	return &user, nil
 }
 
+// Only called with a verified number, so the flag is set together with the phone
 func (ur *UserRepository) UpdatePhone(ctx context.Context, pool *pgxpool.Pool, phone, userId string) (*repository.User, error) {
 	query := `
-		UPDATE users 
-		SET phone = $1 
-		WHERE id = $2 
-		RETURNING
-			email,
-			fileId,
-			fileUri,
-			fileThumbnailUri,
-			bankAccountName,
-			bankAccountHolder,
-			bankAccountNumber;`
+		WITH updated AS (
+			UPDATE users 
+			SET phone = $1, phone_verified = TRUE
+			WHERE id = $2 
+			RETURNING
+				id,
+				email,
+				email_verified,
+				display_name,
+				fileId,
+				fileUri,
+				fileThumbnailUri
+		)
+		SELECT
+			updated.email,
+			updated.email_verified,
+			updated.display_name,
+			updated.fileId,
+			updated.fileUri,
+			updated.fileThumbnailUri,
+			b.id,
+			b.bank_account_name,
+			b.bank_account_holder,
+			b.bank_account_number
+		FROM updated
+		LEFT JOIN bank_accounts b ON b.user_id = updated.id AND b.is_default;`
 
 	var user repository.User
+	user.PhoneVerified = true
 	err := pool.QueryRow(ctx, query, phone, userId).Scan(
 		&user.Email,
+		&user.EmailVerified,
+		&user.DisplayName,
 		&user.FileId,
 		&user.FileUri,
 		&user.FileThumbnailUri,
+		&user.BankAccountId,
 		&user.BankAccountName,
 		&user.BankAccountHolder,
 		&user.BankAccountNumber,
@@ -166,22 +223,31 @@ func (ur *UserRepository) GetUserProfile(ctx context.Context, pool *pgxpool.Pool
 		SELECT 
 			email,
 			phone,
+			email_verified,
+			phone_verified,
+			display_name,
 			fileId,
 			fileUri,
 			fileThumbnailUri,
-			bankAccountName,
-			bankAccountHolder,
-			bankAccountNumber
+			b.id,
+			b.bank_account_name,
+			b.bank_account_holder,
+			b.bank_account_number
 		FROM users 
-		WHERE id = $1;`
+		LEFT JOIN bank_accounts b ON b.user_id = users.id AND b.is_default
+		WHERE users.id = $1;`
 
 	var user repository.User
 	err := pool.QueryRow(ctx, query, userId).Scan(
 		&user.Email,
 		&user.Phone,
+		&user.EmailVerified,
+		&user.PhoneVerified,
+		&user.DisplayName,
 		&user.FileId,
 		&user.FileUri,
 		&user.FileThumbnailUri,
+		&user.BankAccountId,
 		&user.BankAccountName,
 		&user.BankAccountHolder,
 		&user.BankAccountNumber,
@@ -193,64 +259,269 @@ func (ur *UserRepository) GetUserProfile(ctx context.Context, pool *pgxpool.Pool
 	return &user, nil
 }
 
+func (ur *UserRepository) GetSellerProfile(ctx context.Context, pool *pgxpool.Pool, userId string) (*repository.SellerProfile, error) {
+	query := `
+		SELECT
+			id,
+			display_name,
+			fileThumbnailUri,
+			created_at
+		FROM users
+		WHERE id = $1 AND deleted_at IS NULL;`
+
+	var seller repository.SellerProfile
+	err := pool.QueryRow(ctx, query, userId).Scan(
+		&seller.UserId,
+		&seller.DisplayName,
+		&seller.FileThumbnailUri,
+		&seller.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &seller, nil
+}
+
+func (ur *UserRepository) GetRoles(ctx context.Context, pool *pgxpool.Pool, userId string) ([]string, error) {
+	query := `SELECT roles FROM users WHERE id = $1;`
+
+	var roles []string
+	err := pool.QueryRow(ctx, query, userId).Scan(&roles)
+	if err != nil {
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+func (ur *UserRepository) GrantRole(ctx context.Context, pool *pgxpool.Pool, userId, role string) ([]string, error) {
+	query := `
+		UPDATE users
+		SET
+			roles = CASE WHEN $2 = ANY(roles) THEN roles ELSE array_append(roles, $2) END,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING roles;`
+
+	var roles []string
+	err := pool.QueryRow(ctx, query, userId, role).Scan(&roles)
+	if err != nil {
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+func (ur *UserRepository) RevokeRole(ctx context.Context, pool *pgxpool.Pool, userId, role string) ([]string, error) {
+	query := `
+		UPDATE users
+		SET
+			roles = array_remove(roles, $2),
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING roles;`
+
+	var roles []string
+	err := pool.QueryRow(ctx, query, userId, role).Scan(&roles)
+	if err != nil {
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+func (ur *UserRepository) GetPasswordHash(ctx context.Context, pool *pgxpool.Pool, userId string) (string, error) {
+	query := `SELECT password_hash FROM users WHERE id = $1;`
+
+	var passwordHash string
+	err := pool.QueryRow(ctx, query, userId).Scan(&passwordHash)
+	if err != nil {
+		return "", err
+	}
+
+	return passwordHash, nil
+}
+
+func (ur *UserRepository) GetDefaultBankAccountId(ctx context.Context, pool *pgxpool.Pool, userId string) (string, error) {
+	query := `SELECT id FROM bank_accounts WHERE user_id = $1 AND is_default;`
+
+	var bankAccountId string
+	err := pool.QueryRow(ctx, query, userId).Scan(&bankAccountId)
+	if err != nil {
+		return "", err
+	}
+
+	return bankAccountId, nil
+}
+
+func (ur *UserRepository) UpdatePassword(ctx context.Context, pool *pgxpool.Pool, userId, passwordHash string) error {
+	query := `
+		UPDATE users
+		SET password_hash = $1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2;`
+
+	tag, err := pool.Exec(ctx, query, passwordHash, userId)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+func (ur *UserRepository) AnonymizeUser(ctx context.Context, pool *pgxpool.Pool, userId string, subscribers []string) error {
+	tx, err := pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// An empty hash never matches, bcrypt rejects it as malformed
+	query := `
+		UPDATE users
+		SET
+			email = NULL,
+			phone = NULL,
+			email_verified = FALSE,
+			phone_verified = FALSE,
+			password_hash = '',
+			display_name = NULL,
+			fileId = NULL,
+			fileUri = NULL,
+			fileThumbnailUri = NULL,
+			roles = ARRAY['buyer']::TEXT[],
+			deleted_at = CURRENT_TIMESTAMP,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND deleted_at IS NULL;`
+
+	tag, err := tx.Exec(ctx, query, userId)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	_, err = tx.Exec(ctx, `DELETE FROM bank_accounts WHERE user_id = $1;`, userId)
+	if err != nil {
+		return err
+	}
+
+	// Delivered by userEventsService, the row stays until the subscriber acked it
+	query = `
+		INSERT INTO user_event_outbox(subscriber, user_id, deleted_at)
+		SELECT subscriber, id, deleted_at
+		FROM users, unnest($2::TEXT[]) AS subscriber
+		WHERE id = $1;`
+
+	_, err = tx.Exec(ctx, query, userId, subscribers)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
 func (ur *UserRepository) UpdateUserProfile(ctx context.Context, pool *pgxpool.Pool, input repository.UpdateUser, userId string) (*repository.User, error) {
 	// Target query:
 	// `UPDATE users
 	// 	SET
-	// 		bankAccountName = $1,
-	// 		bankAccountHolder = $2,
-	// 		bankAccountNumber = $3,
-	// 		fileId = $5,
-	// 		fileUri = $6,
-	// 		fileThumbnailUri = $7,
-	// 	WHERE id = $4
+	// 		updated_at = CURRENT_TIMESTAMP,
+	// 		fileId = $2,
+	// 		fileUri = $3,
+	// 		fileThumbnailUri = $4,
+	// 		display_name = $5 (or $2 without a file)
+	// 	WHERE id = $1
 	// 	RETURNING
 	// 		email,
 	// 		phone,
+	// 		email_verified,
+	// 		phone_verified,
+	// 		display_name,
 	//		fileId,
 	// 		fileUri,
 	// 		fileThumbnailUri;`
 
+	tx, err := pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return &repository.User{}, err
+	}
+	defer tx.Rollback(ctx)
+
 	query := `
 		UPDATE users 
 		SET
-			bankAccountName = $1,
-			bankAccountHolder = $2,
-			bankAccountNumber = $3`
+			updated_at = CURRENT_TIMESTAMP`
 
-	args := make([]interface{}, 4)
-	args[0] = input.BankAccountName
-	args[1] = input.BankAccountHolder
-	args[2] = input.BankAccountNumber
-	args[3] = userId
+	args := make([]interface{}, 1)
+	args[0] = userId
 
 	if input.FileId != nil {
-		query += `,
-			fileId = $5,
-			fileUri = $6,
-			fileThumbnailUri = $7`
+		query += fmt.Sprintf(`,
+			fileId = $%d,
+			fileUri = $%d,
+			fileThumbnailUri = $%d`, len(args)+1, len(args)+2, len(args)+3)
 		args = append(args, input.FileId, input.FileUri, input.FileThumbnailUri)
 	}
 
+	if input.DisplayName != nil {
+		query += fmt.Sprintf(`,
+			display_name = $%d`, len(args)+1)
+		args = append(args, input.DisplayName)
+	}
+
 	query += `
-		WHERE id = $4
+		WHERE id = $1
 		RETURNING
 			email,
 			phone,
+			email_verified,
+			phone_verified,
+			display_name,
 			fileId,
 			fileUri,
 			fileThumbnailUri;`
 
 	user := &repository.User{
+		BankAccountId:     input.BankAccountId,
 		BankAccountName:   input.BankAccountName,
 		BankAccountHolder: input.BankAccountHolder,
 		BankAccountNumber: input.BankAccountNumber,
 	}
-	err := pool.QueryRow(
+	err = tx.QueryRow(
 		ctx,
 		query,
 		args...,
-	).Scan(&user.Email, &user.Phone, &user.FileId, &user.FileUri, &user.FileThumbnailUri)
+	).Scan(&user.Email, &user.Phone, &user.EmailVerified, &user.PhoneVerified, &user.DisplayName, &user.FileId, &user.FileUri, &user.FileThumbnailUri)
+	if err != nil {
+		return &repository.User{}, err
+	}
+
+	updateBankQuery := `
+		UPDATE bank_accounts
+		SET
+			bank_account_name = $2,
+			bank_account_holder = $3,
+			bank_account_number = $4
+		WHERE user_id = $1 AND id = $5 AND is_default;`
+
+	tag, err := tx.Exec(ctx, updateBankQuery, userId, input.BankAccountName, input.BankAccountHolder, input.BankAccountNumber, input.BankAccountId)
+	if err == nil && tag.RowsAffected() == 0 {
+		// Another default account made meanwhile fails the unique index instead of being overwritten
+		insertBankQuery := `
+			INSERT INTO bank_accounts(id, user_id, bank_account_name, bank_account_holder, bank_account_number, is_default)
+			VALUES($5, $1, $2, $3, $4, TRUE);`
+
+		_, err = tx.Exec(ctx, insertBankQuery, userId, input.BankAccountName, input.BankAccountHolder, input.BankAccountNumber, input.BankAccountId)
+	}
+	if err != nil {
+		return &repository.User{}, err
+	}
+
+	err = tx.Commit(ctx)
 	if err != nil {
 		return &repository.User{}, err
 	}
@@ -261,25 +532,27 @@ func (ur *UserRepository) UpdateUserProfile(ctx context.Context, pool *pgxpool.P
 // Ambil semua profile user dengan array of userId
 //
 // Returns:
-//   - List<response.UserResponse>
+//   - List<response.UserWithIdResponse>, userId dibutuhkan untuk membuka data bank
 //   - error
-func (ur *UserRepository) GetUserProfiles(ctx context.Context, pool *pgxpool.Pool, userIds []string) ([]response.UserResponse, error) {
-	var result []response.UserResponse
+func (ur *UserRepository) GetUserProfiles(ctx context.Context, pool *pgxpool.Pool, userIds []string) ([]response.UserWithIdResponse, error) {
+	var result []response.UserWithIdResponse
 
 	// Menyusun query SQL untuk mengambil profil user berdasarkan userId
 	query := `
 		SELECT 
-			id,
+			users.id,
 			email,
 			phone,
 			fileId,
 			fileUri,
 			fileThumbnailUri,
-			bankAccountName,
-			bankAccountHolder,
-			bankAccountNumber
+			b.id,
+			b.bank_account_name,
+			b.bank_account_holder,
+			b.bank_account_number
 		FROM users 
-		WHERE id = ANY($1::text[]);`
+		LEFT JOIN bank_accounts b ON b.user_id = users.id AND b.is_default
+		WHERE users.id = ANY($1::text[]);`
 
 	// Menjalankan query untuk mendapatkan hasil berdasarkan userIds
 	rows, err := pool.Query(ctx, query, userIds)
@@ -301,6 +574,7 @@ func (ur *UserRepository) GetUserProfiles(ctx context.Context, pool *pgxpool.Poo
 			&user.FileId,
 			&user.FileUri,
 			&user.FileThumbnailUri,
+			&user.BankAccountId,
 			&user.BankAccountName,
 			&user.BankAccountHolder,
 			&user.BankAccountNumber,
@@ -310,12 +584,14 @@ func (ur *UserRepository) GetUserProfiles(ctx context.Context, pool *pgxpool.Poo
 			return nil, err
 		}
 		// Menambahkan user ke dalam hasil
-		_res := response.UserResponse{
+		_res := response.UserWithIdResponse{
+			UserId:            user.UserId.String,
 			Email:             user.Email.String,
 			Phone:             user.Phone.String,
 			FileId:            user.FileId.String,
 			FileUri:           user.FileUri.String,
 			FileThumbnailUri:  user.FileThumbnailUri.String,
+			BankAccountId:     user.BankAccountId.String,
 			BankAccountName:   user.BankAccountName.String,
 			BankAccountHolder: user.BankAccountHolder.String,
 			BankAccountNumber: user.BankAccountNumber.String,
@@ -343,17 +619,19 @@ func (ur *UserRepository) GetUserProfilesWithId(ctx context.Context, pool *pgxpo
 	// Menyusun query SQL untuk mengambil profil user berdasarkan userId
 	query := `
 		SELECT 
-			id,
+			users.id,
 			email,
 			phone,
 			fileId,
 			fileUri,
 			fileThumbnailUri,
-			bankAccountName,
-			bankAccountHolder,
-			bankAccountNumber
+			b.id,
+			b.bank_account_name,
+			b.bank_account_holder,
+			b.bank_account_number
 		FROM users 
-		WHERE id = ANY($1::text[]);`
+		LEFT JOIN bank_accounts b ON b.user_id = users.id AND b.is_default
+		WHERE users.id = ANY($1::text[]);`
 
 	// Menjalankan query untuk mendapatkan hasil berdasarkan userIds
 	rows, err := pool.Query(ctx, query, userIds)
@@ -375,6 +653,7 @@ func (ur *UserRepository) GetUserProfilesWithId(ctx context.Context, pool *pgxpo
 			&user.FileId,
 			&user.FileUri,
 			&user.FileThumbnailUri,
+			&user.BankAccountId,
 			&user.BankAccountName,
 			&user.BankAccountHolder,
 			&user.BankAccountNumber,
@@ -391,6 +670,7 @@ func (ur *UserRepository) GetUserProfilesWithId(ctx context.Context, pool *pgxpo
 			FileId:            user.FileId.String,
 			FileUri:           user.FileUri.String,
 			FileThumbnailUri:  user.FileThumbnailUri.String,
+			BankAccountId:     user.BankAccountId.String,
 			BankAccountName:   user.BankAccountName.String,
 			BankAccountHolder: user.BankAccountHolder.String,
 			BankAccountNumber: user.BankAccountNumber.String,
@@ -0,0 +1,89 @@
+package userEventOutboxRepository
+
+import (
+	"context"
+	"time"
+
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/model/dtos/repository"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/samber/do/v2"
+)
+
+// Delivery side of the user event outbox, rows are written by userRepository.AnonymizeUser
+type UserEventOutboxRepositoryInterface interface {
+	// Claims up to limit due rows and pushes their next attempt lease into the future, so other
+	// instances skip them while they are delivered. Attempts is incremented for each claimed row
+	ClaimDue(ctx context.Context, pool *pgxpool.Pool, limit int, lease time.Duration) ([]repository.UserDeletedOutbox, error)
+	// Removes an acked row
+	Delete(ctx context.Context, pool *pgxpool.Pool, id string) error
+	// Keeps a failed row for another attempt after delay
+	Reschedule(ctx context.Context, pool *pgxpool.Pool, id string, delay time.Duration, lastError string) error
+}
+
+type UserEventOutboxRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewUserEventOutboxRepository(db *pgxpool.Pool) UserEventOutboxRepositoryInterface {
+	return &UserEventOutboxRepository{
+		db: db,
+	}
+}
+
+func NewUserEventOutboxRepositoryInject(i do.Injector) (UserEventOutboxRepositoryInterface, error) {
+	return NewUserEventOutboxRepository(
+		do.MustInvoke[*pgxpool.Pool](i),
+	), nil
+}
+
+func (er *UserEventOutboxRepository) ClaimDue(ctx context.Context, pool *pgxpool.Pool, limit int, lease time.Duration) ([]repository.UserDeletedOutbox, error) {
+	query := `
+		UPDATE user_event_outbox
+		SET
+			attempts = attempts + 1,
+			next_attempt_at = CURRENT_TIMESTAMP + $2 * INTERVAL '1 millisecond'
+		WHERE id IN (
+			SELECT id
+			FROM user_event_outbox
+			WHERE next_attempt_at <= CURRENT_TIMESTAMP
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, subscriber, user_id, deleted_at, attempts;`
+
+	rows, err := pool.Query(ctx, query, limit, lease.Milliseconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []repository.UserDeletedOutbox
+	for rows.Next() {
+		var event repository.UserDeletedOutbox
+		err := rows.Scan(&event.Id, &event.Subscriber, &event.UserId, &event.DeletedAt, &event.Attempts)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+func (er *UserEventOutboxRepository) Delete(ctx context.Context, pool *pgxpool.Pool, id string) error {
+	_, err := pool.Exec(ctx, `DELETE FROM user_event_outbox WHERE id = $1;`, id)
+	return err
+}
+
+func (er *UserEventOutboxRepository) Reschedule(ctx context.Context, pool *pgxpool.Pool, id string, delay time.Duration, lastError string) error {
+	query := `
+		UPDATE user_event_outbox
+		SET
+			next_attempt_at = CURRENT_TIMESTAMP + $2 * INTERVAL '1 millisecond',
+			last_error = $3
+		WHERE id = $1;`
+
+	_, err := pool.Exec(ctx, query, id, delay.Milliseconds(), lastError)
+	return err
+}
@@ -0,0 +1,286 @@
+package accountService
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/auth"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/grpcmw"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/logging"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/cache"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/exceptions"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/helper"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/model/dtos/request"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/model/dtos/response"
+	userRepository "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/repositories/user"
+	authService "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/auth"
+	bankAccountService "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/bankAccount"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/external/grpc/product"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/external/grpc/purchase"
+	productService "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/external/product"
+	purchaseService "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/external/purchase"
+	userEventsService "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/external/userEvents"
+	userService "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/user"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/user/validator"
+	"github.com/bytedance/sonic"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/samber/do/v2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Upper bound for streaming the products and purchases of one export
+const exportTimeout = 2 * time.Minute
+
+type AccountServiceInterface interface {
+	// Anonymizes the user, ends their sessions and queues the event that tells subscribers like product about it.
+	// Access tokens issued to other devices stay valid until they expire
+	DeleteAccount(ctx context.Context, input request.DeleteAccountRequest, principal auth.Principal) error
+	// Loads the profile up front so failures still get a status code, the returned
+	// writer then streams the archive with the products and purchases of the user
+	Export(ctx context.Context, userId string) (func(w *bufio.Writer), error)
+}
+
+type accountService struct {
+	UserRepository     userRepository.UserRepositoryInterface
+	Db                 *pgxpool.Pool
+	Cache              cache.RedisCacheClient
+	authService        authService.AuthServiceInterface
+	userService        userService.UserServiceInterface
+	bankAccountService bankAccountService.BankAccountServiceInterface
+	productService     productService.ProductServiceInterface
+	purchaseService    purchaseService.PurchaseServiceInterface
+	userEvents         userEventsService.UserEventsServiceInterface
+	Logger             *logging.Logger
+}
+
+func NewAccountService(
+	userRepo userRepository.UserRepositoryInterface,
+	db *pgxpool.Pool,
+	cache cache.RedisCacheClient,
+	authService authService.AuthServiceInterface,
+	userService userService.UserServiceInterface,
+	bankAccountService bankAccountService.BankAccountServiceInterface,
+	productService productService.ProductServiceInterface,
+	purchaseService purchaseService.PurchaseServiceInterface,
+	userEvents userEventsService.UserEventsServiceInterface,
+	logger *logging.Logger,
+) AccountServiceInterface {
+	return &accountService{
+		UserRepository:     userRepo,
+		Db:                 db,
+		Cache:              cache,
+		authService:        authService,
+		userService:        userService,
+		bankAccountService: bankAccountService,
+		productService:     productService,
+		purchaseService:    purchaseService,
+		userEvents:         userEvents,
+		Logger:             logger,
+	}
+}
+
+func NewAccountServiceInject(i do.Injector) (AccountServiceInterface, error) {
+	_db := do.MustInvoke[*pgxpool.Pool](i)
+	_cache := do.MustInvoke[cache.RedisCacheClient](i)
+	_userRepo := do.MustInvoke[userRepository.UserRepositoryInterface](i)
+	_authService := do.MustInvoke[authService.AuthServiceInterface](i)
+	_userService := do.MustInvoke[userService.UserServiceInterface](i)
+	_bankAccountService := do.MustInvoke[bankAccountService.BankAccountServiceInterface](i)
+	_productService := do.MustInvoke[productService.ProductServiceInterface](i)
+	_purchaseService := do.MustInvoke[purchaseService.PurchaseServiceInterface](i)
+	_userEvents := do.MustInvoke[userEventsService.UserEventsServiceInterface](i)
+	_logger := do.MustInvoke[*logging.Logger](i)
+
+	return NewAccountService(_userRepo, _db, _cache, _authService, _userService, _bankAccountService, _productService, _purchaseService, _userEvents, _logger), nil
+}
+
+func (as *accountService) DeleteAccount(ctx context.Context, input request.DeleteAccountRequest, principal auth.Principal) error {
+	err := validator.ValidateStructFields(input)
+	if err != nil {
+		return exceptions.ErrBadRequest(err.Error())
+	}
+
+	userId := principal.UserId
+	currentHash, err := as.UserRepository.GetPasswordHash(ctx, as.Db, userId)
+	if err != nil {
+		return as.mapError(ctx, err, "userRepository.GetPasswordHash", userId)
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(currentHash), []byte(input.Password))
+	if err != nil {
+		return exceptions.ErrBadRequest("Wrong password")
+	}
+
+	// Sessions go first, a failure after this point leaves a logged out user instead of
+	// an anonymized one that can still refresh
+	err = as.authService.RevokeSessions(ctx, userId)
+	if err != nil {
+		return err
+	}
+
+	// The UserDeleted event is committed with the anonymization, see userEventsService.Run
+	err = as.UserRepository.AnonymizeUser(ctx, as.Db, userId, as.userEvents.Subscribers())
+	if err != nil {
+		return as.mapError(ctx, err, "userRepository.AnonymizeUser", userId)
+	}
+	as.userEvents.Notify()
+
+	err = as.Cache.RevokeToken(ctx, principal.TokenId, time.Until(principal.ExpiresAt))
+	if err != nil {
+		as.Logger.Error(ctx, "accountService.DeleteAccount failed", "error", err, "userId", userId)
+	}
+	err = as.Cache.DeleteUserProfile(ctx, userId)
+	if err != nil {
+		as.Logger.Error(ctx, "accountService.DeleteAccount failed", "error", err, "userId", userId)
+	}
+	err = as.Cache.DeletePasswordResetToken(ctx, userId)
+	if err != nil {
+		as.Logger.Error(ctx, "accountService.DeleteAccount failed", "error", err, "userId", userId)
+	}
+
+	as.Logger.Info(ctx, "account deleted", "userId", userId)
+
+	return nil
+}
+
+func (as *accountService) Export(ctx context.Context, userId string) (func(w *bufio.Writer), error) {
+	profile, err := as.userService.GetUserProfile(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	bankAccounts, err := as.bankAccountService.GetBankAccounts(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	roles, err := as.UserRepository.GetRoles(ctx, as.Db, userId)
+	if err != nil {
+		return nil, as.mapError(ctx, err, "userRepository.GetRoles", userId)
+	}
+
+	head, err := sonic.Marshal(response.AccountExport{
+		ExportedAt:   time.Now().UTC(),
+		UserId:       userId,
+		Roles:        roles,
+		Profile:      profile,
+		BankAccounts: bankAccounts,
+	})
+	if err != nil {
+		as.Logger.Error(ctx, "accountService.Export failed", "error", err, "userId", userId)
+		return nil, exceptions.ErrServer("Internal server error")
+	}
+
+	requestId := grpcmw.RequestIdFrom(ctx)
+	return func(w *bufio.Writer) {
+		// Runs after the handler returned, the request context can't be used anymore
+		streamCtx, cancel := context.WithTimeout(grpcmw.WithRequestId(context.Background(), requestId), exportTimeout)
+		defer cancel()
+
+		// The head object is reopened so the products and purchases can follow it one by one
+		w.Write(head[:len(head)-1])
+
+		w.WriteString(`,"products":[`)
+		products := &jsonArrayWriter{w: w}
+		productsErr := as.productService.ListProducts(streamCtx, userId, func(p *product.ProductResponse) error {
+			return products.Write(toExportedProduct(p))
+		})
+		if productsErr != nil {
+			as.Logger.Error(ctx, "accountService.Export failed", "error", productsErr, "userId", userId)
+		}
+
+		w.WriteString(`],"purchases":[`)
+		purchases := &jsonArrayWriter{w: w}
+		purchasesErr := as.purchaseService.ListPurchases(streamCtx, userId, func(p *purchase.PurchaseResponse) error {
+			return purchases.Write(toExportedPurchase(p))
+		})
+		if purchasesErr != nil {
+			as.Logger.Error(ctx, "accountService.Export failed", "error", purchasesErr, "userId", userId)
+		}
+
+		// The status is already sent, a reader can only tell a cut off list from this flag
+		fmt.Fprintf(w, `],"complete":%t}`, productsErr == nil && purchasesErr == nil)
+		w.Flush()
+	}, nil
+}
+
+func (as *accountService) mapError(ctx context.Context, err error, op, userId string) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return exceptions.ErrNotFound("User not found")
+	}
+
+	as.Logger.Error(ctx, op+" failed", "error", err, "userId", userId)
+	statusCode, message := helper.MapPgxError(err)
+	return exceptions.NewErrorResponse(statusCode, message)
+}
+
+func toExportedProduct(p *product.ProductResponse) response.ExportedProduct {
+	exported := response.ExportedProduct{
+		ProductId: p.ProductId,
+		Name:      p.Name,
+		Sku:       p.Sku,
+		Qty:       p.Qty,
+		Price:     p.Price,
+		FileId:    p.FileId,
+		Variants:  []response.ExportedProductVariant{},
+	}
+	for _, variant := range p.Variants {
+		exported.Variants = append(exported.Variants, response.ExportedProductVariant{
+			VariantId:  variant.VariantId,
+			Sku:        variant.Sku,
+			Qty:        variant.Qty,
+			Price:      variant.Price,
+			Attributes: variant.Attributes,
+		})
+	}
+
+	return exported
+}
+
+func toExportedPurchase(p *purchase.PurchaseResponse) response.ExportedPurchase {
+	exported := response.ExportedPurchase{
+		PurchaseId:          p.PurchaseId,
+		Role:                p.Role,
+		SenderName:          p.SenderName,
+		SenderContactType:   p.SenderContactType,
+		SenderContactDetail: p.SenderContactDetail,
+		Items:               []response.ExportedPurchasedItem{},
+		CreatedAt:           p.CreatedAt,
+	}
+	for _, item := range p.Items {
+		exported.Items = append(exported.Items, response.ExportedPurchasedItem{
+			ProductId: item.ProductId,
+			VariantId: item.VariantId,
+			Qty:       item.Qty,
+			SellerId:  item.SellerId,
+		})
+	}
+
+	return exported
+}
+
+// Writes the elements of a JSON array as they arrive, the brackets are written by the caller
+type jsonArrayWriter struct {
+	w       *bufio.Writer
+	started bool
+}
+
+func (aw *jsonArrayWriter) Write(v any) error {
+	data, err := sonic.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if aw.started {
+		aw.w.WriteByte(',')
+	}
+	aw.started = true
+
+	if _, err := aw.w.Write(data); err != nil {
+		return err
+	}
+	return aw.w.Flush()
+}
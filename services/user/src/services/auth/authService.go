@@ -0,0 +1,249 @@
+package authService
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/auth"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/logging"
+	authJwt "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/auth/jwt"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/cache"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/config"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/exceptions"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/model/dtos/repository"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/model/dtos/request"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/model/dtos/response"
+	refreshTokenRepository "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/repositories/refreshToken"
+	userRepository "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/repositories/user"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/user/validator"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/samber/do/v2"
+)
+
+type AuthServiceInterface interface {
+	// Issues an access token and starts a new refresh token family (login/register)
+	IssueTokens(ctx context.Context, userId string) (response.TokenResponse, error)
+	Refresh(ctx context.Context, input request.RefreshTokenRequest) (response.TokenResponse, error)
+	Logout(ctx context.Context, input request.LogoutRequest, principal auth.Principal) error
+	// Revokes every refresh token of the user, e.g. after a password change
+	RevokeSessions(ctx context.Context, userId string) error
+}
+
+type authService struct {
+	RefreshTokenRepository refreshTokenRepository.RefreshTokenRepositoryInterface
+	UserRepository         userRepository.UserRepositoryInterface
+	Db                     *pgxpool.Pool
+	Cache                  cache.RedisCacheClient
+	jwtService             authJwt.JwtServiceInterface
+	Logger                 *logging.Logger
+}
+
+func NewAuthService(
+	refreshTokenRepo refreshTokenRepository.RefreshTokenRepositoryInterface,
+	userRepo userRepository.UserRepositoryInterface,
+	db *pgxpool.Pool,
+	cache cache.RedisCacheClient,
+	jwtService authJwt.JwtServiceInterface,
+	logger *logging.Logger,
+) AuthServiceInterface {
+	return &authService{
+		RefreshTokenRepository: refreshTokenRepo,
+		UserRepository:         userRepo,
+		Db:                     db,
+		Cache:                  cache,
+		jwtService:             jwtService,
+		Logger:                 logger,
+	}
+}
+
+func NewAuthServiceInject(i do.Injector) (AuthServiceInterface, error) {
+	_db := do.MustInvoke[*pgxpool.Pool](i)
+	_cache := do.MustInvoke[cache.RedisCacheClient](i)
+	_refreshTokenRepo := do.MustInvoke[refreshTokenRepository.RefreshTokenRepositoryInterface](i)
+	_userRepo := do.MustInvoke[userRepository.UserRepositoryInterface](i)
+	_jwtService := do.MustInvoke[authJwt.JwtServiceInterface](i)
+	_logger := do.MustInvoke[*logging.Logger](i)
+
+	return NewAuthService(_refreshTokenRepo, _userRepo, _db, _cache, _jwtService, _logger), nil
+}
+
+func (as *authService) IssueTokens(ctx context.Context, userId string) (response.TokenResponse, error) {
+	accessToken, err := as.generateAccessToken(ctx, userId)
+	if err != nil {
+		return response.TokenResponse{}, err
+	}
+
+	refreshToken, token, err := newRefreshToken(userId, "")
+	if err != nil {
+		as.Logger.Error(ctx, "authService.IssueTokens failed", "error", err)
+		return response.TokenResponse{}, exceptions.ErrServer(err.Error())
+	}
+
+	err = as.RefreshTokenRepository.Create(ctx, as.Db, token)
+	if err != nil {
+		as.Logger.Error(ctx, "refreshTokenRepository.Create failed", "error", err, "userId", userId)
+		return response.TokenResponse{}, exceptions.ErrServer("Internal server error")
+	}
+
+	return response.TokenResponse{
+		Token:        accessToken.Token,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+func (as *authService) Refresh(ctx context.Context, input request.RefreshTokenRequest) (response.TokenResponse, error) {
+	err := validator.ValidateStructFields(input)
+	if err != nil {
+		return response.TokenResponse{}, exceptions.ErrBadRequest(err.Error())
+	}
+
+	current, err := as.RefreshTokenRepository.GetByHash(ctx, as.Db, hashRefreshToken(input.RefreshToken))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return response.TokenResponse{}, exceptions.ErrUnauthorized("Invalid refresh token")
+		}
+
+		as.Logger.Error(ctx, "refreshTokenRepository.GetByHash failed", "error", err)
+		return response.TokenResponse{}, exceptions.ErrServer("Internal server error")
+	}
+
+	// A revoked token being presented again means it leaked,
+	// so every session that descends from the same login is killed
+	if current.RevokedAt != nil {
+		as.revokeFamily(ctx, current.FamilyId)
+		return response.TokenResponse{}, exceptions.ErrUnauthorized("Invalid refresh token")
+	}
+
+	if time.Now().After(current.ExpiresAt) {
+		return response.TokenResponse{}, exceptions.ErrUnauthorized("Refresh token expired")
+	}
+
+	accessToken, err := as.generateAccessToken(ctx, current.UserId)
+	if err != nil {
+		return response.TokenResponse{}, err
+	}
+
+	refreshToken, next, err := newRefreshToken(current.UserId, current.FamilyId)
+	if err != nil {
+		as.Logger.Error(ctx, "authService.Refresh failed", "error", err)
+		return response.TokenResponse{}, exceptions.ErrServer(err.Error())
+	}
+
+	err = as.RefreshTokenRepository.Rotate(ctx, as.Db, current.Id, next)
+	if err != nil {
+		if errors.Is(err, refreshTokenRepository.ErrTokenAlreadyRevoked) {
+			as.revokeFamily(ctx, current.FamilyId)
+			return response.TokenResponse{}, exceptions.ErrUnauthorized("Invalid refresh token")
+		}
+
+		as.Logger.Error(ctx, "refreshTokenRepository.Rotate failed", "error", err, "id", current.Id)
+		return response.TokenResponse{}, exceptions.ErrServer("Internal server error")
+	}
+
+	return response.TokenResponse{
+		Token:        accessToken.Token,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+func (as *authService) Logout(ctx context.Context, input request.LogoutRequest, principal auth.Principal) error {
+	if input.RefreshToken != "" {
+		current, err := as.RefreshTokenRepository.GetByHash(ctx, as.Db, hashRefreshToken(input.RefreshToken))
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			as.Logger.Error(ctx, "refreshTokenRepository.GetByHash failed", "error", err)
+			return exceptions.ErrServer("Internal server error")
+		}
+
+		// Tokens of other users are ignored, logout must not revoke someone else's session
+		if err == nil && current.UserId == principal.UserId {
+			err = as.RefreshTokenRepository.RevokeFamily(ctx, as.Db, current.FamilyId)
+			if err != nil {
+				as.Logger.Error(ctx, "refreshTokenRepository.RevokeFamily failed", "error", err, "familyId", current.FamilyId)
+				return exceptions.ErrServer("Internal server error")
+			}
+		}
+	}
+
+	err := as.Cache.RevokeToken(ctx, principal.TokenId, time.Until(principal.ExpiresAt))
+	if err != nil {
+		as.Logger.Error(ctx, "authService.Logout failed", "error", err, "tokenId", principal.TokenId)
+		return exceptions.ErrServer("Internal server error")
+	}
+
+	return nil
+}
+
+func (as *authService) RevokeSessions(ctx context.Context, userId string) error {
+	err := as.RefreshTokenRepository.RevokeAllByUserId(ctx, as.Db, userId)
+	if err != nil {
+		as.Logger.Error(ctx, "refreshTokenRepository.RevokeAllByUserId failed", "error", err, "userId", userId)
+		return exceptions.ErrServer("Internal server error")
+	}
+
+	return nil
+}
+
+// Roles are read on every issue, so a granted or revoked role
+// reaches the token at the latest with the next refresh
+func (as *authService) generateAccessToken(ctx context.Context, userId string) (authJwt.AccessToken, error) {
+	roles, err := as.UserRepository.GetRoles(ctx, as.Db, userId)
+	if err != nil {
+		as.Logger.Error(ctx, "userRepository.GetRoles failed", "error", err, "userId", userId)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return authJwt.AccessToken{}, exceptions.ErrUnauthorized("User not found")
+		}
+		return authJwt.AccessToken{}, exceptions.ErrServer("Internal server error")
+	}
+
+	accessToken, err := as.jwtService.GenerateToken(userId, roles)
+	if err != nil {
+		as.Logger.Error(ctx, "unable to sign access token", "error", err, "userId", userId)
+		return authJwt.AccessToken{}, exceptions.ErrServer(err.Error())
+	}
+
+	return accessToken, nil
+}
+
+func (as *authService) revokeFamily(ctx context.Context, familyId string) {
+	as.Logger.Warn(ctx, "refresh token reuse detected, revoking family", "familyId", familyId)
+
+	err := as.RefreshTokenRepository.RevokeFamily(ctx, as.Db, familyId)
+	if err != nil {
+		as.Logger.Error(ctx, "refreshTokenRepository.RevokeFamily failed", "error", err, "familyId", familyId)
+	}
+}
+
+// Generates an opaque refresh token, only its sha256 hash is persisted.
+// An empty familyId starts a new family.
+func newRefreshToken(userId, familyId string) (string, repository.RefreshToken, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", repository.RefreshToken{}, err
+	}
+
+	id := uuid.NewString()
+	if familyId == "" {
+		familyId = id
+	}
+
+	plain := base64.RawURLEncoding.EncodeToString(buf)
+	return plain, repository.RefreshToken{
+		Id:        id,
+		UserId:    userId,
+		TokenHash: hashRefreshToken(plain),
+		FamilyId:  familyId,
+		ExpiresAt: time.Now().Add(config.GetRefreshTokenTtl()),
+	}, nil
+}
+
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
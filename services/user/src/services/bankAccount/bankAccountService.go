@@ -0,0 +1,267 @@
+package bankAccountService
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/logging"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/cache"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/encryption"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/exceptions"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/helper"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/model/dtos/repository"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/model/dtos/request"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/model/dtos/response"
+	bankAccountRepository "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/repositories/bankAccount"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/user/validator"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/samber/do/v2"
+)
+
+const maxBankAccountsPerUser = 10
+
+type BankAccountServiceInterface interface {
+	CreateBankAccount(ctx context.Context, input request.CreateBankAccountRequest, userId string) (response.BankAccountResponse, error)
+	GetBankAccounts(ctx context.Context, userId string) ([]response.BankAccountResponse, error)
+	// 404 when the account does not belong to the user, used by purchase through gRPC
+	GetBankAccount(ctx context.Context, userId, bankAccountId string) (response.BankAccountResponse, error)
+	DeleteBankAccount(ctx context.Context, userId, bankAccountId string) error
+	SetDefaultBankAccount(ctx context.Context, userId, bankAccountId string) error
+	// Moves every stored bank account to the active encryption key, returns the number of rows changed
+	ReencryptBankAccounts(ctx context.Context, batchSize int) (int, error)
+}
+
+type bankAccountService struct {
+	BankAccountRepository bankAccountRepository.BankAccountRepositoryInterface
+	Db                    *pgxpool.Pool
+	Cache                 cache.RedisCacheClient
+	fieldCipher           encryption.FieldCipherInterface
+	Logger                *logging.Logger
+}
+
+func NewBankAccountService(
+	bankAccountRepo bankAccountRepository.BankAccountRepositoryInterface,
+	db *pgxpool.Pool,
+	cache cache.RedisCacheClient,
+	fieldCipher encryption.FieldCipherInterface,
+	logger *logging.Logger,
+) BankAccountServiceInterface {
+	return &bankAccountService{
+		BankAccountRepository: bankAccountRepo,
+		Db:                    db,
+		Cache:                 cache,
+		fieldCipher:           fieldCipher,
+		Logger:                logger,
+	}
+}
+
+func NewBankAccountServiceInject(i do.Injector) (BankAccountServiceInterface, error) {
+	_db := do.MustInvoke[*pgxpool.Pool](i)
+	_cache := do.MustInvoke[cache.RedisCacheClient](i)
+	_bankAccountRepo := do.MustInvoke[bankAccountRepository.BankAccountRepositoryInterface](i)
+	_fieldCipher := do.MustInvoke[encryption.FieldCipherInterface](i)
+	_logger := do.MustInvoke[*logging.Logger](i)
+
+	return NewBankAccountService(_bankAccountRepo, _db, _cache, _fieldCipher, _logger), nil
+}
+
+func (bs *bankAccountService) CreateBankAccount(ctx context.Context, input request.CreateBankAccountRequest, userId string) (response.BankAccountResponse, error) {
+	err := validator.ValidateStructFields(input)
+	if err != nil {
+		return response.BankAccountResponse{}, exceptions.ErrBadRequest(err.Error())
+	}
+
+	count, err := bs.BankAccountRepository.CountByUserId(ctx, bs.Db, userId)
+	if err != nil {
+		bs.Logger.Error(ctx, "bankAccountRepository.CountByUserId failed", "error", err, "userId", userId)
+
+		statusCode, message := helper.MapPgxError(err)
+		return response.BankAccountResponse{}, exceptions.NewErrorResponse(statusCode, message)
+	}
+	if count >= maxBankAccountsPerUser {
+		return response.BankAccountResponse{}, exceptions.ErrConflict(fmt.Sprintf("A user can have at most %d bank accounts", maxBankAccountsPerUser))
+	}
+
+	// The id is chosen before inserting, the sealed fields are bound to it
+	id := uuid.NewString()
+	holder, err := bs.fieldCipher.Encrypt(input.BankAccountHolder, encryption.Binding(userId, id, encryption.BankAccountHolderColumn))
+	if err != nil {
+		bs.Logger.Error(ctx, "bankAccountService.CreateBankAccount failed", "error", err)
+		return response.BankAccountResponse{}, exceptions.ErrServer("Internal server error")
+	}
+	number, err := bs.fieldCipher.Encrypt(input.BankAccountNumber, encryption.Binding(userId, id, encryption.BankAccountNumberColumn))
+	if err != nil {
+		bs.Logger.Error(ctx, "bankAccountService.CreateBankAccount failed", "error", err)
+		return response.BankAccountResponse{}, exceptions.ErrServer("Internal server error")
+	}
+
+	account, err := bs.BankAccountRepository.Create(ctx, bs.Db, repository.BankAccount{
+		Id:                id,
+		UserId:            userId,
+		BankAccountName:   input.BankAccountName,
+		BankAccountHolder: holder,
+		BankAccountNumber: number,
+		IsDefault:         input.IsDefault,
+	})
+	if err != nil {
+		bs.Logger.Error(ctx, "bankAccountRepository.Create failed", "error", err, "userId", userId)
+
+		statusCode, message := helper.MapPgxError(err)
+		return response.BankAccountResponse{}, exceptions.NewErrorResponse(statusCode, message)
+	}
+
+	if account.IsDefault {
+		bs.Cache.DeleteUserProfile(ctx, userId)
+	}
+
+	return bs.toResponse(ctx, account)
+}
+
+func (bs *bankAccountService) GetBankAccounts(ctx context.Context, userId string) ([]response.BankAccountResponse, error) {
+	accounts, err := bs.BankAccountRepository.ListByUserId(ctx, bs.Db, userId)
+	if err != nil {
+		bs.Logger.Error(ctx, "bankAccountRepository.ListByUserId failed", "error", err, "userId", userId)
+
+		statusCode, message := helper.MapPgxError(err)
+		return nil, exceptions.NewErrorResponse(statusCode, message)
+	}
+
+	result := make([]response.BankAccountResponse, 0, len(accounts))
+	for _, account := range accounts {
+		resp, err := bs.toResponse(ctx, account)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, resp)
+	}
+
+	return result, nil
+}
+
+func (bs *bankAccountService) GetBankAccount(ctx context.Context, userId, bankAccountId string) (response.BankAccountResponse, error) {
+	account, err := bs.BankAccountRepository.GetById(ctx, bs.Db, userId, bankAccountId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return response.BankAccountResponse{}, exceptions.ErrNotFound("Bank account not found")
+		}
+
+		bs.Logger.Error(ctx, "bankAccountRepository.GetById failed", "error", err, "bankAccountId", bankAccountId)
+		statusCode, message := helper.MapPgxError(err)
+		return response.BankAccountResponse{}, exceptions.NewErrorResponse(statusCode, message)
+	}
+
+	return bs.toResponse(ctx, account)
+}
+
+func (bs *bankAccountService) DeleteBankAccount(ctx context.Context, userId, bankAccountId string) error {
+	err := bs.BankAccountRepository.Delete(ctx, bs.Db, userId, bankAccountId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return exceptions.ErrNotFound("Bank account not found")
+		}
+
+		bs.Logger.Error(ctx, "bankAccountRepository.Delete failed", "error", err, "bankAccountId", bankAccountId)
+		statusCode, message := helper.MapPgxError(err)
+		return exceptions.NewErrorResponse(statusCode, message)
+	}
+
+	// The deleted account may have been the default one shown on the profile
+	bs.Cache.DeleteUserProfile(ctx, userId)
+
+	return nil
+}
+
+func (bs *bankAccountService) SetDefaultBankAccount(ctx context.Context, userId, bankAccountId string) error {
+	err := bs.BankAccountRepository.SetDefault(ctx, bs.Db, userId, bankAccountId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return exceptions.ErrNotFound("Bank account not found")
+		}
+
+		bs.Logger.Error(ctx, "bankAccountRepository.SetDefault failed", "error", err, "bankAccountId", bankAccountId)
+		statusCode, message := helper.MapPgxError(err)
+		return exceptions.NewErrorResponse(statusCode, message)
+	}
+
+	bs.Cache.DeleteUserProfile(ctx, userId)
+
+	return nil
+}
+
+func (bs *bankAccountService) ReencryptBankAccounts(ctx context.Context, batchSize int) (int, error) {
+	updated := 0
+	afterId := ""
+	for {
+		rows, err := bs.BankAccountRepository.ListForRotation(ctx, bs.Db, afterId, batchSize)
+		if err != nil {
+			bs.Logger.Error(ctx, "bankAccountRepository.ListForRotation failed", "error", err, "afterId", afterId)
+			return updated, err
+		}
+		if len(rows) == 0 {
+			return updated, nil
+		}
+
+		for _, row := range rows {
+			afterId = row.Id
+			if !bs.fieldCipher.NeedsRotation(row.BankAccountHolder) && !bs.fieldCipher.NeedsRotation(row.BankAccountNumber) {
+				continue
+			}
+
+			holder, err := bs.reencrypt(row.BankAccountHolder, encryption.Binding(row.UserId, row.Id, encryption.BankAccountHolderColumn))
+			if err != nil {
+				return updated, fmt.Errorf("bank account %s: %w", row.Id, err)
+			}
+			number, err := bs.reencrypt(row.BankAccountNumber, encryption.Binding(row.UserId, row.Id, encryption.BankAccountNumberColumn))
+			if err != nil {
+				return updated, fmt.Errorf("bank account %s: %w", row.Id, err)
+			}
+
+			// Compared against the values read, a concurrent update is not overwritten
+			ok, err := bs.BankAccountRepository.ReplaceSealedFields(ctx, bs.Db, row, holder, number)
+			if err != nil {
+				bs.Logger.Error(ctx, "bankAccountRepository.ReplaceSealedFields failed", "error", err, "id", row.Id)
+				return updated, err
+			}
+			if ok {
+				updated++
+				// The profile cache holds the sealed values of the default account
+				bs.Cache.DeleteUserProfile(ctx, row.UserId)
+			}
+		}
+	}
+}
+
+func (bs *bankAccountService) reencrypt(value, binding string) (string, error) {
+	plaintext, err := bs.fieldCipher.Decrypt(value, binding)
+	if err != nil {
+		return "", err
+	}
+
+	return bs.fieldCipher.Encrypt(plaintext, binding)
+}
+
+// Holder and number stay sealed in the database, they are only opened here
+func (bs *bankAccountService) toResponse(ctx context.Context, account repository.BankAccount) (response.BankAccountResponse, error) {
+	holder, err := bs.fieldCipher.Decrypt(account.BankAccountHolder, encryption.Binding(account.UserId, account.Id, encryption.BankAccountHolderColumn))
+	if err != nil {
+		bs.Logger.Error(ctx, "bankAccountService.toResponse failed", "error", err, "id", account.Id)
+		return response.BankAccountResponse{}, exceptions.ErrServer("Internal server error")
+	}
+	number, err := bs.fieldCipher.Decrypt(account.BankAccountNumber, encryption.Binding(account.UserId, account.Id, encryption.BankAccountNumberColumn))
+	if err != nil {
+		bs.Logger.Error(ctx, "bankAccountService.toResponse failed", "error", err, "id", account.Id)
+		return response.BankAccountResponse{}, exceptions.ErrServer("Internal server error")
+	}
+
+	return response.BankAccountResponse{
+		BankAccountId:     account.Id,
+		BankAccountName:   account.BankAccountName,
+		BankAccountHolder: holder,
+		BankAccountNumber: number,
+		IsDefault:         account.IsDefault,
+		CreatedAt:         account.CreatedAt,
+	}, nil
+}
@@ -3,39 +3,52 @@ package fileService
 import (
 	"context"
 
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/grpcmw"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/health"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/logging"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/tracing"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/config"
-	functionCallerInfo "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/logger/helper"
-	loggerZap "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/logger/zap"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/model/dtos/service"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/external/grpc/file"
 	"github.com/samber/do/v2"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
 type FileServiceInterface interface {
 	// Call to external file service
 	GetFile(ctx context.Context, fileId string) (response service.File, ok bool)
+	// Asks the file service through grpc.health.v1, used by the readiness check
+	Ready(ctx context.Context) error
 }
 
 type fileService struct {
-	GrpcClient file.FileServiceClient
-	Logger     loggerZap.LoggerInterface
+	GrpcClient  file.FileServiceClient
+	HealthCheck health.Check
+	Logger      *logging.Logger
 }
 
-func NewFileService(grpcClient file.FileServiceClient, logger loggerZap.LoggerInterface) FileServiceInterface {
+func NewFileService(grpcClient file.FileServiceClient, healthCheck health.Check, logger *logging.Logger) FileServiceInterface {
 	return &fileService{
-		GrpcClient: grpcClient,
-		Logger:     logger,
+		GrpcClient:  grpcClient,
+		HealthCheck: healthCheck,
+		Logger:      logger,
 	}
 }
 
 func NewFileServiceInject(i do.Injector) (FileServiceInterface, error) {
-	_logger := do.MustInvoke[loggerZap.LoggerInterface](i)
+	_logger := do.MustInvoke[*logging.Logger](i)
+
+	transport, err := grpcmw.ClientTransport(config.GetGrpcTLS())
+	if err != nil {
+		return nil, err
+	}
 
 	conn, err := grpc.NewClient(
 		config.FILE_SERVICE_BASE_URL,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		append(
+			grpcmw.ClientOptions(grpcmw.ClientConfig{Token: config.GetGrpcServiceToken()}),
+			append(tracing.ClientOptions(), transport)...,
+		)...,
 	)
 	if err != nil {
 		return nil, err
@@ -43,13 +56,13 @@ func NewFileServiceInject(i do.Injector) (FileServiceInterface, error) {
 
 	client := file.NewFileServiceClient(conn)
 
-	return NewFileService(client, _logger), nil
+	return NewFileService(client, health.GrpcCheck(conn), _logger), nil
 }
 
 func (fs *fileService) GetFile(ctx context.Context, fileId string) (service.File, bool) {
 	response, err := fs.GrpcClient.CheckExist(ctx, &file.FileRequest{FileId: fileId})
 	if err != nil {
-		fs.Logger.Error(err.Error(), functionCallerInfo.ExternalFileServiceGetFile)
+		fs.Logger.Error(ctx, "externalFileService.GetFile failed", "error", err)
 		return service.File{}, false
 	}
 
@@ -58,3 +71,7 @@ func (fs *fileService) GetFile(ctx context.Context, fileId string) (service.File
 		FileThumbnailUri: response.ThumbnailUri,
 	}, true
 }
+
+func (fs *fileService) Ready(ctx context.Context) error {
+	return fs.HealthCheck(ctx)
+}
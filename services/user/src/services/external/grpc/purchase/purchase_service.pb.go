@@ -0,0 +1,329 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.4
+// 	protoc        v5.29.3
+// source: proto/external/purchase/purchase_service.proto
+
+package purchase
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type PurchasesByUserIdRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=UserId,proto3" json:"UserId,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PurchasesByUserIdRequest) Reset() {
+	*x = PurchasesByUserIdRequest{}
+	mi := &file_proto_external_purchase_purchase_service_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PurchasesByUserIdRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PurchasesByUserIdRequest) ProtoMessage() {}
+
+func (x *PurchasesByUserIdRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_external_purchase_purchase_service_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PurchasesByUserIdRequest.ProtoReflect.Descriptor instead.
+func (*PurchasesByUserIdRequest) Descriptor() ([]byte, []int) {
+	return file_proto_external_purchase_purchase_service_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *PurchasesByUserIdRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type PurchasedItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=ProductId,proto3" json:"ProductId,omitempty"`
+	VariantId     string                 `protobuf:"bytes,2,opt,name=VariantId,proto3" json:"VariantId,omitempty"` // kosong kalau tanpa varian
+	Qty           int32                  `protobuf:"varint,3,opt,name=Qty,proto3" json:"Qty,omitempty"`
+	SellerId      string                 `protobuf:"bytes,4,opt,name=SellerId,proto3" json:"SellerId,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PurchasedItem) Reset() {
+	*x = PurchasedItem{}
+	mi := &file_proto_external_purchase_purchase_service_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PurchasedItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PurchasedItem) ProtoMessage() {}
+
+func (x *PurchasedItem) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_external_purchase_purchase_service_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PurchasedItem.ProtoReflect.Descriptor instead.
+func (*PurchasedItem) Descriptor() ([]byte, []int) {
+	return file_proto_external_purchase_purchase_service_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PurchasedItem) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *PurchasedItem) GetVariantId() string {
+	if x != nil {
+		return x.VariantId
+	}
+	return ""
+}
+
+func (x *PurchasedItem) GetQty() int32 {
+	if x != nil {
+		return x.Qty
+	}
+	return 0
+}
+
+func (x *PurchasedItem) GetSellerId() string {
+	if x != nil {
+		return x.SellerId
+	}
+	return ""
+}
+
+// Purchase tempat user menjadi pembeli atau penjual
+type PurchaseResponse struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	PurchaseId string                 `protobuf:"bytes,1,opt,name=PurchaseId,proto3" json:"PurchaseId,omitempty"`
+	Role       string                 `protobuf:"bytes,2,opt,name=Role,proto3" json:"Role,omitempty"` // buyer | seller
+	// Data pengirim hanya diisi untuk pembeli
+	SenderName          string `protobuf:"bytes,3,opt,name=SenderName,proto3" json:"SenderName,omitempty"`
+	SenderContactType   string `protobuf:"bytes,4,opt,name=SenderContactType,proto3" json:"SenderContactType,omitempty"`
+	SenderContactDetail string `protobuf:"bytes,5,opt,name=SenderContactDetail,proto3" json:"SenderContactDetail,omitempty"`
+	// Semua item untuk pembeli, penjual hanya menerima item miliknya
+	Items         []*PurchasedItem `protobuf:"bytes,6,rep,name=Items,proto3" json:"Items,omitempty"`
+	CreatedAt     string           `protobuf:"bytes,7,opt,name=CreatedAt,proto3" json:"CreatedAt,omitempty"` // RFC 3339
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PurchaseResponse) Reset() {
+	*x = PurchaseResponse{}
+	mi := &file_proto_external_purchase_purchase_service_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PurchaseResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PurchaseResponse) ProtoMessage() {}
+
+func (x *PurchaseResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_external_purchase_purchase_service_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PurchaseResponse.ProtoReflect.Descriptor instead.
+func (*PurchaseResponse) Descriptor() ([]byte, []int) {
+	return file_proto_external_purchase_purchase_service_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *PurchaseResponse) GetPurchaseId() string {
+	if x != nil {
+		return x.PurchaseId
+	}
+	return ""
+}
+
+func (x *PurchaseResponse) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *PurchaseResponse) GetSenderName() string {
+	if x != nil {
+		return x.SenderName
+	}
+	return ""
+}
+
+func (x *PurchaseResponse) GetSenderContactType() string {
+	if x != nil {
+		return x.SenderContactType
+	}
+	return ""
+}
+
+func (x *PurchaseResponse) GetSenderContactDetail() string {
+	if x != nil {
+		return x.SenderContactDetail
+	}
+	return ""
+}
+
+func (x *PurchaseResponse) GetItems() []*PurchasedItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *PurchaseResponse) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+var File_proto_external_purchase_purchase_service_proto protoreflect.FileDescriptor
+
+var file_proto_external_purchase_purchase_service_proto_rawDesc = string([]byte{
+	0x0a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x65, 0x78, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c,
+	0x2f, 0x70, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x2f, 0x70, 0x75, 0x72, 0x63, 0x68, 0x61,
+	0x73, 0x65, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x12, 0x08, 0x70, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x22, 0x32, 0x0a, 0x18, 0x50, 0x75,
+	0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x73, 0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x49, 0x64, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x55, 0x73, 0x65, 0x72, 0x49, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x55, 0x73, 0x65, 0x72, 0x49, 0x64, 0x22, 0x79,
+	0x0a, 0x0d, 0x50, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x64, 0x49, 0x74, 0x65, 0x6d, 0x12,
+	0x1c, 0x0a, 0x09, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x49, 0x64, 0x12, 0x1c, 0x0a,
+	0x09, 0x56, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x09, 0x56, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x51,
+	0x74, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x51, 0x74, 0x79, 0x12, 0x1a, 0x0a,
+	0x08, 0x53, 0x65, 0x6c, 0x6c, 0x65, 0x72, 0x49, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x53, 0x65, 0x6c, 0x6c, 0x65, 0x72, 0x49, 0x64, 0x22, 0x93, 0x02, 0x0a, 0x10, 0x50, 0x75,
+	0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1e,
+	0x0a, 0x0a, 0x50, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0a, 0x50, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x49, 0x64, 0x12, 0x12,
+	0x0a, 0x04, 0x52, 0x6f, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x52, 0x6f,
+	0x6c, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x4e, 0x61,
+	0x6d, 0x65, 0x12, 0x2c, 0x0a, 0x11, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x74,
+	0x61, 0x63, 0x74, 0x54, 0x79, 0x70, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x11, 0x53,
+	0x65, 0x6e, 0x64, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x54, 0x79, 0x70, 0x65,
+	0x12, 0x30, 0x0a, 0x13, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x63,
+	0x74, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x13, 0x53,
+	0x65, 0x6e, 0x64, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x44, 0x65, 0x74, 0x61,
+	0x69, 0x6c, 0x12, 0x2d, 0x0a, 0x05, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x17, 0x2e, 0x70, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x2e, 0x50, 0x75, 0x72,
+	0x63, 0x68, 0x61, 0x73, 0x65, 0x64, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x05, 0x49, 0x74, 0x65, 0x6d,
+	0x73, 0x12, 0x1c, 0x0a, 0x09, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x32,
+	0x6c, 0x0a, 0x0f, 0x50, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x53, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x12, 0x59, 0x0a, 0x15, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x75, 0x72, 0x63, 0x68, 0x61,
+	0x73, 0x65, 0x73, 0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x49, 0x64, 0x12, 0x22, 0x2e, 0x70, 0x75,
+	0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x2e, 0x50, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x73,
+	0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x49, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1a, 0x2e, 0x70, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x2e, 0x50, 0x75, 0x72, 0x63, 0x68,
+	0x61, 0x73, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x42, 0x25, 0x5a,
+	0x23, 0x73, 0x72, 0x63, 0x2f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2f, 0x65, 0x78,
+	0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x67, 0x72, 0x70, 0x63, 0x2f, 0x70, 0x75, 0x72, 0x63,
+	0x68, 0x61, 0x73, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+})
+
+var (
+	file_proto_external_purchase_purchase_service_proto_rawDescOnce sync.Once
+	file_proto_external_purchase_purchase_service_proto_rawDescData []byte
+)
+
+func file_proto_external_purchase_purchase_service_proto_rawDescGZIP() []byte {
+	file_proto_external_purchase_purchase_service_proto_rawDescOnce.Do(func() {
+		file_proto_external_purchase_purchase_service_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_external_purchase_purchase_service_proto_rawDesc), len(file_proto_external_purchase_purchase_service_proto_rawDesc)))
+	})
+	return file_proto_external_purchase_purchase_service_proto_rawDescData
+}
+
+var file_proto_external_purchase_purchase_service_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_proto_external_purchase_purchase_service_proto_goTypes = []any{
+	(*PurchasesByUserIdRequest)(nil), // 0: purchase.PurchasesByUserIdRequest
+	(*PurchasedItem)(nil),            // 1: purchase.PurchasedItem
+	(*PurchaseResponse)(nil),         // 2: purchase.PurchaseResponse
+}
+var file_proto_external_purchase_purchase_service_proto_depIdxs = []int32{
+	1, // 0: purchase.PurchaseResponse.Items:type_name -> purchase.PurchasedItem
+	0, // 1: purchase.PurchaseService.ListPurchasesByUserId:input_type -> purchase.PurchasesByUserIdRequest
+	2, // 2: purchase.PurchaseService.ListPurchasesByUserId:output_type -> purchase.PurchaseResponse
+	2, // [2:3] is the sub-list for method output_type
+	1, // [1:2] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_proto_external_purchase_purchase_service_proto_init() }
+func file_proto_external_purchase_purchase_service_proto_init() {
+	if File_proto_external_purchase_purchase_service_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_external_purchase_purchase_service_proto_rawDesc), len(file_proto_external_purchase_purchase_service_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_external_purchase_purchase_service_proto_goTypes,
+		DependencyIndexes: file_proto_external_purchase_purchase_service_proto_depIdxs,
+		MessageInfos:      file_proto_external_purchase_purchase_service_proto_msgTypes,
+	}.Build()
+	File_proto_external_purchase_purchase_service_proto = out.File
+	file_proto_external_purchase_purchase_service_proto_goTypes = nil
+	file_proto_external_purchase_purchase_service_proto_depIdxs = nil
+}
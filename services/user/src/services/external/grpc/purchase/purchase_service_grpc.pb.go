@@ -0,0 +1,126 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: proto/external/purchase/purchase_service.proto
+
+package purchase
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	PurchaseService_ListPurchasesByUserId_FullMethodName = "/purchase.PurchaseService/ListPurchasesByUserId"
+)
+
+// PurchaseServiceClient is the client API for PurchaseService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type PurchaseServiceClient interface {
+	// Dipakai user service untuk export data akun, purchase tanpa login tidak punya pembeli
+	ListPurchasesByUserId(ctx context.Context, in *PurchasesByUserIdRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PurchaseResponse], error)
+}
+
+type purchaseServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPurchaseServiceClient(cc grpc.ClientConnInterface) PurchaseServiceClient {
+	return &purchaseServiceClient{cc}
+}
+
+func (c *purchaseServiceClient) ListPurchasesByUserId(ctx context.Context, in *PurchasesByUserIdRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PurchaseResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PurchaseService_ServiceDesc.Streams[0], PurchaseService_ListPurchasesByUserId_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[PurchasesByUserIdRequest, PurchaseResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PurchaseService_ListPurchasesByUserIdClient = grpc.ServerStreamingClient[PurchaseResponse]
+
+// PurchaseServiceServer is the server API for PurchaseService service.
+// All implementations must embed UnimplementedPurchaseServiceServer
+// for forward compatibility.
+type PurchaseServiceServer interface {
+	// Dipakai user service untuk export data akun, purchase tanpa login tidak punya pembeli
+	ListPurchasesByUserId(*PurchasesByUserIdRequest, grpc.ServerStreamingServer[PurchaseResponse]) error
+	mustEmbedUnimplementedPurchaseServiceServer()
+}
+
+// UnimplementedPurchaseServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedPurchaseServiceServer struct{}
+
+func (UnimplementedPurchaseServiceServer) ListPurchasesByUserId(*PurchasesByUserIdRequest, grpc.ServerStreamingServer[PurchaseResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method ListPurchasesByUserId not implemented")
+}
+func (UnimplementedPurchaseServiceServer) mustEmbedUnimplementedPurchaseServiceServer() {}
+func (UnimplementedPurchaseServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafePurchaseServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PurchaseServiceServer will
+// result in compilation errors.
+type UnsafePurchaseServiceServer interface {
+	mustEmbedUnimplementedPurchaseServiceServer()
+}
+
+func RegisterPurchaseServiceServer(s grpc.ServiceRegistrar, srv PurchaseServiceServer) {
+	// If the following call pancis, it indicates UnimplementedPurchaseServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&PurchaseService_ServiceDesc, srv)
+}
+
+func _PurchaseService_ListPurchasesByUserId_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(PurchasesByUserIdRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PurchaseServiceServer).ListPurchasesByUserId(m, &grpc.GenericServerStream[PurchasesByUserIdRequest, PurchaseResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PurchaseService_ListPurchasesByUserIdServer = grpc.ServerStreamingServer[PurchaseResponse]
+
+// PurchaseService_ServiceDesc is the grpc.ServiceDesc for PurchaseService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PurchaseService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "purchase.PurchaseService",
+	HandlerType: (*PurchaseServiceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListPurchasesByUserId",
+			Handler:       _PurchaseService_ListPurchasesByUserId_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "proto/external/purchase/purchase_service.proto",
+}
@@ -0,0 +1,100 @@
+package productService
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/grpcmw"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/health"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/logging"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/tracing"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/config"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/external/grpc/product"
+	"github.com/samber/do/v2"
+	"google.golang.org/grpc"
+)
+
+type ProductServiceInterface interface {
+	// Call to external product service
+	CountListings(ctx context.Context, userId string) (count int64, ok bool)
+	// Calls fn for every product of the user as it arrives from the stream
+	ListProducts(ctx context.Context, userId string, fn func(*product.ProductResponse) error) error
+	// Asks the product service through grpc.health.v1, used by the readiness check
+	Ready(ctx context.Context) error
+}
+
+type productService struct {
+	GrpcClient  product.ProductServiceClient
+	HealthCheck health.Check
+	Logger      *logging.Logger
+}
+
+func NewProductService(grpcClient product.ProductServiceClient, healthCheck health.Check, logger *logging.Logger) ProductServiceInterface {
+	return &productService{
+		GrpcClient:  grpcClient,
+		HealthCheck: healthCheck,
+		Logger:      logger,
+	}
+}
+
+func NewProductServiceInject(i do.Injector) (ProductServiceInterface, error) {
+	_logger := do.MustInvoke[*logging.Logger](i)
+
+	transport, err := grpcmw.ClientTransport(config.GetGrpcTLS())
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := grpc.NewClient(
+		config.GetProductServiceBaseURL(),
+		append(
+			grpcmw.ClientOptions(grpcmw.ClientConfig{Token: config.GetGrpcServiceToken()}),
+			append(tracing.ClientOptions(), transport)...,
+		)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	client := product.NewProductServiceClient(conn)
+
+	return NewProductService(client, health.GrpcCheck(conn), _logger), nil
+}
+
+func (ps *productService) CountListings(ctx context.Context, userId string) (int64, bool) {
+	response, err := ps.GrpcClient.CountProductsByUserId(ctx, &product.ProductCountRequest{UserId: userId})
+	if err != nil {
+		ps.Logger.Error(ctx, "externalProductService.CountListings failed", "error", err, "userId", userId)
+		return 0, false
+	}
+
+	return response.Count, true
+}
+
+func (ps *productService) ListProducts(ctx context.Context, userId string, fn func(*product.ProductResponse) error) error {
+	stream, err := ps.GrpcClient.ListProductsByUserId(ctx, &product.ProductsByUserIdRequest{UserId: userId})
+	if err != nil {
+		ps.Logger.Error(ctx, "externalProductService.ListProducts failed", "error", err, "userId", userId)
+		return err
+	}
+
+	for {
+		response, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			ps.Logger.Error(ctx, "externalProductService.ListProducts failed", "error", err, "userId", userId)
+			return err
+		}
+
+		if err := fn(response); err != nil {
+			return err
+		}
+	}
+}
+
+func (ps *productService) Ready(ctx context.Context) error {
+	return ps.HealthCheck(ctx)
+}
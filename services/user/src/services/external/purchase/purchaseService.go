@@ -0,0 +1,88 @@
+package purchaseService
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/grpcmw"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/health"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/logging"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/tracing"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/config"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/external/grpc/purchase"
+	"github.com/samber/do/v2"
+	"google.golang.org/grpc"
+)
+
+type PurchaseServiceInterface interface {
+	// Calls fn for every purchase the user bought or sold in as it arrives from the stream
+	ListPurchases(ctx context.Context, userId string, fn func(*purchase.PurchaseResponse) error) error
+	// Asks the purchase service through grpc.health.v1, used by the readiness check
+	Ready(ctx context.Context) error
+}
+
+type purchaseService struct {
+	GrpcClient  purchase.PurchaseServiceClient
+	HealthCheck health.Check
+	Logger      *logging.Logger
+}
+
+func NewPurchaseService(grpcClient purchase.PurchaseServiceClient, healthCheck health.Check, logger *logging.Logger) PurchaseServiceInterface {
+	return &purchaseService{
+		GrpcClient:  grpcClient,
+		HealthCheck: healthCheck,
+		Logger:      logger,
+	}
+}
+
+func NewPurchaseServiceInject(i do.Injector) (PurchaseServiceInterface, error) {
+	_logger := do.MustInvoke[*logging.Logger](i)
+
+	transport, err := grpcmw.ClientTransport(config.GetGrpcTLS())
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := grpc.NewClient(
+		config.GetPurchaseServiceBaseURL(),
+		append(
+			grpcmw.ClientOptions(grpcmw.ClientConfig{Token: config.GetGrpcServiceToken()}),
+			append(tracing.ClientOptions(), transport)...,
+		)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	client := purchase.NewPurchaseServiceClient(conn)
+
+	return NewPurchaseService(client, health.GrpcCheck(conn), _logger), nil
+}
+
+func (ps *purchaseService) ListPurchases(ctx context.Context, userId string, fn func(*purchase.PurchaseResponse) error) error {
+	stream, err := ps.GrpcClient.ListPurchasesByUserId(ctx, &purchase.PurchasesByUserIdRequest{UserId: userId})
+	if err != nil {
+		ps.Logger.Error(ctx, "externalPurchaseService.ListPurchases failed", "error", err, "userId", userId)
+		return err
+	}
+
+	for {
+		response, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			ps.Logger.Error(ctx, "externalPurchaseService.ListPurchases failed", "error", err, "userId", userId)
+			return err
+		}
+
+		if err := fn(response); err != nil {
+			return err
+		}
+	}
+}
+
+func (ps *purchaseService) Ready(ctx context.Context) error {
+	return ps.HealthCheck(ctx)
+}
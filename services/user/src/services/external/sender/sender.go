@@ -0,0 +1,101 @@
+package sender
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/config"
+	"github.com/samber/do/v2"
+)
+
+const (
+	ChannelEmail string = "email"
+	ChannelSms          = "sms"
+)
+
+type Message struct {
+	Channel string
+	To      string
+	Subject string
+	Body    string
+}
+
+// Delivers messages to users. Only local drivers exist for now,
+// an email/sms provider only has to implement this interface.
+type SenderInterface interface {
+	Send(ctx context.Context, message Message) error
+}
+
+func NewSenderInject(i do.Injector) (SenderInterface, error) {
+	driver := config.GetSenderDriver()
+	// Both local drivers write codes and reset tokens in plaintext, they must not reach real users
+	if strings.ToUpper(config.MODE) != config.MODE_DEBUG {
+		switch driver {
+		case "":
+			return nil, errors.New("SENDER_DRIVER value requires to be set")
+		case config.SENDER_DRIVER_CONSOLE, config.SENDER_DRIVER_FILE:
+			return nil, fmt.Errorf("SENDER_DRIVER %q only runs in MODE=DEBUG", driver)
+		}
+	} else if driver == "" {
+		driver = config.SENDER_DRIVER_CONSOLE
+	}
+
+	switch driver {
+	case config.SENDER_DRIVER_CONSOLE:
+		return NewConsoleSender(), nil
+	case config.SENDER_DRIVER_FILE:
+		return NewFileSender(config.GetSenderFilePath())
+	default:
+		return nil, fmt.Errorf("unknown SENDER_DRIVER %q", driver)
+	}
+}
+
+type consoleSender struct{}
+
+// Prints messages to stdout, for local runs
+func NewConsoleSender() SenderInterface {
+	return &consoleSender{}
+}
+
+func (cs *consoleSender) Send(ctx context.Context, message Message) error {
+	log.Printf("[%s] to=%s subject=%q body=%q", message.Channel, message.To, message.Subject, message.Body)
+	return nil
+}
+
+type fileSender struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// Appends messages to a file, handy for tests that need to read the codes back
+func NewFileSender(path string) (SenderInterface, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fileSender{file: file}, nil
+}
+
+func (fs *fileSender) Send(ctx context.Context, message Message) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	_, err := fmt.Fprintf(
+		fs.file,
+		"%s\t%s\t%s\t%s\t%s\n",
+		time.Now().Format(time.RFC3339), message.Channel, message.To, message.Subject, message.Body,
+	)
+	return err
+}
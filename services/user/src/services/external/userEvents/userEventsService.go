@@ -0,0 +1,211 @@
+package userEventsService
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/grpcmw"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/logging"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/tracing"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/config"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/model/dtos/repository"
+	userEventOutboxRepository "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/repositories/userEventOutbox"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/proto/userevents"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/samber/do/v2"
+	"google.golang.org/grpc"
+)
+
+// Outbox rows delivered per claim, in parallel
+const outboxBatchSize = 32
+
+type UserEventsServiceInterface interface {
+	// Addresses that get an outbox row for every event, see userRepository.AnonymizeUser
+	Subscribers() []string
+	// Wakes the dispatcher so events that were just committed don't wait for the next poll
+	Notify()
+	// Delivers the outbox until ctx is done. A row is deleted once its subscriber acked it and retried
+	// with backoff otherwise, so subscribers receive every event at least once and have to be idempotent
+	Run(ctx context.Context)
+}
+
+type userEventsService struct {
+	Clients      map[string]userevents.UserEventServiceClient
+	Db           *pgxpool.Pool
+	Outbox       userEventOutboxRepository.UserEventOutboxRepositoryInterface
+	Timeout      time.Duration
+	PollInterval time.Duration
+	RetryBase    time.Duration
+	RetryMax     time.Duration
+	Logger       *logging.Logger
+	wake         chan struct{}
+}
+
+func NewUserEventsService(
+	clients map[string]userevents.UserEventServiceClient,
+	db *pgxpool.Pool,
+	outbox userEventOutboxRepository.UserEventOutboxRepositoryInterface,
+	timeout, pollInterval, retryBase, retryMax time.Duration,
+	logger *logging.Logger,
+) UserEventsServiceInterface {
+	return &userEventsService{
+		Clients:      clients,
+		Db:           db,
+		Outbox:       outbox,
+		Timeout:      timeout,
+		PollInterval: pollInterval,
+		RetryBase:    retryBase,
+		RetryMax:     retryMax,
+		Logger:       logger,
+		wake:         make(chan struct{}, 1),
+	}
+}
+
+func NewUserEventsServiceInject(i do.Injector) (UserEventsServiceInterface, error) {
+	_db := do.MustInvoke[*pgxpool.Pool](i)
+	_outbox := do.MustInvoke[userEventOutboxRepository.UserEventOutboxRepositoryInterface](i)
+	_logger := do.MustInvoke[*logging.Logger](i)
+
+	transport, err := grpcmw.ClientTransport(config.GetGrpcTLS())
+	if err != nil {
+		return nil, err
+	}
+
+	clients := map[string]userevents.UserEventServiceClient{}
+	for _, address := range config.GetUserEventSubscribers() {
+		conn, err := grpc.NewClient(
+			address,
+			append(
+				grpcmw.ClientOptions(grpcmw.ClientConfig{Token: config.GetGrpcServiceToken()}),
+				append(tracing.ClientOptions(), transport)...,
+			)...,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		clients[address] = userevents.NewUserEventServiceClient(conn)
+	}
+
+	return NewUserEventsService(
+		clients,
+		_db,
+		_outbox,
+		config.GetUserEventTimeout(),
+		config.GetUserEventPollInterval(),
+		config.GetUserEventRetryBase(),
+		config.GetUserEventRetryMax(),
+		_logger,
+	), nil
+}
+
+func (ues *userEventsService) Subscribers() []string {
+	subscribers := make([]string, 0, len(ues.Clients))
+	for address := range ues.Clients {
+		subscribers = append(subscribers, address)
+	}
+
+	return subscribers
+}
+
+func (ues *userEventsService) Notify() {
+	select {
+	case ues.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (ues *userEventsService) Run(ctx context.Context) {
+	ticker := time.NewTicker(ues.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		ues.dispatch(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-ues.wake:
+		}
+	}
+}
+
+// Delivers due rows batch by batch until none are left
+func (ues *userEventsService) dispatch(ctx context.Context) {
+	for ctx.Err() == nil {
+		// A claimed row is skipped by other instances until the lease runs out, twice the call timeout
+		// leaves room for the ack or reschedule that follows the call
+		events, err := ues.Outbox.ClaimDue(ctx, ues.Db, outboxBatchSize, 2*ues.Timeout)
+		if err != nil {
+			ues.Logger.Error(ctx, "userEventOutboxRepository.ClaimDue failed", "error", err)
+			return
+		}
+
+		var wg sync.WaitGroup
+		for _, event := range events {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				ues.deliver(ctx, event)
+			}()
+		}
+		wg.Wait()
+
+		if len(events) < outboxBatchSize {
+			return
+		}
+	}
+}
+
+func (ues *userEventsService) deliver(ctx context.Context, event repository.UserDeletedOutbox) {
+	// Claimed rows are finished during shutdown too, otherwise they wait for their lease
+	ctx = context.WithoutCancel(ctx)
+
+	client, ok := ues.Clients[event.Subscriber]
+	if !ok {
+		// The address was removed from USER_EVENT_SUBSCRIBERS, nobody is left to deliver to
+		ues.Logger.Warn(ctx, "dropping user event of an unknown subscriber", "address", event.Subscriber, "userId", event.UserId)
+		ues.ack(ctx, event)
+		return
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, ues.Timeout)
+	_, err := client.UserDeleted(callCtx, &userevents.UserDeletedEvent{
+		UserId:    event.UserId,
+		DeletedAt: event.DeletedAt.UTC().Format(time.RFC3339),
+	})
+	cancel()
+	if err == nil {
+		ues.ack(ctx, event)
+		return
+	}
+
+	delay := ues.retryDelay(event.Attempts)
+	ues.Logger.Error(ctx, "externalUserEvents.UserDeleted failed", "error", err, "address", event.Subscriber, "userId", event.UserId, "attempts", event.Attempts, "retryIn", delay.String())
+
+	err = ues.Outbox.Reschedule(ctx, ues.Db, event.Id, delay, err.Error())
+	if err != nil {
+		// The row is retried once its lease runs out
+		ues.Logger.Error(ctx, "userEventOutboxRepository.Reschedule failed", "error", err, "address", event.Subscriber, "userId", event.UserId)
+	}
+}
+
+func (ues *userEventsService) ack(ctx context.Context, event repository.UserDeletedOutbox) {
+	err := ues.Outbox.Delete(ctx, ues.Db, event.Id)
+	if err != nil {
+		// The row is delivered again once its lease runs out, subscribers handle repeated events
+		ues.Logger.Error(ctx, "userEventOutboxRepository.Delete failed", "error", err, "address", event.Subscriber, "userId", event.UserId)
+	}
+}
+
+// RetryBase after the first failed attempt, doubled with every further one up to RetryMax
+func (ues *userEventsService) retryDelay(attempts int) time.Duration {
+	delay := ues.RetryBase
+	for i := 1; i < attempts && delay < ues.RetryMax; i++ {
+		delay *= 2
+	}
+
+	return min(delay, ues.RetryMax)
+}
@@ -0,0 +1,189 @@
+package loginAttemptService
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/logging"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/cache"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/config"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/exceptions"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/model/dtos/repository"
+	loginLockoutRepository "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/repositories/loginLockout"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/samber/do/v2"
+)
+
+const lockedMessage = "Too many failed login attempts, try again later"
+
+// Counts failed logins per identifier (email/phone) and per client IP.
+// Reaching the limit of either locks it, each following lockout
+// within a day lasts twice as long as the previous one.
+type LoginAttemptServiceInterface interface {
+	// Returns a 429 error while the identifier or the ip is locked
+	Check(ctx context.Context, identifier, ip string) error
+	// Returns a 429 error when this failure caused a lockout
+	RegisterFailure(ctx context.Context, identifier, ip string) error
+	// Clears the failures and the lockout level of the identifier only, a login that
+	// succeeded from a shared ip says nothing about the other attempts from it
+	Reset(ctx context.Context, identifier string)
+}
+
+type loginAttemptService struct {
+	LoginLockoutRepository loginLockoutRepository.LoginLockoutRepositoryInterface
+	Db                     *pgxpool.Pool
+	Cache                  cache.RedisCacheClient
+	Logger                 *logging.Logger
+}
+
+func NewLoginAttemptService(
+	loginLockoutRepo loginLockoutRepository.LoginLockoutRepositoryInterface,
+	db *pgxpool.Pool,
+	cache cache.RedisCacheClient,
+	logger *logging.Logger,
+) LoginAttemptServiceInterface {
+	return &loginAttemptService{
+		LoginLockoutRepository: loginLockoutRepo,
+		Db:                     db,
+		Cache:                  cache,
+		Logger:                 logger,
+	}
+}
+
+func NewLoginAttemptServiceInject(i do.Injector) (LoginAttemptServiceInterface, error) {
+	_db := do.MustInvoke[*pgxpool.Pool](i)
+	_cache := do.MustInvoke[cache.RedisCacheClient](i)
+	_loginLockoutRepo := do.MustInvoke[loginLockoutRepository.LoginLockoutRepositoryInterface](i)
+	_logger := do.MustInvoke[*logging.Logger](i)
+
+	return NewLoginAttemptService(_loginLockoutRepo, _db, _cache, _logger), nil
+}
+
+func (ls *loginAttemptService) Check(ctx context.Context, identifier, ip string) error {
+	var retryAfter time.Duration
+	for _, key := range []string{identifierKey(identifier), ipKey(ip)} {
+		// Redis being down must not lock everybody out, the attempt is let through
+		ttl, err := ls.Cache.GetLoginLockout(ctx, key)
+		if err != nil {
+			ls.Logger.Error(ctx, "loginAttemptService.Check failed", "error", err, "key", key)
+			continue
+		}
+		if ttl > retryAfter {
+			retryAfter = ttl
+		}
+	}
+
+	if retryAfter > 0 {
+		return exceptions.ErrTooManyRequests(lockedMessage, retryAfter)
+	}
+
+	return nil
+}
+
+func (ls *loginAttemptService) RegisterFailure(ctx context.Context, identifier, ip string) error {
+	limits := []struct {
+		scope string
+		key   string
+		max   int64
+	}{
+		{scope: repository.LoginLockoutScopeIdentifier, key: identifierKey(identifier), max: config.GetLoginMaxAttemptsPerIdentifier()},
+		{scope: repository.LoginLockoutScopeIp, key: ipKey(ip), max: config.GetLoginMaxAttemptsPerIp()},
+	}
+
+	var retryAfter time.Duration
+	for _, limit := range limits {
+		count, err := ls.Cache.IncrLoginFailures(ctx, limit.key, config.GetLoginAttemptWindow())
+		if err != nil {
+			ls.Logger.Error(ctx, "loginAttemptService.RegisterFailure failed", "error", err, "key", limit.key)
+			continue
+		}
+		if count < limit.max {
+			continue
+		}
+
+		duration, err := ls.lock(ctx, limit.scope, limit.key, identifier, ip, count)
+		if err != nil {
+			ls.Logger.Error(ctx, "loginAttemptService.RegisterFailure failed", "error", err, "key", limit.key)
+			continue
+		}
+		if duration > retryAfter {
+			retryAfter = duration
+		}
+	}
+
+	if retryAfter > 0 {
+		return exceptions.ErrTooManyRequests(lockedMessage, retryAfter)
+	}
+
+	return nil
+}
+
+func (ls *loginAttemptService) Reset(ctx context.Context, identifier string) {
+	err := ls.Cache.ResetLoginFailures(ctx, identifierKey(identifier))
+	if err != nil {
+		ls.Logger.Error(ctx, "loginAttemptService.Reset failed", "error", err, "identifier", identifier)
+	}
+}
+
+func (ls *loginAttemptService) lock(ctx context.Context, scope, key, identifier, ip string, failedAttempts int64) (time.Duration, error) {
+	level, err := ls.Cache.IncrLoginLockoutLevel(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+
+	duration := lockoutDuration(level)
+	err = ls.Cache.LockLogin(ctx, key, duration)
+	if err != nil {
+		return 0, err
+	}
+
+	ls.Logger.Warn(ctx, "login locked after repeated failures", "key", key, "duration", duration.String())
+
+	lockout := repository.LoginLockout{
+		Scope:          scope,
+		IpAddress:      ip,
+		FailedAttempts: failedAttempts,
+		LockoutLevel:   level,
+		LockedUntil:    time.Now().Add(duration),
+	}
+	if scope == repository.LoginLockoutScopeIdentifier {
+		lockout.Identifier = normalizeIdentifier(identifier)
+	}
+
+	// The lock is already active, a failed audit insert is only logged
+	err = ls.LoginLockoutRepository.Create(ctx, ls.Db, lockout)
+	if err != nil {
+		ls.Logger.Error(ctx, "loginLockoutRepository.Create failed", "error", err, "lockout", lockout)
+	}
+
+	return duration, nil
+}
+
+// base * 2^(level-1), capped at the configured maximum
+func lockoutDuration(level int64) time.Duration {
+	base := config.GetLoginLockoutBase()
+	max := config.GetLoginLockoutMax()
+
+	duration := base
+	for i := int64(1); i < level && duration < max; i++ {
+		duration *= 2
+	}
+	if duration > max {
+		duration = max
+	}
+
+	return duration
+}
+
+func normalizeIdentifier(identifier string) string {
+	return strings.ToLower(strings.TrimSpace(identifier))
+}
+
+func identifierKey(identifier string) string {
+	return "id:" + normalizeIdentifier(identifier)
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
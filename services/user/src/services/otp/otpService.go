@@ -0,0 +1,172 @@
+package otpService
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/logging"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/cache"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/config"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/exceptions"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/model/dtos/service"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/external/sender"
+	"github.com/samber/do/v2"
+)
+
+type Purpose string
+
+const (
+	PurposeVerifyEmail Purpose = "email"
+	PurposeVerifyPhone Purpose = "phone"
+)
+
+const codeDigits = 6
+
+type OtpServiceInterface interface {
+	// Sends a new code to target, a pending code of the same purpose is replaced
+	Issue(ctx context.Context, purpose Purpose, userId, target string) error
+	// Consumes the code and returns the target it was issued for
+	Verify(ctx context.Context, purpose Purpose, userId, code string) (target string, err error)
+}
+
+type otpService struct {
+	Cache  cache.RedisCacheClient
+	Sender sender.SenderInterface
+	Logger *logging.Logger
+}
+
+func NewOtpService(cache cache.RedisCacheClient, sender sender.SenderInterface, logger *logging.Logger) OtpServiceInterface {
+	return &otpService{
+		Cache:  cache,
+		Sender: sender,
+		Logger: logger,
+	}
+}
+
+func NewOtpServiceInject(i do.Injector) (OtpServiceInterface, error) {
+	_cache := do.MustInvoke[cache.RedisCacheClient](i)
+	_sender := do.MustInvoke[sender.SenderInterface](i)
+	_logger := do.MustInvoke[*logging.Logger](i)
+
+	return NewOtpService(_cache, _sender, _logger), nil
+}
+
+func (ots *otpService) Issue(ctx context.Context, purpose Purpose, userId, target string) error {
+	key := otpKey(purpose, userId)
+
+	wait, err := ots.Cache.AcquireOtpCooldown(ctx, key, config.GetOtpResendInterval())
+	if err != nil {
+		ots.Logger.Error(ctx, "otpService.Issue failed", "error", err, "key", key)
+		return exceptions.ErrServer("Internal server error")
+	}
+	if wait > 0 {
+		return exceptions.ErrTooManyRequests("A code was sent recently, try again later", wait)
+	}
+
+	code, err := newCode()
+	if err != nil {
+		ots.Logger.Error(ctx, "otpService.Issue failed", "error", err)
+		return exceptions.ErrServer("Internal server error")
+	}
+
+	ttl := config.GetOtpTtl()
+	err = ots.Cache.SetOtp(ctx, key, service.Otp{
+		CodeHash: hashCode(userId, target, code),
+		Target:   target,
+	}, ttl)
+	if err != nil {
+		ots.Logger.Error(ctx, "otpService.Issue failed", "error", err, "key", key)
+		return exceptions.ErrServer("Internal server error")
+	}
+
+	err = ots.Sender.Send(ctx, sender.Message{
+		Channel: channelOf(purpose),
+		To:      target,
+		Subject: "Your TutupLapak verification code",
+		Body:    fmt.Sprintf("Your verification code is %s, it expires in %s.", code, ttl),
+	})
+	if err != nil {
+		ots.Logger.Error(ctx, "otpService.Issue failed", "error", err, "key", key)
+		return exceptions.ErrServer("Failed to send verification code")
+	}
+
+	return nil
+}
+
+func (ots *otpService) Verify(ctx context.Context, purpose Purpose, userId, code string) (string, error) {
+	key := otpKey(purpose, userId)
+
+	otp, ok, err := ots.Cache.GetOtp(ctx, key)
+	if err != nil {
+		ots.Logger.Error(ctx, "otpService.Verify failed", "error", err, "key", key)
+		return "", exceptions.ErrServer("Internal server error")
+	}
+	if !ok {
+		return "", exceptions.ErrBadRequest("Verification code is invalid or expired")
+	}
+
+	if subtle.ConstantTimeCompare([]byte(otp.CodeHash), []byte(hashCode(userId, otp.Target, code))) != 1 {
+		attempts, err := ots.Cache.IncrOtpAttempts(ctx, key)
+		if err != nil {
+			ots.Logger.Error(ctx, "otpService.Verify failed", "error", err, "key", key)
+			return "", exceptions.ErrServer("Internal server error")
+		}
+
+		// Six digits are quick to enumerate, the code dies after a few misses
+		if attempts >= config.GetOtpMaxAttempts() {
+			ots.deleteOtp(ctx, key)
+			return "", exceptions.ErrTooManyRequests("Too many wrong codes, request a new one", config.GetOtpResendInterval())
+		}
+
+		return "", exceptions.ErrBadRequest("Verification code is invalid or expired")
+	}
+
+	ots.deleteOtp(ctx, key)
+
+	return otp.Target, nil
+}
+
+func (ots *otpService) deleteOtp(ctx context.Context, key string) {
+	err := ots.Cache.DeleteOtp(ctx, key)
+	if err != nil {
+		ots.Logger.Error(ctx, "otpService.Verify failed", "error", err, "key", key)
+	}
+}
+
+func otpKey(purpose Purpose, userId string) string {
+	return string(purpose) + ":" + userId
+}
+
+func channelOf(purpose Purpose) string {
+	if purpose == PurposeVerifyPhone {
+		return sender.ChannelSms
+	}
+
+	return sender.ChannelEmail
+}
+
+func newCode() (string, error) {
+	max := big.NewInt(1)
+	for i := 0; i < codeDigits; i++ {
+		max.Mul(max, big.NewInt(10))
+	}
+
+	n, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%0*d", codeDigits, n), nil
+}
+
+// The user and the target are part of the hash, a code only proves ownership of
+// the address it was sent to
+func hashCode(userId, target, code string) string {
+	sum := sha256.Sum256([]byte(userId + ":" + target + ":" + code))
+	return hex.EncodeToString(sum[:])
+}
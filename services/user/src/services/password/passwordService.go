@@ -0,0 +1,240 @@
+package passwordService
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/logging"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/cache"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/config"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/exceptions"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/helper"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/model/dtos/request"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/model/dtos/response"
+	userRepository "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/repositories/user"
+	authService "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/auth"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/external/sender"
+	loginAttemptService "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/loginAttempt"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/user/validator"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/samber/do/v2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const resetCooldownKeyPrefix = "reset:"
+
+// Wrong current passwords count against the same limits as failed logins,
+// under an identifier no email or phone can take
+const changePasswordAttemptPrefix = "user:"
+
+type PasswordServiceInterface interface {
+	// Revokes every session and returns a fresh token pair for the caller.
+	// Returns a 429 error while wrong current passwords keep the user or the ip locked
+	ChangePassword(ctx context.Context, input request.ChangePasswordRequest, userId, clientIp string) (response.TokenResponse, error)
+	// Sends a reset token when the account exists, the caller can't tell the difference
+	ForgotPassword(ctx context.Context, input request.ForgotPasswordRequest) error
+	ResetPassword(ctx context.Context, input request.ResetPasswordRequest) error
+}
+
+type passwordService struct {
+	UserRepository userRepository.UserRepositoryInterface
+	Db             *pgxpool.Pool
+	Cache          cache.RedisCacheClient
+	authService    authService.AuthServiceInterface
+	Sender         sender.SenderInterface
+	loginAttempt   loginAttemptService.LoginAttemptServiceInterface
+	Logger         *logging.Logger
+}
+
+func NewPasswordService(
+	userRepo userRepository.UserRepositoryInterface,
+	db *pgxpool.Pool,
+	cache cache.RedisCacheClient,
+	authService authService.AuthServiceInterface,
+	sender sender.SenderInterface,
+	loginAttempt loginAttemptService.LoginAttemptServiceInterface,
+	logger *logging.Logger,
+) PasswordServiceInterface {
+	return &passwordService{
+		UserRepository: userRepo,
+		Db:             db,
+		Cache:          cache,
+		authService:    authService,
+		Sender:         sender,
+		loginAttempt:   loginAttempt,
+		Logger:         logger,
+	}
+}
+
+func NewPasswordServiceInject(i do.Injector) (PasswordServiceInterface, error) {
+	_db := do.MustInvoke[*pgxpool.Pool](i)
+	_cache := do.MustInvoke[cache.RedisCacheClient](i)
+	_userRepo := do.MustInvoke[userRepository.UserRepositoryInterface](i)
+	_authService := do.MustInvoke[authService.AuthServiceInterface](i)
+	_sender := do.MustInvoke[sender.SenderInterface](i)
+	_loginAttempt := do.MustInvoke[loginAttemptService.LoginAttemptServiceInterface](i)
+	_logger := do.MustInvoke[*logging.Logger](i)
+
+	return NewPasswordService(_userRepo, _db, _cache, _authService, _sender, _loginAttempt, _logger), nil
+}
+
+func (ps *passwordService) ChangePassword(ctx context.Context, input request.ChangePasswordRequest, userId, clientIp string) (response.TokenResponse, error) {
+	err := validator.ValidateStructFields(input)
+	if err != nil {
+		return response.TokenResponse{}, exceptions.ErrBadRequest(err.Error())
+	}
+
+	attemptIdentifier := changePasswordAttemptPrefix + userId
+	err = ps.loginAttempt.Check(ctx, attemptIdentifier, clientIp)
+	if err != nil {
+		return response.TokenResponse{}, err
+	}
+
+	currentHash, err := ps.UserRepository.GetPasswordHash(ctx, ps.Db, userId)
+	if err != nil {
+		ps.Logger.Error(ctx, "userRepository.GetPasswordHash failed", "error", err, "userId", userId)
+
+		statusCode, message := helper.MapPgxError(err)
+		return response.TokenResponse{}, exceptions.NewErrorResponse(statusCode, message)
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(currentHash), []byte(input.CurrentPassword))
+	if err != nil {
+		if lockErr := ps.loginAttempt.RegisterFailure(ctx, attemptIdentifier, clientIp); lockErr != nil {
+			return response.TokenResponse{}, lockErr
+		}
+		return response.TokenResponse{}, exceptions.ErrBadRequest("Wrong password")
+	}
+
+	ps.loginAttempt.Reset(ctx, attemptIdentifier)
+
+	err = ps.updatePassword(ctx, userId, input.NewPassword)
+	if err != nil {
+		return response.TokenResponse{}, err
+	}
+
+	// A pending forgot-password token must not undo this change
+	err = ps.Cache.DeletePasswordResetToken(ctx, userId)
+	if err != nil {
+		ps.Logger.Error(ctx, "passwordService.ChangePassword failed", "error", err, "userId", userId)
+	}
+
+	return ps.authService.IssueTokens(ctx, userId)
+}
+
+func (ps *passwordService) ForgotPassword(ctx context.Context, input request.ForgotPasswordRequest) error {
+	err := validator.ValidateStructFields(input)
+	if err != nil {
+		return exceptions.ErrBadRequest(err.Error())
+	}
+
+	message := sender.Message{Subject: "Reset your TutupLapak password"}
+
+	var userId string
+	if input.Email != "" {
+		auth, lookupErr := ps.UserRepository.GetAuthByEmail(ctx, ps.Db, input.Email)
+		userId, err = auth.UserId, lookupErr
+		message.Channel, message.To = sender.ChannelEmail, input.Email
+	} else {
+		auth, lookupErr := ps.UserRepository.GetAuthByPhone(ctx, ps.Db, input.Phone)
+		userId, err = auth.UserId, lookupErr
+		message.Channel, message.To = sender.ChannelSms, input.Phone
+	}
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			ps.Logger.Error(ctx, "passwordService.ForgotPassword failed", "error", err)
+		}
+		return nil
+	}
+
+	// Silently dropped too, answering differently would reveal the account exists
+	wait, err := ps.Cache.AcquireOtpCooldown(ctx, resetCooldownKeyPrefix+userId, config.GetOtpResendInterval())
+	if err != nil {
+		ps.Logger.Error(ctx, "passwordService.ForgotPassword failed", "error", err, "userId", userId)
+		return nil
+	}
+	if wait > 0 {
+		return nil
+	}
+
+	token, tokenHash, err := newResetToken()
+	if err != nil {
+		ps.Logger.Error(ctx, "passwordService.ForgotPassword failed", "error", err)
+		return exceptions.ErrServer("Internal server error")
+	}
+
+	ttl := config.GetPasswordResetTtl()
+	err = ps.Cache.SetPasswordResetToken(ctx, userId, tokenHash, ttl)
+	if err != nil {
+		ps.Logger.Error(ctx, "passwordService.ForgotPassword failed", "error", err, "userId", userId)
+		return exceptions.ErrServer("Internal server error")
+	}
+
+	message.Body = fmt.Sprintf("Use this token to reset your password: %s. It expires in %s and works once.", token, ttl)
+	err = ps.Sender.Send(ctx, message)
+	if err != nil {
+		ps.Logger.Error(ctx, "passwordService.ForgotPassword failed", "error", err, "userId", userId)
+		return exceptions.ErrServer("Internal server error")
+	}
+
+	return nil
+}
+
+func (ps *passwordService) ResetPassword(ctx context.Context, input request.ResetPasswordRequest) error {
+	err := validator.ValidateStructFields(input)
+	if err != nil {
+		return exceptions.ErrBadRequest(err.Error())
+	}
+
+	userId, ok, err := ps.Cache.ConsumePasswordResetToken(ctx, hashResetToken(input.Token))
+	if err != nil {
+		ps.Logger.Error(ctx, "passwordService.ResetPassword failed", "error", err)
+		return exceptions.ErrServer("Internal server error")
+	}
+	if !ok {
+		return exceptions.ErrBadRequest("Reset token is invalid or expired")
+	}
+
+	return ps.updatePassword(ctx, userId, input.NewPassword)
+}
+
+// Stores the new hash and logs out every device of the user
+func (ps *passwordService) updatePassword(ctx context.Context, userId, password string) error {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		ps.Logger.Error(ctx, "unable to hash password", "error", err, "userId", userId)
+		return exceptions.ErrBadRequest(err.Error())
+	}
+
+	err = ps.UserRepository.UpdatePassword(ctx, ps.Db, userId, string(hash))
+	if err != nil {
+		ps.Logger.Error(ctx, "userRepository.UpdatePassword failed", "error", err, "userId", userId)
+
+		statusCode, message := helper.MapPgxError(err)
+		return exceptions.NewErrorResponse(statusCode, message)
+	}
+
+	return ps.authService.RevokeSessions(ctx, userId)
+}
+
+// Opaque token sent to the user, only its sha256 hash is stored
+func newResetToken() (string, string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(buf)
+	return token, hashResetToken(token), nil
+}
+
+func hashResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,185 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.4
+// 	protoc        v5.29.3
+// source: proto/user_events.proto
+
+package userevents
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Dikirim setelah akun user dianonimkan
+type UserDeletedEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=userId,proto3" json:"userId,omitempty"`
+	DeletedAt     string                 `protobuf:"bytes,2,opt,name=deletedAt,proto3" json:"deletedAt,omitempty"` // RFC 3339
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserDeletedEvent) Reset() {
+	*x = UserDeletedEvent{}
+	mi := &file_proto_user_events_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserDeletedEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserDeletedEvent) ProtoMessage() {}
+
+func (x *UserDeletedEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_events_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserDeletedEvent.ProtoReflect.Descriptor instead.
+func (*UserDeletedEvent) Descriptor() ([]byte, []int) {
+	return file_proto_user_events_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *UserDeletedEvent) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UserDeletedEvent) GetDeletedAt() string {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return ""
+}
+
+// Handler harus idempoten, event yang sama bisa dikirim ulang
+type UserEventAck struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserEventAck) Reset() {
+	*x = UserEventAck{}
+	mi := &file_proto_user_events_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserEventAck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserEventAck) ProtoMessage() {}
+
+func (x *UserEventAck) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_events_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserEventAck.ProtoReflect.Descriptor instead.
+func (*UserEventAck) Descriptor() ([]byte, []int) {
+	return file_proto_user_events_proto_rawDescGZIP(), []int{1}
+}
+
+var File_proto_user_events_proto protoreflect.FileDescriptor
+
+var file_proto_user_events_proto_rawDesc = string([]byte{
+	0x0a, 0x17, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x65, 0x76, 0x65,
+	0x6e, 0x74, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0a, 0x75, 0x73, 0x65, 0x72, 0x65,
+	0x76, 0x65, 0x6e, 0x74, 0x73, 0x22, 0x48, 0x0a, 0x10, 0x55, 0x73, 0x65, 0x72, 0x44, 0x65, 0x6c,
+	0x65, 0x74, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x73, 0x65,
+	0x72, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49,
+	0x64, 0x12, 0x1c, 0x0a, 0x09, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x41, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x41, 0x74, 0x22,
+	0x0e, 0x0a, 0x0c, 0x55, 0x73, 0x65, 0x72, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x41, 0x63, 0x6b, 0x32,
+	0x59, 0x0a, 0x10, 0x55, 0x73, 0x65, 0x72, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x53, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x12, 0x45, 0x0a, 0x0b, 0x55, 0x73, 0x65, 0x72, 0x44, 0x65, 0x6c, 0x65, 0x74,
+	0x65, 0x64, 0x12, 0x1c, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e,
+	0x55, 0x73, 0x65, 0x72, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x1a, 0x18, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x55, 0x73,
+	0x65, 0x72, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x41, 0x63, 0x6b, 0x42, 0x1f, 0x5a, 0x1d, 0x73, 0x72,
+	0x63, 0x2f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2f, 0x75, 0x73, 0x65, 0x72, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
+})
+
+var (
+	file_proto_user_events_proto_rawDescOnce sync.Once
+	file_proto_user_events_proto_rawDescData []byte
+)
+
+func file_proto_user_events_proto_rawDescGZIP() []byte {
+	file_proto_user_events_proto_rawDescOnce.Do(func() {
+		file_proto_user_events_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_user_events_proto_rawDesc), len(file_proto_user_events_proto_rawDesc)))
+	})
+	return file_proto_user_events_proto_rawDescData
+}
+
+var file_proto_user_events_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_proto_user_events_proto_goTypes = []any{
+	(*UserDeletedEvent)(nil), // 0: userevents.UserDeletedEvent
+	(*UserEventAck)(nil),     // 1: userevents.UserEventAck
+}
+var file_proto_user_events_proto_depIdxs = []int32{
+	0, // 0: userevents.UserEventService.UserDeleted:input_type -> userevents.UserDeletedEvent
+	1, // 1: userevents.UserEventService.UserDeleted:output_type -> userevents.UserEventAck
+	1, // [1:2] is the sub-list for method output_type
+	0, // [0:1] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_proto_user_events_proto_init() }
+func file_proto_user_events_proto_init() {
+	if File_proto_user_events_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_user_events_proto_rawDesc), len(file_proto_user_events_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_user_events_proto_goTypes,
+		DependencyIndexes: file_proto_user_events_proto_depIdxs,
+		MessageInfos:      file_proto_user_events_proto_msgTypes,
+	}.Build()
+	File_proto_user_events_proto = out.File
+	file_proto_user_events_proto_goTypes = nil
+	file_proto_user_events_proto_depIdxs = nil
+}
@@ -0,0 +1,121 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: proto/user_events.proto
+
+package userevents
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	UserEventService_UserDeleted_FullMethodName = "/userevents.UserEventService/UserDeleted"
+)
+
+// UserEventServiceClient is the client API for UserEventService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type UserEventServiceClient interface {
+	UserDeleted(ctx context.Context, in *UserDeletedEvent, opts ...grpc.CallOption) (*UserEventAck, error)
+}
+
+type userEventServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewUserEventServiceClient(cc grpc.ClientConnInterface) UserEventServiceClient {
+	return &userEventServiceClient{cc}
+}
+
+func (c *userEventServiceClient) UserDeleted(ctx context.Context, in *UserDeletedEvent, opts ...grpc.CallOption) (*UserEventAck, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserEventAck)
+	err := c.cc.Invoke(ctx, UserEventService_UserDeleted_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UserEventServiceServer is the server API for UserEventService service.
+// All implementations must embed UnimplementedUserEventServiceServer
+// for forward compatibility.
+type UserEventServiceServer interface {
+	UserDeleted(context.Context, *UserDeletedEvent) (*UserEventAck, error)
+	mustEmbedUnimplementedUserEventServiceServer()
+}
+
+// UnimplementedUserEventServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedUserEventServiceServer struct{}
+
+func (UnimplementedUserEventServiceServer) UserDeleted(context.Context, *UserDeletedEvent) (*UserEventAck, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UserDeleted not implemented")
+}
+func (UnimplementedUserEventServiceServer) mustEmbedUnimplementedUserEventServiceServer() {}
+func (UnimplementedUserEventServiceServer) testEmbeddedByValue()                          {}
+
+// UnsafeUserEventServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to UserEventServiceServer will
+// result in compilation errors.
+type UnsafeUserEventServiceServer interface {
+	mustEmbedUnimplementedUserEventServiceServer()
+}
+
+func RegisterUserEventServiceServer(s grpc.ServiceRegistrar, srv UserEventServiceServer) {
+	// If the following call pancis, it indicates UnimplementedUserEventServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&UserEventService_ServiceDesc, srv)
+}
+
+func _UserEventService_UserDeleted_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserDeletedEvent)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserEventServiceServer).UserDeleted(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserEventService_UserDeleted_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserEventServiceServer).UserDeleted(ctx, req.(*UserDeletedEvent))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// UserEventService_ServiceDesc is the grpc.ServiceDesc for UserEventService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var UserEventService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "userevents.UserEventService",
+	HandlerType: (*UserEventServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "UserDeleted",
+			Handler:    _UserEventService_UserDeleted_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/user_events.proto",
+}
@@ -0,0 +1,107 @@
+package roleService
+
+import (
+	"context"
+	"errors"
+
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/auth"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/logging"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/exceptions"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/helper"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/model/dtos/response"
+	userRepository "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/repositories/user"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/samber/do/v2"
+)
+
+// Role changes reach the access token with the next login or refresh,
+// so they take effect within JWT_ACCESS_TTL
+type RoleServiceInterface interface {
+	GetRoles(ctx context.Context, userId string) (response.RolesResponse, error)
+	GrantRole(ctx context.Context, userId, role string) (response.RolesResponse, error)
+	// adminId is the caller, an admin can't take the admin role from themselves
+	RevokeRole(ctx context.Context, adminId, userId, role string) (response.RolesResponse, error)
+}
+
+type roleService struct {
+	UserRepository userRepository.UserRepositoryInterface
+	Db             *pgxpool.Pool
+	Logger         *logging.Logger
+}
+
+func NewRoleService(
+	userRepo userRepository.UserRepositoryInterface,
+	db *pgxpool.Pool,
+	logger *logging.Logger,
+) RoleServiceInterface {
+	return &roleService{
+		UserRepository: userRepo,
+		Db:             db,
+		Logger:         logger,
+	}
+}
+
+func NewRoleServiceInject(i do.Injector) (RoleServiceInterface, error) {
+	_db := do.MustInvoke[*pgxpool.Pool](i)
+	_userRepo := do.MustInvoke[userRepository.UserRepositoryInterface](i)
+	_logger := do.MustInvoke[*logging.Logger](i)
+
+	return NewRoleService(_userRepo, _db, _logger), nil
+}
+
+func (rs *roleService) GetRoles(ctx context.Context, userId string) (response.RolesResponse, error) {
+	roles, err := rs.UserRepository.GetRoles(ctx, rs.Db, userId)
+	if err != nil {
+		return response.RolesResponse{}, rs.mapError(ctx, err, "userRepository.GetRoles", userId)
+	}
+
+	return response.RolesResponse{UserId: userId, Roles: roles}, nil
+}
+
+func (rs *roleService) GrantRole(ctx context.Context, userId, role string) (response.RolesResponse, error) {
+	if !auth.IsValidRole(role) {
+		return response.RolesResponse{}, exceptions.ErrBadRequest("Unknown role")
+	}
+
+	roles, err := rs.UserRepository.GrantRole(ctx, rs.Db, userId, role)
+	if err != nil {
+		return response.RolesResponse{}, rs.mapError(ctx, err, "userRepository.GrantRole", userId)
+	}
+
+	rs.Logger.Info(ctx, "role granted", "userId", userId, "role", role)
+
+	return response.RolesResponse{UserId: userId, Roles: roles}, nil
+}
+
+func (rs *roleService) RevokeRole(ctx context.Context, adminId, userId, role string) (response.RolesResponse, error) {
+	if !auth.IsValidRole(role) {
+		return response.RolesResponse{}, exceptions.ErrBadRequest("Unknown role")
+	}
+	if role == auth.RoleBuyer {
+		return response.RolesResponse{}, exceptions.ErrBadRequest("Every user is a buyer")
+	}
+	// Keeps an admin from locking themselves out by accident
+	if role == auth.RoleAdmin && adminId == userId {
+		return response.RolesResponse{}, exceptions.ErrConflict("Admins can't revoke their own admin role")
+	}
+
+	roles, err := rs.UserRepository.RevokeRole(ctx, rs.Db, userId, role)
+	if err != nil {
+		return response.RolesResponse{}, rs.mapError(ctx, err, "userRepository.RevokeRole", userId)
+	}
+
+	rs.Logger.Info(ctx, "role revoked", "userId", userId, "role", role)
+
+	return response.RolesResponse{UserId: userId, Roles: roles}, nil
+}
+
+func (rs *roleService) mapError(ctx context.Context, err error, op, userId string) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return exceptions.ErrNotFound("User not found")
+	}
+
+	rs.Logger.Error(ctx, op+" failed", "error", err, "userId", userId)
+	statusCode, message := helper.MapPgxError(err)
+	return exceptions.NewErrorResponse(statusCode, message)
+}
@@ -2,35 +2,49 @@ package userService
 
 import (
 	"context"
+	"errors"
+	"slices"
+	"strings"
 
-	authJwt "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/auth/jwt"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/middleware/logging"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/cache"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/config"
+	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/encryption"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/exceptions"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/helper"
-	functionCallerInfo "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/logger/helper"
-	loggerZap "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/logger/zap"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/model/dtos/repository"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/model/dtos/request"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/model/dtos/response"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/model/dtos/service"
 	userRepository "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/repositories/user"
+	authService "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/auth"
 	fileService "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/external/file"
+	productService "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/external/product"
+	loginAttemptService "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/loginAttempt"
+	otpService "github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/otp"
 	"github.com/TIM-DEBUG-ProjectSprintBatch3/TutupLapak/user/src/services/user/validator"
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/samber/do/v2"
 	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/sync/singleflight"
 )
 
 type UserServiceInterface interface {
 	RegisterByEmail(ctx context.Context, input request.AuthByEmailRequest) (response.AuthResponse, error)
 	RegisterByPhone(ctx context.Context, input request.AuthByPhoneRequest) (response.AuthResponse, error)
-	LoginByEmail(ctx context.Context, input request.AuthByEmailRequest) (response.AuthResponse, error)
-	LoginByPhone(ctx context.Context, input request.AuthByPhoneRequest) (response.AuthResponse, error)
-
-	LinkEmail(ctx context.Context, input request.LinkEmailRequest, userId string) (response.UserResponse, error)
-	LinkPhone(ctx context.Context, input request.LinkPhoneRequest, userId string) (response.UserResponse, error)
+	LoginByEmail(ctx context.Context, input request.AuthByEmailRequest, clientIp string) (response.AuthResponse, error)
+	LoginByPhone(ctx context.Context, input request.AuthByPhoneRequest, clientIp string) (response.AuthResponse, error)
+
+	// Sends a code to the new address, it is linked by VerifyEmail/VerifyPhone
+	LinkEmail(ctx context.Context, input request.LinkEmailRequest, userId string) (response.VerificationResponse, error)
+	LinkPhone(ctx context.Context, input request.LinkPhoneRequest, userId string) (response.VerificationResponse, error)
+	VerifyEmail(ctx context.Context, input request.VerifyCodeRequest, userId string) (response.UserResponse, error)
+	VerifyPhone(ctx context.Context, input request.VerifyCodeRequest, userId string) (response.UserResponse, error)
 	GetUserProfile(ctx context.Context, userId string) (response.UserResponse, error)
+	GetSellerProfile(ctx context.Context, sellerId string) (response.SellerProfileResponse, error)
 	UpdateUserProfile(ctx context.Context, input request.UpdateUserProfileRequest, userId string) (response.UserResponse, error) //for grpc
 
 	GetUserProfiles(ctx context.Context, userIds []string) ([]response.UserResponse, error)
@@ -41,25 +55,38 @@ type userService struct {
 	UserRepository userRepository.UserRepositoryInterface
 	Db             *pgxpool.Pool
 	Cache          cache.RedisCacheClient
-	jwtService     authJwt.JwtServiceInterface
+	authService    authService.AuthServiceInterface
+	loginAttempt   loginAttemptService.LoginAttemptServiceInterface
+	otpService     otpService.OtpServiceInterface
 	fileService    fileService.FileServiceInterface
-	Logger         loggerZap.LoggerInterface
+	productService productService.ProductServiceInterface
+	fieldCipher    encryption.FieldCipherInterface
+	Logger         *logging.Logger
+	profileLoads   singleflight.Group
 }
 
 func NewUserService(
 	userRepo userRepository.UserRepositoryInterface,
 	db *pgxpool.Pool,
 	cache cache.RedisCacheClient,
-	jwtService authJwt.JwtServiceInterface,
+	authService authService.AuthServiceInterface,
+	loginAttempt loginAttemptService.LoginAttemptServiceInterface,
+	otpService otpService.OtpServiceInterface,
 	fileService fileService.FileServiceInterface,
-	logger loggerZap.LoggerInterface,
+	productService productService.ProductServiceInterface,
+	fieldCipher encryption.FieldCipherInterface,
+	logger *logging.Logger,
 ) UserServiceInterface {
 	return &userService{
 		UserRepository: userRepo,
 		Db:             db,
 		Cache:          cache,
-		jwtService:     jwtService,
+		authService:    authService,
+		loginAttempt:   loginAttempt,
+		otpService:     otpService,
 		fileService:    fileService,
+		productService: productService,
+		fieldCipher:    fieldCipher,
 		Logger:         logger,
 	}
 }
@@ -68,11 +95,15 @@ func NewUserServiceInject(i do.Injector) (UserServiceInterface, error) {
 	_db := do.MustInvoke[*pgxpool.Pool](i)
 	_cache := do.MustInvoke[cache.RedisCacheClient](i)
 	_userRepo := do.MustInvoke[userRepository.UserRepositoryInterface](i)
-	_jwtService := do.MustInvoke[authJwt.JwtServiceInterface](i)
+	_authService := do.MustInvoke[authService.AuthServiceInterface](i)
+	_loginAttempt := do.MustInvoke[loginAttemptService.LoginAttemptServiceInterface](i)
+	_otpService := do.MustInvoke[otpService.OtpServiceInterface](i)
 	_fileService := do.MustInvoke[fileService.FileServiceInterface](i)
-	_logger := do.MustInvoke[loggerZap.LoggerInterface](i)
+	_productService := do.MustInvoke[productService.ProductServiceInterface](i)
+	_fieldCipher := do.MustInvoke[encryption.FieldCipherInterface](i)
+	_logger := do.MustInvoke[*logging.Logger](i)
 
-	return NewUserService(_userRepo, _db, _cache, _jwtService, _fileService, _logger), nil
+	return NewUserService(_userRepo, _db, _cache, _authService, _loginAttempt, _otpService, _fileService, _productService, _fieldCipher, _logger), nil
 }
 
 func (us *userService) RegisterByEmail(ctx context.Context, input request.AuthByEmailRequest) (response.AuthResponse, error) {
@@ -83,7 +114,7 @@ func (us *userService) RegisterByEmail(ctx context.Context, input request.AuthBy
 
 	hash, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.MinCost)
 	if err != nil {
-		us.Logger.Error(err.Error(), functionCallerInfo.UserServiceRegisterByEmail)
+		us.Logger.Error(ctx, "userService.RegisterByEmail failed", "error", err)
 		return response.AuthResponse{}, exceptions.ErrBadRequest(err.Error())
 	}
 
@@ -91,16 +122,23 @@ func (us *userService) RegisterByEmail(ctx context.Context, input request.AuthBy
 
 	userId, err := us.UserRepository.CreateUserByEmail(ctx, us.Db, input.Email, passwordHash)
 	if err != nil {
-		us.Logger.Error(err.Error(), functionCallerInfo.UserRepositoryCreateUserByEmail, err)
+		us.Logger.Error(ctx, "userRepository.CreateUserByEmail failed", "error", err)
 
 		statusCode, message := helper.MapPgxError(err)
 		return response.AuthResponse{}, exceptions.NewErrorResponse(statusCode, message)
 	}
 
-	token, err := us.jwtService.GenerateToken(userId)
+	tokens, err := us.authService.IssueTokens(ctx, userId)
 	if err != nil {
-		us.Logger.Error(err.Error(), functionCallerInfo.UserServiceRegisterByEmail)
-		return response.AuthResponse{}, exceptions.ErrServer(err.Error())
+		us.Logger.Error(ctx, "userService.RegisterByEmail failed", "error", err)
+		return response.AuthResponse{}, err
+	}
+
+	// The account works without verification, a failed send is only logged
+	// and the user can ask for a new code through the link endpoint
+	err = us.otpService.Issue(ctx, otpService.PurposeVerifyEmail, userId, input.Email)
+	if err != nil {
+		us.Logger.Error(ctx, "userService.RegisterByEmail failed", "error", err, "userId", userId)
 	}
 
 	us.Cache.SetUserProfile(ctx, userId, &response.UserResponse{
@@ -108,9 +146,10 @@ func (us *userService) RegisterByEmail(ctx context.Context, input request.AuthBy
 	})
 
 	return response.AuthResponse{
-		Email: input.Email,
-		Phone: "",
-		Token: token,
+		Email:        input.Email,
+		Phone:        "",
+		Token:        tokens.Token,
+		RefreshToken: tokens.RefreshToken,
 	}, nil
 }
 
@@ -122,7 +161,7 @@ func (us *userService) RegisterByPhone(ctx context.Context, input request.AuthBy
 
 	hash, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.MinCost)
 	if err != nil {
-		us.Logger.Error(err.Error(), functionCallerInfo.UserServiceRegisterByEmail)
+		us.Logger.Error(ctx, "userService.RegisterByEmail failed", "error", err)
 		return response.AuthResponse{}, exceptions.ErrBadRequest(err.Error())
 	}
 
@@ -130,16 +169,23 @@ func (us *userService) RegisterByPhone(ctx context.Context, input request.AuthBy
 
 	userId, err := us.UserRepository.CreateUserByPhone(context.Background(), us.Db, input.Phone, passwordHash)
 	if err != nil {
-		us.Logger.Error(err.Error(), functionCallerInfo.UserRepositoryCreateUserByPhone, err)
+		us.Logger.Error(ctx, "userRepository.CreateUserByPhone failed", "error", err)
 
 		statusCode, message := helper.MapPgxError(err)
 		return response.AuthResponse{}, exceptions.NewErrorResponse(statusCode, message)
 	}
 
-	token, err := us.jwtService.GenerateToken(userId)
+	tokens, err := us.authService.IssueTokens(ctx, userId)
 	if err != nil {
-		us.Logger.Error(err.Error(), functionCallerInfo.UserServiceRegisterByPhone)
-		return response.AuthResponse{}, exceptions.ErrServer(err.Error())
+		us.Logger.Error(ctx, "userService.RegisterByPhone failed", "error", err)
+		return response.AuthResponse{}, err
+	}
+
+	// The account works without verification, a failed send is only logged
+	// and the user can ask for a new code through the link endpoint
+	err = us.otpService.Issue(ctx, otpService.PurposeVerifyPhone, userId, input.Phone)
+	if err != nil {
+		us.Logger.Error(ctx, "userService.RegisterByPhone failed", "error", err, "userId", userId)
 	}
 
 	us.Cache.SetUserProfile(ctx, userId, &response.UserResponse{
@@ -147,21 +193,33 @@ func (us *userService) RegisterByPhone(ctx context.Context, input request.AuthBy
 	})
 
 	return response.AuthResponse{
-		Email: "",
-		Phone: input.Phone,
-		Token: token,
+		Email:        "",
+		Phone:        input.Phone,
+		Token:        tokens.Token,
+		RefreshToken: tokens.RefreshToken,
 	}, nil
 }
 
-func (us *userService) LoginByEmail(ctx context.Context, input request.AuthByEmailRequest) (response.AuthResponse, error) {
+func (us *userService) LoginByEmail(ctx context.Context, input request.AuthByEmailRequest, clientIp string) (response.AuthResponse, error) {
 	err := validator.ValidateStructFields(input)
 	if err != nil {
 		return response.AuthResponse{}, exceptions.ErrBadRequest(err.Error())
 	}
 
+	err = us.loginAttempt.Check(ctx, input.Email, clientIp)
+	if err != nil {
+		return response.AuthResponse{}, err
+	}
+
 	auth, err := us.UserRepository.GetAuthByEmail(context.Background(), us.Db, input.Email)
 	if err != nil {
-		us.Logger.Error(err.Error(), functionCallerInfo.UserRepositoryGetAuthByEmail, err)
+		us.Logger.Error(ctx, "userRepository.GetAuthByEmail failed", "error", err)
+
+		if errors.Is(err, pgx.ErrNoRows) {
+			if lockErr := us.loginAttempt.RegisterFailure(ctx, input.Email, clientIp); lockErr != nil {
+				return response.AuthResponse{}, lockErr
+			}
+		}
 
 		statusCode, message := helper.MapPgxError(err)
 		return response.AuthResponse{}, exceptions.NewErrorResponse(statusCode, message)
@@ -173,14 +231,20 @@ func (us *userService) LoginByEmail(ctx context.Context, input request.AuthByEma
 
 	err = bcrypt.CompareHashAndPassword([]byte(auth.HashPassword), []byte(input.Password))
 	if err != nil {
-		us.Logger.Error(err.Error(), functionCallerInfo.UserServiceLoginByEmail)
+		us.Logger.Error(ctx, "userService.LoginByEmail failed", "error", err)
+
+		if lockErr := us.loginAttempt.RegisterFailure(ctx, input.Email, clientIp); lockErr != nil {
+			return response.AuthResponse{}, lockErr
+		}
 		return response.AuthResponse{}, exceptions.ErrBadRequest("Wrong password")
 	}
 
-	token, err := us.jwtService.GenerateToken(auth.UserId)
+	us.loginAttempt.Reset(ctx, input.Email)
+
+	tokens, err := us.authService.IssueTokens(ctx, auth.UserId)
 	if err != nil {
-		us.Logger.Error(err.Error(), functionCallerInfo.UserServiceLoginByEmail)
-		return response.AuthResponse{}, exceptions.ErrServer(err.Error())
+		us.Logger.Error(ctx, "userService.LoginByEmail failed", "error", err)
+		return response.AuthResponse{}, err
 	}
 
 	us.Cache.SetUserProfile(ctx, auth.UserId, &response.UserResponse{
@@ -189,21 +253,33 @@ func (us *userService) LoginByEmail(ctx context.Context, input request.AuthByEma
 	})
 
 	return response.AuthResponse{
-		Email: input.Email,
-		Phone: auth.Phone,
-		Token: token,
+		Email:        input.Email,
+		Phone:        auth.Phone,
+		Token:        tokens.Token,
+		RefreshToken: tokens.RefreshToken,
 	}, nil
 }
 
-func (us *userService) LoginByPhone(ctx context.Context, input request.AuthByPhoneRequest) (response.AuthResponse, error) {
+func (us *userService) LoginByPhone(ctx context.Context, input request.AuthByPhoneRequest, clientIp string) (response.AuthResponse, error) {
 	err := validator.ValidateStructFields(input)
 	if err != nil {
 		return response.AuthResponse{}, exceptions.ErrBadRequest(err.Error())
 	}
 
+	err = us.loginAttempt.Check(ctx, input.Phone, clientIp)
+	if err != nil {
+		return response.AuthResponse{}, err
+	}
+
 	auth, err := us.UserRepository.GetAuthByPhone(context.Background(), us.Db, input.Phone)
 	if err != nil {
-		us.Logger.Error(err.Error(), functionCallerInfo.UserRepositoryGetAuthByPhone, err)
+		us.Logger.Error(ctx, "userRepository.GetAuthByPhone failed", "error", err)
+
+		if errors.Is(err, pgx.ErrNoRows) {
+			if lockErr := us.loginAttempt.RegisterFailure(ctx, input.Phone, clientIp); lockErr != nil {
+				return response.AuthResponse{}, lockErr
+			}
+		}
 
 		statusCode, message := helper.MapPgxError(err)
 		return response.AuthResponse{}, exceptions.NewErrorResponse(statusCode, message)
@@ -215,14 +291,20 @@ func (us *userService) LoginByPhone(ctx context.Context, input request.AuthByPho
 
 	err = bcrypt.CompareHashAndPassword([]byte(auth.HashPassword), []byte(input.Password))
 	if err != nil {
-		us.Logger.Error(err.Error(), functionCallerInfo.UserServiceLoginByPhone)
+		us.Logger.Error(ctx, "userService.LoginByPhone failed", "error", err)
+
+		if lockErr := us.loginAttempt.RegisterFailure(ctx, input.Phone, clientIp); lockErr != nil {
+			return response.AuthResponse{}, lockErr
+		}
 		return response.AuthResponse{}, exceptions.ErrBadRequest("Wrong password")
 	}
 
-	token, err := us.jwtService.GenerateToken(auth.UserId)
+	us.loginAttempt.Reset(ctx, input.Phone)
+
+	tokens, err := us.authService.IssueTokens(ctx, auth.UserId)
 	if err != nil {
-		us.Logger.Error(err.Error(), functionCallerInfo.UserServiceLoginByPhone)
-		return response.AuthResponse{}, exceptions.ErrServer(err.Error())
+		us.Logger.Error(ctx, "userService.LoginByPhone failed", "error", err)
+		return response.AuthResponse{}, err
 	}
 
 	us.Cache.SetUserProfile(ctx, auth.UserId, &response.UserResponse{
@@ -231,74 +313,162 @@ func (us *userService) LoginByPhone(ctx context.Context, input request.AuthByPho
 	})
 
 	return response.AuthResponse{
-		Email: auth.Email,
-		Phone: input.Phone,
-		Token: token,
+		Email:        auth.Email,
+		Phone:        input.Phone,
+		Token:        tokens.Token,
+		RefreshToken: tokens.RefreshToken,
 	}, nil
 }
 
-func (us *userService) LinkEmail(ctx context.Context, input request.LinkEmailRequest, userId string) (response.UserResponse, error) {
+func (us *userService) LinkEmail(ctx context.Context, input request.LinkEmailRequest, userId string) (response.VerificationResponse, error) {
+	err := validator.ValidateStructFields(input)
+	if err != nil {
+		return response.VerificationResponse{}, exceptions.ErrBadRequest(err.Error())
+	}
+
+	owner, err := us.UserRepository.GetAuthByEmail(ctx, us.Db, input.Email)
+	if err == nil && owner.UserId != userId {
+		return response.VerificationResponse{}, exceptions.ErrConflict("Email already used")
+	}
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		us.Logger.Error(ctx, "userRepository.GetAuthByEmail failed", "error", err)
+
+		statusCode, message := helper.MapPgxError(err)
+		return response.VerificationResponse{}, exceptions.NewErrorResponse(statusCode, message)
+	}
+
+	err = us.otpService.Issue(ctx, otpService.PurposeVerifyEmail, userId, input.Email)
+	if err != nil {
+		us.Logger.Error(ctx, "userService.LinkEmail failed", "error", err, "userId", userId)
+		return response.VerificationResponse{}, err
+	}
+
+	return response.VerificationResponse{
+		Target:    input.Email,
+		ExpiresIn: int(config.GetOtpTtl().Seconds()),
+	}, nil
+}
+
+func (us *userService) LinkPhone(ctx context.Context, input request.LinkPhoneRequest, userId string) (response.VerificationResponse, error) {
+	err := validator.ValidateStructFields(input)
+	if err != nil {
+		return response.VerificationResponse{}, exceptions.ErrBadRequest(err.Error())
+	}
+
+	owner, err := us.UserRepository.GetAuthByPhone(ctx, us.Db, input.Phone)
+	if err == nil && owner.UserId != userId {
+		return response.VerificationResponse{}, exceptions.ErrConflict("Phone already used")
+	}
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		us.Logger.Error(ctx, "userRepository.GetAuthByPhone failed", "error", err)
+
+		statusCode, message := helper.MapPgxError(err)
+		return response.VerificationResponse{}, exceptions.NewErrorResponse(statusCode, message)
+	}
+
+	err = us.otpService.Issue(ctx, otpService.PurposeVerifyPhone, userId, input.Phone)
+	if err != nil {
+		us.Logger.Error(ctx, "userService.LinkPhone failed", "error", err, "userId", userId)
+		return response.VerificationResponse{}, err
+	}
+
+	return response.VerificationResponse{
+		Target:    input.Phone,
+		ExpiresIn: int(config.GetOtpTtl().Seconds()),
+	}, nil
+}
+
+func (us *userService) VerifyEmail(ctx context.Context, input request.VerifyCodeRequest, userId string) (response.UserResponse, error) {
 	err := validator.ValidateStructFields(input)
 	if err != nil {
 		return response.UserResponse{}, exceptions.ErrBadRequest(err.Error())
 	}
 
-	user, err := us.UserRepository.UpdateEmail(context.Background(), us.Db, input.Email, userId)
+	email, err := us.otpService.Verify(ctx, otpService.PurposeVerifyEmail, userId, input.Code)
 	if err != nil {
-		us.Logger.Error(err.Error(), functionCallerInfo.UserRepositoryUpdateEmail, err)
+		return response.UserResponse{}, err
+	}
+
+	user, err := us.UserRepository.UpdateEmail(ctx, us.Db, email, userId)
+	if err != nil {
+		us.Logger.Error(ctx, "userRepository.UpdateEmail failed", "error", err)
 
 		statusCode, message := helper.MapPgxError(err)
 		return response.UserResponse{}, exceptions.NewErrorResponse(statusCode, message)
 	}
 
 	if user == nil {
-		us.Logger.Error("User not found", functionCallerInfo.UserServiceLinkEmail)
+		us.Logger.Error(ctx, "User not found")
 		return response.UserResponse{}, exceptions.NewNotFoundError("User not found", fiber.StatusNotFound)
 	}
 
 	response := helper.ConvertUserToResponse(user)
-	response.Email = input.Email
+	response.Email = email
 
 	us.Cache.SetUserProfile(ctx, userId, &response)
 
+	err = us.openBankDetails(ctx, userId, response.BankAccountId, &response.BankAccountHolder, &response.BankAccountNumber)
+	if err != nil {
+		return response, err
+	}
+
 	return response, nil
 }
 
-func (us *userService) LinkPhone(ctx context.Context, input request.LinkPhoneRequest, userId string) (response.UserResponse, error) {
+func (us *userService) VerifyPhone(ctx context.Context, input request.VerifyCodeRequest, userId string) (response.UserResponse, error) {
 	err := validator.ValidateStructFields(input)
 	if err != nil {
 		return response.UserResponse{}, exceptions.ErrBadRequest(err.Error())
 	}
 
-	user, err := us.UserRepository.UpdatePhone(context.Background(), us.Db, input.Phone, userId)
+	phone, err := us.otpService.Verify(ctx, otpService.PurposeVerifyPhone, userId, input.Code)
+	if err != nil {
+		return response.UserResponse{}, err
+	}
+
+	user, err := us.UserRepository.UpdatePhone(ctx, us.Db, phone, userId)
 	if err != nil {
-		us.Logger.Error(err.Error(), functionCallerInfo.UserRepositoryUpdatePhone, err)
+		us.Logger.Error(ctx, "userRepository.UpdatePhone failed", "error", err)
 
 		statusCode, message := helper.MapPgxError(err)
 		return response.UserResponse{}, exceptions.NewErrorResponse(statusCode, message)
 	}
 
 	if user == nil {
-		us.Logger.Error("User not found", functionCallerInfo.UserServiceLinkPhone)
+		us.Logger.Error(ctx, "User not found")
 		return response.UserResponse{}, exceptions.NewNotFoundError("User not found", fiber.StatusNotFound)
 	}
 
 	response := helper.ConvertUserToResponse(user)
-	response.Phone = input.Phone
+	response.Phone = phone
 
 	us.Cache.SetUserProfile(ctx, userId, &response)
 
+	err = us.openBankDetails(ctx, userId, response.BankAccountId, &response.BankAccountHolder, &response.BankAccountNumber)
+	if err != nil {
+		return response, err
+	}
+
 	return response, nil
 }
 
 func (us *userService) GetUserProfile(ctx context.Context, userId string) (response.UserResponse, error) {
 	if cachedUser, ok := us.Cache.GetUserProfile(ctx, userId); ok {
+		err := us.openBankDetails(ctx, userId, cachedUser.BankAccountId, &cachedUser.BankAccountHolder, &cachedUser.BankAccountNumber)
+		if err != nil {
+			return response.UserResponse{}, err
+		}
+
 		return response.UserResponse{
 			Email:             cachedUser.Email,
 			Phone:             cachedUser.Phone,
+			EmailVerified:     cachedUser.EmailVerified,
+			PhoneVerified:     cachedUser.PhoneVerified,
+			DisplayName:       cachedUser.DisplayName,
 			FileId:            cachedUser.FileId,
 			FileUri:           cachedUser.FileUri,
 			FileThumbnailUri:  cachedUser.FileThumbnailUri,
+			BankAccountId:     cachedUser.BankAccountId,
 			BankAccountName:   cachedUser.BankAccountName,
 			BankAccountHolder: cachedUser.BankAccountHolder,
 			BankAccountNumber: cachedUser.BankAccountNumber,
@@ -307,14 +477,14 @@ func (us *userService) GetUserProfile(ctx context.Context, userId string) (respo
 
 	user, err := us.UserRepository.GetUserProfile(context.Background(), us.Db, userId)
 	if err != nil {
-		us.Logger.Error(err.Error(), functionCallerInfo.UserRepositoryGetUserProfile, err)
+		us.Logger.Error(ctx, "userRepository.GetUserProfile failed", "error", err)
 
 		statusCode, message := helper.MapPgxError(err)
 		return response.UserResponse{}, exceptions.NewErrorResponse(statusCode, message)
 	}
 
 	if user == nil {
-		us.Logger.Error("Bad request", functionCallerInfo.UserServiceGetUserProfile)
+		us.Logger.Error(ctx, "Bad request")
 		return response.UserResponse{}, exceptions.NewBadRequestError("Bad request", fiber.StatusBadRequest)
 	}
 
@@ -322,19 +492,83 @@ func (us *userService) GetUserProfile(ctx context.Context, userId string) (respo
 
 	us.Cache.SetUserProfile(ctx, userId, &response)
 
+	err = us.openBankDetails(ctx, userId, response.BankAccountId, &response.BankAccountHolder, &response.BankAccountNumber)
+	if err != nil {
+		return response, err
+	}
+
 	return response, nil
 }
 
+func (us *userService) GetSellerProfile(ctx context.Context, sellerId string) (response.SellerProfileResponse, error) {
+	seller, err := us.UserRepository.GetSellerProfile(ctx, us.Db, sellerId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return response.SellerProfileResponse{}, exceptions.ErrNotFound("Seller not found")
+		}
+
+		us.Logger.Error(ctx, "userRepository.GetSellerProfile failed", "error", err, "sellerId", sellerId)
+		statusCode, message := helper.MapPgxError(err)
+		return response.SellerProfileResponse{}, exceptions.NewErrorResponse(statusCode, message)
+	}
+
+	profile := response.SellerProfileResponse{
+		UserId:   seller.UserId,
+		JoinedAt: seller.CreatedAt,
+	}
+	if seller.DisplayName != nil {
+		profile.DisplayName = *seller.DisplayName
+	}
+	if seller.FileThumbnailUri != nil {
+		profile.AvatarUri = *seller.FileThumbnailUri
+	}
+
+	// The profile is still useful without the count, a product outage only hides it
+	if count, ok := us.productService.CountListings(ctx, seller.UserId); ok {
+		profile.ListingCount = &count
+	}
+
+	return profile, nil
+}
+
 func (us *userService) UpdateUserProfile(ctx context.Context, input request.UpdateUserProfileRequest, userId string) (response.UserResponse, error) {
 	err := validator.ValidateStructFields(input)
 	if err != nil {
 		return response.UserResponse{}, exceptions.ErrBadRequest(err.Error())
 	}
 
+	// The sealed fields are bound to the default account, a new id is chosen when the user has none yet
+	bankAccountId, err := us.UserRepository.GetDefaultBankAccountId(ctx, us.Db, userId)
+	if errors.Is(err, pgx.ErrNoRows) {
+		bankAccountId, err = uuid.NewString(), nil
+	}
+	if err != nil {
+		us.Logger.Error(ctx, "userRepository.GetDefaultBankAccountId failed", "error", err, "userId", userId)
+
+		statusCode, message := helper.MapPgxError(err)
+		return response.UserResponse{}, exceptions.NewErrorResponse(statusCode, message)
+	}
+
+	bankAccountHolder, err := us.fieldCipher.Encrypt(input.BankAccountHolder, encryption.Binding(userId, bankAccountId, encryption.BankAccountHolderColumn))
+	if err != nil {
+		us.Logger.Error(ctx, "userService.UpdateUserProfile failed", "error", err)
+		return response.UserResponse{}, exceptions.ErrServer("Internal server error")
+	}
+	bankAccountNumber, err := us.fieldCipher.Encrypt(input.BankAccountNumber, encryption.Binding(userId, bankAccountId, encryption.BankAccountNumberColumn))
+	if err != nil {
+		us.Logger.Error(ctx, "userService.UpdateUserProfile failed", "error", err)
+		return response.UserResponse{}, exceptions.ErrServer("Internal server error")
+	}
+
 	updateUser := repository.UpdateUser{
+		BankAccountId:     &bankAccountId,
 		BankAccountName:   &input.BankAccountName,
-		BankAccountHolder: &input.BankAccountHolder,
-		BankAccountNumber: &input.BankAccountNumber,
+		BankAccountHolder: &bankAccountHolder,
+		BankAccountNumber: &bankAccountNumber,
+	}
+
+	if input.DisplayName != "" {
+		updateUser.DisplayName = &input.DisplayName
 	}
 
 	if input.FileId != "" {
@@ -357,14 +591,14 @@ func (us *userService) UpdateUserProfile(ctx context.Context, input request.Upda
 
 	user, err := us.UserRepository.UpdateUserProfile(context.Background(), us.Db, updateUser, userId)
 	if err != nil {
-		us.Logger.Error(err.Error(), functionCallerInfo.UserRepositoryUpdateUserProfile, err)
+		us.Logger.Error(ctx, "userRepository.UpdateUserProfile failed", "error", err)
 
 		statusCode, message := helper.MapPgxError(err)
 		return response.UserResponse{}, exceptions.NewErrorResponse(statusCode, message)
 	}
 
 	if user == nil {
-		us.Logger.Error("Bad request", functionCallerInfo.UserServiceUpdateUserProfile)
+		us.Logger.Error(ctx, "Bad request")
 		return response.UserResponse{}, exceptions.NewBadRequestError("Bad request", fiber.StatusBadRequest)
 	}
 
@@ -372,6 +606,11 @@ func (us *userService) UpdateUserProfile(ctx context.Context, input request.Upda
 
 	us.Cache.SetUserProfile(ctx, userId, &response)
 
+	err = us.openBankDetails(ctx, userId, response.BankAccountId, &response.BankAccountHolder, &response.BankAccountNumber)
+	if err != nil {
+		return response, err
+	}
+
 	return response, nil
 }
 
@@ -382,23 +621,12 @@ func (us *userService) GetUserProfiles(ctx context.Context, userIds []string) ([
 	}
 	cachedUsers, missedUserIds, ok := us.Cache.MGetUserProfiles(ctx, keyStr)
 
-	var resp []response.UserResponse
+	var resp []response.UserWithIdResponse
 	if ok {
-		for _, user := range cachedUsers {
-			resp = append(resp, response.UserResponse{
-				Email:             user.Email,
-				Phone:             user.Phone,
-				FileId:            user.FileId,
-				FileUri:           user.FileUri,
-				FileThumbnailUri:  user.FileThumbnailUri,
-				BankAccountName:   user.BankAccountName,
-				BankAccountHolder: user.BankAccountHolder,
-				BankAccountNumber: user.BankAccountNumber,
-			})
-		}
+		resp = cachedUsers
 
 		if len(missedUserIds) == 0 {
-			return resp, nil
+			return us.openUserResponses(ctx, resp)
 		}
 	}
 
@@ -413,56 +641,146 @@ func (us *userService) GetUserProfiles(ctx context.Context, userIds []string) ([
 
 	if err != nil {
 		statusCode, message := helper.MapPgxError(err)
-		us.Logger.Error(err.Error(), functionCallerInfo.UserRepositoryGetUserProfile, statusCode, message, err)
+		us.Logger.Error(ctx, "userRepository.GetUserProfile failed", "error", err, "statusCode", statusCode, "message", message)
 		// kalau ada masalah apa-pun, kembalikan badrequest
 		return nil, exceptions.NewBadRequestError(fiber.ErrBadRequest.Message, fiber.StatusBadRequest)
 	}
 
 	if users == nil {
-		us.Logger.Error(fiber.ErrBadRequest.Message, functionCallerInfo.UserServiceGetUserProfile)
+		us.Logger.Error(ctx, fiber.ErrBadRequest.Message)
 		return nil, exceptions.NewBadRequestError(fiber.ErrBadRequest.Message, fiber.StatusBadRequest)
 	}
 
 	resp = append(resp, users...)
 
-	return resp, nil
+	return us.openUserResponses(ctx, resp)
 }
 
 func (us *userService) GetUserProfilesWithId(ctx context.Context, userIds []string) ([]response.UserWithIdResponse, error) {
 	var keyStr []string
-	for _, id := range userIds {
+	for _, id := range uniqueIds(userIds) {
 		keyStr = append(keyStr, "user:"+id)
 	}
-	cachedUsers, missedUserIds, ok := us.Cache.MGetUserProfiles(ctx, keyStr)
-	if ok {
-		if len(missedUserIds) == 0 {
-			return cachedUsers, nil
-		}
-	}
-
-	var queryIds []string
-	if len(missedUserIds) > 0 {
-		queryIds = missedUserIds
-	} else {
-		queryIds = userIds
+	// Redis errors only turn every id into a miss
+	cachedUsers, missedUserIds, _ := us.Cache.MGetUserProfiles(ctx, keyStr)
+	if len(missedUserIds) == 0 {
+		return us.openUserWithIdResponses(ctx, cachedUsers)
 	}
-	users, err := us.UserRepository.GetUserProfilesWithId(ctx, us.Db, queryIds)
 
+	users, err := us.loadUserProfilesWithId(ctx, missedUserIds)
 	if err != nil {
 		statusCode, message := helper.MapPgxError(err)
-		us.Logger.Error(err.Error(), functionCallerInfo.UserRepositoryGetUserProfile, statusCode, message, err)
+		us.Logger.Error(ctx, "userRepository.GetUserProfile failed", "error", err, "statusCode", statusCode, "message", message)
 		// kalau ada masalah apa-pun, kembalikan badrequest
 		return nil, exceptions.NewBadRequestError(fiber.ErrBadRequest.Message, fiber.StatusBadRequest)
 	}
 
-	if users == nil {
-		us.Logger.Error(fiber.ErrBadRequest.Message, functionCallerInfo.UserServiceGetUserProfile)
+	if len(cachedUsers) == 0 && len(users) == 0 {
+		us.Logger.Error(ctx, fiber.ErrBadRequest.Message)
 		return nil, exceptions.NewBadRequestError(fiber.ErrBadRequest.Message, fiber.StatusBadRequest)
 	}
 
-	us.Cache.MSetUserProfiles(ctx, users)
+	// append copies the shared singleflight result, opening happens on our own copy
+	return us.openUserWithIdResponses(ctx, append(cachedUsers, users...))
+}
+
+// Concurrent lookups of the same ids share one query and one cache write.
+// The result is shared between the callers and must not be modified
+func (us *userService) loadUserProfilesWithId(ctx context.Context, userIds []string) ([]response.UserWithIdResponse, error) {
+	sorted := slices.Clone(userIds)
+	slices.Sort(sorted)
+
+	result, err, _ := us.profileLoads.Do(strings.Join(sorted, ","), func() (interface{}, error) {
+		// One caller going away must not fail the others waiting on this load
+		loadCtx := context.WithoutCancel(ctx)
+
+		users, err := us.UserRepository.GetUserProfilesWithId(loadCtx, us.Db, sorted)
+		if err != nil {
+			return nil, err
+		}
+
+		// Still sealed here, so redis only ever holds ciphertext
+		err = us.Cache.MSetUserProfiles(loadCtx, users)
+		if err != nil {
+			us.Logger.Error(ctx, "userService.GetUserProfilesWithId failed", "error", err)
+		}
+
+		return users, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result.([]response.UserWithIdResponse), nil
+}
 
-	cachedUsers = append(cachedUsers, users...)
+func uniqueIds(ids []string) []string {
+	seen := make(map[string]struct{}, len(ids))
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		unique = append(unique, id)
+	}
+
+	return unique
+}
+
+// Bank holder and number stay sealed in the database and in redis, they are only opened here
+func (us *userService) openBankDetails(ctx context.Context, userId, bankAccountId string, holder, number *string) error {
+	fields := []struct {
+		value  *string
+		column string
+	}{
+		{holder, encryption.BankAccountHolderColumn},
+		{number, encryption.BankAccountNumberColumn},
+	}
+	for _, field := range fields {
+		plaintext, err := us.fieldCipher.Decrypt(*field.value, encryption.Binding(userId, bankAccountId, field.column))
+		if err != nil {
+			us.Logger.Error(ctx, "userService.openBankDetails failed", "error", err, "userId", userId)
+			return exceptions.ErrServer("Internal server error")
+		}
+		*field.value = plaintext
+	}
+
+	return nil
+}
+
+// The ids are only needed to open the bank details, the responses leave them out
+func (us *userService) openUserResponses(ctx context.Context, users []response.UserWithIdResponse) ([]response.UserResponse, error) {
+	opened, err := us.openUserWithIdResponses(ctx, users)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]response.UserResponse, 0, len(opened))
+	for _, user := range opened {
+		resp = append(resp, response.UserResponse{
+			Email:             user.Email,
+			Phone:             user.Phone,
+			FileId:            user.FileId,
+			FileUri:           user.FileUri,
+			FileThumbnailUri:  user.FileThumbnailUri,
+			BankAccountId:     user.BankAccountId,
+			BankAccountName:   user.BankAccountName,
+			BankAccountHolder: user.BankAccountHolder,
+			BankAccountNumber: user.BankAccountNumber,
+		})
+	}
+
+	return resp, nil
+}
+
+func (us *userService) openUserWithIdResponses(ctx context.Context, users []response.UserWithIdResponse) ([]response.UserWithIdResponse, error) {
+	for i := range users {
+		err := us.openBankDetails(ctx, users[i].UserId, users[i].BankAccountId, &users[i].BankAccountHolder, &users[i].BankAccountNumber)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	return cachedUsers, nil
+	return users, nil
 }